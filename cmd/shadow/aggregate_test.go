@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kumaraguru1735/shadow/internal/store"
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+// captureOutput runs fn with stdout and stderr sent to files and returns
+// what was written to each
+func captureOutput(t *testing.T, fn func()) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	stdout, err := os.Create(filepath.Join(dir, "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr, err := os.Create(filepath.Join(dir, "stderr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	originalOut, originalErr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = stdout, stderr
+	defer func() { os.Stdout, os.Stderr = originalOut, originalErr }()
+	fn()
+
+	out, _ := os.ReadFile(stdout.Name())
+	errOut, _ := os.ReadFile(stderr.Name())
+	stdout.Close()
+	stderr.Close()
+	return string(out), string(errOut)
+}
+
+func TestAggregateReportToStdout(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	withPlainOutput(t)
+	st, err := store.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"scan-a", "scan-b"} {
+		result := &models.ScanResult{ID: id, Target: id + ".example.com", Status: "completed",
+			Findings: []models.Finding{{Type: "xss", Severity: "high", Title: "XSS → session theft ✅", Location: "https://" + id + ".example.com"}}}
+		if err := st.Save(result); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("format", "markdown", "")
+	cmd.Flags().String("output", "-", "")
+	cmd.Flags().String("targets-file", "", "")
+	cmd.Flags().Bool("ai-analysis", false, "")
+
+	stdout, stderr := captureOutput(t, func() { runAggregateReport(cmd, []string{"scan-a", "scan-b"}) })
+	if !strings.Contains(stdout, "XSS → session theft ✅") {
+		t.Errorf("report not written verbatim to stdout:\n%s", stdout)
+	}
+	if strings.Contains(stdout, "Aggregating") {
+		t.Errorf("progress mixed into the report:\n%s", stdout)
+	}
+	if !strings.Contains(stderr, "Aggregating 2 scans") {
+		t.Errorf("stderr = %q, want the progress there", stderr)
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kumaraguru1735/shadow/internal/ai"
+)
+
+func TestPrintAIUnavailable(t *testing.T) {
+	var buf bytes.Buffer
+	printAIUnavailable(&buf, fmt.Errorf("analysis failed: %w: pi not found", ai.ErrAIUnavailable))
+	got := buf.String()
+	if !strings.Contains(got, "analysis failed: AI unavailable: pi not found") {
+		t.Errorf("output does not show the error:\n%s", got)
+	}
+	for _, step := range ai.UnavailableHelp {
+		if !strings.Contains(got, step) {
+			t.Errorf("output misses the step %q:\n%s", step, got)
+		}
+	}
+
+	// Other errors are shown without the setup steps
+	buf.Reset()
+	printAIUnavailable(&buf, errors.New("rate limited"))
+	if got := buf.String(); !strings.Contains(got, "rate limited") || strings.Contains(got, ai.UnavailableHelp[0]) {
+		t.Errorf("output for another error:\n%s", got)
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kumaraguru1735/shadow/internal/store"
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+func TestNewOnlyHidesKnownFindingsFromTable(t *testing.T) {
+	withPlainOutput(t)
+	st := store.NewWithDir(t.TempDir())
+	known := models.Finding{Type: "header", Severity: "medium", Title: "Missing HSTS", Location: "https://example.com"}
+	if err := st.Save(&models.ScanResult{ID: "baseline-scan", Target: "example.com", Findings: []models.Finding{known}}); err != nil {
+		t.Fatal(err)
+	}
+
+	result := &models.ScanResult{ID: "current-scan", Target: "example.com", Findings: []models.Finding{
+		known,
+		{Type: "open-port", Severity: "high", Title: "Telnet open", Location: "example.com:23"},
+	}}
+	cmd := &cobra.Command{}
+	cmd.Flags().String("baseline", "baseline-scan", "")
+	cmd.Flags().Bool("new-only", true, "")
+
+	captureOutput(t, func() { applyBaseline(cmd, st, result) })
+	var buf bytes.Buffer
+	printFindings(&buf, result, false)
+	if got := buf.String(); strings.Contains(got, "Missing HSTS") || !strings.Contains(got, "Telnet open") {
+		t.Errorf("table does not hide the known finding:\n%s", got)
+	}
+}
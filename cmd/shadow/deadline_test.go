@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func TestAnalysisDeadline(t *testing.T) {
+	tests := []struct {
+		profile     string
+		flag        string
+		wantTimeout bool
+	}{
+		{"deep", "", true},
+		{"research", "5m", true},
+		{"deep", "0", false},
+		{"standard", "5m", false},
+		{"quick", "", false},
+	}
+	for _, tt := range tests {
+		cmd := &cobra.Command{}
+		cmd.Flags().Duration("analysis-deadline", 20*time.Minute, "")
+		if tt.flag != "" {
+			cmd.Flags().Set("analysis-deadline", tt.flag)
+		}
+
+		ctx, cancel := analysisDeadline(context.Background(), cmd, tt.profile)
+		deadline, ok := ctx.Deadline()
+		cancel()
+		if ok != tt.wantTimeout {
+			t.Errorf("%s with %q: deadline set = %v, want %v", tt.profile, tt.flag, ok, tt.wantTimeout)
+		}
+		if ok && time.Until(deadline) > 20*time.Minute {
+			t.Errorf("%s with %q: deadline %s away, want at most the flag value", tt.profile, tt.flag, time.Until(deadline))
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kumaraguru1735/shadow/internal/output"
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+// aiText is an AI response full of symbols plain output would rewrite
+const aiText = "## ✅ Fixed\n│ table │ → next\n⚠️  Rotate the key"
+
+func withPlainOutput(t *testing.T) {
+	t.Helper()
+	original := output.IsPlain()
+	t.Cleanup(func() { output.SetPlain(original) })
+	output.SetPlain(true)
+}
+
+func TestPrintAnalysisShowsRawVerbatim(t *testing.T) {
+	withPlainOutput(t)
+	analysis := &models.AIAnalysis{Summary: "One issue", RiskScore: 40, RawText: aiText + "\n\n", Timestamp: time.Now()}
+
+	var buf bytes.Buffer
+	printAnalysis(&buf, analysis, true)
+	got := buf.String()
+	if !strings.Contains(got, aiText+"\n") {
+		t.Errorf("raw response not printed verbatim:\n%s", got)
+	}
+	if !strings.Contains(got, "[OK] Analysis completed") {
+		t.Errorf("Shadow's own lines not styled for plain output:\n%s", got)
+	}
+
+	buf.Reset()
+	printAnalysis(&buf, analysis, false)
+	if strings.Contains(buf.String(), "Rotate the key") {
+		t.Errorf("raw response shown without --raw:\n%s", buf.String())
+	}
+}
+
+func TestPrintExplanationVerbatim(t *testing.T) {
+	withPlainOutput(t)
+	var buf bytes.Buffer
+	printExplanation(&buf, aiText)
+	if got := buf.String(); got != "\n"+aiText+"\n" {
+		t.Errorf("explanation = %q, want it verbatim", got)
+	}
+}
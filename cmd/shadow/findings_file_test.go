@@ -0,0 +1,51 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestImportFindingsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "findings.json")
+	data := `[
+		{"title": "Open TCP port 22", "severity": "info", "metadata": {"host": "b.example.com"}},
+		{"title": "Open TCP port 80", "severity": "low", "location": "a.example.com:80"},
+		{"title": "Open TCP port 443", "severity": "low", "metadata": {"host": "b.example.com"}}
+	]`
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := importFindingsFile(path, "")
+	if err != nil {
+		t.Fatalf("importFindingsFile: %v", err)
+	}
+	if result.Target != "b.example.com" || result.Metadata.Profile != "imported" || result.Status != "completed" {
+		t.Errorf("result = %+v, want the most common host as target and the imported profile", result)
+	}
+	for _, finding := range result.Findings {
+		if finding.ID == "" {
+			t.Errorf("%s has no ID", finding.Title)
+		}
+	}
+
+	if result, err := importFindingsFile(path, "override.example.com"); err != nil || result.Target != "override.example.com" {
+		t.Errorf("explicit target: %v, %v", result, err)
+	}
+}
+
+func TestImportFindingsFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := importFindingsFile(filepath.Join(dir, "missing.json"), ""); err == nil {
+		t.Error("missing file imported")
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(bad, []byte(`[{"title": "x"}]`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := importFindingsFile(bad, ""); err == nil {
+		t.Error("invalid finding imported")
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kumaraguru1735/shadow/internal/ai"
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+func TestResolveOutputDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "out")
+
+	got, err := resolveOutputDir(dir, false)
+	if err != nil || got != dir {
+		t.Fatalf("new directory: got %q, %v; want %q", got, err, dir)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "result.json"), []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err = resolveOutputDir(dir, false)
+	if err != nil || filepath.Dir(got) != dir {
+		t.Errorf("existing result: got %q, %v; want a timestamped subdirectory of %q", got, err, dir)
+	}
+	if got, err := resolveOutputDir(dir, true); err != nil || got != dir {
+		t.Errorf("existing result with force: got %q, %v; want %q", got, err, dir)
+	}
+}
+
+func TestWriteOutputDir(t *testing.T) {
+	dir := t.TempDir()
+	result := &models.ScanResult{
+		ID:       "scan-1",
+		Target:   "example.com",
+		Findings: []models.Finding{{Type: "open-port", Title: "Open TCP port 443", Severity: "info"}},
+	}
+	writeOutputDir(dir, "markdown", result, ai.UsageSummary{TotalOperations: 2, TotalCost: 0.5}, false)
+
+	var saved models.ScanResult
+	readJSON(t, filepath.Join(dir, "result.json"), &saved)
+	if saved.ID != "scan-1" || len(saved.Findings) != 1 {
+		t.Errorf("result.json = %+v", saved)
+	}
+
+	var usage map[string]interface{}
+	readJSON(t, filepath.Join(dir, "usage.json"), &usage)
+	if usage["total_operations"] != 2.0 || usage["total_cost"] != 0.5 {
+		t.Errorf("usage.json = %v", usage)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "report.md")); err != nil {
+		t.Errorf("report missing: %v", err)
+	}
+}
+
+func TestWriteOutputDirExcludeInfo(t *testing.T) {
+	dir := t.TempDir()
+	result := &models.ScanResult{
+		ID:     "scan-1",
+		Target: "example.com",
+		Findings: []models.Finding{
+			{Type: "reachability", Title: "Target Reachable", Severity: "info"},
+			{Type: "tls", Title: "Expired certificate", Severity: "high"},
+		},
+	}
+	writeOutputDir(dir, "markdown", result, ai.UsageSummary{}, true)
+
+	var saved models.ScanResult
+	readJSON(t, filepath.Join(dir, "result.json"), &saved)
+	if len(saved.Findings) != 2 {
+		t.Errorf("result.json has %d findings, want the complete result", len(saved.Findings))
+	}
+
+	report, err := os.ReadFile(filepath.Join(dir, "report.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(report), "Target Reachable") || !strings.Contains(string(report), "Expired certificate") {
+		t.Errorf("report.md should drop only the info finding:\n%s", report)
+	}
+}
+
+func readJSON(t *testing.T, path string, v any) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+}
+
+func TestFinishInterruptedAnalysis(t *testing.T) {
+	dir := t.TempDir()
+	result := &models.ScanResult{ID: "scan-1", Target: "example.com"}
+	captureOutput(t, func() {
+		finishInterruptedAnalysis(ai.UsageSummary{TotalOperations: 1, TotalCost: 0.25}, dir, "markdown", result, false)
+	})
+
+	var usage map[string]interface{}
+	readJSON(t, filepath.Join(dir, "usage.json"), &usage)
+	if usage["total_operations"] != 1.0 {
+		t.Errorf("usage.json = %v, want the usage so far", usage)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "result.json")); err != nil {
+		t.Errorf("result.json missing: %v", err)
+	}
+}
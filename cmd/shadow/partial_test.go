@@ -0,0 +1,50 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kumaraguru1735/shadow/internal/store"
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+func TestSavePartialResultAppliesIgnoreRules(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	withPlainOutput(t)
+	accepted := models.Finding{Type: "header", Severity: "medium", Title: "Missing HSTS", Location: "https://example.com"}
+	ignoreFile := filepath.Join(t.TempDir(), ".shadowignore.yaml")
+	rules := "rules:\n  - fingerprint: " + accepted.ComputeFingerprint() + "\n    action: suppress\n"
+	if err := os.WriteFile(ignoreFile, []byte(rules), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result := &models.ScanResult{ID: "partial-scan", Target: "example.com", Status: "interrupted", Findings: []models.Finding{
+		accepted,
+		{Type: "open-port", Severity: "high", Title: "Telnet open", Location: "example.com:23"},
+		{Type: "open-port", Severity: "info", Title: "Open TCP port 443", Location: "example.com:443"},
+	}}
+	cmd := &cobra.Command{}
+	cmd.Flags().String("ignore-file", ignoreFile, "")
+	cmd.Flags().Bool("exclude-info", true, "")
+
+	stdout, _ := captureOutput(t, func() { savePartialResult(cmd, result) })
+	if strings.Contains(stdout, "Missing HSTS") || strings.Contains(stdout, "Open TCP port 443") || !strings.Contains(stdout, "Telnet open") {
+		t.Errorf("summary ignores the ignore file or --exclude-info:\n%s", stdout)
+	}
+
+	st, err := store.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved, err := st.Load("partial-scan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !saved.Findings[0].IsSuppressed() {
+		t.Errorf("saved finding not suppressed: %v", saved.Findings[0].Metadata)
+	}
+}
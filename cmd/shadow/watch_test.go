@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kumaraguru1735/shadow/internal/notify"
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+func TestReportWatchDiffPostsNewFindings(t *testing.T) {
+	events := make(chan notify.Event, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event notify.Event
+		json.NewDecoder(r.Body).Decode(&event)
+		events <- event
+	}))
+	defer server.Close()
+
+	kept := models.Finding{Type: "open-port", Title: "Open TCP port 443", Location: "example.com:443"}
+	fixed := models.Finding{Type: "open-port", Title: "Open TCP port 21", Location: "example.com:21"}
+	added := models.Finding{Type: "open-port", Title: "Open TCP port 8080", Location: "example.com:8080"}
+	suppressed := models.Finding{Type: "open-port", Title: "Open TCP port 22", Location: "example.com:22",
+		Metadata: map[string]string{models.MetaSuppressed: "true"}}
+
+	previous := &models.ScanResult{ID: "scan-1", Target: "example.com", Findings: []models.Finding{kept, fixed}}
+	current := &models.ScanResult{ID: "scan-2", Target: "example.com", Findings: []models.Finding{kept, added, suppressed}}
+	reportWatchDiff(context.Background(), previous, current, server.URL)
+
+	event := <-events
+	if event.ScanID != "scan-2" || event.BaselineID != "scan-1" || event.FixedCount != 1 {
+		t.Errorf("event = %+v", event)
+	}
+	if len(event.NewFindings) != 1 || event.NewFindings[0].Title != added.Title {
+		t.Errorf("new findings = %+v, want only the new port (suppressed ones don't count)", event.NewFindings)
+	}
+}
+
+func TestReportWatchDiffSkipsWebhookWithoutNewFindings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("webhook called with nothing new")
+	}))
+	defer server.Close()
+
+	same := []models.Finding{{Type: "open-port", Title: "Open TCP port 443"}}
+	reportWatchDiff(context.Background(), &models.ScanResult{ID: "a", Findings: same}, &models.ScanResult{ID: "b", Findings: same}, server.URL)
+}
+
+func TestWatchLoopRunsOnEveryTick(t *testing.T) {
+	ticks := make(chan time.Time)
+	stopped := false
+	original := newWatchTicker
+	newWatchTicker = func(interval time.Duration) (<-chan time.Time, func()) {
+		if interval != time.Hour {
+			t.Errorf("ticker interval %s, want 1h", interval)
+		}
+		return ticks, func() { stopped = true }
+	}
+	defer func() { newWatchTicker = original }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runs := make(chan int)
+	done := make(chan struct{})
+	go func() {
+		watchLoop(ctx, time.Hour, func(scanCtx context.Context, run int) {
+			if scanCtx != ctx {
+				t.Error("scan did not get the watch context")
+			}
+			runs <- run
+		})
+		close(done)
+	}()
+
+	// The first run starts at once, the next ones on each tick
+	for want := 1; want <= 3; want++ {
+		if run := <-runs; run != want {
+			t.Fatalf("run %d, want %d", run, want)
+		}
+		if want < 3 {
+			ticks <- time.Now()
+		}
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("watch loop did not stop on cancel")
+	}
+	if !stopped {
+		t.Error("ticker was not stopped")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	pi "github.com/joshp123/pi-golang"
+	"github.com/kumaraguru1735/shadow/internal/output"
 	"github.com/kumaraguru1735/shadow/pkg/models"
 )
 
@@ -15,21 +16,51 @@ const (
 	// Retry configuration (from openclaw)
 	maxRetryAttempts = 3
 	baseRetryDelay   = 15 * time.Second
+	maxRetryDelay    = 2 * time.Minute
 
 	// Timeout configuration (increased from 2min to handle long scans)
 	defaultAnalysisTimeout = 10 * time.Minute
 	defaultQueryTimeout    = 5 * time.Minute
+
+	// Warn in progress updates once a call has used this much of its timeout
+	timeoutWarnPercent = 80
 )
 
+// analysisTimeout and queryTimeout bound a single AI call, see
+// SetAnalysisTimeout and SetQueryTimeout
 var (
-	errEmptyResponse      = errors.New("empty AI response")
-	errRateLimitExceeded  = errors.New("rate limit exceeded")
+	analysisTimeout = defaultAnalysisTimeout
+	queryTimeout    = defaultQueryTimeout
 )
 
+// SetAnalysisTimeout sets how long one analysis call may run before it is
+// cancelled. Values of 0 or less restore the default.
+func SetAnalysisTimeout(d time.Duration) {
+	if d <= 0 {
+		d = defaultAnalysisTimeout
+	}
+	analysisTimeout = d
+}
+
+// SetQueryTimeout sets the total time budget of QueryWithRetry, retries
+// included. Values of 0 or less restore the default.
+func SetQueryTimeout(d time.Duration) {
+	if d <= 0 {
+		d = defaultQueryTimeout
+	}
+	queryTimeout = d
+}
+
+var errEmptyResponse = errors.New("empty AI response")
+
 // AdvancedClaudeAnalyzer provides advanced AI analysis with retry logic and better error handling
 type AdvancedClaudeAnalyzer struct {
-	client *pi.OneShotClient
-	model  string
+	client   *pi.OneShotClient
+	opts     pi.OneShotOptions // to restart the client, see restartOnAPIKey
+	onAPIKey bool              // client started on ANTHROPIC_API_KEY
+	model    string
+	retry    *RetryPolicy
+	tracker  *UsageTracker // one record per attempt, retries included
 }
 
 // NewAdvancedClaudeAnalyzer creates an advanced analyzer with openclaw-style features
@@ -46,17 +77,75 @@ func NewAdvancedClaudeAnalyzer() (*AdvancedClaudeAnalyzer, error) {
 	// Set system prompt for security analysis
 	opts.SystemPrompt = buildSystemPrompt()
 
-	client, err := pi.StartOneShot(opts)
+	client, onAPIKey, err := startClient(opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to start pi client: %w", err)
 	}
 
 	return &AdvancedClaudeAnalyzer{
-		client: client,
-		model:  "claude-sonnet-4.5-20250929",
+		client:   client,
+		opts:     opts,
+		onAPIKey: onAPIKey,
+		model:    "claude-sonnet-4.5-20250929",
+		retry:    DefaultRetryPolicy(),
+		tracker:  NewUsageTracker(),
 	}, nil
 }
 
+// restartOnAPIKey restarts the client on ANTHROPIC_API_KEY after OAuth
+// was rejected, reporting whether the call is worth retrying
+func (a *AdvancedClaudeAnalyzer) restartOnAPIKey(err error) bool {
+	if !fallBackToAPIKey(err, a.onAPIKey) {
+		return false
+	}
+	client, onAPIKey, startErr := startClient(a.opts)
+	if startErr != nil {
+		output.Printf("⚠️  Could not restart the AI client on the API key: %v\n", startErr)
+		return false
+	}
+	if a.client != nil {
+		_ = a.client.Close()
+	}
+	a.client = client
+	a.onAPIKey = onAPIKey
+	return true
+}
+
+// GetUsageSummary returns usage statistics for every attempt so far
+func (a *AdvancedClaudeAnalyzer) GetUsageSummary() UsageSummary {
+	return a.tracker.GetSummary()
+}
+
+// runRecorded runs prompt and records the attempt, failed or not, so
+// retries show up in cost and reliability figures. An empty response is
+// recorded as a failure and returned as errEmptyResponse.
+func (a *AdvancedClaudeAnalyzer) runRecorded(ctx context.Context, operation string, prompt string) (pi.RunResult, error) {
+	startTime := time.Now()
+	result, err := runLimited(ctx, a.client, prompt)
+	if err == nil && strings.TrimSpace(result.Text) == "" {
+		err = errEmptyResponse
+	}
+
+	stats := UsageStats{
+		Model:     a.model,
+		Agent:     operation,
+		Duration:  time.Since(startTime),
+		StartTime: startTime,
+		EndTime:   time.Now(),
+		Success:   err == nil,
+	}
+	if err != nil {
+		stats.Error = err.Error()
+	} else {
+		stats.InputTokens = estimateTokens(prompt)
+		stats.OutputTokens = estimateTokens(result.Text)
+	}
+	a.tracker.RecordUsage(stats)
+	appendUsageLog(stats)
+
+	return result, err
+}
+
 // buildSystemPrompt creates a comprehensive system prompt for security analysis
 func buildSystemPrompt() string {
 	return `You are an expert security analyst and penetration tester with deep knowledge of:
@@ -85,12 +174,40 @@ Be direct and technical. Focus on actionable insights.`
 // ProgressCallback is called during analysis to report progress
 type ProgressCallback func(message string)
 
+// timeoutPercent returns how much of the timeout has elapsed, 0-100
+func timeoutPercent(elapsed, timeout time.Duration) int {
+	if timeout <= 0 {
+		return 0
+	}
+	percent := int(elapsed * 100 / timeout)
+	if percent > 100 {
+		percent = 100
+	}
+	return percent
+}
+
+// timeoutWarning is emitted once a call passes timeoutWarnPercent
+func timeoutWarning(name string, percent int) string {
+	return fmt.Sprintf("⚠️  %s has used %d%% of its %v timeout and may be cut off", name, percent, analysisTimeout)
+}
+
 // AnalyzeScanWithRetry performs AI analysis with automatic retry logic (openclaw pattern)
 func (a *AdvancedClaudeAnalyzer) AnalyzeScanWithRetry(ctx context.Context, result *models.ScanResult, progress ProgressCallback) (*models.AIAnalysis, error) {
+	// Suppressed findings never reach the prompt
+	result = result.WithActiveFindings()
+	if len(result.Findings) == 0 {
+		return nil, ErrNoFindings
+	}
+
+	// An oversized prompt fails the same way on every attempt
+	if err := checkPromptSize(a.buildAnalysisPrompt(result)); err != nil {
+		return nil, err
+	}
+
 	// Retry with exponential backoff (timeout is per-attempt, not total)
 	return a.retryWithBackoff(ctx, func(attemptCtx context.Context) (*models.AIAnalysis, error) {
 		// Create fresh timeout context for each attempt
-		timeoutCtx, cancel := context.WithTimeout(attemptCtx, defaultAnalysisTimeout)
+		timeoutCtx, cancel := context.WithTimeout(attemptCtx, analysisTimeout)
 		defer cancel()
 		return a.analyzeScanOnce(timeoutCtx, result, progress)
 	}, progress)
@@ -118,6 +235,7 @@ func (a *AdvancedClaudeAnalyzer) analyzeScanOnce(ctx context.Context, result *mo
 		go func() {
 			ticker := time.NewTicker(15 * time.Second)
 			defer ticker.Stop()
+			warned := false
 
 			for {
 				select {
@@ -125,15 +243,23 @@ func (a *AdvancedClaudeAnalyzer) analyzeScanOnce(ctx context.Context, result *mo
 					return
 				case <-ticker.C:
 					elapsed := time.Since(startTime)
-					progress(fmt.Sprintf("⏱️  Still analyzing... (%.0f seconds elapsed)", elapsed.Seconds()))
+					percent := timeoutPercent(elapsed, analysisTimeout)
+					progress(fmt.Sprintf("⏱️  Still analyzing... (%.0f seconds elapsed, %d%% of timeout)", elapsed.Seconds(), percent))
+					if percent >= timeoutWarnPercent && !warned {
+						progress(timeoutWarning("Analysis", percent))
+						warned = true
+					}
 				}
 			}
 		}()
 	}
 
-	runResult, err := a.client.Run(ctx, prompt)
+	runResult, err := a.runRecorded(ctx, "Advanced Analyzer", prompt)
 	close(done)
 
+	if errors.Is(err, errEmptyResponse) {
+		return nil, err
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to run analysis: %w", err)
 	}
@@ -143,9 +269,6 @@ func (a *AdvancedClaudeAnalyzer) analyzeScanOnce(ctx context.Context, result *mo
 	}
 
 	text := runResult.Text
-	if strings.TrimSpace(text) == "" {
-		return nil, errEmptyResponse
-	}
 
 	if progress != nil {
 		progress("📊 Extracting findings and recommendations...")
@@ -157,6 +280,7 @@ func (a *AdvancedClaudeAnalyzer) analyzeScanOnce(ctx context.Context, result *mo
 		RiskScore:       parseRiskScore(text),
 		CriticalIssues:  parseCriticalIssues(text),
 		Recommendations: parseRecommendations(text),
+		RawText:         text,
 		Timestamp:       time.Now(),
 	}
 
@@ -166,8 +290,9 @@ func (a *AdvancedClaudeAnalyzer) analyzeScanOnce(ctx context.Context, result *mo
 // retryWithBackoff implements openclaw's retry pattern
 func (a *AdvancedClaudeAnalyzer) retryWithBackoff(ctx context.Context, fn func(context.Context) (*models.AIAnalysis, error), progress ProgressCallback) (*models.AIAnalysis, error) {
 	var lastErr error
+	refreshed := false
 
-	for attempt := 0; attempt < maxRetryAttempts; attempt++ {
+	for attempt := 0; attempt < a.retry.MaxAttempts; attempt++ {
 		// Check context before attempting
 		select {
 		case <-ctx.Done():
@@ -176,7 +301,7 @@ func (a *AdvancedClaudeAnalyzer) retryWithBackoff(ctx context.Context, fn func(c
 		}
 
 		if attempt > 0 && progress != nil {
-			progress(fmt.Sprintf("🔄 Attempt %d/%d starting...", attempt+1, maxRetryAttempts))
+			progress(fmt.Sprintf("🔄 Attempt %d/%d starting...", attempt+1, a.retry.MaxAttempts))
 		}
 
 		result, err := fn(ctx)
@@ -184,6 +309,18 @@ func (a *AdvancedClaudeAnalyzer) retryWithBackoff(ctx context.Context, fn func(c
 			return result, nil
 		}
 
+		// An auth rejection of a valid-looking token gets one refresh and retry
+		if !refreshed && refreshAfterAuthFailure(err) {
+			refreshed = true
+			attempt--
+			continue
+		}
+		// Restarts at most once: the new client is on the key
+		if a.restartOnAPIKey(err) {
+			attempt--
+			continue
+		}
+
 		// Check if error is retryable
 		if !isRetryableError(err) {
 			return nil, err
@@ -191,10 +328,10 @@ func (a *AdvancedClaudeAnalyzer) retryWithBackoff(ctx context.Context, fn func(c
 
 		lastErr = err
 
-		// Calculate backoff delay (exponential)
-		if attempt+1 < maxRetryAttempts {
-			delay := baseRetryDelay * time.Duration(attempt+1)
-			fmt.Printf("⚠️  Retry %d/%d after %v (error: %v)\n", attempt+1, maxRetryAttempts, delay, err)
+		// Calculate backoff delay (exponential with jitter)
+		if attempt+1 < a.retry.MaxAttempts {
+			delay := a.retry.Delay(attempt)
+			output.Printf("⚠️  Retry %d/%d after %v (error: %v)\n", attempt+1, a.retry.MaxAttempts, delay.Round(time.Second), err)
 
 			if err := sleepWithContext(ctx, delay); err != nil {
 				return nil, err
@@ -211,33 +348,17 @@ func isRetryableError(err error) bool {
 		return false
 	}
 
-	// Check for specific error types
-	if errors.Is(err, errEmptyResponse) {
-		return true
-	}
-
-	if errors.Is(err, errRateLimitExceeded) {
+	// Categorised errors first; retrying can't fix a bad credential, an
+	// oversized prompt or an empty finding set
+	switch {
+	case errors.Is(err, ErrAuth), errors.Is(err, ErrPromptTooLarge), errors.Is(err, ErrNoFindings):
+		return false
+	case errors.Is(err, ErrRateLimit), errors.Is(err, ErrTimeout), errors.Is(err, errEmptyResponse):
 		return true
 	}
 
-	// Check error message for retryable conditions
-	message := strings.ToLower(err.Error())
-	retryablePatterns := []string{
-		"rate limit",
-		"429",
-		"timeout",
-		"temporary",
-		"connection",
-		"deadline exceeded",
-	}
-
-	for _, pattern := range retryablePatterns {
-		if strings.Contains(message, pattern) {
-			return true
-		}
-	}
-
-	return false
+	// Then the message, for errors no category matched
+	return matchesAny(err, "rate limit", "status 429", " 429 ", "timeout", "temporary", "connection", "deadline exceeded")
 }
 
 // sleepWithContext sleeps with context cancellation support (openclaw pattern)
@@ -256,21 +377,17 @@ func sleepWithContext(ctx context.Context, delay time.Duration) error {
 // QueryWithRetry performs AI queries with retry logic
 func (a *AdvancedClaudeAnalyzer) QueryWithRetry(ctx context.Context, scanID string, question string) (string, error) {
 	// Create timeout context
-	ctx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
 	defer cancel()
 
 	return a.retryStringWithBackoff(ctx, func(ctx context.Context) (string, error) {
 		prompt := fmt.Sprintf("Scan ID: %s\nQuestion: %s", scanID, question)
 
-		runResult, err := a.client.Run(ctx, prompt)
+		runResult, err := a.runRecorded(ctx, "Advanced Query", prompt)
 		if err != nil {
 			return "", err
 		}
 
-		if strings.TrimSpace(runResult.Text) == "" {
-			return "", errEmptyResponse
-		}
-
 		return runResult.Text, nil
 	})
 }
@@ -278,8 +395,9 @@ func (a *AdvancedClaudeAnalyzer) QueryWithRetry(ctx context.Context, scanID stri
 // retryStringWithBackoff implements retry for string-returning functions
 func (a *AdvancedClaudeAnalyzer) retryStringWithBackoff(ctx context.Context, fn func(context.Context) (string, error)) (string, error) {
 	var lastErr error
+	refreshed := false
 
-	for attempt := 0; attempt < maxRetryAttempts; attempt++ {
+	for attempt := 0; attempt < a.retry.MaxAttempts; attempt++ {
 		select {
 		case <-ctx.Done():
 			return "", ctx.Err()
@@ -291,15 +409,26 @@ func (a *AdvancedClaudeAnalyzer) retryStringWithBackoff(ctx context.Context, fn
 			return result, nil
 		}
 
+		if !refreshed && refreshAfterAuthFailure(err) {
+			refreshed = true
+			attempt--
+			continue
+		}
+		// Restarts at most once: the new client is on the key
+		if a.restartOnAPIKey(err) {
+			attempt--
+			continue
+		}
+
 		if !isRetryableError(err) {
 			return "", err
 		}
 
 		lastErr = err
 
-		if attempt+1 < maxRetryAttempts {
-			delay := baseRetryDelay * time.Duration(attempt+1)
-			fmt.Printf("⚠️  Retry %d/%d after %v\n", attempt+1, maxRetryAttempts, delay)
+		if attempt+1 < a.retry.MaxAttempts {
+			delay := a.retry.Delay(attempt)
+			output.Printf("⚠️  Retry %d/%d after %v\n", attempt+1, a.retry.MaxAttempts, delay.Round(time.Second))
 
 			if err := sleepWithContext(ctx, delay); err != nil {
 				return "", err
@@ -357,7 +486,8 @@ Please analyze these security findings and provide:
 		prompt += fmt.Sprintf("- **Type**: %s\n", finding.Type)
 		prompt += fmt.Sprintf("- **Description**: %s\n", finding.Description)
 		if finding.Evidence != "" {
-			prompt += fmt.Sprintf("- **Evidence**: %s\n", finding.Evidence)
+			evidence, _ := models.TruncateEvidence(finding.Evidence, models.DefaultMaxEvidenceLength)
+			prompt += fmt.Sprintf("- **Evidence**: %s\n", evidence)
 		}
 		if finding.Location != "" {
 			prompt += fmt.Sprintf("- **Location**: %s\n", finding.Location)
@@ -0,0 +1,55 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeoutPercent(t *testing.T) {
+	tests := []struct {
+		elapsed time.Duration
+		timeout time.Duration
+		want    int
+	}{
+		{0, 10 * time.Minute, 0},
+		{time.Minute, 10 * time.Minute, 10},
+		{8 * time.Minute, 10 * time.Minute, 80},
+		{12 * time.Minute, 10 * time.Minute, 100}, // capped
+		{time.Minute, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := timeoutPercent(tt.elapsed, tt.timeout); got != tt.want {
+			t.Errorf("timeoutPercent(%v, %v) = %d, want %d", tt.elapsed, tt.timeout, got, tt.want)
+		}
+	}
+}
+
+func TestTimeoutWarning(t *testing.T) {
+	warning := timeoutWarning("Vulnerability Researcher", 85)
+	if !strings.Contains(warning, "Vulnerability Researcher has used 85% of its 10m0s timeout") {
+		t.Errorf("warning = %q", warning)
+	}
+}
+
+func TestSetAITimeouts(t *testing.T) {
+	t.Cleanup(func() {
+		SetAnalysisTimeout(0)
+		SetQueryTimeout(0)
+	})
+
+	SetAnalysisTimeout(25 * time.Minute)
+	SetQueryTimeout(90 * time.Second)
+	if analysisTimeout != 25*time.Minute || queryTimeout != 90*time.Second {
+		t.Errorf("timeouts = %s, %s; want the configured values", analysisTimeout, queryTimeout)
+	}
+	if warning := timeoutWarning("Analysis", 90); !strings.Contains(warning, "of its 25m0s timeout") {
+		t.Errorf("warning = %q, want the configured timeout", warning)
+	}
+
+	SetAnalysisTimeout(0)
+	SetQueryTimeout(-time.Second)
+	if analysisTimeout != defaultAnalysisTimeout || queryTimeout != defaultQueryTimeout {
+		t.Errorf("timeouts = %s, %s; want the defaults restored", analysisTimeout, queryTimeout)
+	}
+}
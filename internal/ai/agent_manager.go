@@ -2,8 +2,10 @@ package ai
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	pi "github.com/joshp123/pi-golang"
@@ -12,34 +14,285 @@ import (
 
 // AgentManager orchestrates multiple specialized AI agents
 type AgentManager struct {
-	agents  map[models.AgentType]*Agent
-	tracker *UsageTracker
+	mu            sync.Mutex
+	configs       map[models.AgentType]*models.AgentConfig
+	agents        map[models.AgentType]*Agent
+	starts        map[models.AgentType]*agentStart
+	start         func(opts pi.OneShotOptions) (agentClient, bool, error)
+	tracker       *UsageTracker
+	retry         *RetryPolicy
+	debug         *debugLogger
+	substitutions []ModelSubstitution
+	status        StatusReporter
+	polish        bool
+	structured    bool
+	language      string
+	compact       bool
+	minSeverity   string
+	includeInfo   bool
+	filter        *models.FindingFilter
+	forceEmpty    bool
+	scanID        string
+
+	// parallelAgents is how many independent deep analysis stages may run
+	// at once; their calls still share the global request limiter
+	parallelAgents int
+}
+
+// StatusReporter shows one live status line per running agent
+// (see output.Printer)
+type StatusReporter interface {
+	SetStatus(key string, line string)
+	ClearStatus(key string)
+}
+
+// ModelSubstitution records an agent that runs on a fallback model, or that
+// couldn't be started at all (To is empty)
+type ModelSubstitution struct {
+	Agent  string
+	From   string
+	To     string
+	Reason string
 }
 
 // Agent represents a specialized AI agent
 type Agent struct {
-	config *models.AgentConfig
-	client *pi.OneShotClient
+	config   *models.AgentConfig
+	client   agentClient
+	onAPIKey bool // started on ANTHROPIC_API_KEY, see fallBackToAPIKey
+}
+
+// agentClient is the part of the pi client agents use
+type agentClient interface {
+	promptRunner
+	Close() error
+}
+
+// agentStart starts one agent type once, however many analyses ask for it
+type agentStart struct {
+	once sync.Once
+	err  error
 }
 
-// NewAgentManager creates a new multi-agent manager
+// resolvePiCommand finds the pi CLI; replaced in tests
+var resolvePiCommand = pi.ResolveCommand
+
+// NewAgentManager creates a new multi-agent manager. Agents start on first
+// use, so it checks up front for what would stop every agent from
+// starting: a missing pi CLI or an OAuth token without the needed scopes.
 func NewAgentManager() (*AgentManager, error) {
+	if _, err := resolvePiCommand(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAIUnavailable, err)
+	}
+	// Catch unusable OAuth tokens here rather than deep inside client.Run
+	if auth, err := NewAuthManager(); err == nil {
+		if err := auth.CheckOAuthScopes(); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrAIUnavailable, err)
+		}
+	}
+	return newAgentManager(), nil
+}
+
+// newAgentManager creates a manager whose agents start on first use, so a
+// quick analysis only pays for the one agent it needs
+func newAgentManager() *AgentManager {
 	manager := &AgentManager{
+		configs: make(map[models.AgentType]*models.AgentConfig),
 		agents:  make(map[models.AgentType]*Agent),
+		starts:  make(map[models.AgentType]*agentStart),
 		tracker: NewUsageTracker(),
+		retry:   DefaultRetryPolicy(),
+		start: func(opts pi.OneShotOptions) (agentClient, bool, error) {
+			client, onAPIKey, err := startClient(opts)
+			if err != nil {
+				return nil, onAPIKey, err
+			}
+			return client, onAPIKey, nil
+		},
+
+		parallelAgents: 1,
 	}
 
-	// Initialize all default agents
 	configs := models.GetDefaultAgents()
 	for i := range configs {
-		agent, err := manager.createAgent(&configs[i])
-		if err != nil {
-			return nil, fmt.Errorf("failed to create agent %s: %w", configs[i].Name, err)
+		manager.configs[configs[i].Type] = &configs[i]
+	}
+	return manager
+}
+
+// agent returns the started agent for agentType, starting it on first use.
+// An agent whose model is unavailable falls back to a substitute, and one
+// that can't start at all is reported; both are reported through progress
+// as they happen. A failed start is remembered. Clients are started without
+// holding m.mu, so other agents aren't held up meanwhile.
+func (m *AgentManager) agent(agentType models.AgentType, progress ProgressCallback) (*Agent, error) {
+	m.mu.Lock()
+	config, ok := m.configs[agentType]
+	start := m.starts[agentType]
+	if ok && start == nil {
+		start = &agentStart{}
+		m.starts[agentType] = start
+	}
+	m.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("agent type %s not found", agentType)
+	}
+
+	start.once.Do(func() {
+		agent, sub, err := m.createAgentWithFallback(config)
+		if sub != nil && progress != nil {
+			if sub.To == "" {
+				progress(fmt.Sprintf("⚠️  %s unavailable (%s): %s", sub.Agent, sub.From, sub.Reason))
+			} else {
+				progress(fmt.Sprintf("⚠️  %s: %s unavailable, using %s", sub.Agent, sub.From, sub.To))
+			}
 		}
-		manager.agents[configs[i].Type] = agent
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if sub != nil {
+			m.substitutions = append(m.substitutions, *sub)
+		}
+		if err == nil {
+			m.agents[agentType] = agent
+		}
+		start.err = err
+	})
+	if start.err != nil {
+		return nil, start.err
 	}
 
-	return manager, nil
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.agents[agentType], nil
+}
+
+// SetStatusReporter shows a live status line for each agent while it runs
+func (m *AgentManager) SetStatusReporter(status StatusReporter) {
+	m.status = status
+}
+
+// SetScanID tags recorded usage with the scan being analyzed, so costs
+// can be compared per scan (see CheckCostAnomaly)
+func (m *AgentManager) SetScanID(scanID string) {
+	m.scanID = scanID
+}
+
+// SetCompactFindings sends only severity, title, location and a short
+// evidence snippet per finding to standard and deep analyses. Quick
+// analyses and triage always use the compact form.
+func (m *AgentManager) SetCompactFindings(enabled bool) {
+	m.compact = enabled
+}
+
+// SetMinSeverity leaves findings below severity out of scan analyses, to
+// keep large finding sets under the prompt ceiling. Empty sends them all.
+func (m *AgentManager) SetMinSeverity(severity string) {
+	m.minSeverity = severity
+}
+
+// SetFindingFilter leaves findings the filter doesn't match out of scan
+// analyses. Nil sends them all.
+func (m *AgentManager) SetFindingFilter(filter *models.FindingFilter) {
+	m.filter = filter
+}
+
+// SetIncludeInfo sends info-level findings to scan analyses. They are left
+// out by default: they rarely change the analysis but cost tokens.
+func (m *AgentManager) SetIncludeInfo(enabled bool) {
+	m.includeInfo = enabled
+}
+
+// SetForceAnalysis calls the AI even when no findings are left to send.
+// By default such analyses fail fast with ErrNoFindings, see EmptyAnalysis.
+func (m *AgentManager) SetForceAnalysis(enabled bool) {
+	m.forceEmpty = enabled
+}
+
+// SetParallelAgents lets up to n independent deep analysis stages run at
+// once. With n > 1 the vulnerability stage starts alongside reconnaissance
+// instead of building on its output, trading that context for time.
+func (m *AgentManager) SetParallelAgents(n int) {
+	if n < 1 {
+		n = 1
+	}
+	m.parallelAgents = n
+}
+
+// runStages runs independent stages, at most m.parallelAgents at a time,
+// and waits for all of them. With one slot they run in order.
+func (m *AgentManager) runStages(stages ...func()) {
+	slots := make(chan struct{}, max(m.parallelAgents, 1))
+	var wg sync.WaitGroup
+	for _, stage := range stages {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(stage func()) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			stage()
+		}(stage)
+	}
+	wg.Wait()
+}
+
+// promptFindings narrows findings to what analysis prompts should see
+func (m *AgentManager) promptFindings(findings []models.Finding) []models.Finding {
+	findings = models.AtLeastSeverity(findings, m.minSeverity)
+	findings = m.filter.Filter(findings)
+	if !m.includeInfo {
+		findings = models.WithoutInfo(findings)
+	}
+	return findings
+}
+
+// Substitutions lists agents started so far that run on a fallback model
+// or couldn't be started
+func (m *AgentManager) Substitutions() []ModelSubstitution {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]ModelSubstitution(nil), m.substitutions...)
+}
+
+// createAgentWithFallback tries the configured model, then each documented
+// substitute in turn. It returns the substitution made, if any, for the
+// caller to record.
+func (m *AgentManager) createAgentWithFallback(config *models.AgentConfig) (*Agent, *ModelSubstitution, error) {
+	requested := config.Model
+	var firstErr error
+
+	for model := requested; model != ""; model = models.ModelFallbacks[model] {
+		config.Model = model
+		agent, err := m.createAgent(config)
+		if err == nil {
+			if model == requested {
+				return agent, nil, nil
+			}
+			return agent, &ModelSubstitution{
+				Agent:  config.Name,
+				From:   requested,
+				To:     model,
+				Reason: firstErr.Error(),
+			}, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	config.Model = requested
+	sub := &ModelSubstitution{
+		Agent:  config.Name,
+		From:   requested,
+		Reason: firstErr.Error(),
+	}
+	return nil, sub, fmt.Errorf("failed to create agent %s: %w", config.Name, firstErr)
+}
+
+// EnableDebugLog writes every agent prompt and raw response (redacted) to
+// <dir>/<agent-type>.md. Off by default.
+func (m *AgentManager) EnableDebugLog(dir string) {
+	m.debug = &debugLogger{dir: dir}
 }
 
 // createAgent creates a new agent with the given configuration
@@ -56,17 +309,92 @@ func (m *AgentManager) createAgent(config *models.AgentConfig) (*Agent, error) {
 	// Set agent-specific system prompt
 	opts.SystemPrompt = m.buildSystemPrompt(config)
 
-	client, err := pi.StartOneShot(opts)
+	client, onAPIKey, err := m.start(opts)
 	if err != nil {
 		return nil, err
 	}
 
 	return &Agent{
-		config: config,
-		client: client,
+		config:   config,
+		client:   client,
+		onAPIKey: onAPIKey,
 	}, nil
 }
 
+// restartAgent replaces an agent whose client was rejected with a newly
+// started one running config, e.g. on the API key after OAuth failed or on
+// a substitute model. If another analysis replaced it first, the new client
+// is dropped in favor of that one.
+func (m *AgentManager) restartAgent(old *Agent, config *models.AgentConfig) (*Agent, error) {
+	m.mu.Lock()
+	current := m.agents[old.config.Type]
+	m.mu.Unlock()
+	if current != nil && current != old {
+		return current, nil
+	}
+
+	agent, err := m.createAgent(config)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if current := m.agents[old.config.Type]; current != nil && current != old {
+		_ = agent.client.Close()
+		return current, nil
+	}
+	if old.client != nil {
+		_ = old.client.Close()
+	}
+	m.agents[config.Type] = agent
+	m.configs[config.Type] = config
+	return agent, nil
+}
+
+// isModelUnavailable reports whether the provider rejected a run because
+// the agent's model doesn't exist or isn't available to the account. pi
+// only checks the model when the first prompt is sent.
+func isModelUnavailable(err error) bool {
+	return matchesAny(err, "model not found", "model_not_found", "not_found_error", "unknown model",
+		"invalid model", "does not have access to model", "model is not available", "model is deprecated")
+}
+
+// substituteModel restarts an agent whose model was rejected on the
+// documented substitute, recording and reporting the substitution as
+// createAgentWithFallback does when a start fails
+func (m *AgentManager) substituteModel(old *Agent, cause error, progress ProgressCallback) (*Agent, error) {
+	next := models.ModelFallbacks[old.config.Model]
+	if next == "" {
+		return nil, cause
+	}
+	config := *old.config
+	config.Model = next
+
+	agent, err := m.restartAgent(old, &config)
+	if err != nil {
+		return nil, err
+	}
+	if agent.config != &config {
+		// Another analysis already replaced the agent
+		return agent, nil
+	}
+
+	sub := ModelSubstitution{
+		Agent:  config.Name,
+		From:   old.config.Model,
+		To:     next,
+		Reason: cause.Error(),
+	}
+	m.mu.Lock()
+	m.substitutions = append(m.substitutions, sub)
+	m.mu.Unlock()
+	if progress != nil {
+		progress(fmt.Sprintf("⚠️  %s: %s unavailable, using %s", sub.Agent, sub.From, sub.To))
+	}
+	return agent, nil
+}
+
 // buildSystemPrompt creates a system prompt for the agent
 func (m *AgentManager) buildSystemPrompt(config *models.AgentConfig) string {
 	basePrompt := `You are an expert security analyst and penetration tester.`
@@ -128,9 +456,15 @@ func (m *AgentManager) AnalyzeWithAgent(
 	prompt string,
 	progress ProgressCallback,
 ) (string, error) {
-	agent, ok := m.agents[agentType]
-	if !ok {
-		return "", fmt.Errorf("agent type %s not found", agentType)
+	// The size check covers everything sent, language suffix included
+	prompt += languageInstruction(m.language)
+	if err := checkPromptSize(prompt); err != nil {
+		return "", err
+	}
+
+	agent, err := m.agent(agentType, progress)
+	if err != nil {
+		return "", err
 	}
 
 	if progress != nil {
@@ -141,11 +475,16 @@ func (m *AgentManager) AnalyzeWithAgent(
 	}
 
 	// Create timeout context
-	timeoutCtx, cancel := context.WithTimeout(ctx, defaultAnalysisTimeout)
+	timeoutCtx, cancel := context.WithTimeout(ctx, analysisTimeout)
 	defer cancel()
 
 	startTime := time.Now()
 
+	if m.status != nil {
+		m.status.SetStatus(agent.config.Name, fmt.Sprintf("   ⏳ %s: running", agent.config.Name))
+		defer m.status.ClearStatus(agent.config.Name)
+	}
+
 	// Show detailed progress updates
 	done := make(chan bool)
 	if progress != nil {
@@ -162,6 +501,7 @@ func (m *AgentManager) AnalyzeWithAgent(
 				"✅ Finalizing analysis",
 			}
 			stageIdx := 0
+			warned := false
 
 			for {
 				select {
@@ -169,12 +509,21 @@ func (m *AgentManager) AnalyzeWithAgent(
 					return
 				case <-ticker.C:
 					elapsed := time.Since(startTime)
+					percent := timeoutPercent(elapsed, analysisTimeout)
+					if m.status != nil {
+						m.status.SetStatus(agent.config.Name, fmt.Sprintf("   ⏳ %s: %.0fs (%d%% of timeout)",
+							agent.config.Name, elapsed.Seconds(), percent))
+					}
 					if stageIdx < len(stages) {
-						progress(fmt.Sprintf("   %s (%.0fs)", stages[stageIdx], elapsed.Seconds()))
+						progress(fmt.Sprintf("   %s (%.0fs, %d%% of timeout)", stages[stageIdx], elapsed.Seconds(), percent))
 						stageIdx++
 					} else {
-						progress(fmt.Sprintf("   ⏱️  %s completing analysis... (%.0fs elapsed)",
-							agent.config.Name, elapsed.Seconds()))
+						progress(fmt.Sprintf("   ⏱️  %s completing analysis... (%.0fs elapsed, %d%% of timeout)",
+							agent.config.Name, elapsed.Seconds(), percent))
+					}
+					if percent >= timeoutWarnPercent && !warned {
+						progress(timeoutWarning(agent.config.Name, percent))
+						warned = true
 					}
 				}
 			}
@@ -182,36 +531,93 @@ func (m *AgentManager) AnalyzeWithAgent(
 	}
 
 	// Run analysis
-	result, err := agent.client.Run(timeoutCtx, prompt)
+	result, agent, err := m.runAgent(timeoutCtx, agent, prompt, progress)
 	close(done)
 
-	duration := time.Since(startTime)
+	if m.debug != nil {
+		m.debug.record(string(agent.config.Type), prompt, result.Text, err)
+	}
 
-	// Record usage stats (note: pi-golang doesn't expose token counts, so we estimate)
+	if err != nil {
+		return "", fmt.Errorf("analysis failed: %w", err)
+	}
+
+	return result.Text, nil
+}
+
+// runAgent runs prompt on agent. Rate limits and transient failures are
+// retried with jittered exponential backoff (see RetryPolicy), so agents
+// that hit the same limit don't all retry at once. An auth rejection gets
+// one token refresh and, failing that, a restart on the API key, and a
+// rejected model falls back to its substitute; the agent that finally ran
+// the prompt is returned. Every attempt is recorded, see
+// recordAttempt.
+func (m *AgentManager) runAgent(ctx context.Context, agent *Agent, prompt string, progress ProgressCallback) (pi.RunResult, *Agent, error) {
+	refreshed := false
+	for attempt := 0; ; attempt++ {
+		startTime := time.Now()
+		result, err := runLimited(ctx, agent.client, prompt)
+		m.recordAttempt(agent, prompt, result, err, startTime)
+		if err == nil {
+			return result, agent, nil
+		}
+
+		if !refreshed && refreshAfterAuthFailure(err) {
+			refreshed = true
+			attempt--
+			continue
+		}
+		if fallBackToAPIKey(err, agent.onAPIKey) {
+			if restarted, rErr := m.restartAgent(agent, agent.config); rErr == nil {
+				agent = restarted
+				attempt--
+				continue
+			}
+		}
+		if isModelUnavailable(err) {
+			if substitute, sErr := m.substituteModel(agent, err, progress); sErr == nil {
+				agent = substitute
+				attempt--
+				continue
+			}
+		}
+
+		if !isRetryableError(err) || attempt+1 >= m.retry.MaxAttempts {
+			return result, agent, err
+		}
+		delay := m.retry.Delay(attempt)
+		if progress != nil {
+			progress(fmt.Sprintf("⚠️  %s: retry %d/%d in %v (%v)",
+				agent.config.Name, attempt+1, m.retry.MaxAttempts-1, delay.Round(time.Second), err))
+		}
+		if sleepErr := sleepWithContext(ctx, delay); sleepErr != nil {
+			return result, agent, err
+		}
+	}
+}
+
+// recordAttempt records the usage of one attempt, failed or not, against
+// the scan being analyzed, so retries show up in cost and reliability
+// figures. pi-golang doesn't expose token counts, so they are estimated.
+func (m *AgentManager) recordAttempt(agent *Agent, prompt string, result pi.RunResult, err error, startTime time.Time) {
 	stats := UsageStats{
+		ScanID:    m.scanID,
 		Model:     agent.config.Model,
 		Agent:     agent.config.Name,
-		Duration:  duration,
+		Duration:  time.Since(startTime),
 		StartTime: startTime,
 		EndTime:   time.Now(),
 		Success:   err == nil,
 	}
-
 	if err != nil {
 		stats.Error = err.Error()
 	} else {
-		// Estimate tokens (rough approximation: 1 token ≈ 4 characters)
-		stats.InputTokens = int64(len(prompt) / 4)
-		stats.OutputTokens = int64(len(result.Text) / 4)
+		stats.InputTokens = estimateTokens(prompt)
+		stats.OutputTokens = estimateTokens(result.Text)
 	}
 
 	m.tracker.RecordUsage(stats)
-
-	if err != nil {
-		return "", fmt.Errorf("analysis failed: %w", err)
-	}
-
-	return result.Text, nil
+	appendUsageLog(stats)
 }
 
 // AnalyzeScanWithAgents performs multi-agent analysis of scan results
@@ -225,6 +631,15 @@ func (m *AgentManager) AnalyzeScanWithAgents(
 		progress("🚀 Starting multi-agent analysis...")
 	}
 
+	// Suppressed and filtered findings never reach the prompts
+	result = result.WithActiveFindings()
+	scanFindings := len(result.Findings)
+	result.Findings = m.promptFindings(result.Findings)
+	if len(result.Findings) == 0 && !m.forceEmpty {
+		return nil, ErrNoFindings
+	}
+	m.tracker.RecordScan(result.ID, scanFindings)
+
 	var analysis *models.AIAnalysis
 	var err error
 
@@ -237,8 +652,9 @@ func (m *AgentManager) AnalyzeScanWithAgents(
 		// Standard: Use Sonnet for balanced analysis
 		analysis, err = m.runStandardAnalysis(ctx, result, progress)
 
-	case "deep":
-		// Deep: Use multiple agents (Sonnet + Opus)
+	case "deep", "research":
+		// Deep: Use multiple agents (Sonnet + Opus). The research profile
+		// follows this with autonomous research, driven by the caller.
 		analysis, err = m.runDeepAnalysis(ctx, result, progress)
 
 	default:
@@ -254,14 +670,15 @@ func (m *AgentManager) runQuickAnalysis(
 	result *models.ScanResult,
 	progress ProgressCallback,
 ) (*models.AIAnalysis, error) {
-	prompt := buildAnalysisPrompt(result)
+	// Quick analyses always send compact findings to keep Haiku calls cheap
+	prompt := m.withStructuredInstructions(buildAnalysisPrompt(result, true))
 
 	text, err := m.AnalyzeWithAgent(ctx, models.AgentTypeQuickScan, prompt, progress)
 	if err != nil {
 		return nil, err
 	}
 
-	return parseAnalysisResponse(text, result.ID), nil
+	return m.finishAnalysis(ctx, text, result.ID, progress), nil
 }
 
 // runStandardAnalysis uses Sonnet for balanced analysis
@@ -289,14 +706,14 @@ func (m *AgentManager) runStandardAnalysis(
 	}
 
 	// Use vulnerability agent for standard analysis
-	prompt := buildAnalysisPrompt(result)
+	prompt := m.withStructuredInstructions(buildAnalysisPrompt(result, m.compact))
 
 	text, err := m.AnalyzeWithAgent(ctx, models.AgentTypeVulnerability, prompt, progress)
 	if err != nil {
 		return nil, err
 	}
 
-	return parseAnalysisResponse(text, result.ID), nil
+	return m.finishAnalysis(ctx, text, result.ID, progress), nil
 }
 
 // runDeepAnalysis uses multiple agents for comprehensive analysis
@@ -309,41 +726,85 @@ func (m *AgentManager) runDeepAnalysis(
 		progress("🔬 Deep analysis mode: Using multiple specialized agents")
 	}
 
-	// Stage 1: Reconnaissance
-	if progress != nil {
-		progress("\n📍 Stage 1/3: Reconnaissance Analysis")
+	// When ctx carries an analysis deadline, stages it cuts off are skipped
+	// and the completed ones are still returned
+	skipped := make([]string, 0)
+	pastDeadline := func() bool {
+		return errors.Is(ctx.Err(), context.DeadlineExceeded)
+	}
+
+	// Stage 1: Reconnaissance, and with parallel agents Stage 2 alongside it
+	reconPrompt := buildReconPrompt(result, m.compact)
+	var reconResult string
+	var err error
+	vulnResult := deadlineSkipped
+	var vulnErr error
+	runVuln := func(reconData string) {
+		vulnPrompt := buildVulnPrompt(result, reconData, m.compact)
+		vulnResult, vulnErr = m.AnalyzeWithAgent(ctx, models.AgentTypeVulnerability, vulnPrompt, progress)
 	}
 
-	reconPrompt := buildReconPrompt(result)
-	reconResult, err := m.AnalyzeWithAgent(ctx, models.AgentTypeRecon, reconPrompt, progress)
+	parallel := m.parallelAgents > 1
+	if parallel {
+		if progress != nil {
+			progress("\n📍 Stages 1-2/3: Reconnaissance and Vulnerability Analysis, in parallel")
+		}
+		m.runStages(
+			func() { reconResult, err = m.AnalyzeWithAgent(ctx, models.AgentTypeRecon, reconPrompt, progress) },
+			func() { runVuln(parallelReconData) },
+		)
+	} else {
+		if progress != nil {
+			progress("\n📍 Stage 1/3: Reconnaissance Analysis")
+		}
+		reconResult, err = m.AnalyzeWithAgent(ctx, models.AgentTypeRecon, reconPrompt, progress)
+	}
 	if err != nil {
+		if pastDeadline() {
+			return nil, fmt.Errorf("analysis deadline reached before any stage completed: %w", err)
+		}
 		return nil, fmt.Errorf("recon stage failed: %w", err)
 	}
 
 	// Stage 2: Vulnerability Analysis
-	if progress != nil {
-		progress("\n🔍 Stage 2/3: Vulnerability Analysis")
+	if !parallel {
+		if pastDeadline() {
+			skipped = append(skipped, "vulnerability")
+		} else {
+			if progress != nil {
+				progress("\n🔍 Stage 2/3: Vulnerability Analysis")
+			}
+			runVuln(reconResult)
+		}
 	}
-
-	vulnPrompt := buildVulnPrompt(result, reconResult)
-	vulnResult, err := m.AnalyzeWithAgent(ctx, models.AgentTypeVulnerability, vulnPrompt, progress)
-	if err != nil {
-		return nil, fmt.Errorf("vulnerability stage failed: %w", err)
+	if vulnErr != nil && pastDeadline() {
+		vulnResult = deadlineSkipped
+		skipped = append(skipped, "vulnerability")
+	} else if vulnErr != nil {
+		return nil, fmt.Errorf("vulnerability stage failed: %w", vulnErr)
 	}
 
 	// Stage 3: Exploitation Analysis (if critical vulns found)
-	if progress != nil {
-		progress("\n💥 Stage 3/3: Exploitation Analysis")
-	}
-
-	exploitPrompt := buildExploitPrompt(result, reconResult, vulnResult)
-	exploitResult, err := m.AnalyzeWithAgent(ctx, models.AgentTypeExploitation, exploitPrompt, progress)
-	if err != nil {
-		// Don't fail the whole analysis if exploitation stage fails
+	exploitResult := deadlineSkipped
+	if pastDeadline() {
+		skipped = append(skipped, "exploitation")
+	} else {
 		if progress != nil {
-			progress(fmt.Sprintf("⚠️  Exploitation analysis unavailable: %v", err))
+			progress("\n💥 Stage 3/3: Exploitation Analysis")
+		}
+
+		exploitPrompt := m.withStructuredInstructions(buildExploitPrompt(result, reconResult, vulnResult))
+		exploitResult, err = m.AnalyzeWithAgent(ctx, models.AgentTypeExploitation, exploitPrompt, progress)
+		if err != nil && pastDeadline() {
+			exploitResult = deadlineSkipped
+			skipped = append(skipped, "exploitation")
+		} else if err != nil {
+			// Don't fail the whole analysis if exploitation stage fails
+			if progress != nil {
+				progress(fmt.Sprintf("⚠️  Exploitation analysis unavailable: %v", err))
+			}
+			exploitResult = "Exploitation analysis not available."
 		}
-		exploitResult = "Exploitation analysis not available."
 	}
 
 	// Combine results
@@ -356,7 +817,77 @@ func (m *AgentManager) runDeepAnalysis(
 # Exploitation Assessment
 %s`, reconResult, vulnResult, exploitResult)
 
-	return parseAnalysisResponse(combinedText, result.ID), nil
+	analysis := parseAnalysisResponse(combinedText, result.ID)
+	if len(skipped) > 0 {
+		analysis.Note = fmt.Sprintf("Partial analysis: the analysis deadline was reached, so the %s stage(s) were skipped",
+			strings.Join(skipped, " and "))
+		if progress != nil {
+			progress("⏰ " + analysis.Note)
+		}
+	}
+	return analysis, nil
+}
+
+// parallelReconData stands in for the reconnaissance output when the
+// vulnerability stage runs alongside it
+const parallelReconData = "Not available: reconnaissance runs in parallel with this stage. Work from the scan findings."
+
+// deadlineSkipped stands in for a deep analysis stage the deadline cut off
+const deadlineSkipped = "Skipped: the analysis deadline was reached."
+
+// AnalyzeTriage performs a cheap first-pass triage using only the Quick
+// Scanner agent. It returns just a one-line summary, a risk score and the
+// critical/high issues, without recommendations or attack chains.
+func (m *AgentManager) AnalyzeTriage(
+	ctx context.Context,
+	result *models.ScanResult,
+	progress ProgressCallback,
+) (*models.AIAnalysis, error) {
+	if progress != nil {
+		progress("⚡ Quick triage: critical and high issues only")
+	}
+
+	result = result.WithActiveFindings()
+	scanFindings := len(result.Findings)
+	result.Findings = m.promptFindings(result.Findings)
+	if len(result.Findings) == 0 && !m.forceEmpty {
+		return nil, ErrNoFindings
+	}
+	m.tracker.RecordScan(result.ID, scanFindings)
+
+	text, err := m.AnalyzeWithAgent(ctx, models.AgentTypeQuickScan, buildTriagePrompt(result), progress)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseTriageResponse(text, result.ID), nil
+}
+
+// ExplainFinding asks the Vulnerability Researcher to explain a single
+// finding in depth. The response is returned verbatim.
+func (m *AgentManager) ExplainFinding(
+	ctx context.Context,
+	target string,
+	finding models.Finding,
+	progress ProgressCallback,
+) (string, error) {
+	return m.AnalyzeWithAgent(ctx, models.AgentTypeVulnerability, buildExplainPrompt(target, finding), progress)
+}
+
+// AnalyzePosture asks the Report agent for an executive posture summary across many scans
+func (m *AgentManager) AnalyzePosture(
+	ctx context.Context,
+	results []*models.ScanResult,
+	progress ProgressCallback,
+) (string, error) {
+	if progress != nil {
+		progress(fmt.Sprintf("📋 Summarizing security posture across %d scans", len(results)))
+	}
+	for _, result := range results {
+		m.tracker.RecordScan(result.ID, len(models.ActiveFindings(result.Findings)))
+	}
+
+	return m.AnalyzeWithAgent(ctx, models.AgentTypeReport, buildPosturePrompt(results), progress)
 }
 
 // GetUsageSummary returns usage statistics
@@ -364,8 +895,11 @@ func (m *AgentManager) GetUsageSummary() UsageSummary {
 	return m.tracker.GetSummary()
 }
 
-// Close closes all agents
+// Close closes the agents that were started
 func (m *AgentManager) Close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	for _, agent := range m.agents {
 		if agent.client != nil {
 			agent.client.Close()
@@ -384,7 +918,7 @@ func normalizeThinking(value string) string {
 	}
 }
 
-func buildAnalysisPrompt(result *models.ScanResult) string {
+func buildAnalysisPrompt(result *models.ScanResult, compact bool) string {
 	return fmt.Sprintf(`# Security Scan Analysis Request
 
 ## Target Information
@@ -407,10 +941,10 @@ Use markdown headings. Be specific and actionable.`,
 		result.Target,
 		result.StartTime.Format(time.RFC3339),
 		len(result.Findings),
-		formatFindings(result.Findings))
+		formatFindingsFor(result.Findings, compact))
 }
 
-func buildReconPrompt(result *models.ScanResult) string {
+func buildReconPrompt(result *models.ScanResult, compact bool) string {
 	return fmt.Sprintf(`# Reconnaissance Analysis
 
 Analyze the target's attack surface:
@@ -424,10 +958,10 @@ Findings: %s
 
 Provide detailed reconnaissance insights.`,
 		result.Target,
-		formatFindings(result.Findings))
+		formatFindingsFor(result.Findings, compact))
 }
 
-func buildVulnPrompt(result *models.ScanResult, reconData string) string {
+func buildVulnPrompt(result *models.ScanResult, reconData string, compact bool) string {
 	return fmt.Sprintf(`# Vulnerability Analysis
 
 Based on reconnaissance findings, perform deep vulnerability analysis:
@@ -446,7 +980,7 @@ Scan Findings:
 Provide comprehensive vulnerability assessment with risk scores.`,
 		result.Target,
 		reconData,
-		formatFindings(result.Findings))
+		formatFindingsFor(result.Findings, compact))
 }
 
 func buildExploitPrompt(result *models.ScanResult, reconData, vulnData string) string {
@@ -472,6 +1006,116 @@ Provide detailed exploitation assessment.`,
 		vulnData)
 }
 
+func buildTriagePrompt(result *models.ScanResult) string {
+	return fmt.Sprintf(`# Quick Security Triage
+
+Target: %s
+Findings: %d
+%s
+
+List ONLY critical and high severity issues. Do not give recommendations,
+attack chains or explanations beyond one short clause per issue.
+
+Respond in exactly this format:
+SUMMARY: <one sentence>
+RISK SCORE: <0-100>
+ISSUES:
+- [critical|high] <issue> - <why it matters>
+
+If there are no critical or high issues, write "ISSUES: none".`,
+		result.Target,
+		len(result.Findings),
+		formatFindingsCompact(result.Findings))
+}
+
+func parseTriageResponse(text string, scanID string) *models.AIAnalysis {
+	analysis := &models.AIAnalysis{
+		ScanID:         scanID,
+		RiskScore:      parseRiskScore(text),
+		CriticalIssues: make([]string, 0),
+		RawText:        text,
+		Timestamp:      time.Now(),
+	}
+
+	inIssues := false
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		upper := strings.ToUpper(trimmed)
+
+		switch {
+		case strings.HasPrefix(upper, "SUMMARY:"):
+			analysis.Summary = strings.TrimSpace(trimmed[len("SUMMARY:"):])
+			inIssues = false
+		case strings.HasPrefix(upper, "ISSUES:"):
+			inIssues = true
+		case inIssues && (strings.HasPrefix(trimmed, "-") || strings.HasPrefix(trimmed, "*")):
+			analysis.CriticalIssues = append(analysis.CriticalIssues, strings.TrimSpace(trimmed[1:]))
+		}
+	}
+
+	if analysis.Summary == "" {
+		analysis.Summary = parseAnalysisSummary(text)
+	}
+
+	return analysis
+}
+
+func buildExplainPrompt(target string, finding models.Finding) string {
+	return fmt.Sprintf(`# Explain a Security Finding
+
+Target: %s
+
+## Finding
+%s
+
+## Task
+Explain this single finding for an engineer who has to act on it:
+1. **What it means** - the underlying weakness in plain terms
+2. **How it is exploited** - realistic attacker steps and prerequisites
+3. **Impact** - what an attacker gains
+4. **How to fix it** - concrete remediation steps and how to verify the fix
+
+Focus only on this finding.`,
+		target,
+		formatFindingsDetailed([]models.Finding{finding}))
+}
+
+func buildPosturePrompt(results []*models.ScanResult) string {
+	var targets strings.Builder
+	for _, result := range results {
+		result = result.WithActiveFindings()
+		counts := make(map[string]int)
+		for _, finding := range result.Findings {
+			counts[strings.ToLower(finding.Severity)]++
+		}
+		targets.WriteString(fmt.Sprintf("\n## %s (scan %s)\n", result.Target, result.ID))
+		targets.WriteString(fmt.Sprintf("Findings: %d critical, %d high, %d medium, %d low, %d info\n",
+			counts["critical"], counts["high"], counts["medium"], counts["low"], counts["info"]))
+		for _, finding := range result.Findings {
+			if models.SeverityRank(finding.Severity) >= models.SeverityRank("high") {
+				targets.WriteString(fmt.Sprintf("- [%s] %s\n", finding.Severity, finding.Title))
+			}
+		}
+	}
+
+	return fmt.Sprintf(`# Executive Security Posture Summary
+
+You are summarizing security scans of %d targets for an executive audience.
+
+## Scanned Targets
+%s
+
+## Task
+Write a concise overall posture summary (one short paragraph, at most 5 sentences):
+- Overall security posture across the fleet
+- The most significant recurring weaknesses
+- Which targets need attention first and why
+
+Respond with the paragraph only, no headings or lists.`,
+		len(results),
+		targets.String())
+}
+
 func formatFindings(findings []models.Finding) string {
 	if len(findings) == 0 {
 		return "No findings detected"
@@ -486,18 +1130,77 @@ func formatFindings(findings []models.Finding) string {
 		if finding.Description != "" {
 			result.WriteString(fmt.Sprintf("\n   Details: %s", finding.Description))
 		}
+		if finding.Confidence != "" {
+			result.WriteString(fmt.Sprintf("\n   Confidence: %s", finding.Confidence))
+		}
 	}
 
 	return result.String()
 }
 
+// formatFindingsFor picks compact or full formatting
+func formatFindingsFor(findings []models.Finding, compact bool) string {
+	if compact {
+		return formatFindingsCompact(findings)
+	}
+	return formatFindings(findings)
+}
+
+// compactEvidenceLength caps the evidence snippet in compact findings
+const compactEvidenceLength = 160
+
+// formatFindingsCompact lists only severity, title, location and a short
+// single-line evidence snippet per finding. Used for triage and quick
+// analyses, where descriptions cost tokens without changing the outcome.
+func formatFindingsCompact(findings []models.Finding) string {
+	if len(findings) == 0 {
+		return "No findings detected"
+	}
+
+	var result strings.Builder
+	for i, finding := range findings {
+		result.WriteString(fmt.Sprintf("\n%d. [%s] %s", i+1, finding.Severity, finding.Title))
+		if finding.Location != "" {
+			result.WriteString(" @ " + finding.Location)
+		}
+		if evidence := strings.Join(strings.Fields(finding.Evidence), " "); evidence != "" {
+			if runes := []rune(evidence); len(runes) > compactEvidenceLength {
+				evidence = string(runes[:compactEvidenceLength]) + "..."
+			}
+			result.WriteString("\n   Evidence: " + evidence)
+		}
+	}
+
+	return result.String()
+}
+
+// EmptyAnalysis is the verdict for a scan with nothing to analyze: zero
+// risk and no issues, recorded without calling the AI
+func EmptyAnalysis(scanID string) *models.AIAnalysis {
+	return &models.AIAnalysis{
+		ScanID:          scanID,
+		Summary:         "No findings to analyze, so no issues were identified.",
+		CriticalIssues:  []string{},
+		Recommendations: []models.Recommendation{},
+		Note:            "The AI was not called because no findings were left after suppression and filtering",
+		Timestamp:       time.Now(),
+	}
+}
+
+// parseAnalysisResponse prefers a structured JSON block when the response
+// has one and falls back to scraping the markdown sections
 func parseAnalysisResponse(text string, scanID string) *models.AIAnalysis {
+	if analysis, ok := parseStructuredAnalysis(text, scanID); ok {
+		return analysis
+	}
+
 	return &models.AIAnalysis{
 		ScanID:          scanID,
 		Summary:         parseAnalysisSummary(text),
 		RiskScore:       parseRiskScore(text),
 		CriticalIssues:  parseCriticalIssues(text),
 		Recommendations: parseRecommendations(text),
+		RawText:         text,
 		Timestamp:       time.Now(),
 	}
 }
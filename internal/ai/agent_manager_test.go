@@ -0,0 +1,561 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pi "github.com/joshp123/pi-golang"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+func TestBuildTriagePrompt(t *testing.T) {
+	result := &models.ScanResult{
+		Target: "example.com",
+		Findings: []models.Finding{
+			{Severity: "high", Title: "Exposed admin panel", Location: "https://example.com/admin"},
+		},
+	}
+	prompt := buildTriagePrompt(result)
+	for _, want := range []string{"Target: example.com", "Findings: 1", "Exposed admin panel", "ONLY critical and high", "ISSUES: none"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("triage prompt is missing %q", want)
+		}
+	}
+}
+
+func TestParseTriageResponse(t *testing.T) {
+	text := `SUMMARY: One exposed admin panel needs attention.
+RISK SCORE: 72
+ISSUES:
+- [high] Exposed admin panel - anyone can reach the login
+* [critical] Default credentials - full takeover`
+
+	analysis := parseTriageResponse(text, "scan-1")
+	if analysis.ScanID != "scan-1" || analysis.RiskScore != 72 || analysis.RawText != text {
+		t.Errorf("scan %q, risk %d", analysis.ScanID, analysis.RiskScore)
+	}
+	if analysis.Summary != "One exposed admin panel needs attention." {
+		t.Errorf("summary = %q", analysis.Summary)
+	}
+	if len(analysis.CriticalIssues) != 2 || analysis.CriticalIssues[1] != "[critical] Default credentials - full takeover" {
+		t.Errorf("issues = %q", analysis.CriticalIssues)
+	}
+	if len(analysis.Recommendations) != 0 || len(analysis.AttackChains) != 0 {
+		t.Error("triage produced recommendations or attack chains")
+	}
+
+	none := parseTriageResponse("SUMMARY: Nothing urgent.\nRISK SCORE: 5\nISSUES: none", "scan-2")
+	if len(none.CriticalIssues) != 0 || none.RiskScore != 5 {
+		t.Errorf("no-issue triage = %+v", none)
+	}
+}
+
+func TestBuildExplainPrompt(t *testing.T) {
+	finding := models.Finding{
+		Type:     "sqli",
+		Severity: "critical",
+		Title:    "SQL injection in login",
+		Location: "https://example.com/login",
+		Evidence: "' OR 1=1 -- returned every user",
+	}
+	prompt := buildExplainPrompt("example.com", finding)
+	for _, want := range []string{"Target: example.com", finding.Title, finding.Location, finding.Evidence, "How it is exploited", "How to fix it", "Focus only on this finding."} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("explain prompt is missing %q", want)
+		}
+	}
+}
+
+func TestPosturePromptLeavesOutSuppressed(t *testing.T) {
+	result := &models.ScanResult{
+		ID:     "scan-1",
+		Target: "example.com",
+		Findings: []models.Finding{
+			{Severity: "critical", Title: "Accepted risk", Metadata: map[string]string{models.MetaSuppressed: "true"}},
+			{Severity: "high", Title: "Exposed admin panel"},
+		},
+	}
+	prompt := buildPosturePrompt([]*models.ScanResult{result})
+	if strings.Contains(prompt, "Accepted risk") || !strings.Contains(prompt, "Findings: 0 critical, 1 high") {
+		t.Errorf("posture prompt includes a suppressed finding:\n%s", prompt)
+	}
+}
+
+// withUnavailableModels starts fake clients in place of pi, failing for the
+// given models, and returns a counter of start attempts. The pi CLI is
+// reported as installed.
+func withUnavailableModels(t *testing.T, unavailable ...string) *int {
+	t.Helper()
+	original, originalResolve := startOneShot, resolvePiCommand
+	t.Cleanup(func() { startOneShot, resolvePiCommand = original, originalResolve })
+	resolvePiCommand = func() (pi.Command, error) { return pi.Command{}, nil }
+
+	starts := new(int)
+	startOneShot = func(opts pi.OneShotOptions) (*pi.OneShotClient, error) {
+		*starts++
+		for _, model := range unavailable {
+			if model == opts.Dragons.Model {
+				return nil, errors.New("model " + model + " is not available on this plan")
+			}
+		}
+		return &pi.OneShotClient{}, nil
+	}
+	return starts
+}
+
+// fakeAgents starts fake clients in place of pi, rejecting the models in
+// rejectStart and answering prompts with run
+type fakeAgents struct {
+	mu          sync.Mutex
+	started     []string // models, in start order
+	closed      int
+	rejectStart map[string]error
+	run         func(model string, prompt string) (string, error)
+}
+
+type fakeAgentClient struct {
+	agents *fakeAgents
+	model  string
+}
+
+func (c *fakeAgentClient) Run(ctx context.Context, prompt string) (pi.RunResult, error) {
+	text, err := c.agents.run(c.model, prompt)
+	return pi.RunResult{Text: text}, err
+}
+
+func (c *fakeAgentClient) Close() error {
+	c.agents.mu.Lock()
+	defer c.agents.mu.Unlock()
+	c.agents.closed++
+	return nil
+}
+
+func (f *fakeAgents) start(opts pi.OneShotOptions) (agentClient, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	model := opts.Dragons.Model
+	f.started = append(f.started, model)
+	if err := f.rejectStart[model]; err != nil {
+		return nil, false, err
+	}
+	return &fakeAgentClient{agents: f, model: model}, false, nil
+}
+
+func (f *fakeAgents) startedModels() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.started...)
+}
+
+// newFakeManager returns a manager whose agents run on fakes, with usage
+// logged under a temporary home directory
+func newFakeManager(t *testing.T) (*AgentManager, *fakeAgents) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	fake := &fakeAgents{
+		rejectStart: make(map[string]error),
+		run: func(model string, prompt string) (string, error) {
+			return "## Executive Summary\nAnalyzed by " + model, nil
+		},
+	}
+	manager := newAgentManager()
+	manager.start = fake.start
+	return manager, fake
+}
+
+func testScanResult() *models.ScanResult {
+	return &models.ScanResult{
+		ID:     "scan-1",
+		Target: "example.com",
+		Findings: []models.Finding{
+			{ID: "f1", Type: "missing-header", Severity: "medium", Title: "Missing HSTS", Location: "https://example.com"},
+			{ID: "f2", Type: "open-port", Severity: "high", Title: "Telnet open", Location: "example.com:23"},
+		},
+	}
+}
+
+func TestQuickAnalysisStartsOneAgent(t *testing.T) {
+	manager, fake := newFakeManager(t)
+	defer manager.Close()
+
+	if _, err := manager.AnalyzeScanWithAgents(context.Background(), testScanResult(), "quick", nil); err != nil {
+		t.Fatalf("quick analysis: %v", err)
+	}
+	started := fake.startedModels()
+	if len(started) != 1 || started[0] != "claude-haiku-4.5" {
+		t.Errorf("started %v, want only the quick scan agent", started)
+	}
+
+	manager.Close()
+	if fake.closed != 1 {
+		t.Errorf("closed %d clients, want 1", fake.closed)
+	}
+}
+
+func TestAgentStartsOnceConcurrently(t *testing.T) {
+	manager, fake := newFakeManager(t)
+	defer manager.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := manager.agent(models.AgentTypeRecon, nil); err != nil {
+				t.Errorf("agent: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if started := fake.startedModels(); len(started) != 1 {
+		t.Errorf("started %v, want one client for concurrent callers", started)
+	}
+}
+
+func TestAgentFallsBackToSubstituteModel(t *testing.T) {
+	manager, fake := newFakeManager(t)
+	defer manager.Close()
+	fake.rejectStart["claude-opus-4.6"] = errors.New("model not found: claude-opus-4.6")
+
+	var notices []string
+	agent, err := manager.agent(models.AgentTypeExploitation, func(msg string) { notices = append(notices, msg) })
+	if err != nil {
+		t.Fatalf("agent: %v", err)
+	}
+	if agent.config.Model != "claude-sonnet-4.5-20250929" {
+		t.Errorf("exploitation agent runs %s, want the Sonnet fallback", agent.config.Model)
+	}
+	if len(notices) != 1 || !strings.Contains(notices[0], "claude-opus-4.6 unavailable, using claude-sonnet-4.5-20250929") {
+		t.Errorf("notices = %q, want the substitution", notices)
+	}
+	subs := manager.Substitutions()
+	if len(subs) != 1 || subs[0].To != "claude-sonnet-4.5-20250929" {
+		t.Errorf("substitutions = %+v", subs)
+	}
+}
+
+func TestAgentUnavailableIsReported(t *testing.T) {
+	manager, fake := newFakeManager(t)
+	defer manager.Close()
+	fake.rejectStart["claude-haiku-4.5"] = errors.New("pi exited")
+
+	var notices []string
+	progress := func(msg string) { notices = append(notices, msg) }
+	if _, err := manager.agent(models.AgentTypeQuickScan, progress); err == nil {
+		t.Fatal("expected the quick scan agent to fail")
+	}
+	if len(notices) != 1 || !strings.Contains(notices[0], "Quick Scanner unavailable (claude-haiku-4.5): pi exited") {
+		t.Errorf("notices = %q, want the unavailable agent reported", notices)
+	}
+
+	// The failure is remembered, not retried
+	if _, err := manager.agent(models.AgentTypeQuickScan, progress); err == nil {
+		t.Error("expected the remembered failure")
+	}
+	if started := fake.startedModels(); len(started) != 1 {
+		t.Errorf("started %v, want a single attempt", started)
+	}
+}
+
+func TestNewAgentManagerWithoutPi(t *testing.T) {
+	original := resolvePiCommand
+	defer func() { resolvePiCommand = original }()
+	resolvePiCommand = func() (pi.Command, error) {
+		return pi.Command{}, errors.New("pi CLI not found")
+	}
+
+	if _, err := NewAgentManager(); !errors.Is(err, ErrAIUnavailable) {
+		t.Errorf("NewAgentManager error = %v, want ErrAIUnavailable", err)
+	}
+}
+
+func TestFormatFindingsCompact(t *testing.T) {
+	findings := []models.Finding{
+		{Severity: "high", Title: "Reflected XSS", Location: "https://example.com/search",
+			Description: "A long description that compact mode leaves out", Evidence: "<script>\n  alert(1)\n</script>"},
+		{Severity: "info", Title: "Open TCP port 22", Evidence: strings.Repeat("x", 200)},
+	}
+
+	got := formatFindingsCompact(findings)
+	for _, want := range []string{
+		"1. [high] Reflected XSS @ https://example.com/search",
+		"Evidence: <script> alert(1) </script>",
+		"2. [info] Open TCP port 22\n",
+		"Evidence: " + strings.Repeat("x", compactEvidenceLength) + "...",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("compact findings missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "long description") {
+		t.Errorf("compact findings include the description:\n%s", got)
+	}
+
+	if formatFindingsCompact(nil) != "No findings detected" {
+		t.Error("empty findings not reported")
+	}
+	if !strings.Contains(formatFindingsFor(findings, false), "long description") {
+		t.Error("full formatting dropped the description")
+	}
+}
+
+func TestDeepAnalysisPastDeadline(t *testing.T) {
+	withUnavailableModels(t, "claude-opus-4.6", "claude-sonnet-4.5-20250929", "claude-haiku-4.5")
+
+	manager, err := NewAgentManager()
+	if err != nil {
+		t.Fatalf("NewAgentManager: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+
+	_, err = manager.runDeepAnalysis(ctx, &models.ScanResult{ID: "scan-1", Target: "example.com"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "analysis deadline reached before any stage completed") {
+		t.Errorf("err = %v, want the deadline named as the reason", err)
+	}
+}
+
+func TestPromptFindings(t *testing.T) {
+	findings := []models.Finding{
+		{Title: "Target Reachable", Severity: "info"},
+		{Title: "Verbose banner", Severity: "low"},
+		{Title: "SQL injection", Severity: "high"},
+	}
+
+	tests := []struct {
+		minSeverity string
+		includeInfo bool
+		want        int
+	}{
+		{"", false, 2},
+		{"", true, 3},
+		{"high", true, 1},
+	}
+	for _, tt := range tests {
+		m := &AgentManager{minSeverity: tt.minSeverity, includeInfo: tt.includeInfo}
+		if got := m.promptFindings(findings); len(got) != tt.want {
+			t.Errorf("min %q, include info %v: got %d findings, want %d", tt.minSeverity, tt.includeInfo, len(got), tt.want)
+		}
+	}
+}
+
+func TestRunStages(t *testing.T) {
+	tests := []struct {
+		parallel int
+		wantPeak int32
+	}{
+		{0, 1}, // below 1 is clamped to sequential
+		{1, 1},
+		{2, 2},
+		{5, 3},
+	}
+	for _, tt := range tests {
+		m := &AgentManager{}
+		m.SetParallelAgents(tt.parallel)
+
+		var running, peak atomic.Int32
+		var order []int
+		var mu sync.Mutex
+		stage := func(i int) func() {
+			return func() {
+				n := running.Add(1)
+				for {
+					old := peak.Load()
+					if n <= old || peak.CompareAndSwap(old, n) {
+						break
+					}
+				}
+				time.Sleep(20 * time.Millisecond)
+				running.Add(-1)
+				mu.Lock()
+				order = append(order, i)
+				mu.Unlock()
+			}
+		}
+		m.runStages(stage(1), stage(2), stage(3))
+
+		if peak.Load() != tt.wantPeak {
+			t.Errorf("parallel %d: peak %d stages at once, want %d", tt.parallel, peak.Load(), tt.wantPeak)
+		}
+		if len(order) != 3 {
+			t.Errorf("parallel %d: ran stages %v, want all three", tt.parallel, order)
+		}
+		if tt.wantPeak == 1 && fmt.Sprint(order) != "[1 2 3]" {
+			t.Errorf("parallel %d: order %v, want sequential", tt.parallel, order)
+		}
+	}
+}
+
+func TestAnalyzeWithAgentRetriesRateLimit(t *testing.T) {
+	manager, fake := newFakeManager(t)
+	defer manager.Close()
+	manager.retry = &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+	calls := 0
+	fake.run = func(model string, prompt string) (string, error) {
+		calls++
+		if calls == 1 {
+			return "", errors.New("429 rate limit exceeded")
+		}
+		return "recovered", nil
+	}
+
+	var notices []string
+	text, err := manager.AnalyzeWithAgent(context.Background(), models.AgentTypeQuickScan, "prompt", func(msg string) {
+		notices = append(notices, msg)
+	})
+	if err != nil || text != "recovered" {
+		t.Fatalf("AnalyzeWithAgent = %q, %v; want the retried response", text, err)
+	}
+	if calls != 2 {
+		t.Errorf("ran %d times, want 2", calls)
+	}
+	retried := false
+	for _, notice := range notices {
+		retried = retried || strings.Contains(notice, "retry 1/2")
+	}
+	if !retried {
+		t.Errorf("notices = %q, want the retry reported", notices)
+	}
+}
+
+func TestAnalyzeWithAgentGivesUp(t *testing.T) {
+	manager, fake := newFakeManager(t)
+	defer manager.Close()
+	manager.retry = &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+	calls := 0
+	fake.run = func(model string, prompt string) (string, error) {
+		calls++
+		return "", errors.New("429 rate limit exceeded")
+	}
+	if _, err := manager.AnalyzeWithAgent(context.Background(), models.AgentTypeQuickScan, "prompt", nil); !errors.Is(err, ErrRateLimit) {
+		t.Errorf("AnalyzeWithAgent error = %v, want ErrRateLimit", err)
+	}
+	if calls != 3 {
+		t.Errorf("ran %d times, want 3 attempts", calls)
+	}
+
+	// Errors a retry can't fix are returned at once
+	calls = 0
+	fake.run = func(model string, prompt string) (string, error) {
+		calls++
+		return "", ErrPromptTooLarge
+	}
+	if _, err := manager.AnalyzeWithAgent(context.Background(), models.AgentTypeQuickScan, "prompt", nil); err == nil || calls != 1 {
+		t.Errorf("non-retryable error: ran %d times, err %v; want 1 attempt", calls, err)
+	}
+}
+
+func TestAnalyzeWithAgentRecordsEveryAttempt(t *testing.T) {
+	manager, fake := newFakeManager(t)
+	defer manager.Close()
+	manager.retry = &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	manager.SetScanID("scan-7")
+
+	calls := 0
+	fake.run = func(model string, prompt string) (string, error) {
+		calls++
+		if calls < 3 {
+			return "", errors.New("connection reset by peer")
+		}
+		return "done", nil
+	}
+	if _, err := manager.AnalyzeWithAgent(context.Background(), models.AgentTypeQuickScan, "prompt", nil); err != nil {
+		t.Fatalf("AnalyzeWithAgent: %v", err)
+	}
+
+	summary := manager.GetUsageSummary()
+	if summary.TotalOperations != 3 || summary.SuccessfulOperations != 1 {
+		t.Errorf("recorded %d operations, %d successful; want 3 and 1", summary.TotalOperations, summary.SuccessfulOperations)
+	}
+	for _, usage := range manager.tracker.usages {
+		if usage.ScanID != "scan-7" {
+			t.Errorf("attempt recorded for scan %q, want scan-7", usage.ScanID)
+		}
+		if !usage.Success && usage.Error == "" {
+			t.Error("failed attempt recorded without its error")
+		}
+	}
+}
+
+func TestAnalyzeWithAgentFallsBackWhenRunRejectsModel(t *testing.T) {
+	manager, fake := newFakeManager(t)
+	defer manager.Close()
+
+	// pi starts fine and only rejects the model when the prompt is sent
+	fake.run = func(model string, prompt string) (string, error) {
+		if model == "claude-opus-4.6" {
+			return "", errors.New(`404 {"type":"error","error":{"type":"not_found_error","message":"model: claude-opus-4.6"}}`)
+		}
+		return "analyzed by " + model, nil
+	}
+
+	var notices []string
+	text, err := manager.AnalyzeWithAgent(context.Background(), models.AgentTypeExploitation, "prompt", func(msg string) {
+		notices = append(notices, msg)
+	})
+	if err != nil {
+		t.Fatalf("AnalyzeWithAgent: %v", err)
+	}
+	if text != "analyzed by claude-sonnet-4.5-20250929" {
+		t.Errorf("response %q, want it from the Sonnet fallback", text)
+	}
+
+	subs := manager.Substitutions()
+	if len(subs) != 1 || subs[0].From != "claude-opus-4.6" || subs[0].To != "claude-sonnet-4.5-20250929" {
+		t.Errorf("substitutions = %+v, want Opus to Sonnet", subs)
+	}
+	reported := false
+	for _, notice := range notices {
+		reported = reported || strings.Contains(notice, "claude-opus-4.6 unavailable, using claude-sonnet-4.5-20250929")
+	}
+	if !reported {
+		t.Errorf("notices = %q, want the substitution reported", notices)
+	}
+	if fake.closed != 1 {
+		t.Errorf("closed %d clients, want the rejected Opus client closed", fake.closed)
+	}
+
+	// Later calls go straight to the substitute
+	fake.run = func(model string, prompt string) (string, error) {
+		if model != "claude-sonnet-4.5-20250929" {
+			t.Errorf("ran on %s after the substitution", model)
+		}
+		return "ok", nil
+	}
+	if _, err := manager.AnalyzeWithAgent(context.Background(), models.AgentTypeExploitation, "prompt", nil); err != nil {
+		t.Errorf("second AnalyzeWithAgent: %v", err)
+	}
+}
+
+func TestExplainFinding(t *testing.T) {
+	manager, fake := newFakeManager(t)
+	defer manager.Close()
+	result := testScanResult()
+	explanation := "## What it means\n✅ Telnet sends credentials in clear text  \n"
+	var prompt string
+	fake.run = func(model string, p string) (string, error) {
+		prompt = p
+		return explanation, nil
+	}
+
+	got, err := manager.ExplainFinding(context.Background(), result.Target, result.Findings[1], nil)
+	if err != nil {
+		t.Fatalf("ExplainFinding: %v", err)
+	}
+	if got != explanation {
+		t.Errorf("explanation = %q, want the response verbatim", got)
+	}
+	if !strings.Contains(prompt, "Telnet open") || strings.Contains(prompt, "Missing HSTS") {
+		t.Errorf("prompt should hold only the selected finding:\n%s", prompt)
+	}
+}
@@ -0,0 +1,119 @@
+package ai
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	pi "github.com/joshp123/pi-golang"
+
+	"github.com/kumaraguru1735/shadow/internal/output"
+)
+
+// startOneShot starts a pi client; replaced in tests
+var startOneShot = pi.StartOneShot
+
+// apiKeyAppDir nests the pi app directories used after falling back to the
+// API key under ~/.shadow
+const apiKeyAppDir = "shadow/api-key/"
+
+var (
+	// usingAPIKey is set once OAuth has failed in this run and clients
+	// are started on ANTHROPIC_API_KEY instead
+	usingAPIKey        atomic.Bool
+	apiKeyFallbackOnce sync.Once
+)
+
+// startClient starts a pi client for an analyzer and reports whether it
+// was started on ANTHROPIC_API_KEY. Every analyzer starts its clients here,
+// so once OAuth fails in a run and the key is set, all of them switch to
+// the key (see fallBackToAPIKey). Failures wrap ErrAIUnavailable.
+func startClient(opts pi.OneShotOptions) (*pi.OneShotClient, bool, error) {
+	onAPIKey := usingAPIKey.Load()
+	var client *pi.OneShotClient
+	var err error
+	if onAPIKey {
+		client, err = startWithAPIKey(opts)
+	} else {
+		client, err = startOneShot(opts)
+		if err != nil && fallBackToAPIKey(err, false) {
+			onAPIKey = true
+			client, err = startWithAPIKey(opts)
+		}
+	}
+	if err != nil {
+		return nil, onAPIKey, fmt.Errorf("%w: %w", ErrAIUnavailable, err)
+	}
+	return client, onAPIKey, nil
+}
+
+// fallBackToAPIKey switches the rest of the run to ANTHROPIC_API_KEY after
+// an OAuth authentication failure, such as an expired token that couldn't
+// be refreshed. It reports whether the failing client, started on the key
+// if onAPIKey, should be started again with startClient. A client that was
+// already on the key is never restarted: the key itself was rejected.
+func fallBackToAPIKey(err error, onAPIKey bool) bool {
+	if onAPIKey || !isAuthError(err) || os.Getenv("ANTHROPIC_API_KEY") == "" {
+		return false
+	}
+	if usingAPIKey.Load() {
+		// Another client already switched; this one still runs on OAuth
+		return true
+	}
+	// Without OAuth credentials the key itself was rejected
+	if oauthTokenPath() == "" {
+		return false
+	}
+
+	apiKeyFallbackOnce.Do(func() {
+		output.Printf("⚠️  OAuth authentication failed (%v); using ANTHROPIC_API_KEY for the rest of this run\n", err)
+		usingAPIKey.Store(true)
+	})
+	return true
+}
+
+// startWithAPIKey starts the client in a pi app directory of its own whose
+// credential files are empty, so pi can't pick up the OAuth token it would
+// otherwise prefer and reads ANTHROPIC_API_KEY instead
+func startWithAPIKey(opts pi.OneShotOptions) (*pi.OneShotClient, error) {
+	name := strings.TrimPrefix(opts.AppName, ".")
+	if name == "" {
+		name = "shadow"
+	}
+	opts.AppName = apiKeyAppDir + name
+	if err := blankCredentials(opts.AppName); err != nil {
+		return nil, err
+	}
+	return startOneShot(opts)
+}
+
+// blankCredentials writes empty oauth.json and auth.json files to the pi
+// agent directory of appName. pi-golang copies OAuth credentials into that
+// directory when they are newer than its copy, so the blanks are dated far
+// in the future to keep them in place.
+func blankCredentials(appName string) error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	agentDir := filepath.Join(home, "."+appName, "pi-agent")
+	if err := os.MkdirAll(agentDir, 0700); err != nil {
+		return err
+	}
+
+	future := time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, name := range []string{"oauth.json", "auth.json"} {
+		path := filepath.Join(agentDir, name)
+		if err := os.WriteFile(path, []byte("{}\n"), 0600); err != nil {
+			return err
+		}
+		if err := os.Chtimes(path, future, future); err != nil {
+			return err
+		}
+	}
+	return nil
+}
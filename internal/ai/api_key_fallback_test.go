@@ -0,0 +1,151 @@
+package ai
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	pi "github.com/joshp123/pi-golang"
+)
+
+var errUnauthorized = errors.New("401 unauthorized: token has expired")
+
+// withFallbackEnv gives the test a home directory holding OAuth
+// credentials and an API key, and resets the run-wide fallback state
+func withFallbackEnv(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("ANTHROPIC_API_KEY", "sk-ant-test")
+	if err := os.MkdirAll(filepath.Join(home, ".claude"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".claude", ".credentials.json"), []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	resetFallback := func() {
+		usingAPIKey.Store(false)
+		apiKeyFallbackOnce = sync.Once{}
+	}
+	resetFallback()
+	original := startOneShot
+	t.Cleanup(func() {
+		startOneShot = original
+		resetFallback()
+	})
+	return home
+}
+
+// fakeStart rejects OAuth app directories and accepts API key ones,
+// recording the app names it was started with
+func fakeStart(started *[]string) func(pi.OneShotOptions) (*pi.OneShotClient, error) {
+	return func(opts pi.OneShotOptions) (*pi.OneShotClient, error) {
+		*started = append(*started, opts.AppName)
+		if !strings.HasPrefix(opts.AppName, apiKeyAppDir) {
+			return nil, errUnauthorized
+		}
+		return &pi.OneShotClient{}, nil
+	}
+}
+
+func TestStartClientFallsBackToAPIKey(t *testing.T) {
+	home := withFallbackEnv(t)
+	var started []string
+	startOneShot = fakeStart(&started)
+
+	client, onAPIKey, err := startClient(pi.OneShotOptions{AppName: "shadow"})
+	if err != nil {
+		t.Fatalf("startClient: %v", err)
+	}
+	if client == nil || !onAPIKey {
+		t.Errorf("startClient = %v, onAPIKey %v; want a client on the key", client, onAPIKey)
+	}
+	if len(started) != 2 || started[1] != apiKeyAppDir+"shadow" {
+		t.Errorf("started %v, want OAuth then %s", started, apiKeyAppDir+"shadow")
+	}
+	for _, name := range []string{"oauth.json", "auth.json"} {
+		data, err := os.ReadFile(filepath.Join(home, "."+apiKeyAppDir+"shadow", "pi-agent", name))
+		if err != nil || strings.TrimSpace(string(data)) != "{}" {
+			t.Errorf("%s = %q, %v; want blank credentials", name, data, err)
+		}
+	}
+
+	// Later clients go straight to the key
+	started = nil
+	if _, onAPIKey, err := startClient(pi.OneShotOptions{AppName: "shadow"}); err != nil || !onAPIKey {
+		t.Errorf("second startClient: onAPIKey %v, %v", onAPIKey, err)
+	}
+	if len(started) != 1 {
+		t.Errorf("second startClient started %v, want the key only", started)
+	}
+}
+
+func TestStartClientKeyRejected(t *testing.T) {
+	withFallbackEnv(t)
+	startOneShot = func(pi.OneShotOptions) (*pi.OneShotClient, error) {
+		return nil, errUnauthorized
+	}
+
+	_, _, err := startClient(pi.OneShotOptions{AppName: "shadow"})
+	if !errors.Is(err, ErrAIUnavailable) {
+		t.Errorf("startClient error = %v, want ErrAIUnavailable", err)
+	}
+}
+
+func TestFallBackToAPIKey(t *testing.T) {
+	withFallbackEnv(t)
+
+	if fallBackToAPIKey(errors.New("connection reset"), false) {
+		t.Error("fell back on a non-auth error")
+	}
+	if fallBackToAPIKey(errUnauthorized, true) {
+		t.Error("fell back for a client already on the key")
+	}
+	if !fallBackToAPIKey(errUnauthorized, false) {
+		t.Fatal("did not fall back on an OAuth auth error")
+	}
+	if !usingAPIKey.Load() {
+		t.Error("fallback was not recorded for the rest of the run")
+	}
+
+	// Once switched, OAuth clients still restart but key clients don't
+	if !fallBackToAPIKey(errUnauthorized, false) {
+		t.Error("an OAuth client was not restarted after the switch")
+	}
+	if fallBackToAPIKey(errUnauthorized, true) {
+		t.Error("a client on the key was restarted after the switch")
+	}
+}
+
+func TestFallBackToAPIKeyNeedsKeyAndOAuth(t *testing.T) {
+	withFallbackEnv(t)
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	if fallBackToAPIKey(errUnauthorized, false) {
+		t.Error("fell back without ANTHROPIC_API_KEY")
+	}
+
+	t.Setenv("ANTHROPIC_API_KEY", "sk-ant-test")
+	t.Setenv("HOME", t.TempDir())
+	if fallBackToAPIKey(errUnauthorized, false) {
+		t.Error("fell back without OAuth credentials, so the key itself was rejected")
+	}
+}
+
+func TestStartClientMissingCLI(t *testing.T) {
+	withFallbackEnv(t)
+	startOneShot = func(pi.OneShotOptions) (*pi.OneShotClient, error) {
+		return nil, errors.New(`exec: "pi": executable file not found in $PATH`)
+	}
+
+	_, onAPIKey, err := startClient(pi.OneShotOptions{AppName: "shadow"})
+	if !errors.Is(err, ErrAIUnavailable) {
+		t.Errorf("startClient error = %v, want ErrAIUnavailable", err)
+	}
+	if onAPIKey || usingAPIKey.Load() {
+		t.Error("fell back to the API key on an error that isn't about authentication")
+	}
+}
@@ -0,0 +1,56 @@
+package ai
+
+import "os"
+
+// AuthCheck is the outcome of auth-check, for monitoring scripts. It never
+// holds token values.
+type AuthCheck struct {
+	OAuthFound    bool            `json:"oauth_found"`
+	OAuthPath     string          `json:"oauth_path,omitempty"`
+	OAuthExpired  bool            `json:"oauth_expired"`
+	MissingScopes []string        `json:"missing_scopes,omitempty"`
+	APIKeyPresent bool            `json:"api_key_present"`
+	Connection    *ConnectionTest `json:"connection,omitempty"` // nil when the test was skipped
+	Healthy       bool            `json:"healthy"`
+}
+
+// ConnectionTest is the result of starting an AI client
+type ConnectionTest struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// CheckAuth looks for OAuth credentials and an API key and, with connect,
+// starts an AI client to test the connection. It is healthy when some
+// credential is usable (an API key, or an OAuth token that is neither expired
+// nor missing scopes) and the connection test, if run, passed.
+func CheckAuth(connect bool) AuthCheck {
+	check := AuthCheck{
+		OAuthPath:     oauthTokenPath(),
+		APIKeyPresent: os.Getenv("ANTHROPIC_API_KEY") != "",
+	}
+	check.OAuthFound = check.OAuthPath != ""
+
+	// Expiry and scopes are only recorded in Claude Code's credentials file
+	if manager, err := NewAuthManager(); err == nil {
+		if status, err := manager.GetAuthStatus(); err == nil && status.HasOAuth {
+			check.OAuthExpired = status.OAuthExpired
+			check.MissingScopes = status.MissingScopes()
+		}
+	}
+
+	if connect {
+		check.Connection = &ConnectionTest{OK: true}
+		analyzer, err := NewPiClaudeAnalyzer()
+		if err != nil {
+			check.Connection = &ConnectionTest{Error: err.Error()}
+		} else {
+			analyzer.Close()
+		}
+	}
+
+	// A token without the scopes analysis needs is as unusable as an expired one
+	usable := check.APIKeyPresent || (check.OAuthFound && !check.OAuthExpired && len(check.MissingScopes) == 0)
+	check.Healthy = usable && (check.Connection == nil || check.Connection.OK)
+	return check
+}
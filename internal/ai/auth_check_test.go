@@ -0,0 +1,86 @@
+package ai
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// withCredentials sets up a home directory holding a Claude Code
+// credentials file that expires at expiresAt with the given scopes, or no
+// file when scopes is nil, and sets the API key
+func withCredentials(t *testing.T, expiresAt time.Time, scopes []string, apiKey string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("ANTHROPIC_API_KEY", apiKey)
+	if scopes == nil {
+		return
+	}
+
+	creds := ClaudeCredentials{ClaudeAiOauth: OAuthCredentials{
+		AccessToken:  "sk-ant-oat01-secret-access",
+		RefreshToken: "sk-ant-REDACTED",
+		ExpiresAt:    expiresAt.UnixMilli(),
+		Scopes:       scopes,
+	}}
+	data, err := json.Marshal(creds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(home, ".claude")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".credentials.json"), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckAuth(t *testing.T) {
+	valid := time.Now().Add(time.Hour)
+	expired := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		scopes    []string
+		apiKey    string
+		healthy   bool
+	}{
+		{"no credentials", valid, nil, "", false},
+		{"API key only", valid, nil, "sk-ant-api03-secret-key", true},
+		{"valid OAuth", valid, RequiredOAuthScopes, "", true},
+		{"expired OAuth", expired, RequiredOAuthScopes, "", false},
+		{"OAuth missing scopes", valid, []string{"user:profile"}, "", false},
+		{"expired OAuth with an API key", expired, RequiredOAuthScopes, "sk-ant-api03-secret-key", true},
+	}
+	for _, tt := range tests {
+		withCredentials(t, tt.expiresAt, tt.scopes, tt.apiKey)
+		check := CheckAuth(false)
+
+		if check.Healthy != tt.healthy {
+			t.Errorf("%s: healthy = %v, want %v (%+v)", tt.name, check.Healthy, tt.healthy, check)
+		}
+		if check.Connection != nil {
+			t.Errorf("%s: connection tested without connect", tt.name)
+		}
+		if tt.name == "expired OAuth" && !check.OAuthExpired {
+			t.Errorf("%s: expiry not reported", tt.name)
+		}
+		if tt.name == "OAuth missing scopes" && len(check.MissingScopes) == 0 {
+			t.Errorf("%s: missing scopes not reported", tt.name)
+		}
+
+		data, err := json.Marshal(check)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(data), "secret") {
+			t.Errorf("%s: JSON output holds a credential: %s", tt.name, data)
+		}
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"os/exec"
 	"path/filepath"
 	"time"
+
+	"github.com/kumaraguru1735/shadow/internal/output"
 )
 
 // AuthManager handles authentication generation and management
@@ -43,14 +45,94 @@ type ClaudeCredentials struct {
 
 // AuthStatus represents the current authentication status
 type AuthStatus struct {
-	HasOAuth       bool
-	HasAPIKey      bool
-	OAuthPath      string
-	OAuthExpired   bool
-	ExpiresIn      time.Duration
-	Subscription   string
-	RateLimitTier  string
-	Scopes         []string
+	HasOAuth        bool
+	HasAPIKey       bool
+	OAuthPath       string
+	OAuthExpired    bool
+	ExpiryUncertain bool // expiry is within the clock skew tolerance either way
+	ExpiresIn       time.Duration
+	Subscription    string
+	RateLimitTier   string
+	Scopes          []string
+}
+
+// MarshalJSON emits the status for monitoring, with ExpiresIn in whole
+// seconds. AuthStatus never holds token values, so none can leak here.
+func (s AuthStatus) MarshalJSON() ([]byte, error) {
+	scopes := s.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	return json.Marshal(struct {
+		Authenticated    bool     `json:"authenticated"`
+		HasOAuth         bool     `json:"has_oauth"`
+		HasAPIKey        bool     `json:"has_api_key"`
+		OAuthPath        string   `json:"oauth_path,omitempty"`
+		OAuthExpired     bool     `json:"oauth_expired"`
+		ExpiryUncertain  bool     `json:"expiry_uncertain"`
+		ExpiresInSeconds int64    `json:"expires_in_seconds"`
+		Subscription     string   `json:"subscription,omitempty"`
+		RateLimitTier    string   `json:"rate_limit_tier,omitempty"`
+		Scopes           []string `json:"scopes"`
+		MissingScopes    []string `json:"missing_scopes,omitempty"`
+	}{
+		Authenticated:    s.HasAPIKey || (s.HasOAuth && !s.OAuthExpired),
+		HasOAuth:         s.HasOAuth,
+		HasAPIKey:        s.HasAPIKey,
+		OAuthPath:        s.OAuthPath,
+		OAuthExpired:     s.OAuthExpired,
+		ExpiryUncertain:  s.ExpiryUncertain,
+		ExpiresInSeconds: int64(s.ExpiresIn / time.Second),
+		Subscription:     s.Subscription,
+		RateLimitTier:    s.RateLimitTier,
+		Scopes:           scopes,
+		MissingScopes:    s.MissingScopes(),
+	})
+}
+
+// RequiredOAuthScopes lists the scopes an OAuth token needs to call the
+// Anthropic Messages API. user:inference grants model inference; tokens
+// without it (e.g. profile-only logins) are rejected on every request.
+var RequiredOAuthScopes = []string{"user:inference"}
+
+// MissingScopes returns the required OAuth scopes the token lacks. Older
+// credential files don't record scopes at all; those aren't reported.
+func (s *AuthStatus) MissingScopes() []string {
+	if !s.HasOAuth || len(s.Scopes) == 0 {
+		return nil
+	}
+
+	granted := make(map[string]bool, len(s.Scopes))
+	for _, scope := range s.Scopes {
+		granted[scope] = true
+	}
+
+	var missing []string
+	for _, scope := range RequiredOAuthScopes {
+		if !granted[scope] {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
+
+// CheckOAuthScopes fails early, with a fix, when the OAuth token can't be used
+// for analysis and no API key is available as an alternative
+func (m *AuthManager) CheckOAuthScopes() error {
+	status, err := m.GetAuthStatus()
+	if err != nil {
+		return err
+	}
+
+	missing := status.MissingScopes()
+	if len(missing) == 0 || status.HasAPIKey {
+		return nil
+	}
+
+	return fmt.Errorf("OAuth token is missing required scope(s) %v (granted: %v)\n"+
+		"   💡 Log in again with Claude Code (claude /login) to get a token with inference access,\n"+
+		"      or set ANTHROPIC_API_KEY", missing, status.Scopes)
 }
 
 // GetAuthStatus checks the current authentication status
@@ -72,11 +154,15 @@ func (m *AuthManager) GetAuthStatus() (*AuthStatus, error) {
 				expiresAt := time.Unix(creds.ClaudeAiOauth.ExpiresAt/1000, 0)
 				now := time.Now()
 
-				if expiresAt.Before(now) {
+				// A token that expired only moments ago may still be valid
+				// if the local clock runs fast, and vice versa
+				remaining := expiresAt.Sub(now)
+				if remaining <= -clockSkewTolerance {
 					status.OAuthExpired = true
-				} else {
-					status.ExpiresIn = expiresAt.Sub(now)
+				} else if remaining > 0 {
+					status.ExpiresIn = remaining
 				}
+				status.ExpiryUncertain = remaining > -clockSkewTolerance && remaining < clockSkewTolerance
 
 				status.Subscription = creds.ClaudeAiOauth.SubscriptionType
 				status.RateLimitTier = creds.ClaudeAiOauth.RateLimitTier
@@ -152,6 +238,10 @@ scanning:
   threads: 50
   timeout: 30s
   rate_limit: 100
+  max_evidence_length: 4096
+  privileged_tools: [nmap, masscan, naabu]  # only these may be run with sudo (names or absolute paths)
+  tool_timeout: 0s  # per external tool in smart-scan; 0s uses the plan's time estimate
+  severity_overrides: {}  # finding type to severity, e.g. {security-header: high}
 
 # AI Analysis Configuration
 ai:
@@ -159,6 +249,13 @@ ai:
   auto_analyze: false
   retry_attempts: 3
   retry_delay: 15s
+  max_concurrent_requests: 2  # AI requests in flight at once, across all agents
+  clock_skew_tolerance: 2m    # slack around OAuth token expiry for clock drift
+  cost_confirm_threshold: 1.0 # USD; deep/research runs estimated above this ask to confirm
+  structured_analysis: false  # ask agents for a JSON analysis instead of parsing markdown
+  max_prompt_tokens: 150000   # estimated ceiling per prompt; larger finding sets fail before any AI call
+  analysis_timeout: 10m       # per analysis call; raise on slower tiers or huge scans
+  query_timeout: 5m           # per query, retries included
 `
 
 	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
@@ -197,8 +294,9 @@ func (m *AuthManager) RefreshOAuth() error {
 	return nil
 }
 
-// ShowOAuthToken displays OAuth token information (masked)
-func (m *AuthManager) ShowOAuthToken() error {
+// ShowOAuthToken displays OAuth token information. Tokens are masked unless
+// reveal is set; callers must confirm with the user before revealing.
+func (m *AuthManager) ShowOAuthToken(reveal bool) error {
 	claudeCredsPath := filepath.Join(m.homeDir, ".claude", ".credentials.json")
 
 	data, err := os.ReadFile(claudeCredsPath)
@@ -215,24 +313,30 @@ func (m *AuthManager) ShowOAuthToken() error {
 	accessToken := creds.ClaudeAiOauth.AccessToken
 	refreshToken := creds.ClaudeAiOauth.RefreshToken
 
-	if len(accessToken) > 20 {
-		accessToken = accessToken[:20] + "..."
-	}
-	if len(refreshToken) > 20 {
-		refreshToken = refreshToken[:20] + "..."
+	if !reveal {
+		accessToken = maskToken(accessToken)
+		refreshToken = maskToken(refreshToken)
 	}
 
-	fmt.Println("OAuth Token Information:")
-	fmt.Printf("  Access Token:  %s\n", accessToken)
-	fmt.Printf("  Refresh Token: %s\n", refreshToken)
-	fmt.Printf("  Expires At:    %s\n", time.Unix(creds.ClaudeAiOauth.ExpiresAt/1000, 0).Format(time.RFC3339))
-	fmt.Printf("  Scopes:        %v\n", creds.ClaudeAiOauth.Scopes)
-	fmt.Printf("  Subscription:  %s\n", creds.ClaudeAiOauth.SubscriptionType)
-	fmt.Printf("  Rate Tier:     %s\n", creds.ClaudeAiOauth.RateLimitTier)
+	output.Println("OAuth Token Information:")
+	output.Printf("  Access Token:  %s\n", accessToken)
+	output.Printf("  Refresh Token: %s\n", refreshToken)
+	output.Printf("  Expires At:    %s\n", time.Unix(creds.ClaudeAiOauth.ExpiresAt/1000, 0).Format(time.RFC3339))
+	output.Printf("  Scopes:        %v\n", creds.ClaudeAiOauth.Scopes)
+	output.Printf("  Subscription:  %s\n", creds.ClaudeAiOauth.SubscriptionType)
+	output.Printf("  Rate Tier:     %s\n", creds.ClaudeAiOauth.RateLimitTier)
 
 	return nil
 }
 
+// maskToken keeps only the first 20 characters of a token
+func maskToken(token string) string {
+	if len(token) > 20 {
+		return token[:20] + "..."
+	}
+	return token
+}
+
 // SetupAPIKey helps setup API key authentication
 func (m *AuthManager) SetupAPIKey(apiKey string) error {
 	shadowDir := filepath.Join(m.homeDir, ".shadow")
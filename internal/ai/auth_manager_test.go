@@ -0,0 +1,186 @@
+package ai
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testAuthManager returns an AuthManager whose home directory holds the
+// given OAuth credentials
+func testAuthManager(t *testing.T, oauth OAuthCredentials) *AuthManager {
+	t.Helper()
+	home := t.TempDir()
+	data, err := json.Marshal(ClaudeCredentials{ClaudeAiOauth: oauth})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(home, ".claude"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".claude", ".credentials.json"), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return &AuthManager{homeDir: home}
+}
+
+// captureStdout returns everything fn prints to stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- string(data)
+	}()
+	fn()
+	w.Close()
+	return <-done
+}
+
+func TestMaskToken(t *testing.T) {
+	tests := []struct {
+		token, want string
+	}{
+		{"short", "short"},
+		{"12345678901234567890", "12345678901234567890"},
+		{"sk-ant-REDACTED", "sk-ant-oat01-abcdefg..."},
+	}
+	for _, tt := range tests {
+		if got := maskToken(tt.token); got != tt.want {
+			t.Errorf("maskToken(%q) = %q, want %q", tt.token, got, tt.want)
+		}
+	}
+}
+
+func TestShowOAuthToken(t *testing.T) {
+	const access = "sk-ant-REDACTED"
+	const refresh = "sk-ant-REDACTED"
+	manager := testAuthManager(t, OAuthCredentials{AccessToken: access, RefreshToken: refresh})
+
+	masked := captureStdout(t, func() {
+		if err := manager.ShowOAuthToken(false); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if strings.Contains(masked, access) || strings.Contains(masked, refresh) {
+		t.Errorf("masked output leaks a full token:\n%s", masked)
+	}
+	if !strings.Contains(masked, maskToken(access)) {
+		t.Errorf("masked output missing %q:\n%s", maskToken(access), masked)
+	}
+
+	revealed := captureStdout(t, func() {
+		if err := manager.ShowOAuthToken(true); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(revealed, access) || !strings.Contains(revealed, refresh) {
+		t.Errorf("revealed output missing the full tokens:\n%s", revealed)
+	}
+}
+
+func TestShowOAuthTokenWithoutCredentials(t *testing.T) {
+	manager := &AuthManager{homeDir: t.TempDir()}
+	if err := manager.ShowOAuthToken(false); err == nil {
+		t.Error("ShowOAuthToken succeeded without a credentials file")
+	}
+}
+
+func TestMissingScopes(t *testing.T) {
+	tests := []struct {
+		name   string
+		status AuthStatus
+		want   []string
+	}{
+		{"inference granted", AuthStatus{HasOAuth: true, Scopes: []string{"user:profile", "user:inference"}}, nil},
+		{"profile only", AuthStatus{HasOAuth: true, Scopes: []string{"user:profile"}}, []string{"user:inference"}},
+		{"scopes not recorded", AuthStatus{HasOAuth: true}, nil},
+		{"no oauth", AuthStatus{Scopes: []string{"user:profile"}}, nil},
+	}
+	for _, tt := range tests {
+		got := tt.status.MissingScopes()
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCheckOAuthScopes(t *testing.T) {
+	profileOnly := OAuthCredentials{AccessToken: "token", Scopes: []string{"user:profile"}}
+
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	err := testAuthManager(t, profileOnly).CheckOAuthScopes()
+	if err == nil || !strings.Contains(err.Error(), "user:inference") {
+		t.Errorf("profile-only token: got %v, want a missing user:inference error", err)
+	}
+
+	granted := OAuthCredentials{AccessToken: "token", Scopes: []string{"user:inference"}}
+	if err := testAuthManager(t, granted).CheckOAuthScopes(); err != nil {
+		t.Errorf("inference token: %v", err)
+	}
+
+	t.Setenv("ANTHROPIC_API_KEY", "sk-ant-test")
+	if err := testAuthManager(t, profileOnly).CheckOAuthScopes(); err != nil {
+		t.Errorf("profile-only token with an API key: %v", err)
+	}
+}
+
+func TestAuthStatusJSON(t *testing.T) {
+	const access = "sk-ant-oat01-never-in-json"
+	manager := testAuthManager(t, OAuthCredentials{
+		AccessToken:      access,
+		RefreshToken:     "sk-ant-ort01-never-in-json",
+		ExpiresAt:        time.Now().Add(90 * time.Minute).UnixMilli(),
+		Scopes:           []string{"user:profile"},
+		SubscriptionType: "max",
+	})
+	t.Setenv("ANTHROPIC_API_KEY", "")
+
+	status, err := manager.GetAuthStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(status)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "never-in-json") {
+		t.Fatalf("JSON leaks a token: %s", data)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["authenticated"] != true || got["has_oauth"] != true || got["has_api_key"] != false || got["subscription"] != "max" {
+		t.Errorf("unexpected status fields: %s", data)
+	}
+	if seconds := got["expires_in_seconds"].(float64); seconds < 80*60 || seconds > 90*60 {
+		t.Errorf("expires_in_seconds = %v, want about 5400", seconds)
+	}
+	if missing, _ := got["missing_scopes"].([]interface{}); len(missing) != 1 || missing[0] != "user:inference" {
+		t.Errorf("missing_scopes = %v, want [user:inference]", got["missing_scopes"])
+	}
+}
+
+func TestAuthStatusJSONUnauthenticated(t *testing.T) {
+	data, err := json.Marshal(AuthStatus{HasOAuth: true, OAuthExpired: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"authenticated":false`) || !strings.Contains(string(data), `"scopes":[]`) {
+		t.Errorf("got %s, want unauthenticated with an empty scopes list", data)
+	}
+}
@@ -2,21 +2,75 @@ package ai
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	pi "github.com/joshp123/pi-golang"
+	"github.com/kumaraguru1735/shadow/internal/output"
 	"github.com/kumaraguru1735/shadow/pkg/models"
 )
 
 // AutonomousSecurityResearcher conducts iterative security research
 type AutonomousSecurityResearcher struct {
-	client        *pi.OneShotClient
+	client        researchClient
 	findings      []models.Finding
 	hypotheses    []SecurityHypothesis
 	investigations []Investigation
 	maxIterations int
+	model         string
+	thinking      string
+
+	resumed    []ResearchIteration                   // completed iterations of an earlier run
+	checkpoint func(iterations []ResearchIteration) error // called after every iteration
+}
+
+// ResearcherOptions choose the model and thinking depth of an autonomous
+// researcher. The zero value is ResearchModel at "high" thinking; Sonnet
+// at "low" is far cheaper for routine runs.
+type ResearcherOptions struct {
+	Model    string // a model ID, or opus, sonnet or haiku
+	Thinking string // "low" or "high"
+}
+
+// researchModelAliases are the short names ResearcherOptions.Model accepts
+var researchModelAliases = map[string]string{
+	"opus":   "claude-opus-4.6",
+	"sonnet": "claude-sonnet-4.5",
+	"haiku":  "claude-haiku-4.5",
+}
+
+// Resolve fills in the defaults, expands model aliases and rejects models
+// and thinking levels pi can't run
+func (o ResearcherOptions) Resolve() (ResearcherOptions, error) {
+	model := strings.ToLower(strings.TrimSpace(o.Model))
+	if model == "" {
+		model = ResearchModel
+	}
+	if full, ok := researchModelAliases[model]; ok {
+		model = full
+	}
+	if _, ok := modelPricing[model]; !ok {
+		return o, fmt.Errorf("unknown research model %q (use opus, sonnet, haiku or a full model ID)", o.Model)
+	}
+
+	thinking := strings.ToLower(strings.TrimSpace(o.Thinking))
+	switch thinking {
+	case "":
+		thinking = "high"
+	case "low", "high":
+	default:
+		return o, fmt.Errorf("unknown thinking level %q (use low or high)", o.Thinking)
+	}
+
+	return ResearcherOptions{Model: model, Thinking: thinking}, nil
+}
+
+// researchClient is the part of the pi client the researcher uses
+type researchClient interface {
+	promptRunner
+	Close() error
 }
 
 // SecurityHypothesis represents AI's theory about potential vulnerabilities
@@ -40,15 +94,40 @@ type Investigation struct {
 	Timestamp    time.Time
 }
 
-// NewAutonomousSecurityResearcher creates an autonomous AI security researcher
-func NewAutonomousSecurityResearcher() (*AutonomousSecurityResearcher, error) {
+// NewAutonomousSecurityResearcher creates an autonomous AI security
+// researcher on the model and thinking depth chosen by options
+func NewAutonomousSecurityResearcher(options ResearcherOptions) (*AutonomousSecurityResearcher, error) {
+	options, err := options.Resolve()
+	if err != nil {
+		return nil, err
+	}
+
+	client, _, err := startClient(researcherOneShotOptions(options))
+	if err != nil {
+		return nil, err
+	}
+
+	return &AutonomousSecurityResearcher{
+		client:        client,
+		findings:      make([]models.Finding, 0),
+		hypotheses:    make([]SecurityHypothesis, 0),
+		investigations: make([]Investigation, 0),
+		maxIterations: 5,
+		model:         options.Model,
+		thinking:      options.Thinking,
+	}, nil
+}
+
+// researcherOneShotOptions builds the pi client options for resolved
+// researcher options
+func researcherOneShotOptions(options ResearcherOptions) pi.OneShotOptions {
 	opts := pi.DefaultOneShotOptions()
 	opts.AppName = "shadow-autonomous-researcher"
 	opts.Mode = pi.ModeDragons
 	opts.Dragons = pi.DragonsOptions{
 		Provider: "anthropic",
-		Model:    "claude-opus-4.6", // Use most capable model for deep thinking
-		Thinking: "high",             // Maximum thinking depth
+		Model:    options.Model,
+		Thinking: options.Thinking,
 	}
 
 	opts.SystemPrompt = `You are an elite autonomous security researcher and threat hunter.
@@ -89,18 +168,20 @@ You reason like a real attacker:
 
 Be thorough, creative, and think outside the box.`
 
-	client, err := pi.StartOneShot(opts)
-	if err != nil {
-		return nil, err
-	}
+	return opts
+}
 
-	return &AutonomousSecurityResearcher{
-		client:        client,
-		findings:      make([]models.Finding, 0),
-		hypotheses:    make([]SecurityHypothesis, 0),
-		investigations: make([]Investigation, 0),
-		maxIterations: 5,
-	}, nil
+// ResumeFrom makes the next run reuse iterations completed by an earlier
+// run of the same target instead of running them again
+func (asr *AutonomousSecurityResearcher) ResumeFrom(iterations []ResearchIteration) {
+	asr.resumed = iterations
+}
+
+// SetCheckpoint has every completed iteration reported to save, with all
+// iterations so far, so they survive a later failure. A failed save is
+// reported through progress but doesn't stop the run.
+func (asr *AutonomousSecurityResearcher) SetCheckpoint(save func(iterations []ResearchIteration) error) {
+	asr.checkpoint = save
 }
 
 // ConductAutonomousResearch performs iterative AI-driven security research
@@ -125,67 +206,94 @@ func (asr *AutonomousSecurityResearcher) ConductAutonomousResearch(
 		Iterations:     make([]ResearchIteration, 0),
 	}
 
-	// Iteration 1: Initial Analysis & Hypothesis Generation
-	if progress != nil {
-		progress("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-		progress("🔬 ITERATION 1: Initial Analysis & Hypothesis Generation")
-		progress("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	}
-
-	iteration1, err := asr.initialAnalysis(ctx, target, initialFindings, progress)
-	if err != nil {
-		return nil, fmt.Errorf("iteration 1 failed: %w", err)
-	}
-	report.Iterations = append(report.Iterations, *iteration1)
-
-	// Iteration 2: Backdoor & Hidden Threat Detection
-	if progress != nil {
-		progress("")
-		progress("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-		progress("🔬 ITERATION 2: Backdoor & Hidden Threat Detection")
-		progress("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	}
+	// Each iteration builds on the previous one's output
+	iterations := []struct {
+		title string
+		run   func(previous *ResearchIteration) (*ResearchIteration, error)
+	}{
+		{"Initial Analysis & Hypothesis Generation", func(*ResearchIteration) (*ResearchIteration, error) {
+			return asr.initialAnalysis(ctx, target, initialFindings, progress)
+		}},
+		{"Backdoor & Hidden Threat Detection", func(previous *ResearchIteration) (*ResearchIteration, error) {
+			return asr.backdoorDetection(ctx, target, previous.Findings, progress)
+		}},
+		{"Attack Path & Exploitation Analysis", func(previous *ResearchIteration) (*ResearchIteration, error) {
+			return asr.attackPathAnalysis(ctx, target, previous.Findings, progress)
+		}},
+		{"Deep Dive Investigations", func(previous *ResearchIteration) (*ResearchIteration, error) {
+			return asr.deepDiveInvestigation(ctx, target, previous.NewHypotheses, progress)
+		}},
+	}
+
+	var previous *ResearchIteration
+	for i, step := range iterations {
+		if i < len(asr.resumed) {
+			iteration := asr.resumed[i]
+			if progress != nil {
+				progress(fmt.Sprintf("⏭️  ITERATION %d: %s (resumed from the saved run)", i+1, step.title))
+			}
+			report.Iterations = append(report.Iterations, iteration)
+			previous = &iteration
+			continue
+		}
 
-	iteration2, err := asr.backdoorDetection(ctx, target, iteration1.Findings, progress)
-	if err != nil {
-		return nil, fmt.Errorf("iteration 2 failed: %w", err)
-	}
-	report.Iterations = append(report.Iterations, *iteration2)
+		// A cancelled run (Ctrl-C, deadline) keeps the completed iterations
+		if ctx.Err() != nil {
+			return asr.finish(report, researchStoppedNote(ctx.Err(), i, len(iterations)), progress), nil
+		}
 
-	// Iteration 3: Attack Path & Exploitation Analysis
-	if progress != nil {
-		progress("")
-		progress("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-		progress("🔬 ITERATION 3: Attack Path & Exploitation Analysis")
-		progress("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	}
+		if progress != nil {
+			if i > 0 {
+				progress("")
+			}
+			progress("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+			progress(fmt.Sprintf("🔬 ITERATION %d: %s", i+1, step.title))
+			progress("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		}
 
-	iteration3, err := asr.attackPathAnalysis(ctx, target, iteration2.Findings, progress)
-	if err != nil {
-		return nil, fmt.Errorf("iteration 3 failed: %w", err)
-	}
-	report.Iterations = append(report.Iterations, *iteration3)
+		iteration, err := step.run(previous)
+		if err != nil && ctx.Err() != nil {
+			return asr.finish(report, researchStoppedNote(ctx.Err(), i, len(iterations)), progress), nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("iteration %d failed: %w", i+1, err)
+		}
+		report.Iterations = append(report.Iterations, *iteration)
+		previous = iteration
 
-	// Iteration 4: Deep Dive Investigations
-	if progress != nil {
-		progress("")
-		progress("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-		progress("🔬 ITERATION 4: Deep Dive Investigations")
-		progress("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		if asr.checkpoint != nil {
+			if err := asr.checkpoint(report.Iterations); err != nil && progress != nil {
+				progress(fmt.Sprintf("⚠️  Could not save iteration %d: %v", i+1, err))
+			}
+		}
 	}
 
-	iteration4, err := asr.deepDiveInvestigation(ctx, target, iteration3.NewHypotheses, progress)
-	if err != nil {
-		return nil, fmt.Errorf("iteration 4 failed: %w", err)
-	}
-	report.Iterations = append(report.Iterations, *iteration4)
+	return asr.finish(report, "", progress), nil
+}
 
-	// Final Summary
+// finish closes the report over its completed iterations. note explains
+// why the run stopped early, if it did.
+func (asr *AutonomousSecurityResearcher) finish(report *AutonomousResearchReport, note string, progress ProgressCallback) *AutonomousResearchReport {
 	report.EndTime = time.Now()
 	report.TotalDuration = report.EndTime.Sub(report.StartTime)
 	report.FinalConclusions = asr.synthesizeFindings(report.Iterations)
+	report.Note = note
+
+	if note != "" && progress != nil {
+		progress("")
+		progress("⏹  " + note)
+	}
+	return report
+}
 
-	return report, nil
+// researchStoppedNote explains a research run cut short by err after
+// completed of total iterations
+func researchStoppedNote(err error, completed int, total int) string {
+	reason := "the research was interrupted"
+	if errors.Is(err, context.DeadlineExceeded) {
+		reason = "the analysis deadline was reached"
+	}
+	return fmt.Sprintf("Partial research: %s after %d of %d iterations", reason, completed, total)
 }
 
 // initialAnalysis - AI thinks about initial findings
@@ -258,10 +366,10 @@ Think like an attacker. What would YOU target? What looks suspicious?
 [Concrete actions to take]`, target, formatFindingsDetailed(findings))
 
 	// Show full transparency about what AI is being asked
-	logAIActivity(progress, "Sending prompt to Claude Opus 4.6", map[string]string{
+	logAIActivity(progress, "Sending prompt to "+asr.model, map[string]string{
 		"🔧 Tool":        "Claude API (Anthropic)",
-		"🎯 Model":       "claude-opus-4.6",
-		"🧠 Thinking":    "HIGH (extended reasoning enabled)",
+		"🎯 Model":       asr.model,
+		"🧠 Thinking":    strings.ToUpper(asr.thinking),
 		"⏱️  Timeout":    "10 minutes",
 		"📊 Input size":  fmt.Sprintf("%d characters", len(prompt)),
 		"🔒 Security":    "Read-only analysis, no code execution",
@@ -308,13 +416,13 @@ Think like an attacker. What would YOU target? What looks suspicious?
 					return
 				case <-ticker.C:
 					elapsed := time.Since(startTime)
-					progress(fmt.Sprintf("   ⏱️  Still working... %.0f seconds elapsed (Opus thinking deeply...)", elapsed.Seconds()))
+					progress(fmt.Sprintf("   ⏱️  Still working... %.0f seconds elapsed (%s thinking...)", elapsed.Seconds(), asr.model))
 				}
 			}
 		}()
 	}
 
-	result, err := asr.client.Run(ctx, prompt)
+	result, err := runLimited(ctx, asr.client, prompt)
 
 	if done != nil {
 		close(done)
@@ -374,7 +482,7 @@ func (asr *AutonomousSecurityResearcher) backdoorDetection(
 	logAIActivity(progress, "Backdoor Detection Analysis", map[string]string{
 		"🎯 Focus":      "Hidden backdoors, malicious code, debug endpoints",
 		"🔍 Method":     "Pattern recognition, anomaly detection",
-		"🧠 AI Model":   "Claude Opus 4.6",
+		"🧠 AI Model":   asr.model,
 		"⚠️  Warning":   "AI analyzes only - does NOT execute any code",
 	})
 
@@ -452,7 +560,7 @@ Look for indicators of:
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
 	defer cancel()
 
-	result, err := asr.client.Run(ctx, prompt)
+	result, err := runLimited(ctx, asr.client, prompt)
 	if err != nil {
 		return nil, err
 	}
@@ -559,7 +667,7 @@ Impact: [low/medium/high/critical]
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
 	defer cancel()
 
-	result, err := asr.client.Run(ctx, prompt)
+	result, err := runLimited(ctx, asr.client, prompt)
 	if err != nil {
 		return nil, err
 	}
@@ -664,7 +772,7 @@ Think at the DEEPEST level. Consider edge cases, race conditions, timing issues.
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
 	defer cancel()
 
-	result, err := asr.client.Run(ctx, prompt)
+	result, err := runLimited(ctx, asr.client, prompt)
 	if err != nil {
 		return nil, err
 	}
@@ -685,15 +793,8 @@ Think at the DEEPEST level. Consider edge cases, race conditions, timing issues.
 	return iteration, nil
 }
 
-// ResearchIteration represents one iteration of autonomous research
-type ResearchIteration struct {
-	Number        int
-	Phase         string
-	Findings      string
-	NewHypotheses []string
-	NextSteps     []string
-	Timestamp     time.Time
-}
+// ResearchIteration is the outcome of one research iteration
+type ResearchIteration = models.ResearchIteration
 
 // AutonomousResearchReport contains complete research results
 type AutonomousResearchReport struct {
@@ -703,6 +804,23 @@ type AutonomousResearchReport struct {
 	TotalDuration    time.Duration
 	Iterations       []ResearchIteration
 	FinalConclusions string
+	Note             string // why the run stopped before all iterations, if it did
+}
+
+// Summary condenses the report for storing with a scan result
+func (r *AutonomousResearchReport) Summary() *models.ResearchSummary {
+	phases := make([]string, 0, len(r.Iterations))
+	for _, iteration := range r.Iterations {
+		phases = append(phases, iteration.Phase)
+	}
+
+	return &models.ResearchSummary{
+		Phases:      phases,
+		Conclusions: r.FinalConclusions,
+		Note:        r.Note,
+		Duration:    r.TotalDuration,
+		Timestamp:   r.EndTime,
+	}
 }
 
 // Helper functions
@@ -720,7 +838,8 @@ func formatFindingsDetailed(findings []models.Finding) string {
 			result.WriteString(fmt.Sprintf("   Description: %s\n", finding.Description))
 		}
 		if finding.Evidence != "" {
-			result.WriteString(fmt.Sprintf("   Evidence: %s\n", finding.Evidence))
+			evidence, _ := models.TruncateEvidence(finding.Evidence, models.DefaultMaxEvidenceLength)
+			result.WriteString(fmt.Sprintf("   Evidence: %s\n", evidence))
 		}
 		if finding.Location != "" {
 			result.WriteString(fmt.Sprintf("   Location: %s\n", finding.Location))
@@ -807,18 +926,18 @@ func (asr *AutonomousSecurityResearcher) synthesizeFindings(iterations []Researc
 
 // PrintReport displays the complete autonomous research report
 func (report *AutonomousResearchReport) PrintReport() {
-	fmt.Println("\n🧠 Autonomous Security Research Report")
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Printf("\n🎯 Target: %s\n", report.Target)
-	fmt.Printf("⏱️  Duration: %v\n", report.TotalDuration.Round(time.Second))
-	fmt.Printf("🔬 Iterations: %d\n", len(report.Iterations))
+	output.Println("\n🧠 Autonomous Security Research Report")
+	output.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	output.Printf("\n🎯 Target: %s\n", report.Target)
+	output.Printf("⏱️  Duration: %v\n", report.TotalDuration.Round(time.Second))
+	output.Printf("🔬 Iterations: %d\n", len(report.Iterations))
 
 	for _, iteration := range report.Iterations {
-		fmt.Printf("\n━━ Iteration %d: %s ━━\n", iteration.Number, iteration.Phase)
-		fmt.Println(iteration.Findings[:min(500, len(iteration.Findings))] + "...")
+		output.Printf("\n━━ Iteration %d: %s ━━\n", iteration.Number, iteration.Phase)
+		output.Println(iteration.Findings[:min(500, len(iteration.Findings))] + "...")
 	}
 
-	fmt.Println("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	output.Println("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 }
 
 func min(a, b int) int {
@@ -828,10 +947,12 @@ func min(a, b int) int {
 	return b
 }
 
-// Close closes the researcher
+// Close closes the researcher. It is safe to call more than once, e.g.
+// deferred after a cancelled run.
 func (asr *AutonomousSecurityResearcher) Close() {
 	if asr.client != nil {
 		asr.client.Close()
+		asr.client = nil
 	}
 }
 
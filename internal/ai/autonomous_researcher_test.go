@@ -0,0 +1,171 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	pi "github.com/joshp123/pi-golang"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+// scriptedResearchClient answers research prompts, calling onRun before
+// each answer
+type scriptedResearchClient struct {
+	calls  int
+	closed int
+	onRun  func(call int) error
+}
+
+func (c *scriptedResearchClient) Run(ctx context.Context, message string) (pi.RunResult, error) {
+	c.calls++
+	if c.onRun != nil {
+		if err := c.onRun(c.calls); err != nil {
+			return pi.RunResult{}, err
+		}
+	}
+	return pi.RunResult{Text: "## Findings\n- Debug endpoint exposed"}, nil
+}
+
+func (c *scriptedResearchClient) Close() error {
+	c.closed++
+	return nil
+}
+
+func TestAutonomousResearchReportSummary(t *testing.T) {
+	end := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	report := &AutonomousResearchReport{
+		EndTime:       end,
+		TotalDuration: 3 * time.Minute,
+		Iterations: []ResearchIteration{
+			{Number: 1, Phase: "Reconnaissance"},
+			{Number: 2, Phase: "Hypothesis Testing"},
+		},
+		FinalConclusions: "No exploitable issues.",
+	}
+
+	summary := report.Summary()
+	if strings.Join(summary.Phases, ",") != "Reconnaissance,Hypothesis Testing" {
+		t.Errorf("phases = %v", summary.Phases)
+	}
+	if summary.Conclusions != report.FinalConclusions || summary.Duration != 3*time.Minute || !summary.Timestamp.Equal(end) {
+		t.Errorf("summary = %+v", summary)
+	}
+}
+
+func TestResearcherOptionsResolve(t *testing.T) {
+	tests := []struct {
+		options         ResearcherOptions
+		model, thinking string
+	}{
+		{ResearcherOptions{}, ResearchModel, "high"},
+		{ResearcherOptions{Model: " Sonnet ", Thinking: "LOW"}, "claude-sonnet-4.5", "low"},
+		{ResearcherOptions{Model: "claude-haiku-4.5"}, "claude-haiku-4.5", "high"},
+	}
+	for _, tt := range tests {
+		got, err := tt.options.Resolve()
+		if err != nil || got.Model != tt.model || got.Thinking != tt.thinking {
+			t.Errorf("%+v: got %+v, %v; want %s/%s", tt.options, got, err, tt.model, tt.thinking)
+		}
+	}
+
+	for _, options := range []ResearcherOptions{{Model: "gpt-4"}, {Thinking: "medium"}} {
+		if _, err := options.Resolve(); err == nil {
+			t.Errorf("%+v resolved without error", options)
+		}
+	}
+}
+
+func TestResearcherOneShotOptions(t *testing.T) {
+	opts := researcherOneShotOptions(ResearcherOptions{Model: "claude-sonnet-4.5", Thinking: "low"})
+	if opts.Dragons.Model != "claude-sonnet-4.5" || opts.Dragons.Thinking != "low" || opts.Dragons.Provider != "anthropic" {
+		t.Errorf("dragons options = %+v", opts.Dragons)
+	}
+	if opts.SystemPrompt == "" {
+		t.Error("system prompt missing")
+	}
+}
+
+func TestConductAutonomousResearchKeepsCompletedIterations(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := &scriptedResearchClient{onRun: func(call int) error {
+		if call == 3 {
+			cancel()
+			return context.Canceled
+		}
+		return nil
+	}}
+	researcher := &AutonomousSecurityResearcher{client: client, model: ResearchModel, thinking: "high"}
+	findings := []models.Finding{{Severity: "medium", Title: "Open TCP port 8080"}}
+
+	report, err := researcher.ConductAutonomousResearch(ctx, "example.com", findings, nil)
+	if err != nil {
+		t.Fatalf("cancelled run returned %v, want the partial report", err)
+	}
+	if len(report.Iterations) != 2 {
+		t.Errorf("got %d iterations, want the 2 completed before the cancel", len(report.Iterations))
+	}
+	if want := "Partial research: the research was interrupted after 2 of 4 iterations"; report.Note != want {
+		t.Errorf("note = %q, want %q", report.Note, want)
+	}
+	if report.Summary().Note != report.Note {
+		t.Error("note not carried into the summary")
+	}
+
+	researcher.Close()
+	researcher.Close()
+	if client.closed != 1 {
+		t.Errorf("client closed %d times, want once", client.closed)
+	}
+}
+
+func TestConductAutonomousResearchFails(t *testing.T) {
+	client := &scriptedResearchClient{onRun: func(call int) error {
+		return errors.New("connection reset")
+	}}
+	researcher := &AutonomousSecurityResearcher{client: client, model: ResearchModel, thinking: "high"}
+	if _, err := researcher.ConductAutonomousResearch(context.Background(), "example.com", nil, nil); err == nil || !strings.Contains(err.Error(), "iteration 1 failed") {
+		t.Errorf("err = %v, want iteration 1 to fail", err)
+	}
+}
+
+func TestConductAutonomousResearchResumes(t *testing.T) {
+	client := &scriptedResearchClient{}
+	researcher := &AutonomousSecurityResearcher{client: client, model: ResearchModel, thinking: "high"}
+	researcher.ResumeFrom([]ResearchIteration{
+		{Number: 1, Phase: "Initial Analysis", Findings: "saved 1"},
+		{Number: 2, Phase: "Backdoor Detection", Findings: "saved 2"},
+		{Number: 3, Phase: "Attack Path Analysis", Findings: "saved 3", NewHypotheses: []string{"debug endpoint"}},
+	})
+
+	var saved [][]ResearchIteration
+	researcher.SetCheckpoint(func(iterations []ResearchIteration) error {
+		saved = append(saved, append([]ResearchIteration(nil), iterations...))
+		return errors.New("disk full") // reported, but the run goes on
+	})
+
+	report, err := researcher.ConductAutonomousResearch(context.Background(), "example.com", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.calls != 1 {
+		t.Errorf("%d AI calls, want only the remaining iteration", client.calls)
+	}
+	if len(report.Iterations) != 4 || report.Iterations[2].Findings != "saved 3" || report.Note != "" {
+		t.Errorf("report = %+v, want the saved iterations and one new one", report)
+	}
+	if len(saved) != 1 || len(saved[0]) != 4 {
+		t.Errorf("checkpoints = %d, want one with all 4 iterations", len(saved))
+	}
+}
+
+func TestResearchStoppedNote(t *testing.T) {
+	if got := researchStoppedNote(context.DeadlineExceeded, 3, 4); got != "Partial research: the analysis deadline was reached after 3 of 4 iterations" {
+		t.Errorf("deadline note = %q", got)
+	}
+}
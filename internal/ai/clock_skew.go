@@ -0,0 +1,77 @@
+package ai
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/kumaraguru1735/shadow/internal/output"
+)
+
+// defaultClockSkewTolerance absorbs small differences between the local
+// clock and the token issuer's clock when judging OAuth expiry
+const defaultClockSkewTolerance = 2 * time.Minute
+
+var clockSkewTolerance = defaultClockSkewTolerance
+
+// SetClockSkewTolerance sets how far either side of the OAuth expiry time a
+// token is treated as "uncertain" rather than valid or expired
+func SetClockSkewTolerance(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	clockSkewTolerance = d
+}
+
+// isAuthError reports whether a model call was rejected for authentication
+func isAuthError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrAuth) {
+		return true
+	}
+
+	message := strings.ToLower(err.Error())
+	for _, pattern := range []string{
+		"401",
+		"unauthorized",
+		"authentication_error",
+		"invalid x-api-key",
+		"token has expired",
+		"token expired",
+		"invalid bearer token",
+	} {
+		if strings.Contains(message, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// refreshAfterAuthFailure handles an auth rejection for a token that looked
+// valid: the local clock may be skewed, so the token is refreshed. It
+// reports whether the call is worth retrying.
+func refreshAfterAuthFailure(err error) bool {
+	if !isAuthError(err) {
+		return false
+	}
+
+	manager, mErr := NewAuthManager()
+	if mErr != nil {
+		return false
+	}
+	status, sErr := manager.GetAuthStatus()
+	if sErr != nil || !status.HasOAuth || status.OAuthExpired {
+		return false
+	}
+
+	output.Printf("⚠️  Authentication rejected although the OAuth token looks valid (expires in %v); "+
+		"the system clock may be skewed. Refreshing token...\n", status.ExpiresIn.Round(time.Second))
+
+	if err := manager.RefreshOAuth(); err != nil {
+		output.Printf("⚠️  OAuth refresh failed: %v\n", err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,82 @@
+package ai
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestIsAuthError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{errors.New("401 Unauthorized"), true},
+		{errors.New(`{"type":"authentication_error"}`), true},
+		{errors.New("OAuth token has expired"), true},
+		{errors.New("rate limit exceeded"), false},
+		{errors.New("connection reset by peer"), false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := isAuthError(tt.err); got != tt.want {
+			t.Errorf("isAuthError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestSetClockSkewTolerance(t *testing.T) {
+	defer SetClockSkewTolerance(defaultClockSkewTolerance)
+
+	SetClockSkewTolerance(-time.Minute)
+	if clockSkewTolerance != 0 {
+		t.Errorf("negative tolerance stored as %v, want 0", clockSkewTolerance)
+	}
+	SetClockSkewTolerance(5 * time.Minute)
+	if clockSkewTolerance != 5*time.Minute {
+		t.Errorf("tolerance = %v, want 5m", clockSkewTolerance)
+	}
+}
+
+func TestGetAuthStatusClockSkew(t *testing.T) {
+	SetClockSkewTolerance(2 * time.Minute)
+	defer SetClockSkewTolerance(defaultClockSkewTolerance)
+
+	tests := []struct {
+		name          string
+		expiresIn     time.Duration
+		wantExpired   bool
+		wantUncertain bool
+	}{
+		{"valid", time.Hour, false, false},
+		{"about to expire", time.Minute, false, true},
+		{"just expired", -time.Minute, false, true},
+		{"expired beyond tolerance", -5 * time.Minute, true, false},
+	}
+	for _, tt := range tests {
+		manager := testAuthManager(t, OAuthCredentials{
+			AccessToken: "token",
+			ExpiresAt:   time.Now().Add(tt.expiresIn).UnixMilli(),
+		})
+		status, err := manager.GetAuthStatus()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status.OAuthExpired != tt.wantExpired || status.ExpiryUncertain != tt.wantUncertain {
+			t.Errorf("%s: expired=%v uncertain=%v, want expired=%v uncertain=%v",
+				tt.name, status.OAuthExpired, status.ExpiryUncertain, tt.wantExpired, tt.wantUncertain)
+		}
+	}
+}
+
+func TestRefreshAfterAuthFailureSkipsOtherErrors(t *testing.T) {
+	if refreshAfterAuthFailure(errors.New("rate limit exceeded")) {
+		t.Error("refresh attempted for a non-auth error")
+	}
+
+	// No OAuth credentials: nothing to refresh
+	t.Setenv("HOME", t.TempDir())
+	if refreshAfterAuthFailure(errors.New("401 Unauthorized")) {
+		t.Error("refresh attempted without OAuth credentials")
+	}
+}
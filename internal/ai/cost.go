@@ -0,0 +1,181 @@
+package ai
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+// assumedOutputTokens is the response size assumed per agent call when
+// estimating up front. Real responses are usually smaller, so estimates
+// err on the expensive side.
+const assumedOutputTokens = 4000
+
+// assumedThinkingTokens is the extended thinking assumed per call at each
+// thinking level. Thinking is billed as output.
+var assumedThinkingTokens = map[string]int64{
+	"low":  2000,
+	"high": 8000,
+}
+
+// ResearchModel is the model autonomous research runs on by default
+const ResearchModel = "claude-opus-4.6"
+
+// ResearchPhases are the iterations an autonomous research run makes, in
+// order, one model call each
+var ResearchPhases = []string{"Initial Analysis", "Backdoor Detection", "Attack Path Analysis", "Deep Dive Investigation"}
+
+// assumedOutputRate is the output tokens per second assumed per model when
+// estimating how long a call takes
+var assumedOutputRate = map[string]float64{
+	"claude-opus-4.6":            30,
+	"claude-sonnet-4.5":          60,
+	"claude-sonnet-4.5-20250929": 60,
+	"claude-haiku-4.5":           120,
+}
+
+// defaultOutputRate is assumed for models missing from assumedOutputRate
+const defaultOutputRate = 50
+
+// CostEstimate is an up-front guess at what an AI run will cost
+type CostEstimate struct {
+	Calls        int
+	InputTokens  int64
+	OutputTokens int64
+	Cost         float64
+}
+
+// String formats the estimate for a confirmation prompt
+func (e CostEstimate) String() string {
+	return fmt.Sprintf("~$%.2f (%d calls, ~%s input / ~%s output tokens)",
+		e.Cost, e.Calls, FormatTokens(e.InputTokens), FormatTokens(e.OutputTokens))
+}
+
+// add accounts for one call to model with the given prompt
+func (e *CostEstimate) add(model string, prompt string, context int64) {
+	e.addCall(model, estimateTokens(prompt)+context, assumedOutputTokens)
+}
+
+// addCall accounts for one call with the given token counts
+func (e *CostEstimate) addCall(model string, input int64, output int64) {
+	stats := UsageStats{
+		Model:        model,
+		InputTokens:  input,
+		OutputTokens: output,
+	}
+
+	e.Calls++
+	e.InputTokens += stats.InputTokens
+	e.OutputTokens += stats.OutputTokens
+	e.Cost += stats.CalculateCost()
+}
+
+// merge adds other's calls, tokens and cost to e
+func (e *CostEstimate) merge(other CostEstimate) {
+	e.Calls += other.Calls
+	e.InputTokens += other.InputTokens
+	e.OutputTokens += other.OutputTokens
+	e.Cost += other.Cost
+}
+
+// estimateTokens approximates token count at roughly 4 characters per token
+func estimateTokens(text string) int64 {
+	return int64(len(text)/4) + 1
+}
+
+// agentModel returns the configured model for an agent type
+func agentModel(agentType models.AgentType) string {
+	if config := models.GetAgentByType(agentType); config != nil {
+		return config.Model
+	}
+	return ""
+}
+
+// EstimateAnalysisCost estimates what AnalyzeScanWithAgents will cost for
+// profile, plus the autonomous research stage for the research profile.
+// Earlier stage outputs fed into later prompts are counted at
+// assumedOutputTokens each. compact matches SetCompactFindings.
+func EstimateAnalysisCost(result *models.ScanResult, profile string, compact bool) CostEstimate {
+	result = result.WithActiveFindings()
+
+	var estimate CostEstimate
+	switch profile {
+	case "quick":
+		estimate.add(agentModel(models.AgentTypeQuickScan), buildAnalysisPrompt(result, true), 0)
+
+	case "deep", "research":
+		estimate.add(agentModel(models.AgentTypeRecon), buildReconPrompt(result, compact), 0)
+		estimate.add(agentModel(models.AgentTypeVulnerability), buildVulnPrompt(result, "", compact), assumedOutputTokens)
+		estimate.add(agentModel(models.AgentTypeExploitation), buildExploitPrompt(result, "", ""), 2*assumedOutputTokens)
+
+	default:
+		estimate.add(agentModel(models.AgentTypeVulnerability), buildAnalysisPrompt(result, compact), 0)
+	}
+
+	if profile == "research" {
+		estimate.merge(EstimateResearchCost(result.Findings, ResearchModel, "high", len(ResearchPhases)).Total)
+	}
+
+	return estimate
+}
+
+// ResearchEstimate is an up-front guess at what an autonomous research run
+// will cost and how long it will take, per iteration and in total
+type ResearchEstimate struct {
+	Model      string
+	Iterations []IterationEstimate
+	Total      CostEstimate
+	Duration   time.Duration
+}
+
+// IterationEstimate is the estimate for one research iteration
+type IterationEstimate struct {
+	Number   int
+	Phase    string
+	Estimate CostEstimate
+	Duration time.Duration
+}
+
+// After returns the estimate without the first completed iterations, for
+// a resumed run
+func (e ResearchEstimate) After(completed int) ResearchEstimate {
+	remaining := ResearchEstimate{Model: e.Model}
+	for _, iteration := range e.Iterations[min(completed, len(e.Iterations)):] {
+		remaining.Iterations = append(remaining.Iterations, iteration)
+		remaining.Total.merge(iteration.Estimate)
+		remaining.Duration += iteration.Duration
+	}
+	return remaining
+}
+
+// EstimateResearchCost estimates an autonomous research run of iterations
+// calls to model at the given thinking level over findings. Each call gets
+// the findings plus the previous iterations' output, so later iterations
+// cost more. Durations assume the model's typical output rate.
+func EstimateResearchCost(findings []models.Finding, model string, thinking string, iterations int) ResearchEstimate {
+	estimate := ResearchEstimate{Model: model}
+	prompt := estimateTokens(formatFindingsDetailed(findings))
+
+	rate, ok := assumedOutputRate[model]
+	if !ok {
+		rate = defaultOutputRate
+	}
+
+	for i := 0; i < iterations; i++ {
+		iteration := IterationEstimate{Number: i + 1, Phase: "Follow-up"}
+		if i < len(ResearchPhases) {
+			iteration.Phase = ResearchPhases[i]
+		}
+
+		output := assumedOutputTokens + assumedThinkingTokens[thinking]
+		iteration.Estimate.addCall(model, prompt+int64(i)*assumedOutputTokens, output)
+		iteration.Duration = time.Duration(float64(output) / rate * float64(time.Second))
+
+		estimate.Iterations = append(estimate.Iterations, iteration)
+		estimate.Total.merge(iteration.Estimate)
+		estimate.Duration += iteration.Duration
+	}
+
+	return estimate
+}
@@ -0,0 +1,150 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+func costTestResult() *models.ScanResult {
+	return &models.ScanResult{
+		ID:     "scan-1",
+		Target: "example.com",
+		Findings: []models.Finding{
+			{Type: "xss", Title: "Reflected XSS", Severity: "high", Evidence: strings.Repeat("<script>", 200)},
+			{Type: "open-port", Title: "Open TCP port 22", Severity: "info"},
+		},
+	}
+}
+
+func TestEstimateTokens(t *testing.T) {
+	if got := estimateTokens(strings.Repeat("a", 400)); got != 101 {
+		t.Errorf("estimateTokens(400 chars) = %d, want 101", got)
+	}
+	if got := estimateTokens(""); got != 1 {
+		t.Errorf("estimateTokens(\"\") = %d, want 1", got)
+	}
+}
+
+func TestEstimateAnalysisCostCalls(t *testing.T) {
+	result := costTestResult()
+	research := len(ResearchPhases)
+	tests := []struct {
+		profile string
+		calls   int
+		output  int64
+	}{
+		{"quick", 1, assumedOutputTokens},
+		{"standard", 1, assumedOutputTokens},
+		{"deep", 3, 3 * assumedOutputTokens},
+		// Research thinks at "high", which is billed as output
+		{"research", 3 + research, 3*assumedOutputTokens + int64(research)*(assumedOutputTokens+assumedThinkingTokens["high"])},
+	}
+	for _, tt := range tests {
+		estimate := EstimateAnalysisCost(result, tt.profile, false)
+		if estimate.Calls != tt.calls {
+			t.Errorf("%s: %d calls, want %d", tt.profile, estimate.Calls, tt.calls)
+		}
+		if estimate.OutputTokens != tt.output || estimate.Cost <= 0 {
+			t.Errorf("%s: estimate = %+v", tt.profile, estimate)
+		}
+	}
+
+	deep := EstimateAnalysisCost(result, "deep", false)
+	full := EstimateAnalysisCost(result, "research", false)
+	if full.Cost <= deep.Cost || deep.Cost <= EstimateAnalysisCost(result, "standard", false).Cost {
+		t.Errorf("costs not ordered standard < deep < research: deep %.4f, research %.4f", deep.Cost, full.Cost)
+	}
+}
+
+func TestEstimateAnalysisCostIgnoresSuppressed(t *testing.T) {
+	result := costTestResult()
+	before := EstimateAnalysisCost(result, "standard", false)
+
+	result.Findings[0].Metadata = map[string]string{models.MetaSuppressed: "true"}
+	if after := EstimateAnalysisCost(result, "standard", false); after.InputTokens >= before.InputTokens {
+		t.Errorf("suppressed evidence still counted: %d input tokens, was %d", after.InputTokens, before.InputTokens)
+	}
+}
+
+func TestCostEstimateString(t *testing.T) {
+	got := CostEstimate{Calls: 3, InputTokens: 12500, OutputTokens: 800, Cost: 1.234}.String()
+	if want := "~$1.23 (3 calls, ~12.5K input / ~800 output tokens)"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestEstimateAnalysisCostCompact(t *testing.T) {
+	result := &models.ScanResult{Target: "example.com"}
+	for i := 0; i < 50; i++ {
+		result.Findings = append(result.Findings, models.Finding{
+			Severity:    "medium",
+			Title:       fmt.Sprintf("Missing security header %d", i),
+			Location:    fmt.Sprintf("https://example.com/page/%d", i),
+			Description: strings.Repeat("The response does not set the header, which leaves browsers without protection. ", 5),
+			Evidence:    strings.Repeat("HTTP/1.1 200 OK\nServer: nginx\n", 100),
+		})
+	}
+
+	full := EstimateAnalysisCost(result, "standard", false)
+	compact := EstimateAnalysisCost(result, "standard", true)
+	if compact.InputTokens*4 > full.InputTokens*3 {
+		t.Errorf("compact estimate %d input tokens, want at least a quarter below full %d", compact.InputTokens, full.InputTokens)
+	}
+}
+
+func TestEstimateResearchCost(t *testing.T) {
+	findings := costTestResult().Findings
+	estimate := EstimateResearchCost(findings, ResearchModel, "high", 6)
+
+	if len(estimate.Iterations) != 6 || estimate.Total.Calls != 6 {
+		t.Fatalf("estimate = %+v, want 6 iterations", estimate)
+	}
+	if estimate.Iterations[0].Phase != ResearchPhases[0] || estimate.Iterations[5].Phase != "Follow-up" {
+		t.Errorf("phases %q and %q", estimate.Iterations[0].Phase, estimate.Iterations[5].Phase)
+	}
+
+	var cost float64
+	var duration time.Duration
+	for i, iteration := range estimate.Iterations {
+		if i > 0 && iteration.Estimate.InputTokens <= estimate.Iterations[i-1].Estimate.InputTokens {
+			t.Errorf("iteration %d doesn't carry the previous output", iteration.Number)
+		}
+		cost += iteration.Estimate.Cost
+		duration += iteration.Duration
+	}
+	if estimate.Total.Cost != cost || estimate.Duration != duration {
+		t.Errorf("totals %.4f / %v, want the sum %.4f / %v", estimate.Total.Cost, estimate.Duration, cost, duration)
+	}
+
+	low := EstimateResearchCost(findings, ResearchModel, "low", 6)
+	if low.Total.OutputTokens >= estimate.Total.OutputTokens {
+		t.Errorf("low thinking output %d not below high %d", low.Total.OutputTokens, estimate.Total.OutputTokens)
+	}
+
+	haiku := EstimateResearchCost(findings, "claude-haiku-4.5", "high", 6)
+	if haiku.Total.Cost >= estimate.Total.Cost || haiku.Duration >= estimate.Duration {
+		t.Errorf("haiku estimate %.4f / %v not below opus %.4f / %v", haiku.Total.Cost, haiku.Duration, estimate.Total.Cost, estimate.Duration)
+	}
+	if got := EstimateResearchCost(findings, "unknown-model", "high", 1).Iterations[0].Duration; got != 240*time.Second {
+		t.Errorf("unknown model duration = %v, want the default rate", got)
+	}
+}
+
+func TestResearchEstimateAfter(t *testing.T) {
+	estimate := EstimateResearchCost(costTestResult().Findings, ResearchModel, "high", 4)
+
+	remaining := estimate.After(3)
+	if len(remaining.Iterations) != 1 || remaining.Iterations[0].Number != 4 || remaining.Total.Calls != 1 {
+		t.Errorf("After(3) = %+v, want only iteration 4", remaining)
+	}
+	if remaining.Total.Cost != estimate.Iterations[3].Estimate.Cost || remaining.Duration != estimate.Iterations[3].Duration {
+		t.Errorf("After(3) totals %.4f / %v", remaining.Total.Cost, remaining.Duration)
+	}
+	if done := estimate.After(9); len(done.Iterations) != 0 || done.Total.Cost != 0 {
+		t.Errorf("After past the end = %+v, want nothing left", done)
+	}
+}
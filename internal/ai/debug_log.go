@@ -0,0 +1,76 @@
+package ai
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kumaraguru1735/shadow/internal/output"
+	"github.com/kumaraguru1735/shadow/internal/redact"
+)
+
+// debugLogger writes each agent's prompt and raw response to disk so the
+// response parsers can be debugged. Secrets are redacted before writing.
+type debugLogger struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// DebugLogDir returns ~/.shadow/debug/<scan-id>
+func DebugLogDir(scanID string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".shadow", "debug", scanID), nil
+}
+
+// record appends one prompt/response exchange to <dir>/<agent>.md
+func (d *debugLogger) record(agent string, prompt string, response string, callErr error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := os.MkdirAll(d.dir, 0700); err != nil {
+		output.Printf("⚠️  AI debug log unavailable: %v\n", err)
+		return
+	}
+
+	var entry strings.Builder
+	entry.WriteString(fmt.Sprintf("# %s - %s\n\n", agent, time.Now().Format(time.RFC3339)))
+	entry.WriteString("## Prompt\n\n```\n" + redact.String(prompt) + "\n```\n\n")
+	if callErr != nil {
+		entry.WriteString("## Error\n\n```\n" + redact.String(callErr.Error()) + "\n```\n\n")
+	} else {
+		entry.WriteString("## Response\n\n```\n" + redact.String(response) + "\n```\n\n")
+	}
+
+	path := filepath.Join(d.dir, debugLogName(agent))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		output.Printf("⚠️  AI debug log unavailable: %v\n", err)
+		return
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(entry.String()); err != nil {
+		output.Printf("⚠️  AI debug log write failed: %v\n", err)
+	}
+}
+
+// debugLogName turns an agent identifier into a safe file name
+func debugLogName(agent string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		default:
+			return '-'
+		}
+	}, agent)
+	return name + ".md"
+}
@@ -0,0 +1,42 @@
+package ai
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDebugLoggerRecordsRedactedExchanges(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "debug", "scan-1")
+	logger := &debugLogger{dir: dir}
+
+	logger.record("Quick Scanner", "Cookie: session=abc123\nFindings: 2", "RISK SCORE: 40", nil)
+	logger.record("Quick Scanner", "retry", "", errors.New("auth failed for sk-ant-oat01-secrettoken"))
+
+	data, err := os.ReadFile(filepath.Join(dir, "quick-scanner.md"))
+	if err != nil {
+		t.Fatalf("debug log not written: %v", err)
+	}
+	log := string(data)
+	for _, want := range []string{"## Prompt", "Findings: 2", "## Response", "RISK SCORE: 40", "## Error", "auth failed for [REDACTED]"} {
+		if !strings.Contains(log, want) {
+			t.Errorf("debug log is missing %q", want)
+		}
+	}
+	if strings.Contains(log, "abc123") || strings.Contains(log, "secrettoken") {
+		t.Errorf("debug log holds a secret:\n%s", log)
+	}
+	if strings.Count(log, "# Quick Scanner - ") != 2 {
+		t.Error("exchanges weren't appended to one file per agent")
+	}
+}
+
+func TestDebugLogName(t *testing.T) {
+	for agent, want := range map[string]string{"quick_scan": "quick_scan.md", "../Vuln Researcher": "---vuln-researcher.md"} {
+		if got := debugLogName(agent); got != want {
+			t.Errorf("debugLogName(%q) = %q, want %q", agent, got, want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Error categories returned (wrapped) by the analyzers, so callers can
+// branch with errors.Is instead of matching message text
+var (
+	// ErrAuth means the provider rejected the credentials
+	ErrAuth = errors.New("AI authentication failed")
+	// ErrRateLimit means the provider is throttling requests
+	ErrRateLimit = errors.New("AI rate limit exceeded")
+	// ErrTimeout means an AI call ran out of time
+	ErrTimeout = errors.New("AI request timed out")
+	// ErrPromptTooLarge is returned before any provider call when a prompt
+	// is estimated to exceed the configured token ceiling
+	ErrPromptTooLarge = errors.New("AI prompt too large")
+	// ErrNoFindings means there was nothing to analyze once suppressed and
+	// filtered findings were removed; no provider call is made. Callers
+	// usually record EmptyAnalysis instead.
+	ErrNoFindings = errors.New("no findings to analyze")
+	// ErrAIUnavailable means no AI client could be started, e.g. the pi
+	// CLI is missing or no credentials are usable. The constructors
+	// return it, and so do analyses whose agents couldn't start.
+	ErrAIUnavailable = errors.New("AI unavailable")
+)
+
+// UnavailableHelp lists the steps that make AI features available, for
+// commands to show with ErrAIUnavailable
+var UnavailableHelp = []string{
+	"Run 'shadow auth-check' to see which credentials Shadow finds",
+	"Install the pi CLI: npm install -g @mariozechner/pi-coding-agent",
+	"Log in with Claude Code (OAuth), or set ANTHROPIC_API_KEY (in the environment or ~/.shadow/.env)",
+}
+
+// classifyError wraps a provider error in its category so errors.Is works
+// on it. Errors that already carry a category, cancellations and
+// unrecognised errors are returned unchanged.
+func classifyError(err error) error {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return err
+	}
+	for _, category := range []error{ErrAuth, ErrRateLimit, ErrTimeout, ErrPromptTooLarge, ErrNoFindings, ErrAIUnavailable} {
+		if errors.Is(err, category) {
+			return err
+		}
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	case isAuthError(err):
+		return fmt.Errorf("%w: %w", ErrAuth, err)
+	// A bare "429" also turns up in request IDs and token counts
+	case matchesAny(err, "rate limit", "rate_limit", "status 429", " 429 ", "overloaded"):
+		return fmt.Errorf("%w: %w", ErrRateLimit, err)
+	case matchesAny(err, "timeout", "timed out", "deadline exceeded"):
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	}
+	return err
+}
+
+// matchesAny reports whether err's message contains any of the patterns,
+// case-insensitively
+func matchesAny(err error, patterns ...string) bool {
+	message := strings.ToLower(err.Error())
+	for _, pattern := range patterns {
+		if strings.Contains(message, pattern) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	pi "github.com/joshp123/pi-golang"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want error
+	}{
+		{errors.New("401 Unauthorized"), ErrAuth},
+		{errors.New("API error: status 429 Too Many Requests"), ErrRateLimit},
+		{errors.New("HTTP 429 Too Many Requests"), ErrRateLimit},
+		{errors.New(`{"type":"rate_limit_error"}`), ErrRateLimit},
+		{errors.New("overloaded_error: try again later"), ErrRateLimit},
+		{errors.New("read tcp: i/o timeout"), ErrTimeout},
+		{fmt.Errorf("run: %w", context.DeadlineExceeded), ErrTimeout},
+		{fmt.Errorf("%w: already classified", ErrPromptTooLarge), ErrPromptTooLarge},
+		{fmt.Errorf("%w: pi: executable file not found", ErrAIUnavailable), ErrAIUnavailable},
+	}
+	for _, tt := range tests {
+		err := classifyError(tt.err)
+		if !errors.Is(err, tt.want) || !errors.Is(err, tt.err) {
+			t.Errorf("classifyError(%v) = %v, want it wrapped as %v", tt.err, err, tt.want)
+		}
+	}
+
+	for _, err := range []error{nil, context.Canceled, errors.New("connection reset by peer"), errors.New("request req_4291ab failed: connection reset")} {
+		if got := classifyError(err); got != err {
+			t.Errorf("classifyError(%v) = %v, want it unchanged", err, got)
+		}
+	}
+}
+
+func TestIsRetryableErrorCategories(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{fmt.Errorf("%w: 401", ErrAuth), false},
+		{ErrPromptTooLarge, false},
+		{ErrNoFindings, false},
+		{fmt.Errorf("%w: slow down", ErrRateLimit), true},
+		{fmt.Errorf("%w: deadline", ErrTimeout), true},
+		{errEmptyResponse, true},
+		{errors.New("temporary failure in name resolution"), true},
+		{errors.New("invalid request"), false},
+	}
+	for _, tt := range tests {
+		if got := isRetryableError(tt.err); got != tt.want {
+			t.Errorf("isRetryableError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+// failingRunner fails every Run with err
+type failingRunner struct {
+	err error
+}
+
+func (r failingRunner) Run(ctx context.Context, message string) (pi.RunResult, error) {
+	return pi.RunResult{}, r.err
+}
+
+func TestRunLimitedClassifiesErrors(t *testing.T) {
+	_, err := runLimited(context.Background(), failingRunner{errors.New("HTTP 401: invalid x-api-key")}, "prompt")
+	if !errors.Is(err, ErrAuth) {
+		t.Errorf("runLimited error = %v, want ErrAuth", err)
+	}
+	if !isAuthError(err) {
+		t.Error("isAuthError doesn't accept ErrAuth")
+	}
+}
+
+func TestAnalyzeWithoutFindings(t *testing.T) {
+	starts := withUnavailableModels(t)
+	manager, err := NewAgentManager()
+	if err != nil {
+		t.Fatalf("NewAgentManager: %v", err)
+	}
+	manager.SetMinSeverity("high")
+
+	result := &models.ScanResult{ID: "scan-1", Findings: []models.Finding{
+		{Severity: "critical", Title: "Accepted", Metadata: map[string]string{models.MetaSuppressed: "true"}},
+		{Severity: "low", Title: "Below the minimum"},
+	}}
+	if _, err := manager.AnalyzeScanWithAgents(context.Background(), result, "standard", nil); !errors.Is(err, ErrNoFindings) {
+		t.Errorf("AnalyzeScanWithAgents error = %v, want ErrNoFindings", err)
+	}
+	result.Findings = result.Findings[:1]
+	if _, err := manager.AnalyzeTriage(context.Background(), result, nil); !errors.Is(err, ErrNoFindings) {
+		t.Errorf("AnalyzeTriage error = %v, want ErrNoFindings", err)
+	}
+	if *starts != 0 {
+		t.Errorf("%d agents started, want none for an empty finding set", *starts)
+	}
+}
+
+func TestEmptyAnalysis(t *testing.T) {
+	analysis := EmptyAnalysis("scan-1")
+	if analysis.ScanID != "scan-1" || analysis.RiskScore != 0 || analysis.Note == "" || analysis.Timestamp.IsZero() {
+		t.Errorf("EmptyAnalysis = %+v", analysis)
+	}
+	if analysis.CriticalIssues == nil || analysis.Recommendations == nil {
+		t.Error("empty analysis has nil lists")
+	}
+}
+
+func TestForceAnalysisCallsTheAI(t *testing.T) {
+	starts := withUnavailableModels(t, "claude-opus-4.6", "claude-sonnet-4.5-20250929", "claude-haiku-4.5")
+	manager, err := NewAgentManager()
+	if err != nil {
+		t.Fatalf("NewAgentManager: %v", err)
+	}
+	manager.SetForceAnalysis(true)
+
+	result := &models.ScanResult{ID: "scan-1", Findings: []models.Finding{{Severity: "info", Title: "Target Reachable"}}}
+	if _, err := manager.AnalyzeTriage(context.Background(), result, nil); err == nil || errors.Is(err, ErrNoFindings) {
+		t.Errorf("forced triage error = %v, want the agent start failure", err)
+	}
+	if *starts == 0 {
+		t.Error("forced analysis didn't start an agent")
+	}
+}
@@ -0,0 +1,98 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+// ReviewFalsePositives asks the AI which of the scan's findings are likely
+// false positives of the pattern-based modules. It returns the reason for
+// each flagged finding keyed by fingerprint; findings are never removed,
+// see models.MarkFalsePositives. Suppressed findings and findings already
+// flagged are not sent.
+func (m *AgentManager) ReviewFalsePositives(
+	ctx context.Context,
+	result *models.ScanResult,
+	progress ProgressCallback,
+) (map[string]string, error) {
+	findings := make([]models.Finding, 0, len(result.Findings))
+	for _, finding := range models.ActiveFindings(result.Findings) {
+		if finding.FalsePositiveReason() == "" {
+			finding.EnsureFingerprint()
+			findings = append(findings, finding)
+		}
+	}
+	if len(findings) == 0 {
+		return nil, ErrNoFindings
+	}
+	m.tracker.RecordScan(result.ID, len(models.ActiveFindings(result.Findings)))
+
+	if progress != nil {
+		progress(fmt.Sprintf("🔎 Cross-checking %d findings for false positives", len(findings)))
+	}
+
+	text, err := m.AnalyzeWithAgent(ctx, models.AgentTypeVulnerability, buildFalsePositivePrompt(result.Target, findings), progress)
+	if err != nil {
+		return nil, err
+	}
+
+	verdicts := make(map[string]string)
+	for number, reason := range parseFalsePositives(text, len(findings)) {
+		verdicts[findings[number-1].Fingerprint] = reason
+	}
+	return verdicts, nil
+}
+
+func buildFalsePositivePrompt(target string, findings []models.Finding) string {
+	return fmt.Sprintf(`# False Positive Review
+
+Target: %s
+
+The findings below come from pattern-based scanner modules, which flag
+anything that matches a signature. Review each one and decide whether it
+is likely a false positive: the evidence doesn't support it, it describes
+expected behaviour, or the check can't tell from what it saw.
+
+## Findings
+%s
+
+Only flag findings you have a concrete reason to doubt; when unsure, leave
+a finding out. Respond with one line per likely false positive, in exactly
+this format:
+FP <finding number>: <one-sentence reason>
+
+If none are likely false positives, write "FP: none".`,
+		target,
+		formatFindingsCompact(findings))
+}
+
+// falsePositiveLine matches "FP 3: reason", tolerating list markers and
+// markdown emphasis around the label
+var falsePositiveLine = regexp.MustCompile(`(?i)^[-*\s]*\**FP\s*#?(\d+)\**\s*[:\-–]\s*(.+)$`)
+
+// parseFalsePositives returns the reason for each finding number (1-based)
+// the response flags. Numbers outside 1..count and empty reasons are
+// ignored; the first reason given for a number wins.
+func parseFalsePositives(text string, count int) map[int]string {
+	flagged := make(map[int]string)
+	for _, line := range strings.Split(text, "\n") {
+		match := falsePositiveLine.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+		number, err := strconv.Atoi(match[1])
+		reason := strings.TrimSpace(strings.Trim(match[2], "*"))
+		if err != nil || number < 1 || number > count || reason == "" {
+			continue
+		}
+		if _, seen := flagged[number]; !seen {
+			flagged[number] = reason
+		}
+	}
+	return flagged
+}
@@ -0,0 +1,69 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+func TestParseFalsePositives(t *testing.T) {
+	text := `Reviewed all findings.
+FP 1: The header is set on the HTTPS redirect target
+- **FP 3**: Banner comes from a honeypot
+* FP #2 - Port is filtered, not open
+FP 1: duplicate reason
+FP 9: out of range
+FP 4:
+FP: none`
+
+	got := parseFalsePositives(text, 4)
+	want := map[int]string{
+		1: "The header is set on the HTTPS redirect target",
+		2: "Port is filtered, not open",
+		3: "Banner comes from a honeypot",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("parseFalsePositives = %q, want %q", got, want)
+	}
+	for number, reason := range want {
+		if got[number] != reason {
+			t.Errorf("FP %d: got %q, want %q", number, got[number], reason)
+		}
+	}
+
+	if got := parseFalsePositives("FP: none", 3); len(got) != 0 {
+		t.Errorf("\"FP: none\" flagged %q", got)
+	}
+}
+
+func TestBuildFalsePositivePrompt(t *testing.T) {
+	prompt := buildFalsePositivePrompt("example.com", []models.Finding{{Severity: "low", Title: "Missing HSTS header"}})
+	for _, want := range []string{"Target: example.com", "Missing HSTS header", "FP <finding number>: <one-sentence reason>"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt is missing %q", want)
+		}
+	}
+}
+
+func TestReviewFalsePositivesSkipsFlagged(t *testing.T) {
+	starts := withUnavailableModels(t)
+	manager, err := NewAgentManager()
+	if err != nil {
+		t.Fatalf("NewAgentManager: %v", err)
+	}
+
+	flagged := models.Finding{Severity: "low", Title: "Missing HSTS header"}
+	flagged.SetMeta(models.MetaLikelyFalsePositive, "already reviewed")
+	suppressed := models.Finding{Severity: "high", Title: "Accepted", Metadata: map[string]string{models.MetaSuppressed: "true"}}
+
+	result := &models.ScanResult{ID: "scan-1", Findings: []models.Finding{flagged, suppressed}}
+	if _, err := manager.ReviewFalsePositives(context.Background(), result, nil); !errors.Is(err, ErrNoFindings) {
+		t.Errorf("err = %v, want ErrNoFindings", err)
+	}
+	if *starts != 0 {
+		t.Errorf("%d agents started, want none", *starts)
+	}
+}
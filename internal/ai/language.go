@@ -0,0 +1,50 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+)
+
+// languageNames maps common language codes to the name used in prompts.
+// Other values are passed to the agent as given.
+var languageNames = map[string]string{
+	"de": "German",
+	"es": "Spanish",
+	"fr": "French",
+	"it": "Italian",
+	"ja": "Japanese",
+	"ko": "Korean",
+	"nl": "Dutch",
+	"pt": "Portuguese",
+	"ru": "Russian",
+	"zh": "Chinese",
+}
+
+// SetLanguage makes every agent write its prose (summaries,
+// recommendations, explanations) in lang, e.g. "es" or "ja". Empty or
+// "en" keeps English.
+func (m *AgentManager) SetLanguage(lang string) {
+	m.language = strings.TrimSpace(lang)
+}
+
+// languageInstruction returns the prompt suffix asking for lang, or "" for English
+func languageInstruction(lang string) string {
+	code := strings.ToLower(lang)
+	if code == "" || code == "en" || strings.HasPrefix(code, "en-") || strings.HasPrefix(code, "en_") {
+		return ""
+	}
+
+	name := lang
+	if known, ok := languageNames[strings.SplitN(strings.SplitN(code, "-", 2)[0], "_", 2)[0]]; ok {
+		name = known
+	}
+
+	return fmt.Sprintf(`
+
+## Language
+Write all prose (summaries, issue descriptions, recommendations, steps) in %s.
+Keep technical identifiers in English exactly as they appear: CVE and CWE IDs,
+HTTP header names, protocol and product names, file paths, URLs, commands and
+code. Keep the section headings and any required output format markers
+(such as SUMMARY:, RISK SCORE: or JSON keys) in English.`, name)
+}
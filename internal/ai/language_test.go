@@ -0,0 +1,45 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLanguageInstruction(t *testing.T) {
+	tests := []struct {
+		lang string
+		want string // language named in the instruction, "" for none
+	}{
+		{"", ""},
+		{"en", ""},
+		{"en-GB", ""},
+		{"es", "Spanish"},
+		{"pt-BR", "Portuguese"},
+		{"zh_TW", "Chinese"},
+		{"JA", "Japanese"},
+		{"Swahili", "Swahili"},
+	}
+	for _, tt := range tests {
+		got := languageInstruction(tt.lang)
+		if tt.want == "" {
+			if got != "" {
+				t.Errorf("languageInstruction(%q) = %q, want none", tt.lang, got)
+			}
+			continue
+		}
+		if !strings.Contains(got, "in "+tt.want+".") {
+			t.Errorf("languageInstruction(%q) = %q, want it to ask for %s", tt.lang, got, tt.want)
+		}
+		if !strings.Contains(got, "CVE and CWE IDs") {
+			t.Errorf("languageInstruction(%q) doesn't keep identifiers in English", tt.lang)
+		}
+	}
+}
+
+func TestSetLanguageTrims(t *testing.T) {
+	m := &AgentManager{}
+	m.SetLanguage("  de ")
+	if m.language != "de" {
+		t.Errorf("language = %q, want de", m.language)
+	}
+}
@@ -0,0 +1,49 @@
+package ai
+
+import (
+	"context"
+
+	pi "github.com/joshp123/pi-golang"
+)
+
+// defaultMaxConcurrentRequests keeps Shadow under provider rate limits
+// when deep analysis or multi-target scans fire many agent calls at once
+const defaultMaxConcurrentRequests = 2
+
+// requestSlots is a process-wide semaphore shared by every analyzer,
+// agent, planner and researcher
+var requestSlots = make(chan struct{}, defaultMaxConcurrentRequests)
+
+// promptRunner is the part of the pi client the analyzers depend on
+type promptRunner interface {
+	Run(ctx context.Context, message string) (pi.RunResult, error)
+}
+
+// SetMaxConcurrentRequests sets how many AI requests may be in flight at
+// once. It must be called at startup, before any request is made.
+func SetMaxConcurrentRequests(n int) {
+	if n < 1 {
+		n = 1
+	}
+	requestSlots = make(chan struct{}, n)
+}
+
+// MaxConcurrentRequests returns how many AI requests may be in flight at once
+func MaxConcurrentRequests() int {
+	return cap(requestSlots)
+}
+
+// runLimited runs a prompt once a request slot is free
+func runLimited(ctx context.Context, client promptRunner, prompt string) (pi.RunResult, error) {
+	slots := requestSlots
+
+	select {
+	case slots <- struct{}{}:
+	case <-ctx.Done():
+		return pi.RunResult{}, classifyError(ctx.Err())
+	}
+	defer func() { <-slots }()
+
+	result, err := client.Run(ctx, prompt)
+	return result, classifyError(err)
+}
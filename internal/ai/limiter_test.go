@@ -0,0 +1,107 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pi "github.com/joshp123/pi-golang"
+)
+
+// blockingRunner holds every Run until release is closed, recording the
+// most calls it saw in flight at once
+type blockingRunner struct {
+	release  chan struct{}
+	inFlight atomic.Int32
+	peak     atomic.Int32
+	started  chan struct{}
+}
+
+func (r *blockingRunner) Run(ctx context.Context, message string) (pi.RunResult, error) {
+	n := r.inFlight.Add(1)
+	defer r.inFlight.Add(-1)
+	for {
+		peak := r.peak.Load()
+		if n <= peak || r.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	r.started <- struct{}{}
+
+	select {
+	case <-r.release:
+		return pi.RunResult{Text: message}, nil
+	case <-ctx.Done():
+		return pi.RunResult{}, ctx.Err()
+	}
+}
+
+func TestRunLimitedCapsRequestsInFlight(t *testing.T) {
+	defer SetMaxConcurrentRequests(defaultMaxConcurrentRequests)
+	SetMaxConcurrentRequests(2)
+
+	runner := &blockingRunner{release: make(chan struct{}), started: make(chan struct{}, 6)}
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := runLimited(context.Background(), runner, "prompt"); err != nil {
+				t.Errorf("runLimited: %v", err)
+			}
+		}()
+	}
+
+	// Two requests start; the rest wait for a slot
+	<-runner.started
+	<-runner.started
+	select {
+	case <-runner.started:
+		t.Fatal("a third request started while two were in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(runner.release)
+	wg.Wait()
+	if peak := runner.peak.Load(); peak != 2 {
+		t.Errorf("peak requests in flight = %d, want 2", peak)
+	}
+}
+
+func TestRunLimitedCancelledWhileWaiting(t *testing.T) {
+	defer SetMaxConcurrentRequests(defaultMaxConcurrentRequests)
+	SetMaxConcurrentRequests(1)
+
+	runner := &blockingRunner{release: make(chan struct{}), started: make(chan struct{}, 2)}
+	defer close(runner.release)
+	go runLimited(context.Background(), runner, "holds the only slot")
+	<-runner.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := runLimited(ctx, runner, "waits"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("runLimited error = %v, want the deadline while waiting for a slot", err)
+	}
+	if peak := runner.peak.Load(); peak != 1 {
+		t.Errorf("peak requests in flight = %d, want 1", peak)
+	}
+}
+
+func TestSetMaxConcurrentRequestsFloor(t *testing.T) {
+	defer SetMaxConcurrentRequests(defaultMaxConcurrentRequests)
+	SetMaxConcurrentRequests(0)
+	if cap(requestSlots) != 1 {
+		t.Errorf("slots = %d, want at least 1", cap(requestSlots))
+	}
+}
+
+func TestMaxConcurrentRequests(t *testing.T) {
+	defer SetMaxConcurrentRequests(defaultMaxConcurrentRequests)
+	SetMaxConcurrentRequests(3)
+	if got := MaxConcurrentRequests(); got != 3 {
+		t.Errorf("MaxConcurrentRequests() = %d, want 3", got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
 
 	pi "github.com/joshp123/pi-golang"
@@ -29,7 +30,7 @@ func NewPiClaudeAnalyzer() (*PiClaudeAnalyzer, error) {
 		Thinking: "high",
 	}
 
-	client, err := pi.StartOneShot(opts)
+	client, _, err := startClient(opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to start pi client: %w (ensure you have pi CLI installed or Claude Code OAuth configured)", err)
 	}
@@ -42,10 +43,18 @@ func NewPiClaudeAnalyzer() (*PiClaudeAnalyzer, error) {
 
 // AnalyzeScan performs AI analysis on scan results
 func (a *PiClaudeAnalyzer) AnalyzeScan(ctx context.Context, result *models.ScanResult) (*models.AIAnalysis, error) {
+	result = result.WithActiveFindings()
+	if len(result.Findings) == 0 {
+		return nil, ErrNoFindings
+	}
+
 	prompt := a.buildAnalysisPrompt(result)
+	if err := checkPromptSize(prompt); err != nil {
+		return nil, err
+	}
 
 	// Use the Run method which handles event parsing internally
-	runResult, err := a.client.Run(ctx, prompt)
+	runResult, err := runLimited(ctx, a.client, prompt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to run analysis: %w", err)
 	}
@@ -58,6 +67,7 @@ func (a *PiClaudeAnalyzer) AnalyzeScan(ctx context.Context, result *models.ScanR
 		RiskScore:       parseRiskScore(text),
 		CriticalIssues:  parseCriticalIssues(text),
 		Recommendations: parseRecommendations(text),
+		RawText:         text,
 	}
 
 	return analysis, nil
@@ -91,7 +101,7 @@ Findings:
 func (a *PiClaudeAnalyzer) QueryResults(ctx context.Context, scanID string, question string) (string, error) {
 	prompt := fmt.Sprintf("Scan ID: %s\nQuestion: %s", scanID, question)
 
-	runResult, err := a.client.Run(ctx, prompt)
+	runResult, err := runLimited(ctx, a.client, prompt)
 	if err != nil {
 		return "", fmt.Errorf("failed to run query: %w", err)
 	}
@@ -124,7 +134,7 @@ func parseRiskScore(text string) int {
 	for _, line := range lines {
 		if strings.Contains(strings.ToLower(line), "risk score") {
 			// Extract number (basic parsing)
-			for i := 0; i < len(line)-1; i++ {
+			for i := 0; i < len(line); i++ {
 				if line[i] >= '0' && line[i] <= '9' {
 					score := 0
 					for j := i; j < len(line) && line[j] >= '0' && line[j] <= '9'; j++ {
@@ -140,79 +150,209 @@ func parseRiskScore(text string) int {
 	return 50 // default medium risk
 }
 
+// isCriticalIssuesHeading reports whether a heading or label line opens the
+// critical issues section ("## Critical Issues", "**Critical Vulnerabilities:**")
+func isCriticalIssuesHeading(line string) bool {
+	label := strings.ToLower(strings.Trim(line, "#*:0123456789. \t"))
+	return strings.HasPrefix(label, "critical issue") ||
+		strings.HasPrefix(label, "critical vulnerabilit") ||
+		strings.HasPrefix(label, "critical finding")
+}
+
+// parseCriticalIssues reads the list under the "Critical Issues" heading,
+// up to the next heading. Each top-level item becomes one issue; indented
+// lines under it are its severity justification, joined as
+// "title - justification". The word "critical" in prose elsewhere is ignored.
 func parseCriticalIssues(text string) []string {
 	issues := []string{}
-	lines := strings.Split(text, "\n")
 	inCritical := false
 
-	for _, line := range lines {
-		lower := strings.ToLower(line)
-		if strings.Contains(lower, "critical") {
-			inCritical = true
+	var current []string
+	flush := func() {
+		if len(current) > 0 {
+			issues = append(issues, strings.Join(current, " - "))
+			current = nil
+		}
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
 			continue
 		}
-		if inCritical && strings.TrimSpace(line) != "" {
-			if strings.HasPrefix(strings.TrimSpace(line), "-") || strings.HasPrefix(strings.TrimSpace(line), "*") {
-				issues = append(issues, strings.TrimSpace(line))
-			}
+
+		marker := listMarker.FindString(trimmed)
+		indented := len(line)-len(strings.TrimLeft(line, " \t")) > 0
+
+		// Headings and unindented "Label:" lines open or close the section
+		if isMarkdownHeading(trimmed) || (marker == "" && !indented && strings.HasSuffix(trimmed, ":")) {
+			flush()
+			inCritical = isCriticalIssuesHeading(trimmed)
+			continue
 		}
-		if inCritical && strings.Contains(lower, "recommendation") {
-			break
+		if !inCritical {
+			continue
+		}
+
+		item := strings.TrimSpace(strings.TrimPrefix(trimmed, marker))
+
+		if marker != "" && !indented {
+			flush()
+			title, justification := splitRecTitle(item)
+			current = []string{title}
+			if justification != title {
+				current = append(current, justification)
+			}
+		} else if len(current) > 0 {
+			current = append(current, strings.Trim(item, "* "))
 		}
 	}
+	flush()
 
 	return issues
 }
 
+var (
+	// recPriorityTag matches a leading [HIGH] / **[Critical]** style tag
+	recPriorityTag = regexp.MustCompile(`(?i)^\**\[(critical|high|medium|low)\]\**\s*`)
+	// recAnnotation matches "Effort: low", "Impact: ..." and "Priority: high"
+	// either inline in parentheses or on their own line
+	recAnnotation = regexp.MustCompile(`(?i)\b(priority|effort|impact)\s*:\s*\**([^,;()]+?)\**\s*(?:[,;)]|$)`)
+	// recInlineAnnotations matches a trailing "(Effort: low, Impact: high)"
+	recInlineAnnotations = regexp.MustCompile(`(?i)\s*\((?:[^()]*\b(?:priority|effort|impact)\s*:[^()]*)\)\s*$`)
+	// listMarker matches "-", "*", "1." or "1)" list markers
+	listMarker = regexp.MustCompile(`^(?:[-*]|\d+[.)])\s+`)
+)
+
+// isMarkdownHeading reports whether line is a "#" heading or a bold-only
+// line such as "**Recommendations:**"
+func isMarkdownHeading(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, "#") {
+		return true
+	}
+	return strings.HasPrefix(trimmed, "**") && strings.HasSuffix(strings.TrimSuffix(trimmed, ":"), "**")
+}
+
+// parseRecommendations reads the recommendations section into structured
+// recommendations. Top-level list items become recommendations; their
+// priority comes from a [HIGH] style tag, effort and impact from
+// "Effort:"/"Impact:" annotations, and indented list items become Steps.
+// Missing fields keep the medium/unknown/medium defaults.
 func parseRecommendations(text string) []models.Recommendation {
 	recommendations := []models.Recommendation{}
-	lines := strings.Split(text, "\n")
 	inRecommendations := false
 
-	for _, line := range lines {
-		lower := strings.ToLower(line)
-		if strings.Contains(lower, "recommendation") {
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+
+		mentionsRecs := strings.Contains(strings.ToLower(trimmed), "recommendation")
+		if isMarkdownHeading(trimmed) {
+			inRecommendations = mentionsRecs
+			continue
+		}
+		// Plain "Recommendations:" label lines also open the section
+		if mentionsRecs && strings.HasSuffix(trimmed, ":") && listMarker.FindString(trimmed) == "" {
 			inRecommendations = true
 			continue
 		}
-		if inRecommendations && strings.TrimSpace(line) != "" {
-			trimmed := strings.TrimSpace(line)
-			if strings.HasPrefix(trimmed, "-") ||
-			   strings.HasPrefix(trimmed, "*") ||
-			   strings.HasPrefix(trimmed, "1") {
-				// Simple recommendation structure
-				rec := models.Recommendation{
-					Priority:    "medium",
-					Title:       trimmed,
-					Description: trimmed,
-					Impact:      "unknown",
-					Effort:      "medium",
-				}
-				recommendations = append(recommendations, rec)
+		if !inRecommendations {
+			continue
+		}
+
+		marker := listMarker.FindString(trimmed)
+		indented := len(line)-len(strings.TrimLeft(line, " \t")) > 0
+
+		// Indented lines and bare annotations belong to the current item
+		if len(recommendations) > 0 && (indented || marker == "") {
+			current := &recommendations[len(recommendations)-1]
+			item := strings.TrimSpace(strings.TrimPrefix(trimmed, marker))
+			if !applyRecAnnotations(current, item) && marker != "" {
+				current.Steps = append(current.Steps, strings.Trim(item, "* "))
 			}
+			continue
+		}
+		if marker == "" {
+			continue
+		}
+
+		rec := models.Recommendation{
+			Priority: "medium",
+			Impact:   "unknown",
+			Effort:   "medium",
+			Steps:    make([]string, 0),
+		}
+
+		item := strings.TrimSpace(strings.TrimPrefix(trimmed, marker))
+		if tag := recPriorityTag.FindStringSubmatch(item); tag != nil {
+			rec.Priority = strings.ToLower(tag[1])
+			item = item[len(tag[0]):]
+		}
+		if inline := recInlineAnnotations.FindString(item); inline != "" {
+			applyRecAnnotations(&rec, inline)
+			item = strings.TrimSuffix(item, inline)
 		}
+
+		rec.Title, rec.Description = splitRecTitle(item)
+		recommendations = append(recommendations, rec)
 	}
 
 	return recommendations
 }
 
+// applyRecAnnotations sets priority, effort and impact from annotations in
+// text and reports whether any were found
+func applyRecAnnotations(rec *models.Recommendation, text string) bool {
+	matches := recAnnotation.FindAllStringSubmatch(text, -1)
+	for _, match := range matches {
+		value := strings.TrimSpace(match[2])
+		switch strings.ToLower(match[1]) {
+		case "priority":
+			if models.SeverityRank(value) > 0 {
+				rec.Priority = strings.ToLower(value)
+			}
+		case "effort":
+			rec.Effort = strings.ToLower(value)
+		case "impact":
+			rec.Impact = value
+		}
+	}
+	return len(matches) > 0
+}
+
+// splitRecTitle splits "**Title** - description" or "Title: description"
+// into title and description. Without a separator both are the full text.
+func splitRecTitle(item string) (string, string) {
+	item = strings.TrimSpace(item)
+
+	if strings.HasPrefix(item, "**") {
+		if end := strings.Index(item[2:], "**"); end >= 0 {
+			title := strings.TrimSpace(strings.TrimSuffix(item[2:2+end], ":"))
+			rest := strings.TrimLeft(item[2+end+2:], " :-–—")
+			if rest == "" {
+				rest = title
+			}
+			return title, rest
+		}
+	}
+
+	for _, sep := range []string{" - ", ": "} {
+		if title, rest, ok := strings.Cut(item, sep); ok && title != "" && rest != "" {
+			return strings.TrimSpace(title), strings.TrimSpace(rest)
+		}
+	}
+
+	return item, item
+}
+
 // GetAuthenticationStatus checks what authentication method is available
 func GetAuthenticationStatus() string {
 	// Check for OAuth token (Claude Code)
-	home, err := os.UserHomeDir()
-	if err == nil {
-		oauthPaths := []string{
-			home + "/.claude/.credentials.json", // Claude Code credentials
-			home + "/.claude/oauth.json",
-			home + "/.config/claude/oauth.json",
-			home + "/.config/anthropic/oauth.json",
-			home + "/.pi/agent/oauth.json",
-		}
-		for _, path := range oauthPaths {
-			if _, err := os.Stat(path); err == nil {
-				return fmt.Sprintf("✓ Claude Code OAuth token found at %s", path)
-			}
-		}
+	if path := oauthTokenPath(); path != "" {
+		return fmt.Sprintf("✓ Claude Code OAuth token found at %s", path)
 	}
 
 	// Check for API key
@@ -222,3 +362,24 @@ func GetAuthenticationStatus() string {
 
 	return "✗ No authentication found - set ANTHROPIC_API_KEY or use Claude Code OAuth"
 }
+
+// oauthTokenPath returns the first OAuth credentials file found, or ""
+func oauthTokenPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	oauthPaths := []string{
+		home + "/.claude/.credentials.json", // Claude Code credentials
+		home + "/.claude/oauth.json",
+		home + "/.config/claude/oauth.json",
+		home + "/.config/anthropic/oauth.json",
+		home + "/.pi/agent/oauth.json",
+	}
+	for _, path := range oauthPaths {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
@@ -0,0 +1,116 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseRecommendations(t *testing.T) {
+	text := `## Summary
+The site has one recommendation-worthy issue.
+
+## Recommendations
+1. **[HIGH]** **Enable HSTS** - Force HTTPS for all visitors (Effort: low, Impact: blocks downgrade attacks)
+   - Add Strict-Transport-Security to responses
+   - Submit the domain to the preload list
+2. [Critical] Patch the CMS: the installed version has a public RCE
+   Effort: high
+   Priority: critical
+- Rotate the exposed API key
+
+## Risk Score
+70`
+
+	recs := parseRecommendations(text)
+	if len(recs) != 3 {
+		t.Fatalf("got %d recommendations, want 3: %+v", len(recs), recs)
+	}
+
+	hsts := recs[0]
+	if hsts.Priority != "high" || hsts.Effort != "low" || hsts.Impact != "blocks downgrade attacks" {
+		t.Errorf("HSTS annotations = %+v", hsts)
+	}
+	if hsts.Title != "Enable HSTS" || hsts.Description != "Force HTTPS for all visitors" {
+		t.Errorf("HSTS title %q, description %q", hsts.Title, hsts.Description)
+	}
+	if strings.Join(hsts.Steps, "|") != "Add Strict-Transport-Security to responses|Submit the domain to the preload list" {
+		t.Errorf("HSTS steps = %q", hsts.Steps)
+	}
+
+	cms := recs[1]
+	if cms.Priority != "critical" || cms.Effort != "high" || cms.Title != "Patch the CMS" || len(cms.Steps) != 0 {
+		t.Errorf("CMS recommendation = %+v", cms)
+	}
+
+	key := recs[2]
+	if key.Priority != "medium" || key.Effort != "medium" || key.Impact != "unknown" || key.Title != "Rotate the exposed API key" {
+		t.Errorf("defaults not kept: %+v", key)
+	}
+}
+
+func TestParseRecommendationsIgnoresOtherSections(t *testing.T) {
+	text := `## Critical Issues
+- SQL injection in /login
+
+## Recommendations
+- Use parameterized queries
+
+## Notes
+- Scan was rate limited`
+
+	recs := parseRecommendations(text)
+	if len(recs) != 1 || recs[0].Title != "Use parameterized queries" {
+		t.Errorf("recommendations = %+v, want only the one under the heading", recs)
+	}
+}
+
+func TestSplitRecTitle(t *testing.T) {
+	tests := []struct {
+		item, title, description string
+	}{
+		{"**Enable HSTS**: force HTTPS", "Enable HSTS", "force HTTPS"},
+		{"**Enable HSTS**", "Enable HSTS", "Enable HSTS"},
+		{"Enable HSTS - force HTTPS", "Enable HSTS", "force HTTPS"},
+		{"Enable HSTS", "Enable HSTS", "Enable HSTS"},
+	}
+	for _, tt := range tests {
+		title, description := splitRecTitle(tt.item)
+		if title != tt.title || description != tt.description {
+			t.Errorf("splitRecTitle(%q) = %q, %q; want %q, %q", tt.item, title, description, tt.title, tt.description)
+		}
+	}
+}
+
+func TestParseCriticalIssues(t *testing.T) {
+	text := `## Summary
+No critical problems with TLS. The missing headers are non-critical.
+
+## Critical Issues
+1. **SQL injection in /login**: attacker-controlled input reaches the query
+   - Exploitable without authentication
+- Exposed .git directory
+
+## Recommendations
+- Critical: patch the login form`
+
+	issues := parseCriticalIssues(text)
+	want := []string{
+		"SQL injection in /login - attacker-controlled input reaches the query - Exploitable without authentication",
+		"Exposed .git directory",
+	}
+	if strings.Join(issues, "\n") != strings.Join(want, "\n") {
+		t.Errorf("issues = %q, want %q", issues, want)
+	}
+}
+
+func TestParseCriticalIssuesLabelHeading(t *testing.T) {
+	text := `Critical Vulnerabilities:
+- Default admin credentials
+Recommendations:
+- Change the password`
+
+	issues := parseCriticalIssues(text)
+	if len(issues) != 1 || issues[0] != "Default admin credentials" {
+		t.Errorf("issues = %q, want only the default credentials issue", issues)
+	}
+}
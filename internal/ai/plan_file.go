@@ -0,0 +1,63 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// planFileName replaces characters that don't belong in a file name
+var planFileName = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+
+// DefaultPlansDir returns ~/.shadow/plans
+func DefaultPlansDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".shadow", "plans"), nil
+}
+
+// Save writes the plan as JSON to dir/<target>-<timestamp>.json and
+// returns the file's path
+func (plan *ReconPlan) Save(dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create plans directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode plan: %w", err)
+	}
+
+	name := fmt.Sprintf("%s-%s.json",
+		planFileName.ReplaceAllString(plan.Target, "_"),
+		plan.CreatedAt.Format("20060102-150405"))
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", fmt.Errorf("failed to save plan: %w", err)
+	}
+	return path, nil
+}
+
+// LoadPlan reads a plan written by Save
+func LoadPlan(path string) (*ReconPlan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan: %w", err)
+	}
+
+	var plan ReconPlan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse plan %s: %w", path, err)
+	}
+	if plan.Target == "" {
+		return nil, fmt.Errorf("plan %s has no target", path)
+	}
+
+	// Plans edited by hand may have dropped the tool index
+	plan.indexTools()
+	return &plan, nil
+}
@@ -0,0 +1,95 @@
+package ai
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPlanSaveAndLoad(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "plans")
+	plan := (&ReconPlanner{}).parseReconPlan(planWithEstimate, "https://example.com:8443/app")
+	plan.Mode = "standard"
+	plan.CreatedAt = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	path, err := plan.Save(dir)
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if filepath.Base(path) != "https_example.com_8443_app-20260301-120000.json" {
+		t.Errorf("saved as %s", filepath.Base(path))
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("plan file mode = %v, %v; want 0600", info, err)
+	}
+
+	loaded, err := LoadPlan(path)
+	if err != nil {
+		t.Fatalf("LoadPlan: %v", err)
+	}
+	if loaded.Target != plan.Target || loaded.Mode != "standard" || loaded.EstimatedTime != "15-20 minutes" || !loaded.CreatedAt.Equal(plan.CreatedAt) {
+		t.Errorf("loaded plan = %+v", loaded)
+	}
+	if len(loaded.Phases) != 1 || len(loaded.Phases[0].Tools) != 1 || !loaded.Phases[0].Tools[0].RequiresRoot {
+		t.Errorf("phases = %+v", loaded.Phases)
+	}
+}
+
+func TestLoadPlanRebuildsToolIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "edited.json")
+	edited := `{"target": "example.com", "phases": [{"name": "Ports", "tools": [{"name": "nmap"}, {"name": "naabu"}]}]}`
+	if err := os.WriteFile(path, []byte(edited), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := LoadPlan(path)
+	if err != nil {
+		t.Fatalf("LoadPlan: %v", err)
+	}
+	if strings.Join(plan.RequiredTools, ",") != "naabu,nmap" || len(plan.ToolPhases["nmap"]) != 1 {
+		t.Errorf("tool index = %v, %v", plan.RequiredTools, plan.ToolPhases)
+	}
+}
+
+func TestLoadPlanErrors(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"invalid.json":   "{not json",
+		"no-target.json": `{"phases": []}`,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadPlan(path); err == nil {
+			t.Errorf("%s: loaded without error", name)
+		}
+	}
+	if _, err := LoadPlan(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("missing plan loaded without error")
+	}
+}
+
+func TestReconPlanJSONFields(t *testing.T) {
+	plan := (&ReconPlanner{}).parseReconPlan(planWithEstimate, "example.com")
+	data, err := json.Marshal(plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"target", "mode", "phases", "requires_root", "required_tools", "tool_phases", "estimated_time", "created_at"} {
+		if _, ok := fields[key]; !ok {
+			t.Errorf("plan JSON lacks %q: %s", key, data)
+		}
+	}
+	if !strings.Contains(string(data), `"requires_root":true`) || !strings.Contains(string(data), `"name":"nmap"`) {
+		t.Errorf("plan JSON = %s", data)
+	}
+}
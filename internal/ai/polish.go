@@ -0,0 +1,132 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+// polishRecommendation matches "- [high] [effort: low] Title | impact"
+var polishRecommendation = regexp.MustCompile(`(?i)^[-*]\s*\[(critical|high|medium|low)\]\s*\[effort:\s*(low|medium|high)\]\s*(.+)$`)
+
+// polishStep matches a numbered step under a recommendation
+var polishStep = regexp.MustCompile(`^\d+[.)]\s+(.+)$`)
+
+// SetPolish enables the reporter polish pass for quick and standard
+// analyses: the raw agent text is reformatted by the Quick Scanner (Haiku)
+// into clean, typed AIAnalysis fields. Off by default to avoid the extra call.
+func (m *AgentManager) SetPolish(enabled bool) {
+	m.polish = enabled
+}
+
+// finishAnalysis parses raw agent text into an AIAnalysis, running the
+// polish pass first when enabled. A failed polish falls back to the
+// plain parse.
+func (m *AgentManager) finishAnalysis(
+	ctx context.Context,
+	text string,
+	scanID string,
+	progress ProgressCallback,
+) *models.AIAnalysis {
+	if analysis, ok := parseStructuredAnalysis(text, scanID); ok {
+		return analysis // already structured, nothing to polish
+	}
+
+	analysis := parseAnalysisResponse(text, scanID)
+	if !m.polish {
+		return analysis
+	}
+
+	if progress != nil {
+		progress("\n✨ Polishing analysis into structured recommendations")
+	}
+
+	polished, err := m.AnalyzeWithAgent(ctx, models.AgentTypeQuickScan, buildPolishPrompt(text), progress)
+	if err != nil {
+		if progress != nil {
+			progress(fmt.Sprintf("⚠️  Polish pass unavailable, keeping raw analysis: %v", err))
+		}
+		return analysis
+	}
+
+	return parsePolishResponse(polished, analysis)
+}
+
+func buildPolishPrompt(text string) string {
+	return fmt.Sprintf(`# Reformat Security Analysis
+
+Reformat the analysis below. Do not add new findings or change the
+assessment; only restructure it.
+
+## Analysis
+%s
+
+Respond in exactly this format:
+SUMMARY: <2-3 sentences for an executive>
+RISK SCORE: <0-100>
+ISSUES:
+- <critical issue> - <why it matters>
+RECOMMENDATIONS:
+- [critical|high|medium|low] [effort: low|medium|high] <title> | <impact>
+  1. <step>
+  2. <step>`, text)
+}
+
+// parsePolishResponse reads the polish format into a copy of base. Fields
+// the response doesn't provide keep their values from base.
+func parsePolishResponse(text string, base *models.AIAnalysis) *models.AIAnalysis {
+	analysis := *base
+
+	var issues []string
+	var recommendations []models.Recommendation
+	section := ""
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		upper := strings.ToUpper(trimmed)
+
+		switch {
+		case strings.HasPrefix(upper, "SUMMARY:"):
+			if summary := strings.TrimSpace(trimmed[len("SUMMARY:"):]); summary != "" {
+				analysis.Summary = summary
+			}
+			section = ""
+		case strings.HasPrefix(upper, "RISK SCORE:"):
+			analysis.RiskScore = parseRiskScore(trimmed)
+			section = ""
+		case strings.HasPrefix(upper, "ISSUES:"):
+			section = "issues"
+		case strings.HasPrefix(upper, "RECOMMENDATIONS:"):
+			section = "recommendations"
+		case section == "issues" && (strings.HasPrefix(trimmed, "-") || strings.HasPrefix(trimmed, "*")):
+			issues = append(issues, strings.TrimSpace(trimmed[1:]))
+		case section == "recommendations":
+			if match := polishRecommendation.FindStringSubmatch(trimmed); match != nil {
+				title, impact, _ := strings.Cut(match[3], "|")
+				recommendations = append(recommendations, models.Recommendation{
+					Priority:    strings.ToLower(match[1]),
+					Effort:      strings.ToLower(match[2]),
+					Title:       strings.TrimSpace(title),
+					Description: strings.TrimSpace(title),
+					Impact:      strings.TrimSpace(impact),
+					Steps:       make([]string, 0),
+				})
+			} else if match := polishStep.FindStringSubmatch(trimmed); match != nil && len(recommendations) > 0 {
+				last := &recommendations[len(recommendations)-1]
+				last.Steps = append(last.Steps, strings.TrimSpace(match[1]))
+			}
+		}
+	}
+
+	if len(issues) > 0 {
+		analysis.CriticalIssues = issues
+	}
+	if len(recommendations) > 0 {
+		analysis.Recommendations = recommendations
+	}
+
+	return &analysis
+}
@@ -0,0 +1,73 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+func TestParsePolishResponse(t *testing.T) {
+	base := &models.AIAnalysis{ScanID: "scan-1", Summary: "raw summary", RiskScore: 10, CriticalIssues: []string{"raw issue"}}
+	text := `SUMMARY: The site is exposed to reflected XSS.
+RISK SCORE: 72
+ISSUES:
+- Reflected XSS on /search - session theft
+RECOMMENDATIONS:
+- [High] [effort: low] Encode output | Blocks script injection
+  1. Escape query parameters in templates
+  2. Add a Content-Security-Policy
+- [low] [effort: medium] Add HSTS
+`
+
+	got := parsePolishResponse(text, base)
+	if got.Summary != "The site is exposed to reflected XSS." || got.RiskScore != 72 || got.ScanID != "scan-1" {
+		t.Errorf("analysis = %+v", got)
+	}
+	if len(got.CriticalIssues) != 1 || got.CriticalIssues[0] != "Reflected XSS on /search - session theft" {
+		t.Errorf("issues = %q", got.CriticalIssues)
+	}
+	if len(got.Recommendations) != 2 {
+		t.Fatalf("got %d recommendations, want 2", len(got.Recommendations))
+	}
+	first := got.Recommendations[0]
+	if first.Priority != "high" || first.Effort != "low" || first.Title != "Encode output" || first.Impact != "Blocks script injection" {
+		t.Errorf("first recommendation = %+v", first)
+	}
+	if len(first.Steps) != 2 || first.Steps[1] != "Add a Content-Security-Policy" {
+		t.Errorf("steps = %q", first.Steps)
+	}
+	if second := got.Recommendations[1]; second.Title != "Add HSTS" || second.Impact != "" || len(second.Steps) != 0 {
+		t.Errorf("second recommendation = %+v", second)
+	}
+	if base.Summary != "raw summary" {
+		t.Error("parsePolishResponse modified its base analysis")
+	}
+}
+
+func TestParsePolishResponseKeepsBaseFields(t *testing.T) {
+	base := &models.AIAnalysis{Summary: "raw summary", CriticalIssues: []string{"raw issue"}}
+	got := parsePolishResponse("Sorry, I can't reformat that.", base)
+	if got.Summary != "raw summary" || len(got.CriticalIssues) != 1 {
+		t.Errorf("analysis = %+v, want the base fields kept", got)
+	}
+}
+
+func TestFinishAnalysisWithoutPolish(t *testing.T) {
+	text := "Summary line\nRisk Score: 40"
+	got := (&AgentManager{}).finishAnalysis(context.Background(), text, "scan-1", nil)
+	want := parseAnalysisResponse(text, "scan-1")
+	if got.RiskScore != want.RiskScore || got.Summary != want.Summary {
+		t.Errorf("finishAnalysis = %+v, want the plain parse %+v", got, want)
+	}
+}
+
+func TestBuildPolishPrompt(t *testing.T) {
+	prompt := buildPolishPrompt("RAW ANALYSIS TEXT")
+	for _, want := range []string{"RAW ANALYSIS TEXT", "RECOMMENDATIONS:", "[effort: low|medium|high]"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package ai
+
+import "fmt"
+
+// DefaultMaxPromptTokens leaves headroom under the models' 200k context
+// window for the system prompt and the response
+const DefaultMaxPromptTokens = 150000
+
+// maxPromptTokens is the process-wide prompt ceiling, see SetMaxPromptTokens
+var maxPromptTokens int64 = DefaultMaxPromptTokens
+
+// SetMaxPromptTokens sets the estimated token ceiling for a single prompt.
+// Values below 1 restore the default. It must be called at startup, before
+// any request is made.
+func SetMaxPromptTokens(n int64) {
+	if n < 1 {
+		n = DefaultMaxPromptTokens
+	}
+	maxPromptTokens = n
+}
+
+// checkPromptSize fails with ErrPromptTooLarge when prompt is estimated to
+// exceed the ceiling, so oversized finding sets get a clear error instead
+// of an opaque context-length failure from the provider
+func checkPromptSize(prompt string) error {
+	tokens := estimateTokens(prompt)
+	if tokens <= maxPromptTokens {
+		return nil
+	}
+	return fmt.Errorf("%w: ~%d tokens exceeds the %d-token ceiling (ai.max_prompt_tokens); "+
+		"try --min-severity to drop low-severity findings, --compact-findings to shorten each one, "+
+		"or analyze the findings in smaller chunks", ErrPromptTooLarge, tokens, maxPromptTokens)
+}
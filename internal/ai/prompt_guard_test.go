@@ -0,0 +1,75 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+// withMaxPromptTokens sets the prompt ceiling for one test
+func withMaxPromptTokens(t *testing.T, n int64) {
+	t.Helper()
+	original := maxPromptTokens
+	t.Cleanup(func() { maxPromptTokens = original })
+	SetMaxPromptTokens(n)
+}
+
+func TestCheckPromptSize(t *testing.T) {
+	withMaxPromptTokens(t, 100)
+
+	if err := checkPromptSize(strings.Repeat("a", 396)); err != nil {
+		t.Errorf("prompt at the ceiling: %v", err)
+	}
+	err := checkPromptSize(strings.Repeat("a", 400))
+	if !errors.Is(err, ErrPromptTooLarge) || !strings.Contains(err.Error(), "--min-severity") {
+		t.Errorf("prompt over the ceiling: %v, want ErrPromptTooLarge with a hint", err)
+	}
+
+	SetMaxPromptTokens(0)
+	if maxPromptTokens != DefaultMaxPromptTokens {
+		t.Errorf("SetMaxPromptTokens(0) left %d, want the default", maxPromptTokens)
+	}
+}
+
+func TestOversizedScanFailsBeforeStartingAgents(t *testing.T) {
+	withMaxPromptTokens(t, 1000)
+	starts := withUnavailableModels(t)
+
+	manager, err := NewAgentManager()
+	if err != nil {
+		t.Fatalf("NewAgentManager: %v", err)
+	}
+	result := &models.ScanResult{ID: "scan-1", Target: "example.com"}
+	for i := 0; i < 200; i++ {
+		result.Findings = append(result.Findings, models.Finding{
+			Severity: "low", Title: "Verbose server banner", Description: strings.Repeat("banner detail ", 10),
+		})
+	}
+
+	_, err = manager.AnalyzeScanWithAgents(context.Background(), result, "standard", nil)
+	if !errors.Is(err, ErrPromptTooLarge) {
+		t.Errorf("err = %v, want ErrPromptTooLarge", err)
+	}
+	if *starts != 0 {
+		t.Errorf("%d agents started, want the size checked first", *starts)
+	}
+}
+
+func TestPromptSizeIncludesLanguageInstruction(t *testing.T) {
+	withMaxPromptTokens(t, 100)
+	manager, fake := newFakeManager(t)
+	defer manager.Close()
+	manager.SetLanguage("de")
+
+	// At the ceiling on its own, over it once the language suffix is added
+	_, err := manager.AnalyzeWithAgent(context.Background(), models.AgentTypeVulnerability, strings.Repeat("a", 396), nil)
+	if !errors.Is(err, ErrPromptTooLarge) {
+		t.Errorf("err = %v, want ErrPromptTooLarge", err)
+	}
+	if len(fake.started) != 0 {
+		t.Errorf("agents started: %v", fake.started)
+	}
+}
@@ -3,10 +3,16 @@ package ai
 import (
 	"context"
 	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	pi "github.com/joshp123/pi-golang"
+	"github.com/kumaraguru1735/shadow/internal/output"
+	"github.com/kumaraguru1735/shadow/internal/scanner"
 )
 
 // ReconPlanner uses AI to plan reconnaissance strategy
@@ -16,31 +22,34 @@ type ReconPlanner struct {
 
 // ReconPlan represents the AI's reconnaissance strategy
 type ReconPlan struct {
-	Target          string
-	Phases          []ReconPhase
-	RequiresRoot    bool
-	RequiredTools   []string
-	EstimatedTime   string
-	Reasoning       string
+	Target        string           `json:"target"`
+	Mode          string           `json:"mode"` // quick, standard or deep
+	Phases        []ReconPhase     `json:"phases"`
+	RequiresRoot  bool             `json:"requires_root"`
+	RequiredTools []string         `json:"required_tools"` // sorted, each tool once
+	ToolPhases    map[string][]int `json:"tool_phases"`    // tool -> 1-based numbers of the phases using it
+	EstimatedTime string           `json:"estimated_time"`
+	Reasoning     string           `json:"reasoning"`
+	CreatedAt     time.Time        `json:"created_at"`
 }
 
 // ReconPhase represents a single phase of reconnaissance
 type ReconPhase struct {
-	Name            string
-	Description     string
-	Tools           []ToolRequirement
-	Priority        string // "critical", "high", "medium", "low"
-	ExpectedOutputs []string
+	Name            string            `json:"name"`
+	Description     string            `json:"description"`
+	Tools           []ToolRequirement `json:"tools"`
+	Priority        string            `json:"priority"` // "critical", "high", "medium", "low"
+	ExpectedOutputs []string          `json:"expected_outputs"`
 }
 
 // ToolRequirement defines what a tool needs to run
 type ToolRequirement struct {
-	Name         string
-	Command      string
-	RequiresRoot bool
-	Flags        []string
-	Purpose      string
-	Fallback     string // Alternative if tool unavailable
+	Name         string   `json:"name"`
+	Command      string   `json:"command,omitempty"`
+	RequiresRoot bool     `json:"requires_root"`
+	Flags        []string `json:"flags,omitempty"`
+	Purpose      string   `json:"purpose"`
+	Fallback     string   `json:"fallback,omitempty"` // Alternative if tool unavailable
 }
 
 // NewReconPlanner creates a new reconnaissance planner
@@ -74,7 +83,7 @@ Consider:
 
 Provide structured, executable reconnaissance plans.`
 
-	client, err := pi.StartOneShot(opts)
+	client, _, err := startClient(opts)
 	if err != nil {
 		return nil, err
 	}
@@ -84,11 +93,33 @@ Provide structured, executable reconnaissance plans.`
 
 // PlanReconnaissance asks AI to create a reconnaissance plan
 func (rp *ReconPlanner) PlanReconnaissance(ctx context.Context, target string, mode string) (*ReconPlan, error) {
-	prompt := fmt.Sprintf(`# Reconnaissance Planning Request
+	prompt := buildReconPlanPrompt(target, mode)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	result, err := runLimited(ctx, rp.client, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recon plan: %w", err)
+	}
+
+	// Parse the AI's response into a structured plan
+	plan := rp.parseReconPlan(result.Text, target)
+	plan.Mode = mode
+	plan.CreatedAt = time.Now()
+	return plan, nil
+}
+
+// buildReconPlanPrompt asks for a plan for target, with hints for its type
+func buildReconPlanPrompt(target string, mode string) string {
+	return fmt.Sprintf(`# Reconnaissance Planning Request
 
 ## Target
 %s
 
+## Target Type
+%s
+
 ## Mode
 %s (quick/standard/deep)
 
@@ -137,19 +168,31 @@ If tool X not available: [alternative]
 ### REASONING
 [Why this approach is optimal for this target]
 
-Be specific about commands and explain your reasoning.`, target, mode)
-
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
-	defer cancel()
+Be specific about commands and explain your reasoning.`, target, describeTargetType(target), mode)
+}
 
-	result, err := rp.client.Run(ctx, prompt)
+// describeTargetType classifies target and says what recon suits it, so
+// the plan doesn't run web checks against a bare IP or vice versa
+func describeTargetType(target string) string {
+	parsed, err := scanner.ParseTarget(target)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create recon plan: %w", err)
+		return "Unknown - plan broad reconnaissance."
 	}
 
-	// Parse the AI's response into a structured plan
-	plan := rp.parseReconPlan(result.Text, target)
-	return plan, nil
+	switch {
+	case parsed.Scheme == "http" || parsed.Scheme == "https":
+		return "Web URL - focus on HTTP reconnaissance: technologies, security headers, " +
+			"TLS, exposed paths and the application itself. Port scanning is secondary."
+	case net.ParseIP(parsed.Host) != nil && parsed.Port != 0:
+		return fmt.Sprintf("Bare IP address with port %d - focus on identifying and "+
+			"fingerprinting the service on that port. There is no DNS name to enumerate.", parsed.Port)
+	case net.ParseIP(parsed.Host) != nil:
+		return "Bare IP address - focus on port and service enumeration and version " +
+			"detection. Skip subdomain enumeration; there is no DNS name to enumerate."
+	default:
+		return "Domain name - start with DNS and subdomain enumeration, then port " +
+			"scanning and HTTP reconnaissance of the hosts found."
+	}
 }
 
 // parseReconPlan converts AI's text response into structured ReconPlan
@@ -161,7 +204,7 @@ func (rp *ReconPlanner) parseReconPlan(response string, target string) *ReconPla
 
 	lines := strings.Split(response, "\n")
 	var currentPhase *ReconPhase
-	var inPermissions, inReasoning bool
+	var inPermissions, inReasoning, inEstimate bool
 
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
@@ -178,19 +221,39 @@ func (rp *ReconPlanner) parseReconPlan(response string, target string) *ReconPla
 			}
 			inPermissions = false
 			inReasoning = false
+			inEstimate = false
 		}
 
 		// Parse permissions section
 		if strings.HasPrefix(line, "### PERMISSIONS REQUIRED") {
 			inPermissions = true
 			inReasoning = false
+			inEstimate = false
 			continue
 		}
 
+		// Parse time estimate section
+		if strings.HasPrefix(line, "### ESTIMATED TIME") {
+			inEstimate = true
+			inPermissions = false
+			// "### ESTIMATED TIME: 15 minutes" puts it on the heading itself
+			line = strings.TrimSpace(strings.TrimLeft(strings.TrimPrefix(line, "### ESTIMATED TIME"), ":"))
+			if line == "" {
+				continue
+			}
+		}
+		if strings.HasPrefix(line, "### ") {
+			inEstimate = false
+		}
+		if inEstimate && line != "" && plan.EstimatedTime == "" {
+			plan.EstimatedTime = strings.TrimSpace(strings.ReplaceAll(line, "**", ""))
+		}
+
 		// Parse reasoning section
 		if strings.HasPrefix(line, "### REASONING") {
 			inReasoning = true
 			inPermissions = false
+			inEstimate = false
 			if currentPhase != nil {
 				plan.Phases = append(plan.Phases, *currentPhase)
 				currentPhase = nil
@@ -203,9 +266,21 @@ func (rp *ReconPlanner) parseReconPlan(response string, target string) *ReconPla
 			plan.RequiresRoot = true
 		}
 
-		// Extract reasoning
-		if inReasoning && line != "" && !strings.HasPrefix(line, "#") {
-			plan.Reasoning += line + " "
+		// Extract reasoning, keeping paragraph breaks and list items
+		if inReasoning && !strings.HasPrefix(line, "#") {
+			switch {
+			case line == "":
+				if plan.Reasoning != "" && !strings.HasSuffix(plan.Reasoning, "\n\n") {
+					plan.Reasoning = strings.TrimRight(plan.Reasoning, " \n") + "\n\n"
+				}
+			case isListItem(line):
+				if plan.Reasoning != "" && !strings.HasSuffix(plan.Reasoning, "\n") {
+					plan.Reasoning = strings.TrimRight(plan.Reasoning, " ") + "\n"
+				}
+				plan.Reasoning += line + "\n"
+			default:
+				plan.Reasoning += line + " "
+			}
 		}
 
 		// Parse phase details
@@ -221,7 +296,6 @@ func (rp *ReconPlanner) parseReconPlan(response string, target string) *ReconPla
 				tool := rp.parseToolRequirement(line)
 				if tool.Name != "" {
 					currentPhase.Tools = append(currentPhase.Tools, tool)
-					plan.RequiredTools = append(plan.RequiredTools, tool.Name)
 				}
 			}
 		}
@@ -235,9 +309,32 @@ func (rp *ReconPlanner) parseReconPlan(response string, target string) *ReconPla
 	// Store full reasoning
 	plan.Reasoning = strings.TrimSpace(plan.Reasoning)
 
+	plan.indexTools()
+
 	return plan
 }
 
+// indexTools fills RequiredTools and ToolPhases from the phases, listing
+// each tool once however many phases use it
+func (plan *ReconPlan) indexTools() {
+	plan.RequiredTools = make([]string, 0)
+	plan.ToolPhases = make(map[string][]int)
+
+	for i, phase := range plan.Phases {
+		for _, tool := range phase.Tools {
+			phases := plan.ToolPhases[tool.Name]
+			if len(phases) == 0 {
+				plan.RequiredTools = append(plan.RequiredTools, tool.Name)
+			}
+			if len(phases) == 0 || phases[len(phases)-1] != i+1 {
+				plan.ToolPhases[tool.Name] = append(phases, i+1)
+			}
+		}
+	}
+
+	sort.Strings(plan.RequiredTools)
+}
+
 // parseToolRequirement extracts tool details from a line
 func (rp *ReconPlanner) parseToolRequirement(line string) ToolRequirement {
 	// Example: "- nmap (requires root: yes) - Port scanning"
@@ -261,56 +358,180 @@ func (rp *ReconPlanner) parseToolRequirement(line string) ToolRequirement {
 	return tool
 }
 
-// PrintPlan displays the reconnaissance plan to the user
-func (plan *ReconPlan) PrintPlan() {
-	fmt.Println("\n🎯 AI-Generated Reconnaissance Plan")
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Printf("\n📍 Target: %s\n", plan.Target)
+// DefaultToolTimeout bounds each tool when the plan has no usable estimate
+const DefaultToolTimeout = 10 * time.Minute
+
+// estimateDuration matches "15 minutes", "1-2 hours", "30s" and the like
+var estimateDuration = regexp.MustCompile(`(?i)(\d+)(?:\s*-\s*(\d+))?\s*(h|hours?|hrs?|m|min|mins|minutes?|s|sec|secs|seconds?)\b`)
 
-	if plan.Reasoning != "" {
-		fmt.Printf("\n💭 Strategy:\n%s\n", wrapText(plan.Reasoning, 70))
+// ToolTimeout returns how long a single tool may run: the upper bound of
+// the plan's estimated time, or DefaultToolTimeout when there is none.
+func (plan *ReconPlan) ToolTimeout() time.Duration {
+	timeout := time.Duration(0)
+
+	for _, match := range estimateDuration.FindAllStringSubmatch(plan.EstimatedTime, -1) {
+		amount := match[1]
+		if match[2] != "" {
+			amount = match[2]
+		}
+		n, err := strconv.Atoi(amount)
+		if err != nil {
+			continue
+		}
+
+		unit := time.Second
+		switch strings.ToLower(match[3])[0] {
+		case 'h':
+			unit = time.Hour
+		case 'm':
+			unit = time.Minute
+		}
+		if d := time.Duration(n) * unit; d > timeout {
+			timeout = d
+		}
+	}
+
+	if timeout == 0 {
+		return DefaultToolTimeout
+	}
+	return timeout
+}
+
+// phasePriorityRank orders phase priorities, most important highest.
+// Unknown or missing priorities rank last.
+func phasePriorityRank(priority string) int {
+	fields := strings.Fields(strings.ToLower(priority))
+	if len(fields) == 0 {
+		return 0
+	}
+	switch strings.Trim(fields[0], "*:,.-") {
+	case "critical":
+		return 4
+	case "high":
+		return 3
+	case "medium":
+		return 2
+	case "low":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// PhasesByPriority returns the phases ordered critical → low, keeping the
+// planned order among phases of equal priority
+func (plan *ReconPlan) PhasesByPriority() []ReconPhase {
+	phases := append([]ReconPhase{}, plan.Phases...)
+	sort.SliceStable(phases, func(i, j int) bool {
+		return phasePriorityRank(phases[i].Priority) > phasePriorityRank(phases[j].Priority)
+	})
+	return phases
+}
+
+// toolInstallHints tell users how to install tools recon plans commonly use
+var toolInstallHints = map[string]string{
+	"nmap":      "apt install nmap / brew install nmap",
+	"masscan":   "apt install masscan / brew install masscan",
+	"naabu":     "go install github.com/projectdiscovery/naabu/v2/cmd/naabu@latest",
+	"subfinder": "go install github.com/projectdiscovery/subfinder/v2/cmd/subfinder@latest",
+	"whatweb":   "apt install whatweb / brew install whatweb",
+	"dig":       "apt install dnsutils / brew install bind",
+	"curl":      "apt install curl / brew install curl",
+}
+
+// InstallHint returns how to install tool, or a generic hint
+func InstallHint(tool string) string {
+	if hint, ok := toolInstallHints[tool]; ok {
+		return hint
+	}
+	return "install " + tool + " with your package manager"
+}
+
+// MissingTools returns the plan's tools that lookPath can't find, each
+// listed once in plan order. lookPath is normally exec.LookPath.
+func (plan *ReconPlan) MissingTools(lookPath func(string) (string, error)) []ToolRequirement {
+	missing := make([]ToolRequirement, 0)
+	seen := make(map[string]bool)
+
+	for _, phase := range plan.Phases {
+		for _, tool := range phase.Tools {
+			if seen[tool.Name] {
+				continue
+			}
+			seen[tool.Name] = true
+
+			if _, err := lookPath(tool.Name); err != nil {
+				missing = append(missing, tool)
+			}
+		}
+	}
+
+	return missing
+}
+
+// PrintPlan displays the reconnaissance plan to the user. The AI's
+// reasoning is only shown with showReasoning, keeping the plan terse.
+func (plan *ReconPlan) PrintPlan(showReasoning bool) {
+	output.Println("\n🎯 AI-Generated Reconnaissance Plan")
+	output.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	output.Printf("\n📍 Target: %s\n", plan.Target)
+
+	if showReasoning && plan.Reasoning != "" {
+		output.Printf("\n💭 Strategy:\n%s\n", wrapText(plan.Reasoning, 70))
 	}
 
 	// Show phases
-	fmt.Printf("\n📋 Reconnaissance Phases (%d):\n", len(plan.Phases))
+	output.Printf("\n📋 Reconnaissance Phases (%d):\n", len(plan.Phases))
 	for i, phase := range plan.Phases {
-		fmt.Printf("\n%d. %s\n", i+1, phase.Name)
+		output.Printf("\n%d. %s\n", i+1, phase.Name)
 		if phase.Priority != "" {
-			fmt.Printf("   Priority: %s\n", getPriorityEmoji(phase.Priority))
+			output.Printf("   Priority: %s\n", getPriorityEmoji(phase.Priority))
 		}
 		if phase.Description != "" {
-			fmt.Printf("   %s\n", phase.Description)
+			output.Printf("   %s\n", phase.Description)
 		}
 		if len(phase.Tools) > 0 {
-			fmt.Println("   Tools needed:")
+			output.Println("   Tools needed:")
 			for _, tool := range phase.Tools {
 				rootBadge := ""
 				if tool.RequiresRoot {
 					rootBadge = " [ROOT REQUIRED]"
 				}
-				fmt.Printf("      • %s%s - %s\n", tool.Name, rootBadge, tool.Purpose)
+				output.Printf("      • %s%s - %s\n", tool.Name, rootBadge, tool.Purpose)
 			}
 		}
 	}
 
 	// Show permission requirements
-	fmt.Println("\n🔐 Permissions:")
+	output.Println("\n🔐 Permissions:")
 	if plan.RequiresRoot {
-		fmt.Println("   ⚠️  Root/sudo access required for some scans")
-		fmt.Println("   💡 Shadow will ask for permission before running privileged commands")
+		output.Println("   ⚠️  Root/sudo access required for some scans")
+		output.Println("   💡 Shadow will ask for permission before running privileged commands")
 	} else {
-		fmt.Println("   ✓ No elevated permissions needed")
+		output.Println("   ✓ No elevated permissions needed")
 	}
 
 	// Show required tools
 	if len(plan.RequiredTools) > 0 {
-		fmt.Println("\n🛠️  Required Tools:")
+		output.Println("\n🛠️  Required Tools:")
 		for _, tool := range plan.RequiredTools {
-			fmt.Printf("   • %s\n", tool)
+			phases := make([]string, 0, len(plan.ToolPhases[tool]))
+			for _, phase := range plan.ToolPhases[tool] {
+				phases = append(phases, strconv.Itoa(phase))
+			}
+			if len(phases) == 0 {
+				output.Printf("   • %s\n", tool)
+				continue
+			}
+			output.Printf("   • %s (phase %s)\n", tool, strings.Join(phases, ", "))
 		}
 	}
 
-	fmt.Println("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	if plan.EstimatedTime != "" {
+		output.Printf("\n⏱️  Estimated Time: %s\n", plan.EstimatedTime)
+	}
+
+	output.Println("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 }
 
 // Helper functions
@@ -330,7 +551,27 @@ func getPriorityEmoji(priority string) string {
 	}
 }
 
+// isListItem reports whether line starts a markdown list item
+func isListItem(line string) bool {
+	if strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ") {
+		return true
+	}
+	digits := len(line) - len(strings.TrimLeft(line, "0123456789"))
+	return digits > 0 && strings.HasPrefix(line[digits:], ". ")
+}
+
+// wrapText wraps each line of text to width, keeping existing line and
+// paragraph breaks
 func wrapText(text string, width int) string {
+	paragraphs := strings.Split(text, "\n")
+	for i, paragraph := range paragraphs {
+		paragraphs[i] = wrapLine(paragraph, width)
+	}
+	return strings.Join(paragraphs, "\n")
+}
+
+// wrapLine wraps a single line of text to width
+func wrapLine(text string, width int) string {
 	words := strings.Fields(text)
 	if len(words) == 0 {
 		return text
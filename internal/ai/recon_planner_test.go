@@ -0,0 +1,220 @@
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMissingTools(t *testing.T) {
+	plan := &ReconPlan{Phases: []ReconPhase{
+		{Name: "Ports", Tools: []ToolRequirement{{Name: "nmap"}, {Name: "naabu"}}},
+		{Name: "Web", Tools: []ToolRequirement{{Name: "whatweb"}, {Name: "naabu"}, {Name: "curl"}}},
+	}}
+	installed := map[string]bool{"nmap": true, "curl": true}
+	var looked []string
+	lookPath := func(name string) (string, error) {
+		looked = append(looked, name)
+		if installed[name] {
+			return "/usr/bin/" + name, nil
+		}
+		return "", errors.New("not found")
+	}
+
+	var names []string
+	for _, tool := range plan.MissingTools(lookPath) {
+		names = append(names, tool.Name)
+	}
+	if strings.Join(names, ",") != "naabu,whatweb" {
+		t.Errorf("missing = %v, want naabu and whatweb once each in plan order", names)
+	}
+	if len(looked) != 4 {
+		t.Errorf("looked up %v, want each tool once", looked)
+	}
+}
+
+func TestInstallHint(t *testing.T) {
+	if hint := InstallHint("subfinder"); !strings.Contains(hint, "go install github.com/projectdiscovery/subfinder") {
+		t.Errorf("subfinder hint = %q", hint)
+	}
+	if hint := InstallHint("gobuster"); hint != "install gobuster with your package manager" {
+		t.Errorf("unknown tool hint = %q", hint)
+	}
+}
+
+func TestToolTimeout(t *testing.T) {
+	tests := []struct {
+		estimate string
+		want     time.Duration
+	}{
+		{"15 minutes", 15 * time.Minute},
+		{"15-20 minutes", 20 * time.Minute},
+		{"1-2 hours", 2 * time.Hour},
+		{"30s per host, 5 min overall", 5 * time.Minute},
+		{"a while", DefaultToolTimeout},
+		{"", DefaultToolTimeout},
+	}
+	for _, tt := range tests {
+		plan := &ReconPlan{EstimatedTime: tt.estimate}
+		if got := plan.ToolTimeout(); got != tt.want {
+			t.Errorf("%q: got %s, want %s", tt.estimate, got, tt.want)
+		}
+	}
+}
+
+func TestParseReconPlanIndexesTools(t *testing.T) {
+	response := `### PHASE 1: Discovery
+- nmap (requires root: yes) - find open ports
+- whatweb (requires root: no) - fingerprint
+- nmap (requires root: no) - version scan
+
+### PHASE 2: Web
+- nuclei (requires root: no) - templates
+- whatweb (requires root: no) - fingerprint again`
+
+	plan := (&ReconPlanner{}).parseReconPlan(response, "example.com")
+	if got := strings.Join(plan.RequiredTools, ","); got != "nmap,nuclei,whatweb" {
+		t.Errorf("RequiredTools = %s, want each tool once, sorted", got)
+	}
+	for tool, want := range map[string]string{"nmap": "[1]", "whatweb": "[1 2]", "nuclei": "[2]"} {
+		if got := fmt.Sprint(plan.ToolPhases[tool]); got != want {
+			t.Errorf("%s: phases %s, want %s", tool, got, want)
+		}
+	}
+}
+
+const planWithEstimate = `### PHASE 1: Port Discovery
+- nmap (requires root: yes) - find open ports
+
+### PERMISSIONS REQUIRED
+- Root access: yes, for SYN scans
+
+### ESTIMATED TIME
+**15-20 minutes**
+
+### REASONING
+Start broad, then narrow down.`
+
+func TestParseReconPlanEstimate(t *testing.T) {
+	planner := &ReconPlanner{}
+
+	plan := planner.parseReconPlan(planWithEstimate, "example.com")
+	if plan.EstimatedTime != "15-20 minutes" {
+		t.Errorf("EstimatedTime = %q, want %q", plan.EstimatedTime, "15-20 minutes")
+	}
+	if plan.ToolTimeout() != 20*time.Minute {
+		t.Errorf("ToolTimeout = %s, want the upper bound of the estimate", plan.ToolTimeout())
+	}
+
+	onHeading := planner.parseReconPlan("### ESTIMATED TIME: about 1 hour\n### REASONING\nQuick.", "example.com")
+	if onHeading.EstimatedTime != "about 1 hour" {
+		t.Errorf("estimate on the heading = %q, want %q", onHeading.EstimatedTime, "about 1 hour")
+	}
+
+	none := planner.parseReconPlan("### PHASE 1: Ports\n- nmap (requires root: no) - ports", "example.com")
+	if none.EstimatedTime != "" || none.ToolTimeout() != DefaultToolTimeout {
+		t.Errorf("no estimate: %q, timeout %s; want empty and the default", none.EstimatedTime, none.ToolTimeout())
+	}
+}
+
+func TestPrintPlanShowsEstimate(t *testing.T) {
+	plan := (&ReconPlanner{}).parseReconPlan(planWithEstimate, "example.com")
+
+	printed := captureStdout(t, func() { plan.PrintPlan(false) })
+	if !strings.Contains(printed, "Estimated Time: 15-20 minutes") {
+		t.Errorf("PrintPlan output lacks the estimate:\n%s", printed)
+	}
+}
+
+func TestPhasesByPriority(t *testing.T) {
+	plan := &ReconPlan{Phases: []ReconPhase{
+		{Name: "web", Priority: "Medium"},
+		{Name: "unrated"},
+		{Name: "ports", Priority: "**CRITICAL** - do first"},
+		{Name: "dns", Priority: "medium"},
+		{Name: "vulns", Priority: "high:"},
+	}}
+
+	var names []string
+	for _, phase := range plan.PhasesByPriority() {
+		names = append(names, phase.Name)
+	}
+	if got := strings.Join(names, ","); got != "ports,vulns,web,dns,unrated" {
+		t.Errorf("order = %s, want critical first, planned order among equals, unrated last", got)
+	}
+	if plan.Phases[0].Name != "web" {
+		t.Error("PhasesByPriority reordered the plan itself")
+	}
+}
+
+func TestBuildReconPlanPrompt(t *testing.T) {
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{"https://example.com/login", "Web URL"},
+		{"http://10.0.0.5:8080", "Web URL"},
+		{"10.0.0.5", "Skip subdomain enumeration"},
+		{"10.0.0.5:22", "with port 22"},
+		{"[2001:db8::1]:443", "with port 443"},
+		{"example.com", "Domain name"},
+	}
+	for _, tt := range tests {
+		prompt := buildReconPlanPrompt(tt.target, "quick")
+		if !strings.Contains(prompt, tt.want) || !strings.Contains(prompt, "## Target\n"+tt.target) {
+			t.Errorf("%s: prompt lacks %q:\n%s", tt.target, tt.want, prompt)
+		}
+	}
+}
+
+func TestParseReconPlanReasoning(t *testing.T) {
+	response := `### REASONING
+The target exposes a web app,
+so start with HTTP.
+
+Priorities:
+- fingerprint the stack
+1. check headers
+Then scan ports.`
+
+	plan := (&ReconPlanner{}).parseReconPlan(response, "example.com")
+	want := "The target exposes a web app, so start with HTTP.\n\nPriorities:\n- fingerprint the stack\n1. check headers\nThen scan ports."
+	if plan.Reasoning != want {
+		t.Errorf("Reasoning = %q, want %q", plan.Reasoning, want)
+	}
+}
+
+func TestPrintPlanReasoningOnRequest(t *testing.T) {
+	plan := (&ReconPlanner{}).parseReconPlan(planWithEstimate, "example.com")
+
+	if printed := captureStdout(t, func() { plan.PrintPlan(false) }); strings.Contains(printed, "Start broad") {
+		t.Errorf("reasoning shown without showReasoning:\n%s", printed)
+	}
+	if printed := captureStdout(t, func() { plan.PrintPlan(true) }); !strings.Contains(printed, "Strategy:\nStart broad, then narrow down.") {
+		t.Errorf("reasoning missing with showReasoning:\n%s", printed)
+	}
+}
+
+func TestIsListItem(t *testing.T) {
+	for line, want := range map[string]bool{
+		"- item":      true,
+		"* item":      true,
+		"12. item":    true,
+		"-item":       false,
+		"1.5 seconds": false,
+		"Plain text":  false,
+	} {
+		if got := isListItem(line); got != want {
+			t.Errorf("isListItem(%q) = %v, want %v", line, got, want)
+		}
+	}
+}
+
+func TestWrapTextKeepsLineBreaks(t *testing.T) {
+	got := wrapText("one two three four\n\n- five six", 9)
+	if got != "one two\nthree\nfour\n\n- five\nsix" {
+		t.Errorf("wrapText = %q", got)
+	}
+}
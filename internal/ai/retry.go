@@ -0,0 +1,82 @@
+package ai
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// RetryPolicy controls how failed AI calls are retried
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration // delay before the first retry, doubled per attempt
+	MaxDelay    time.Duration // cap on the exponential delay
+	Jitter      bool          // full jitter: wait a random time in [0, delay]
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// retryAttempts and retryDelay are the configured ai.retry_attempts and
+// ai.retry_delay, see SetRetry
+var (
+	retryAttempts = maxRetryAttempts
+	retryDelay    = baseRetryDelay
+)
+
+// SetRetry sets how many times an AI call is attempted and the delay before
+// the first retry, for analyzers created afterwards. Values of 0 or less
+// restore the defaults.
+func SetRetry(attempts int, delay time.Duration) {
+	if attempts <= 0 {
+		attempts = maxRetryAttempts
+	}
+	if delay <= 0 {
+		delay = baseRetryDelay
+	}
+	retryAttempts = attempts
+	retryDelay = delay
+}
+
+// DefaultRetryPolicy returns the policy used by the analyzers: the
+// configured attempts and delay, with full jitter
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: retryAttempts,
+		BaseDelay:   retryDelay,
+		MaxDelay:    max(maxRetryDelay, retryDelay),
+		Jitter:      true,
+	}
+}
+
+// WithSeed makes the jitter deterministic (useful for reproducing timing)
+func (p *RetryPolicy) WithSeed(seed int64) *RetryPolicy {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rand = rand.New(rand.NewSource(seed))
+	return p
+}
+
+// Delay returns how long to wait before retrying after the given attempt
+// (0-based). Without jitter, concurrent agents that hit the same rate limit
+// would all retry at the same instant.
+func (p *RetryPolicy) Delay(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 0; i < attempt && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if !p.Jitter || delay <= 0 {
+		return delay
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.rand == nil {
+		p.rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return time.Duration(p.rand.Int63n(int64(delay) + 1))
+}
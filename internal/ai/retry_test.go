@@ -0,0 +1,114 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+func TestRetryPolicyDelayWithoutJitter(t *testing.T) {
+	policy := &RetryPolicy{MaxAttempts: 5, BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for attempt, expected := range want {
+		if got := policy.Delay(attempt); got != expected {
+			t.Errorf("Delay(%d) = %v, want %v", attempt, got, expected)
+		}
+	}
+}
+
+func TestRetryPolicyJitterStaysInRange(t *testing.T) {
+	policy := (&RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: 4 * time.Second, Jitter: true}).WithSeed(1)
+
+	distinct := make(map[time.Duration]bool)
+	for i := 0; i < 50; i++ {
+		delay := policy.Delay(2)
+		if delay < 0 || delay > 4*time.Second {
+			t.Fatalf("Delay(2) = %v, want within [0, 4s]", delay)
+		}
+		distinct[delay] = true
+	}
+	if len(distinct) < 2 {
+		t.Error("jittered delays are all the same")
+	}
+}
+
+func TestRetryPolicySeedIsDeterministic(t *testing.T) {
+	a := (&RetryPolicy{BaseDelay: time.Second, MaxDelay: time.Minute, Jitter: true}).WithSeed(42)
+	b := (&RetryPolicy{BaseDelay: time.Second, MaxDelay: time.Minute, Jitter: true}).WithSeed(42)
+	for attempt := 0; attempt < 5; attempt++ {
+		if da, db := a.Delay(attempt), b.Delay(attempt); da != db {
+			t.Errorf("attempt %d: %v != %v with the same seed", attempt, da, db)
+		}
+	}
+}
+
+func TestRetryWithBackoff(t *testing.T) {
+	analyzer := &AdvancedClaudeAnalyzer{retry: &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}}
+
+	calls := 0
+	analysis, err := analyzer.retryWithBackoff(context.Background(), func(context.Context) (*models.AIAnalysis, error) {
+		calls++
+		if calls < 3 {
+			return nil, errors.New("rate limited")
+		}
+		return &models.AIAnalysis{RiskScore: 10}, nil
+	}, nil)
+	if err != nil || analysis.RiskScore != 10 || calls != 3 {
+		t.Errorf("got %v, %v after %d calls; want success on the third attempt", analysis, err, calls)
+	}
+
+	calls = 0
+	_, err = analyzer.retryWithBackoff(context.Background(), func(context.Context) (*models.AIAnalysis, error) {
+		calls++
+		return nil, errors.New("rate limit still exceeded")
+	}, nil)
+	if err == nil || !strings.Contains(err.Error(), "max retries exceeded") || calls != 3 {
+		t.Errorf("error %v after %d calls; want failure after 3 attempts", err, calls)
+	}
+
+	// Errors that can't succeed on retry fail at once
+	calls = 0
+	_, err = analyzer.retryWithBackoff(context.Background(), func(context.Context) (*models.AIAnalysis, error) {
+		calls++
+		return nil, errors.New("invalid prompt")
+	}, nil)
+	if err == nil || calls != 1 {
+		t.Errorf("error %v after %d calls; want no retry", err, calls)
+	}
+}
+
+func TestRetryWithBackoffStopsOnCancel(t *testing.T) {
+	analyzer := &AdvancedClaudeAnalyzer{retry: &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Hour}}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	calls := 0
+	_, err := analyzer.retryStringWithBackoff(ctx, func(context.Context) (string, error) {
+		calls++
+		cancel()
+		return "", errors.New("rate limited")
+	})
+	if !errors.Is(err, context.Canceled) || calls != 1 {
+		t.Errorf("error %v after %d calls; want cancellation during the backoff", err, calls)
+	}
+}
+
+func TestSetRetry(t *testing.T) {
+	defer SetRetry(0, 0)
+
+	SetRetry(5, 2*time.Second)
+	policy := DefaultRetryPolicy()
+	if policy.MaxAttempts != 5 || policy.BaseDelay != 2*time.Second || !policy.Jitter {
+		t.Errorf("DefaultRetryPolicy() = %+v, want the configured 5 attempts from 2s with jitter", policy)
+	}
+
+	SetRetry(0, 0)
+	policy = DefaultRetryPolicy()
+	if policy.MaxAttempts != maxRetryAttempts || policy.BaseDelay != baseRetryDelay {
+		t.Errorf("DefaultRetryPolicy() = %+v, want the defaults restored", policy)
+	}
+}
@@ -0,0 +1,78 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+// AnalyzeSSL asks the Vulnerability agent to assess a TLS configuration
+// from an SSL check rather than from generic findings
+func (m *AgentManager) AnalyzeSSL(
+	ctx context.Context,
+	result *models.SSLResult,
+	progress ProgressCallback,
+) (string, error) {
+	if progress != nil {
+		progress(fmt.Sprintf("🔒 Assessing TLS posture of %s (grade %s)", result.Target, result.Grade))
+	}
+
+	return m.AnalyzeWithAgent(ctx, models.AgentTypeVulnerability, buildSSLPrompt(result), progress)
+}
+
+func buildSSLPrompt(result *models.SSLResult) string {
+	issues := "None detected"
+	if len(result.Issues) > 0 {
+		issues = "- " + strings.Join(result.Issues, "\n- ")
+	}
+
+	supported := "Unknown"
+	if len(result.SupportedVersions) > 0 {
+		supported = strings.Join(result.SupportedVersions, ", ")
+	}
+
+	return fmt.Sprintf(`# TLS Configuration Assessment
+
+Target: %s
+
+## Certificate
+- Trusted: %t
+- Subject: %s
+- Issuer: %s
+- Valid: %s to %s (%d days to expiry)
+
+## Protocol
+- Negotiated version: %s
+- Negotiated cipher: %s
+- Accepted versions: %s
+
+## Automated Grade
+%s (A+ best, F worst)
+
+## Detected Issues
+%s
+
+## Task
+Assess this TLS configuration:
+1. **Protocol downgrade risk** - which accepted versions can an attacker force, and what does that enable
+2. **Certificate chain** - trust, expiry and hostname problems and their impact
+3. **Cipher weaknesses** - whether the negotiated and accepted ciphers are adequate
+4. **Grade** - whether the automated grade is fair and what it would take to reach A+
+5. **Fixes** - concrete server configuration changes, most important first
+
+Be specific and concise.`,
+		result.Target,
+		result.Valid,
+		result.Subject,
+		result.Issuer,
+		result.NotBefore.Format("2006-01-02"),
+		result.NotAfter.Format("2006-01-02"),
+		result.DaysToExpiry,
+		result.Version,
+		result.Cipher,
+		supported,
+		result.Grade,
+		issues)
+}
@@ -0,0 +1,102 @@
+package ai
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+// jsonFence matches a ```json fenced block
+var jsonFence = regexp.MustCompile("(?s)```json\\s*\\n(.*?)\\n\\s*```")
+
+// structuredInstructions asks the agent to finish with a JSON object
+// matching models.AIAnalysis
+const structuredInstructions = `
+
+## Structured Output
+After your analysis, end with a single fenced ` + "```json" + ` block containing
+exactly this object (no comments, no trailing text after the block):
+` + "```json" + `
+{
+  "summary": "2-3 sentence executive summary",
+  "risk_score": 0,
+  "critical_issues": ["issue - why it matters"],
+  "recommendations": [
+    {"priority": "critical|high|medium|low", "title": "", "description": "",
+     "impact": "", "effort": "low|medium|high", "steps": [""]}
+  ],
+  "attack_chains": [
+    {"id": "chain-1", "severity": "critical|high|medium|low", "description": "",
+     "steps": [""], "impact": "", "likelihood": "low|medium|high"}
+  ],
+  "finding_confidence": {"<exact finding title>": "low|medium|high"}
+}
+` + "```" + `
+Only list findings in finding_confidence whose confidence you would change,
+e.g. likely false positives (low) or findings the evidence confirms (high).`
+
+// SetStructuredOutput makes quick, standard and deep analyses ask for a
+// JSON object matching AIAnalysis, which is parsed directly instead of
+// scraping markdown. Responses without valid JSON still fall back to text
+// parsing.
+func (m *AgentManager) SetStructuredOutput(enabled bool) {
+	m.structured = enabled
+}
+
+// withStructuredInstructions appends the JSON output instructions to
+// prompt when structured output is enabled
+func (m *AgentManager) withStructuredInstructions(prompt string) string {
+	if !m.structured {
+		return prompt
+	}
+	return prompt + structuredInstructions
+}
+
+// parseStructuredAnalysis parses the last ```json block in text into an
+// AIAnalysis. It reports false if there is no block, it isn't valid JSON,
+// or it has no summary.
+func parseStructuredAnalysis(text string, scanID string) (*models.AIAnalysis, bool) {
+	blocks := jsonFence.FindAllStringSubmatch(text, -1)
+	if len(blocks) == 0 {
+		return nil, false
+	}
+
+	var analysis models.AIAnalysis
+	if err := json.Unmarshal([]byte(blocks[len(blocks)-1][1]), &analysis); err != nil {
+		return nil, false
+	}
+	if strings.TrimSpace(analysis.Summary) == "" {
+		return nil, false
+	}
+
+	analysis.ScanID = scanID
+	analysis.RawText = text
+	analysis.Timestamp = time.Now()
+	if analysis.RiskScore < 0 {
+		analysis.RiskScore = 0
+	} else if analysis.RiskScore > 100 {
+		analysis.RiskScore = 100
+	}
+	if analysis.CriticalIssues == nil {
+		analysis.CriticalIssues = []string{}
+	}
+	for title, level := range analysis.Confidence {
+		analysis.Confidence[title] = strings.ToLower(strings.TrimSpace(level))
+	}
+	if analysis.Recommendations == nil {
+		analysis.Recommendations = []models.Recommendation{}
+	}
+	for i := range analysis.Recommendations {
+		rec := &analysis.Recommendations[i]
+		rec.Priority = strings.ToLower(rec.Priority)
+		rec.Effort = strings.ToLower(rec.Effort)
+		if models.SeverityRank(rec.Priority) == 0 {
+			rec.Priority = "medium"
+		}
+	}
+
+	return &analysis, true
+}
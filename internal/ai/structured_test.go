@@ -0,0 +1,68 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseStructuredAnalysis(t *testing.T) {
+	text := "## Analysis\nSome prose first.\n\n```json\n{\"summary\": \"draft\"}\n```\n\nFinal answer:\n```json\n" + `{
+  "summary": "Reflected XSS exposes user sessions.",
+  "risk_score": 140,
+  "critical_issues": ["Reflected XSS on /search - session theft"],
+  "recommendations": [
+    {"priority": "HIGH", "title": "Encode output", "effort": "Low", "steps": ["Escape query parameters"]},
+    {"priority": "urgent", "title": "Add a CSP"}
+  ],
+  "finding_confidence": {"Missing HSTS header": " Low "}
+}` + "\n```\n"
+
+	analysis, ok := parseStructuredAnalysis(text, "scan-1")
+	if !ok {
+		t.Fatal("valid JSON block not parsed")
+	}
+	if analysis.ScanID != "scan-1" || analysis.Summary != "Reflected XSS exposes user sessions." || analysis.Timestamp.IsZero() {
+		t.Errorf("analysis = %+v, want the last block with the scan ID and a timestamp", analysis)
+	}
+	if analysis.RiskScore != 100 {
+		t.Errorf("risk score = %d, want it clamped to 100", analysis.RiskScore)
+	}
+	if recs := analysis.Recommendations; len(recs) != 2 || recs[0].Priority != "high" || recs[0].Effort != "low" || recs[1].Priority != "medium" {
+		t.Errorf("recommendations = %+v, want normalized priorities", recs)
+	}
+	if analysis.Confidence["Missing HSTS header"] != "low" {
+		t.Errorf("finding confidence = %v, want normalized levels", analysis.Confidence)
+	}
+	if analysis.RawText != text {
+		t.Error("raw response not kept")
+	}
+}
+
+func TestParseStructuredAnalysisFallsBack(t *testing.T) {
+	for name, text := range map[string]string{
+		"no block":      "## Summary\nAll good.",
+		"invalid json":  "```json\n{\"summary\": \n```",
+		"empty summary": "```json\n{\"risk_score\": 20}\n```",
+	} {
+		if _, ok := parseStructuredAnalysis(text, "scan-1"); ok {
+			t.Errorf("%s: parsed as structured", name)
+		}
+	}
+
+	analysis := parseAnalysisResponse("## Summary\nAll good.\n\nRisk Score: 15", "scan-1")
+	if analysis.RiskScore != 15 || analysis.CriticalIssues == nil || analysis.RawText != "## Summary\nAll good.\n\nRisk Score: 15" {
+		t.Errorf("text fallback = %+v", analysis)
+	}
+}
+
+func TestWithStructuredInstructions(t *testing.T) {
+	m := &AgentManager{}
+	if got := m.withStructuredInstructions("prompt"); got != "prompt" {
+		t.Errorf("instructions added while disabled: %q", got)
+	}
+
+	m.SetStructuredOutput(true)
+	if got := m.withStructuredInstructions("prompt"); !strings.HasPrefix(got, "prompt") || !strings.Contains(got, `"risk_score"`) {
+		t.Errorf("instructions missing while enabled: %q", got)
+	}
+}
@@ -0,0 +1,198 @@
+package ai
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// usageLogMu serializes appends from concurrent agents
+var usageLogMu sync.Mutex
+
+// usageLogPath locates the usage log; replaced in tests
+var usageLogPath = UsageLogPath
+
+// DailyUsage is the usage summary for one calendar day (local time)
+type DailyUsage struct {
+	Day     string       `json:"day"` // YYYY-MM-DD
+	Summary UsageSummary `json:"summary"`
+}
+
+// UsageLogPath returns ~/.shadow/usage.jsonl, where every AI call is
+// appended so spend can be reported across runs
+func UsageLogPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".shadow", "usage.jsonl"), nil
+}
+
+// appendUsageLog persists one usage record. Failures are ignored: usage
+// history is best effort and must never fail an analysis.
+func appendUsageLog(stats UsageStats) {
+	path, err := usageLogPath()
+	if err != nil {
+		return
+	}
+
+	line, err := json.Marshal(stats)
+	if err != nil {
+		return
+	}
+
+	usageLogMu.Lock()
+	defer usageLogMu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	_, _ = file.Write(append(line, '\n'))
+}
+
+// LoadUsage reads persisted usage records that started within [from, to).
+// A zero from or to leaves that side of the window open. Malformed lines
+// are skipped.
+func LoadUsage(from, to time.Time) ([]UsageStats, error) {
+	path, err := usageLogPath()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []UsageStats{}, nil
+		}
+		return nil, fmt.Errorf("failed to read usage log: %w", err)
+	}
+	defer file.Close()
+
+	usages := make([]UsageStats, 0)
+	lines := bufio.NewScanner(file)
+	lines.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for lines.Scan() {
+		var stats UsageStats
+		if err := json.Unmarshal(lines.Bytes(), &stats); err != nil {
+			continue
+		}
+		if !from.IsZero() && stats.StartTime.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !stats.StartTime.Before(to) {
+			continue
+		}
+		usages = append(usages, stats)
+	}
+	if err := lines.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read usage log: %w", err)
+	}
+
+	return usages, nil
+}
+
+// UsageByDay groups usage records by local calendar day, oldest first
+func UsageByDay(usages []UsageStats) []DailyUsage {
+	byDay := make(map[string][]UsageStats)
+	for _, usage := range usages {
+		day := usage.StartTime.Local().Format("2006-01-02")
+		byDay[day] = append(byDay[day], usage)
+	}
+
+	days := make([]DailyUsage, 0, len(byDay))
+	for day, records := range byDay {
+		days = append(days, DailyUsage{Day: day, Summary: SummarizeUsage(records)})
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Day < days[j].Day })
+
+	return days
+}
+
+// Cost anomaly detection settings
+const (
+	anomalyFactor     = 3.0 // warn above this multiple of the recent average
+	anomalyHistory    = 20  // recent scans averaged
+	anomalyMinHistory = 3   // fewer scans than this is too little to judge
+)
+
+// CostAnomaly describes a scan whose AI cost is far above recent scans
+type CostAnomaly struct {
+	Cost    float64
+	Average float64
+	Scans   int // scans in the average
+}
+
+// Factor is how many times the average this scan cost
+func (a *CostAnomaly) Factor() float64 {
+	return a.Cost / a.Average
+}
+
+// CheckCostAnomaly compares cost against the average per-scan cost of the
+// most recent scans in the usage log, excluding scanID. It returns nil
+// when the cost is normal or there isn't enough history to judge.
+func CheckCostAnomaly(scanID string, cost float64) *CostAnomaly {
+	usages, err := LoadUsage(time.Time{}, time.Time{})
+	if err != nil {
+		return nil
+	}
+	return costAnomaly(usages, scanID, cost)
+}
+
+func costAnomaly(usages []UsageStats, scanID string, cost float64) *CostAnomaly {
+	type scanCost struct {
+		cost float64
+		last time.Time
+	}
+
+	byScan := make(map[string]*scanCost)
+	for _, usage := range usages {
+		if usage.ScanID == "" || usage.ScanID == scanID {
+			continue
+		}
+		entry, ok := byScan[usage.ScanID]
+		if !ok {
+			entry = &scanCost{}
+			byScan[usage.ScanID] = entry
+		}
+		entry.cost += usage.CalculateCost()
+		if usage.StartTime.After(entry.last) {
+			entry.last = usage.StartTime
+		}
+	}
+
+	scans := make([]*scanCost, 0, len(byScan))
+	for _, entry := range byScan {
+		scans = append(scans, entry)
+	}
+	if len(scans) < anomalyMinHistory {
+		return nil
+	}
+
+	sort.Slice(scans, func(i, j int) bool { return scans[i].last.After(scans[j].last) })
+	if len(scans) > anomalyHistory {
+		scans = scans[:anomalyHistory]
+	}
+
+	var total float64
+	for _, entry := range scans {
+		total += entry.cost
+	}
+	average := total / float64(len(scans))
+
+	if average <= 0 || cost <= average*anomalyFactor {
+		return nil
+	}
+
+	return &CostAnomaly{Cost: cost, Average: average, Scans: len(scans)}
+}
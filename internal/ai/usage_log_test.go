@@ -0,0 +1,119 @@
+package ai
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// withUsageLog points the usage log at a temporary file
+func withUsageLog(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "usage.jsonl")
+	original := usageLogPath
+	usageLogPath = func() (string, error) { return path, nil }
+	t.Cleanup(func() { usageLogPath = original })
+	return path
+}
+
+func TestUsageLogRoundTrip(t *testing.T) {
+	path := withUsageLog(t)
+
+	day1 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+	day2 := day1.AddDate(0, 0, 1)
+	appendUsageLog(UsageStats{ScanID: "a", Model: "claude-haiku-4.5", InputTokens: 1000, StartTime: day1, Success: true})
+	appendUsageLog(UsageStats{ScanID: "b", Model: "claude-haiku-4.5", InputTokens: 2000, StartTime: day2, Success: true})
+
+	// Malformed lines are skipped
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString("not json\n")
+	file.Close()
+
+	all, err := LoadUsage(time.Time{}, time.Time{})
+	if err != nil || len(all) != 2 {
+		t.Fatalf("LoadUsage = %d records, %v; want 2", len(all), err)
+	}
+
+	since, err := LoadUsage(day2, time.Time{})
+	if err != nil || len(since) != 1 || since[0].ScanID != "b" {
+		t.Errorf("LoadUsage(since day 2) = %+v, %v; want only scan b", since, err)
+	}
+	until, err := LoadUsage(time.Time{}, day2)
+	if err != nil || len(until) != 1 || until[0].ScanID != "a" {
+		t.Errorf("LoadUsage(until day 2) = %+v, %v; want only scan a", until, err)
+	}
+
+	days := UsageByDay(all)
+	if len(days) != 2 || days[0].Day != "2026-03-01" || days[1].Summary.TotalInputTokens != 2000 {
+		t.Errorf("UsageByDay = %+v", days)
+	}
+}
+
+func TestLoadUsageMissingLog(t *testing.T) {
+	withUsageLog(t)
+	usages, err := LoadUsage(time.Time{}, time.Time{})
+	if err != nil || len(usages) != 0 {
+		t.Errorf("LoadUsage = %v, %v; want nothing", usages, err)
+	}
+}
+
+func TestCostAnomaly(t *testing.T) {
+	now := time.Now()
+	usages := make([]UsageStats, 0)
+	for i, scan := range []string{"s1", "s2", "s3", "s4"} {
+		usages = append(usages, UsageStats{
+			ScanID:       scan,
+			Model:        "claude-sonnet-4.5-20250929",
+			InputTokens:  100_000,
+			OutputTokens: 10_000,
+			StartTime:    now.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	average := usages[0].CalculateCost()
+
+	if anomaly := costAnomaly(usages, "new", average*2); anomaly != nil {
+		t.Errorf("2x the average flagged: %+v", anomaly)
+	}
+	anomaly := costAnomaly(usages, "new", average*5)
+	if anomaly == nil || anomaly.Scans != 4 || anomaly.Factor() < 4.9 {
+		t.Errorf("5x the average = %+v, want an anomaly over 4 scans", anomaly)
+	}
+	if anomaly := costAnomaly(usages[:2], "new", average*10); anomaly != nil {
+		t.Errorf("flagged with too little history: %+v", anomaly)
+	}
+}
+
+func TestCostAnomalyExcludesCurrentScan(t *testing.T) {
+	now := time.Now()
+	var usages []UsageStats
+	for i, scan := range []string{"s1", "s2", "s3", "current"} {
+		usages = append(usages, UsageStats{
+			ScanID:       scan,
+			Model:        "claude-haiku-4.5",
+			InputTokens:  10_000,
+			OutputTokens: 1_000,
+			StartTime:    now.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	// Untagged usage (e.g. explain or query) doesn't count as a scan
+	usages = append(usages, UsageStats{Model: "claude-opus-4.6", InputTokens: 1_000_000})
+
+	average := usages[0].CalculateCost()
+	anomaly := costAnomaly(usages, "current", average*4)
+	if anomaly == nil || anomaly.Scans != 3 || math.Abs(anomaly.Average-average) > 1e-9 {
+		t.Errorf("anomaly = %+v, want an average of %f over the 3 other scans", anomaly, average)
+	}
+}
+
+func TestFormatTokens(t *testing.T) {
+	for tokens, want := range map[int64]string{0: "0", 999: "999", 1000: "1.0K", 12345: "12.3K"} {
+		if got := FormatTokens(tokens); got != want {
+			t.Errorf("FormatTokens(%d) = %q, want %q", tokens, got, want)
+		}
+	}
+}
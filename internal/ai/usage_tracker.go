@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/kumaraguru1735/shadow/internal/output"
 )
 
 // ModelPricing contains pricing information for Claude models (per million tokens)
@@ -33,15 +35,16 @@ var modelPricing = map[string]ModelPricing{
 
 // UsageStats tracks model usage for a single operation
 type UsageStats struct {
-	Model        string
-	Agent        string
-	InputTokens  int64
-	OutputTokens int64
-	Duration     time.Duration
-	StartTime    time.Time
-	EndTime      time.Time
-	Success      bool
-	Error        string
+	ScanID       string        `json:"scan_id,omitempty"`
+	Model        string        `json:"model"`
+	Agent        string        `json:"agent"`
+	InputTokens  int64         `json:"input_tokens"`
+	OutputTokens int64         `json:"output_tokens"`
+	Duration     time.Duration `json:"duration"`
+	StartTime    time.Time     `json:"start_time"`
+	EndTime      time.Time     `json:"end_time"`
+	Success      bool          `json:"success"`
+	Error        string        `json:"error,omitempty"`
 }
 
 // CalculateCost estimates the cost of this usage
@@ -61,13 +64,28 @@ func (u *UsageStats) CalculateCost() float64 {
 type UsageTracker struct {
 	mu     sync.RWMutex
 	usages []UsageStats
+	scans  map[string]int // findings per analyzed scan, by scan ID
 }
 
 // NewUsageTracker creates a new usage tracker
 func NewUsageTracker() *UsageTracker {
 	return &UsageTracker{
 		usages: make([]UsageStats, 0),
+		scans:  make(map[string]int),
+	}
+}
+
+// RecordScan notes that a scan with the given number of findings was
+// analyzed, for the per-scan and per-finding costs in the summary. A scan
+// analyzed more than once (e.g. a false-positive review, then analysis)
+// counts once.
+func (t *UsageTracker) RecordScan(scanID string, findings int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if scanID == "" {
+		scanID = fmt.Sprintf("unsaved-%d", len(t.scans))
 	}
+	t.scans[scanID] = findings
 }
 
 // RecordUsage adds a usage record
@@ -82,12 +100,22 @@ func (t *UsageTracker) GetSummary() UsageSummary {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
 
+	summary := SummarizeUsage(t.usages)
+	for _, findings := range t.scans {
+		summary.Scans++
+		summary.Findings += findings
+	}
+	return summary
+}
+
+// SummarizeUsage aggregates usage records by agent and model
+func SummarizeUsage(usages []UsageStats) UsageSummary {
 	summary := UsageSummary{
 		ByAgent: make(map[string]AgentSummary),
 		ByModel: make(map[string]ModelSummary),
 	}
 
-	for _, usage := range t.usages {
+	for _, usage := range usages {
 		// Overall totals
 		summary.TotalInputTokens += usage.InputTokens
 		summary.TotalOutputTokens += usage.OutputTokens
@@ -127,60 +155,89 @@ func (t *UsageTracker) GetSummary() UsageSummary {
 
 // UsageSummary provides aggregated usage statistics
 type UsageSummary struct {
-	TotalInputTokens      int64
-	TotalOutputTokens     int64
-	TotalCost             float64
-	TotalDuration         time.Duration
-	TotalOperations       int
-	SuccessfulOperations  int
-	ByAgent               map[string]AgentSummary
-	ByModel               map[string]ModelSummary
+	TotalInputTokens      int64                   `json:"total_input_tokens"`
+	TotalOutputTokens     int64                   `json:"total_output_tokens"`
+	TotalCost             float64                 `json:"total_cost"`
+	TotalDuration         time.Duration           `json:"total_duration"`
+	TotalOperations       int                     `json:"total_operations"`
+	SuccessfulOperations  int                     `json:"successful_operations"`
+	ByAgent               map[string]AgentSummary `json:"by_agent"`
+	ByModel               map[string]ModelSummary `json:"by_model"`
+
+	// Scans and Findings count the scans analyzed and the findings they
+	// hold. They are only known to a live tracker, not the usage log.
+	Scans    int `json:"scans,omitempty"`
+	Findings int `json:"findings,omitempty"`
+}
+
+// CostPerScan is the estimated cost divided by the scans analyzed, or 0
+// when no scan was
+func (s *UsageSummary) CostPerScan() float64 {
+	if s.Scans == 0 {
+		return 0
+	}
+	return s.TotalCost / float64(s.Scans)
+}
+
+// CostPerFinding is the estimated cost divided by the findings of the
+// scans analyzed, or 0 when there were none
+func (s *UsageSummary) CostPerFinding() float64 {
+	if s.Findings == 0 {
+		return 0
+	}
+	return s.TotalCost / float64(s.Findings)
 }
 
 // AgentSummary provides per-agent statistics
 type AgentSummary struct {
-	Agent        string
-	Model        string
-	InputTokens  int64
-	OutputTokens int64
-	Cost         float64
-	Duration     time.Duration
-	Operations   int
-	Successes    int
+	Agent        string        `json:"agent"`
+	Model        string        `json:"model"`
+	InputTokens  int64         `json:"input_tokens"`
+	OutputTokens int64         `json:"output_tokens"`
+	Cost         float64       `json:"cost"`
+	Duration     time.Duration `json:"duration"`
+	Operations   int           `json:"operations"`
+	Successes    int           `json:"successes"`
 }
 
 // ModelSummary provides per-model statistics
 type ModelSummary struct {
-	Model        string
-	InputTokens  int64
-	OutputTokens int64
-	Cost         float64
-	Operations   int
+	Model        string  `json:"model"`
+	InputTokens  int64   `json:"input_tokens"`
+	OutputTokens int64   `json:"output_tokens"`
+	Cost         float64 `json:"cost"`
+	Operations   int     `json:"operations"`
 }
 
 // PrintSummary prints a formatted summary of usage
 func (s *UsageSummary) PrintSummary() {
-	fmt.Println("\n📊 AI Model Usage Summary")
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	output.Println("\n📊 AI Model Usage Summary")
+	output.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 
 	// Overall stats
-	fmt.Printf("\n📈 Overall Statistics:\n")
-	fmt.Printf("   Operations: %d/%d successful\n", s.SuccessfulOperations, s.TotalOperations)
-	fmt.Printf("   Total Tokens: %s input, %s output\n",
-		formatTokens(s.TotalInputTokens),
-		formatTokens(s.TotalOutputTokens))
-	fmt.Printf("   Estimated Cost: $%.4f\n", s.TotalCost)
-	fmt.Printf("   Total Duration: %v\n", s.TotalDuration.Round(time.Second))
+	output.Printf("\n📈 Overall Statistics:\n")
+	output.Printf("   Operations: %d/%d successful\n", s.SuccessfulOperations, s.TotalOperations)
+	output.Printf("   Total Tokens: %s input, %s output\n",
+		FormatTokens(s.TotalInputTokens),
+		FormatTokens(s.TotalOutputTokens))
+	output.Printf("   Estimated Cost: $%.4f\n", s.TotalCost)
+	if s.Scans > 0 {
+		output.Printf("   Cost per Scan: $%.4f (%d scans)\n", s.CostPerScan(), s.Scans)
+	}
+	if s.Findings > 0 {
+		output.Printf("   Cost per Finding: $%.4f (%d findings)\n", s.CostPerFinding(), s.Findings)
+	}
+	output.Printf("   Total Duration: %v\n", s.TotalDuration.Round(time.Second))
 
 	// By agent
 	if len(s.ByAgent) > 0 {
-		fmt.Printf("\n🤖 By Agent:\n")
+		output.Printf("\n🤖 By Agent:\n")
 		for _, agent := range s.ByAgent {
-			fmt.Printf("   %s (using %s)\n", agent.Agent, getModelShortName(agent.Model))
-			fmt.Printf("      Tokens: %s in, %s out\n",
-				formatTokens(agent.InputTokens),
-				formatTokens(agent.OutputTokens))
-			fmt.Printf("      Cost: $%.4f | Duration: %v | Success: %d/%d\n",
+			output.Printf("   %s (using %s)\n", agent.Agent, getModelShortName(agent.Model))
+			output.Printf("      Tokens: %s in, %s out\n",
+				FormatTokens(agent.InputTokens),
+				FormatTokens(agent.OutputTokens))
+			output.Printf("      Cost: $%.4f | Duration: %v | Success: %d/%d\n",
 				agent.Cost,
 				agent.Duration.Round(time.Second),
 				agent.Successes,
@@ -190,24 +247,25 @@ func (s *UsageSummary) PrintSummary() {
 
 	// By model
 	if len(s.ByModel) > 0 {
-		fmt.Printf("\n🎯 By Model:\n")
+		output.Printf("\n🎯 By Model:\n")
 		for _, model := range s.ByModel {
-			fmt.Printf("   %s\n", getModelDisplayName(model.Model))
-			fmt.Printf("      Tokens: %s in, %s out\n",
-				formatTokens(model.InputTokens),
-				formatTokens(model.OutputTokens))
-			fmt.Printf("      Cost: $%.4f | Operations: %d\n",
+			output.Printf("   %s\n", getModelDisplayName(model.Model))
+			output.Printf("      Tokens: %s in, %s out\n",
+				FormatTokens(model.InputTokens),
+				FormatTokens(model.OutputTokens))
+			output.Printf("      Cost: $%.4f | Operations: %d\n",
 				model.Cost,
 				model.Operations)
 		}
 	}
 
-	fmt.Println("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	output.Println("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 }
 
 // Helper functions
 
-func formatTokens(tokens int64) string {
+// FormatTokens abbreviates a token count for display, e.g. 12.3K
+func FormatTokens(tokens int64) string {
 	if tokens < 1000 {
 		return fmt.Sprintf("%d", tokens)
 	}
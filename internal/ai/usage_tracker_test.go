@@ -0,0 +1,46 @@
+package ai
+
+import (
+	"math"
+	"testing"
+)
+
+func TestUsageTrackerCostPerFinding(t *testing.T) {
+	tracker := NewUsageTracker()
+	usage := UsageStats{Model: "claude-sonnet-4.5-20250929", InputTokens: 100_000, OutputTokens: 10_000}
+	tracker.RecordUsage(usage)
+	tracker.RecordUsage(usage)
+
+	// No scans recorded: nothing to divide by
+	summary := tracker.GetSummary()
+	if summary.CostPerScan() != 0 || summary.CostPerFinding() != 0 {
+		t.Errorf("per-scan %f, per-finding %f without scans; want 0", summary.CostPerScan(), summary.CostPerFinding())
+	}
+
+	// A scan reviewed then analyzed counts once
+	tracker.RecordScan("scan-1", 3)
+	tracker.RecordScan("scan-1", 3)
+	tracker.RecordScan("scan-2", 1)
+	summary = tracker.GetSummary()
+	if summary.Scans != 2 || summary.Findings != 4 {
+		t.Fatalf("scans %d, findings %d; want 2 and 4", summary.Scans, summary.Findings)
+	}
+	want := 2 * usage.CalculateCost()
+	if math.Abs(summary.CostPerFinding()-want/4) > 1e-9 || math.Abs(summary.CostPerScan()-want/2) > 1e-9 {
+		t.Errorf("per-finding %f, per-scan %f; want %f and %f", summary.CostPerFinding(), summary.CostPerScan(), want/4, want/2)
+	}
+}
+
+func TestUsageTrackerScanWithoutFindings(t *testing.T) {
+	tracker := NewUsageTracker()
+	tracker.RecordUsage(UsageStats{Model: "claude-haiku-4.5", InputTokens: 1000})
+	tracker.RecordScan("", 0)
+
+	summary := tracker.GetSummary()
+	if summary.Scans != 1 || summary.CostPerScan() == 0 {
+		t.Errorf("unsaved scan not counted: %+v", summary)
+	}
+	if summary.CostPerFinding() != 0 {
+		t.Errorf("per-finding cost with no findings = %f, want 0", summary.CostPerFinding())
+	}
+}
@@ -0,0 +1,256 @@
+package batch
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Item states. A target moves pending → scanned → done; failed items are
+// not retried. Scanned items resume at the analysis step.
+const (
+	StatusPending = "pending"
+	StatusScanned = "scanned"
+	StatusDone    = "done"
+	StatusFailed  = "failed"
+)
+
+// Item is one target of a batch
+type Item struct {
+	Target string `json:"target"`
+	Status string `json:"status"`
+	ScanID string `json:"scan_id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Queue is the persisted state of a multi-target scan. It is saved after
+// every step, so an interrupted batch resumes where it stopped.
+type Queue struct {
+	Source     string    `json:"source"`                // targets file the batch was created from
+	SourceHash string    `json:"source_hash,omitempty"` // HashTargets of its targets, see Matches
+	Items      []Item    `json:"items"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+
+	path string
+}
+
+// Runner scans and analyzes one target. Scan returns the stored scan's ID.
+type Runner interface {
+	Scan(ctx context.Context, target string) (string, error)
+	Analyze(ctx context.Context, scanID string) error
+}
+
+// Options control how a batch reacts to rate limiting
+type Options struct {
+	Analyze     bool             // run the analysis step after each scan
+	Cooldown    time.Duration    // pause before retrying a rate-limited analysis
+	MaxPauses   int              // consecutive pauses before the batch gives up; 0 for no limit
+	RateLimited func(error) bool // reports whether an error is a persistent rate limit
+	Progress    func(string)     // optional status messages
+}
+
+// ErrTooManyPauses stops a batch whose provider stays rate limited; the
+// queue is saved and the batch can be resumed later
+var ErrTooManyPauses = errors.New("still rate limited after repeated cooldowns")
+
+// DefaultDir returns ~/.shadow/batches
+func DefaultDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".shadow", "batches"), nil
+}
+
+// PathFor returns the queue file for a targets file, keyed by its absolute
+// path so the same file always resumes the same batch. An edited file
+// still maps to the same queue; Matches tells whether it changed.
+func PathFor(dir string, targetsFile string) string {
+	if abs, err := filepath.Abs(targetsFile); err == nil {
+		targetsFile = abs
+	}
+	sum := sha256.Sum256([]byte(targetsFile))
+	return filepath.Join(dir, hex.EncodeToString(sum[:6])+".json")
+}
+
+// New creates a queue at path with every target pending
+func New(path string, source string, targets []string) *Queue {
+	queue := &Queue{
+		Source:     source,
+		SourceHash: HashTargets(targets),
+		Items:      make([]Item, 0, len(targets)),
+		CreatedAt:  time.Now(),
+		path:       path,
+	}
+	for _, target := range targets {
+		queue.Items = append(queue.Items, Item{Target: target, Status: StatusPending})
+	}
+	return queue
+}
+
+// HashTargets returns a hash of the target list, in order. Comments and
+// blank lines of the targets file are not part of it.
+func HashTargets(targets []string) string {
+	hash := sha256.New()
+	for _, target := range targets {
+		hash.Write([]byte(target + "\n"))
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Matches reports whether the queue was created from targets. Queues saved
+// before SourceHash was recorded are compared by their items.
+func (q *Queue) Matches(targets []string) bool {
+	if q.SourceHash != "" {
+		return q.SourceHash == HashTargets(targets)
+	}
+	queued := make([]string, 0, len(q.Items))
+	for _, item := range q.Items {
+		queued = append(queued, item.Target)
+	}
+	return HashTargets(queued) == HashTargets(targets)
+}
+
+// Load reads a queue saved at path
+func Load(path string) (*Queue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var queue Queue
+	if err := json.Unmarshal(data, &queue); err != nil {
+		return nil, fmt.Errorf("failed to parse batch queue %s: %w", path, err)
+	}
+	queue.path = path
+	return &queue, nil
+}
+
+// Path returns the file the queue is saved to
+func (q *Queue) Path() string {
+	return q.path
+}
+
+// Save writes the queue, replacing the previous copy atomically
+func (q *Queue) Save() error {
+	if err := os.MkdirAll(filepath.Dir(q.path), 0700); err != nil {
+		return fmt.Errorf("failed to create batch directory: %w", err)
+	}
+
+	q.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(q, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode batch queue: %w", err)
+	}
+
+	tmpPath := q.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to save batch queue: %w", err)
+	}
+	if err := os.Rename(tmpPath, q.path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to save batch queue: %w", err)
+	}
+	return nil
+}
+
+// Count returns how many items have the given status
+func (q *Queue) Count(status string) int {
+	count := 0
+	for _, item := range q.Items {
+		if item.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Remaining returns how many items still need work
+func (q *Queue) Remaining() int {
+	return q.Count(StatusPending) + q.Count(StatusScanned)
+}
+
+// Run works through the queue in order. A rate-limited analysis pauses
+// the whole batch for the cooldown and is then retried, so targets are
+// never skipped because the provider was busy. The queue is saved after
+// every step; Run returns early, with the queue still resumable, when ctx
+// is cancelled or MaxPauses cooldowns in a row didn't help.
+func (q *Queue) Run(ctx context.Context, runner Runner, opts Options) error {
+	progress := opts.Progress
+	if progress == nil {
+		progress = func(string) {}
+	}
+
+	pauses := 0
+	for i := range q.Items {
+		item := &q.Items[i]
+
+		for item.Status == StatusPending || item.Status == StatusScanned {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			if item.Status == StatusPending {
+				progress(fmt.Sprintf("[%d/%d] Scanning %s", i+1, len(q.Items), item.Target))
+				scanID, err := runner.Scan(ctx, item.Target)
+				switch {
+				case err != nil && ctx.Err() != nil:
+					return ctx.Err()
+				case err != nil:
+					item.Status, item.Error = StatusFailed, err.Error()
+				case opts.Analyze:
+					item.Status, item.ScanID = StatusScanned, scanID
+				default:
+					item.Status, item.ScanID = StatusDone, scanID
+				}
+			} else {
+				progress(fmt.Sprintf("[%d/%d] Analyzing %s (scan %s)", i+1, len(q.Items), item.Target, item.ScanID))
+				err := runner.Analyze(ctx, item.ScanID)
+				switch {
+				case err != nil && ctx.Err() != nil:
+					return ctx.Err()
+				case err != nil && opts.RateLimited != nil && opts.RateLimited(err):
+					pauses++
+					if opts.MaxPauses > 0 && pauses > opts.MaxPauses {
+						return fmt.Errorf("%w (%d cooldowns): %v", ErrTooManyPauses, opts.MaxPauses, err)
+					}
+					progress(fmt.Sprintf("Rate limited; pausing the batch for %v (cooldown %d)", opts.Cooldown, pauses))
+					if err := sleep(ctx, opts.Cooldown); err != nil {
+						return err
+					}
+					continue
+				case err != nil:
+					item.Status, item.Error = StatusFailed, err.Error()
+				default:
+					item.Status, item.Error = StatusDone, ""
+					pauses = 0
+				}
+			}
+
+			if err := q.Save(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// sleep waits for d unless ctx ends first
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,151 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var errRateLimited = errors.New("429 rate limit exceeded")
+
+// fakeRunner scans every target and rate-limits the first limited
+// analyses before succeeding
+type fakeRunner struct {
+	limited  int
+	scanned  []string
+	analyzed []string
+	failScan map[string]bool
+}
+
+func (r *fakeRunner) Scan(ctx context.Context, target string) (string, error) {
+	if r.failScan[target] {
+		return "", errors.New("connection refused")
+	}
+	r.scanned = append(r.scanned, target)
+	return "scan-" + target, nil
+}
+
+func (r *fakeRunner) Analyze(ctx context.Context, scanID string) error {
+	if r.limited > 0 {
+		r.limited--
+		return errRateLimited
+	}
+	r.analyzed = append(r.analyzed, scanID)
+	return nil
+}
+
+func testOptions(maxPauses int, messages *[]string) Options {
+	return Options{
+		Analyze:     true,
+		Cooldown:    time.Millisecond,
+		MaxPauses:   maxPauses,
+		RateLimited: func(err error) bool { return errors.Is(err, errRateLimited) },
+		Progress:    func(msg string) { *messages = append(*messages, msg) },
+	}
+}
+
+func TestRunPausesOnRateLimitAndRecovers(t *testing.T) {
+	queue := New(filepath.Join(t.TempDir(), "queue.json"), "targets.txt", []string{"a.example", "b.example", "c.example"})
+	runner := &fakeRunner{limited: 2, failScan: map[string]bool{"b.example": true}}
+
+	var messages []string
+	if err := queue.Run(context.Background(), runner, testOptions(3, &messages)); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if strings.Join(runner.analyzed, ",") != "scan-a.example,scan-c.example" {
+		t.Errorf("analyzed %v, want every scanned target despite the rate limit", runner.analyzed)
+	}
+	if queue.Count(StatusDone) != 2 || queue.Count(StatusFailed) != 1 || queue.Remaining() != 0 {
+		t.Errorf("done %d, failed %d, remaining %d; want 2, 1, 0",
+			queue.Count(StatusDone), queue.Count(StatusFailed), queue.Remaining())
+	}
+	pauses := 0
+	for _, msg := range messages {
+		if strings.Contains(msg, "pausing the batch") {
+			pauses++
+		}
+	}
+	if pauses != 2 {
+		t.Errorf("paused %d times, want 2", pauses)
+	}
+}
+
+func TestRunStopsAfterMaxPausesAndResumes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.json")
+	targets := []string{"a.example", "b.example"}
+	queue := New(path, "targets.txt", targets)
+	runner := &fakeRunner{limited: 10}
+
+	var messages []string
+	if err := queue.Run(context.Background(), runner, testOptions(2, &messages)); !errors.Is(err, ErrTooManyPauses) {
+		t.Fatalf("Run error = %v, want ErrTooManyPauses", err)
+	}
+
+	// The saved queue picks up at the analysis step, without rescanning
+	saved, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !saved.Matches(targets) || saved.Items[0].Status != StatusScanned || saved.Remaining() != 2 {
+		t.Fatalf("saved queue = %+v", saved.Items)
+	}
+	runner.limited = 0
+	if err := saved.Run(context.Background(), runner, testOptions(2, &messages)); err != nil {
+		t.Fatalf("resumed Run: %v", err)
+	}
+	if len(runner.scanned) != 2 || saved.Count(StatusDone) != 2 {
+		t.Errorf("scanned %v, done %d; want each target scanned once and both done", runner.scanned, saved.Count(StatusDone))
+	}
+}
+
+func TestRunStopsOnCancel(t *testing.T) {
+	queue := New(filepath.Join(t.TempDir(), "queue.json"), "targets.txt", []string{"a.example"})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var messages []string
+	if err := queue.Run(ctx, &fakeRunner{}, testOptions(0, &messages)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Run error = %v, want context.Canceled", err)
+	}
+	if queue.Remaining() != 1 {
+		t.Errorf("remaining %d, want the target left for a resume", queue.Remaining())
+	}
+}
+
+func TestQueueMatches(t *testing.T) {
+	queue := New("queue.json", "targets.txt", []string{"a.example", "b.example"})
+
+	if !queue.Matches([]string{"a.example", "b.example"}) {
+		t.Error("same targets don't match")
+	}
+	for _, edited := range [][]string{
+		{"b.example", "a.example"},
+		{"a.example"},
+		{"a.example", "b.example", "c.example"},
+		{"a.exampleb.example"},
+	} {
+		if queue.Matches(edited) {
+			t.Errorf("edited targets %q match", edited)
+		}
+	}
+
+	// Queues saved before the hash was recorded compare their items
+	queue.SourceHash = ""
+	if !queue.Matches([]string{"a.example", "b.example"}) || queue.Matches([]string{"a.example"}) {
+		t.Error("queue without a hash compared wrongly")
+	}
+}
+
+func TestPathForIsStable(t *testing.T) {
+	dir := t.TempDir()
+	if PathFor(dir, "targets.txt") != PathFor(dir, "./targets.txt") {
+		t.Error("relative spellings of one file map to different queues")
+	}
+	if PathFor(dir, "targets.txt") == PathFor(dir, "other.txt") {
+		t.Error("different files share a queue")
+	}
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+// Config mirrors ~/.shadow/config.yaml (see AuthManager.GenerateAPIKeyConfig)
+type Config struct {
+	Scanning ScanningConfig `yaml:"scanning"`
+	AI       AIConfig       `yaml:"ai"`
+}
+
+// ScanningConfig holds scan engine settings
+type ScanningConfig struct {
+	Threads           int           `yaml:"threads"`
+	Timeout           time.Duration `yaml:"timeout"`
+	RateLimit         int           `yaml:"rate_limit"`
+	MaxEvidenceLength int           `yaml:"max_evidence_length"` // bytes kept per finding
+	PrivilegedTools   []string      `yaml:"privileged_tools"`    // names or absolute paths smart-scan may run; empty uses the defaults
+	ToolTimeout       time.Duration `yaml:"tool_timeout"`        // per external tool in smart-scan; 0 uses the plan's estimate
+
+	// SeverityOverrides sets the severity of every finding of a type,
+	// e.g. security-header: high, for teams that weigh issues differently
+	SeverityOverrides map[string]string `yaml:"severity_overrides"`
+}
+
+// AIConfig holds AI analysis settings
+type AIConfig struct {
+	Enabled               bool          `yaml:"enabled"`
+	AutoAnalyze           bool          `yaml:"auto_analyze"`
+	RetryAttempts         int           `yaml:"retry_attempts"`
+	RetryDelay            time.Duration `yaml:"retry_delay"`
+	MaxConcurrentRequests int           `yaml:"max_concurrent_requests"`
+	ClockSkewTolerance    time.Duration `yaml:"clock_skew_tolerance"`   // slack around OAuth expiry
+	CostConfirmThreshold  float64       `yaml:"cost_confirm_threshold"` // USD; deep/research runs above this ask first
+	StructuredAnalysis    bool          `yaml:"structured_analysis"`    // ask agents for JSON instead of scraping markdown
+	MaxPromptTokens       int64         `yaml:"max_prompt_tokens"`      // estimated ceiling per prompt; 0 uses the default
+	AnalysisTimeout       time.Duration `yaml:"analysis_timeout"`       // per analysis call; 0 uses the default
+	QueryTimeout          time.Duration `yaml:"query_timeout"`          // per query, retries included; 0 uses the default
+}
+
+// Default returns the configuration used when no config file exists
+func Default() *Config {
+	return &Config{
+		Scanning: ScanningConfig{
+			Threads:           50,
+			Timeout:           30 * time.Second,
+			RateLimit:         100,
+			MaxEvidenceLength: models.DefaultMaxEvidenceLength,
+		},
+		AI: AIConfig{
+			Enabled:               true,
+			RetryAttempts:         3,
+			RetryDelay:            15 * time.Second,
+			MaxConcurrentRequests: 2,
+			ClockSkewTolerance:    2 * time.Minute,
+			CostConfirmThreshold:  1.0,
+			AnalysisTimeout:       10 * time.Minute,
+			QueryTimeout:          5 * time.Minute,
+		},
+	}
+}
+
+// DefaultPath returns ~/.shadow/config.yaml
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".shadow", "config.yaml"), nil
+}
+
+// Load reads the config file at path, layered over the defaults.
+// A missing file is not an error.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// validate checks values the YAML types can't, normalizing them in place
+func (c *Config) validate() error {
+	for findingType, severity := range c.Scanning.SeverityOverrides {
+		if models.SeverityRank(severity) == 0 {
+			return fmt.Errorf("scanning.severity_overrides: %q for %s is not one of %s",
+				severity, findingType, strings.Join(models.SeverityLevels, ", "))
+		}
+		c.Scanning.SeverityOverrides[findingType] = strings.ToLower(strings.TrimSpace(severity))
+	}
+	return nil
+}
+
+// LoadDefault loads ~/.shadow/config.yaml
+func LoadDefault() (*Config, error) {
+	path, err := DefaultPath()
+	if err != nil {
+		return Default(), err
+	}
+	return Load(path)
+}
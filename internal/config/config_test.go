@@ -0,0 +1,86 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadLayersOverDefaults(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `
+scanning:
+  threads: 10
+  severity_overrides:
+    security-header: " High "
+ai:
+  retry_delay: 30s
+  max_concurrent_requests: 4
+  clock_skew_tolerance: 5m
+  cost_confirm_threshold: 2.5
+  max_prompt_tokens: 80000
+  analysis_timeout: 30m
+`))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Scanning.Threads != 10 || cfg.AI.RetryDelay != 30*time.Second || cfg.AI.MaxConcurrentRequests != 4 {
+		t.Errorf("threads %d, retry delay %s, max requests %d; want the file's values", cfg.Scanning.Threads, cfg.AI.RetryDelay, cfg.AI.MaxConcurrentRequests)
+	}
+	if cfg.AI.ClockSkewTolerance != 5*time.Minute || cfg.AI.CostConfirmThreshold != 2.5 {
+		t.Errorf("clock skew tolerance %s, cost threshold %.2f; want 5m and 2.50", cfg.AI.ClockSkewTolerance, cfg.AI.CostConfirmThreshold)
+	}
+	if cfg.AI.MaxPromptTokens != 80000 {
+		t.Errorf("max prompt tokens %d, want 80000", cfg.AI.MaxPromptTokens)
+	}
+	if cfg.AI.AnalysisTimeout != 30*time.Minute || cfg.AI.QueryTimeout != Default().AI.QueryTimeout {
+		t.Errorf("analysis timeout %s, query timeout %s; want 30m and the default", cfg.AI.AnalysisTimeout, cfg.AI.QueryTimeout)
+	}
+	if cfg.AI.RetryAttempts != Default().AI.RetryAttempts || cfg.Scanning.Timeout != Default().Scanning.Timeout {
+		t.Error("settings missing from the file lost their defaults")
+	}
+	if cfg.Scanning.SeverityOverrides["security-header"] != "high" {
+		t.Errorf("override = %q, want it normalized to high", cfg.Scanning.SeverityOverrides["security-header"])
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil || cfg.Scanning.Threads != Default().Scanning.Threads {
+		t.Errorf("Load(missing) = %+v, %v; want the defaults", cfg, err)
+	}
+}
+
+func TestLoadMalformedFile(t *testing.T) {
+	_, err := Load(writeConfig(t, "ai: [unclosed\n"))
+	if err == nil || !strings.Contains(err.Error(), "failed to parse config") {
+		t.Errorf("Load error = %v, want a parse error", err)
+	}
+}
+
+func TestDefaultMaxEvidenceLength(t *testing.T) {
+	cfg, err := Load(writeConfig(t, "scanning:\n  max_evidence_length: 1024\n"))
+	if err != nil || cfg.Scanning.MaxEvidenceLength != 1024 {
+		t.Errorf("max evidence length = %d, %v; want 1024", cfg.Scanning.MaxEvidenceLength, err)
+	}
+	if Default().Scanning.MaxEvidenceLength <= 0 {
+		t.Error("no default evidence limit")
+	}
+}
+
+func TestLoadRejectsUnknownSeverity(t *testing.T) {
+	_, err := Load(writeConfig(t, "scanning:\n  severity_overrides:\n    open-port: urgent\n"))
+	if err == nil || !strings.Contains(err.Error(), `"urgent" for open-port`) {
+		t.Errorf("Load error = %v, want the bad override named", err)
+	}
+}
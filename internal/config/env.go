@@ -0,0 +1,76 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// envFileKeys are the variables .env files may set. Other entries, such
+// as a project's own settings in its .env, are left alone.
+var envFileKeys = map[string]bool{
+	"ANTHROPIC_API_KEY": true,
+}
+
+// EnvFiles returns the .env files loaded at startup, highest priority
+// first: the project-local ./.env, then ~/.shadow/.env (written by
+// auth-setup)
+func EnvFiles() []string {
+	files := []string{".env"}
+	if home, err := os.UserHomeDir(); err == nil {
+		files = append(files, filepath.Join(home, ".shadow", ".env"))
+	}
+	return files
+}
+
+// LoadEnvFile sets the variables Shadow uses from a file of KEY=value
+// lines, without overriding variables that are already set. Blank lines,
+// # comments, an "export " prefix and quoted values are accepted; lines
+// that don't set one of Shadow's variables are skipped, whatever their
+// format. A missing file is not an error. Returns the names of the
+// variables set.
+func LoadEnvFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	set := make([]string, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// Other tools' lines are skipped before they are validated, so a
+		// project .env in a format Shadow doesn't parse can't stop it
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		key = strings.TrimSpace(key)
+		if !envFileKeys[key] {
+			continue
+		}
+		if !ok {
+			return set, fmt.Errorf("%s:%d: expected %s=value", path, lineNo, key)
+		}
+		if _, exists := os.LookupEnv(key); exists {
+			continue
+		}
+
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return set, fmt.Errorf("failed to set %s: %w", key, err)
+		}
+		set = append(set, key)
+	}
+	return set, scanner.Err()
+}
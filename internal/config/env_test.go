@@ -0,0 +1,85 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeEnvFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadEnvFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"plain", "ANTHROPIC_API_KEY=sk-plain\n", "sk-plain"},
+		{"export and quotes", "export ANTHROPIC_API_KEY=\"sk-quoted\"\n", "sk-quoted"},
+		{"single quotes", "ANTHROPIC_API_KEY='sk-single'\n", "sk-single"},
+		{"comments and blanks", "# key\n\n  ANTHROPIC_API_KEY = sk-spaced  \n", "sk-spaced"},
+		{"other tools' lines", "OTHER=1\n[section]\nsome free text\nANTHROPIC_API_KEY=sk-mixed\n", "sk-mixed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ANTHROPIC_API_KEY", "")
+			os.Unsetenv("ANTHROPIC_API_KEY")
+
+			set, err := LoadEnvFile(writeEnvFile(t, tt.content))
+			if err != nil {
+				t.Fatalf("LoadEnvFile: %v", err)
+			}
+			if len(set) != 1 || set[0] != "ANTHROPIC_API_KEY" {
+				t.Errorf("set = %v, want [ANTHROPIC_API_KEY]", set)
+			}
+			if got := os.Getenv("ANTHROPIC_API_KEY"); got != tt.want {
+				t.Errorf("ANTHROPIC_API_KEY = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadEnvFileKeepsExistingAndIgnoresOtherKeys(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "sk-from-env")
+	t.Setenv("SHADOW_TEST_OTHER", "")
+	os.Unsetenv("SHADOW_TEST_OTHER")
+
+	set, err := LoadEnvFile(writeEnvFile(t, "ANTHROPIC_API_KEY=sk-from-file\nSHADOW_TEST_OTHER=1\n"))
+	if err != nil {
+		t.Fatalf("LoadEnvFile: %v", err)
+	}
+	if len(set) != 0 {
+		t.Errorf("set = %v, want none", set)
+	}
+	if got := os.Getenv("ANTHROPIC_API_KEY"); got != "sk-from-env" {
+		t.Errorf("ANTHROPIC_API_KEY = %q, want the existing value", got)
+	}
+	if _, ok := os.LookupEnv("SHADOW_TEST_OTHER"); ok {
+		t.Error("SHADOW_TEST_OTHER was set from the file")
+	}
+}
+
+func TestLoadEnvFileMalformedShadowLine(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	os.Unsetenv("ANTHROPIC_API_KEY")
+
+	_, err := LoadEnvFile(writeEnvFile(t, "# comment\nANTHROPIC_API_KEY\n"))
+	if err == nil || !strings.Contains(err.Error(), ".env:2: expected ANTHROPIC_API_KEY=value") {
+		t.Errorf("err = %v, want the malformed line number", err)
+	}
+}
+
+func TestLoadEnvFileMissing(t *testing.T) {
+	set, err := LoadEnvFile(filepath.Join(t.TempDir(), "missing.env"))
+	if err != nil || len(set) != 0 {
+		t.Errorf("LoadEnvFile(missing) = %v, %v; want nothing and no error", set, err)
+	}
+}
@@ -0,0 +1,203 @@
+package doctor
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	pi "github.com/joshp123/pi-golang"
+	"github.com/kumaraguru1735/shadow/internal/ai"
+	"github.com/kumaraguru1735/shadow/internal/scanner"
+)
+
+// Status of a single check
+type Status int
+
+const (
+	Pass Status = iota
+	Warn        // works, but something is degraded or optional is missing
+	Fail
+)
+
+// Result is the outcome of one check
+type Result struct {
+	Name   string
+	Status Status
+	Detail string
+	Fix    string // shown when the check doesn't pass
+}
+
+// Check is one named diagnostic
+type Check struct {
+	Name string
+	Run  func() Result
+}
+
+// aiEndpoint is probed for network reachability
+const aiEndpoint = "api.anthropic.com:443"
+
+// externalTools are referenced by the recon planner
+var externalTools = []struct {
+	name        string
+	versionArgs []string
+}{
+	{"nmap", []string{"--version"}},
+	{"subfinder", []string{"-version"}},
+	{"whatweb", []string{"--version"}},
+	{"dig", []string{"-v"}},
+}
+
+// DefaultChecks returns every check run by `shadow doctor`
+func DefaultChecks() []Check {
+	checks := []Check{
+		{Name: "Authentication", Run: checkAuth},
+		{Name: "pi CLI", Run: checkPiCLI},
+	}
+	for _, tool := range externalTools {
+		tool := tool
+		checks = append(checks, Check{
+			Name: tool.name,
+			Run:  func() Result { return checkTool(tool.name, tool.versionArgs) },
+		})
+	}
+	checks = append(checks,
+		Check{Name: "sudo", Run: checkSudo},
+		Check{Name: "~/.shadow writable", Run: checkShadowDir},
+		Check{Name: "AI endpoint reachable", Run: checkNetwork},
+	)
+	return checks
+}
+
+// RunAll runs the checks in order
+func RunAll(checks []Check) []Result {
+	results := make([]Result, 0, len(checks))
+	for _, check := range checks {
+		result := check.Run()
+		result.Name = check.Name
+		results = append(results, result)
+	}
+	return results
+}
+
+// Print writes the checklist and returns the number of failed checks
+func Print(w io.Writer, results []Result) int {
+	failures, warnings := 0, 0
+	for _, result := range results {
+		icon := "✅"
+		switch result.Status {
+		case Warn:
+			icon = "⚠️ "
+			warnings++
+		case Fail:
+			icon = "❌"
+			failures++
+		}
+
+		fmt.Fprintf(w, "%s %-22s %s\n", icon, result.Name, result.Detail)
+		if result.Status != Pass && result.Fix != "" {
+			fmt.Fprintf(w, "   💡 %s\n", result.Fix)
+		}
+	}
+
+	fmt.Fprintln(w, "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Fprintf(w, "%d passed, %d warnings, %d failed\n", len(results)-failures-warnings, warnings, failures)
+	return failures
+}
+
+func checkAuth() Result {
+	manager, err := ai.NewAuthManager()
+	if err != nil {
+		return Result{Status: Fail, Detail: err.Error()}
+	}
+	status, err := manager.GetAuthStatus()
+	if err != nil {
+		return Result{Status: Fail, Detail: err.Error()}
+	}
+
+	switch {
+	case status.HasOAuth && !status.OAuthExpired:
+		if missing := status.MissingScopes(); len(missing) > 0 && !status.HasAPIKey {
+			return Result{Status: Fail, Detail: fmt.Sprintf("OAuth token lacks scope %v", missing),
+				Fix: "Log in again with Claude Code (claude /login) or set ANTHROPIC_API_KEY"}
+		}
+		return Result{Status: Pass, Detail: fmt.Sprintf("OAuth valid, expires in %v", status.ExpiresIn.Round(time.Minute))}
+	case status.HasAPIKey:
+		detail := "ANTHROPIC_API_KEY set"
+		if status.OAuthExpired {
+			return Result{Status: Warn, Detail: detail + ", OAuth token expired", Fix: "Run: shadow auth-refresh"}
+		}
+		return Result{Status: Pass, Detail: detail}
+	case status.OAuthExpired:
+		return Result{Status: Fail, Detail: "OAuth token expired", Fix: "Run: shadow auth-refresh"}
+	default:
+		return Result{Status: Fail, Detail: "no OAuth token or API key",
+			Fix: "Run: shadow auth-setup, or export ANTHROPIC_API_KEY='sk-ant-...'"}
+	}
+}
+
+func checkPiCLI() Result {
+	command, err := pi.ResolveCommand()
+	if err != nil {
+		return Result{Status: Fail, Detail: "not found",
+			Fix: "Install: npm install -g @mariozechner/pi-coding-agent"}
+	}
+	return Result{Status: Pass, Detail: command.Executable}
+}
+
+func checkTool(name string, versionArgs []string) Result {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return Result{Status: Warn, Detail: "not installed (recon plans that use it will fail)",
+			Fix: fmt.Sprintf("Install %s with your package manager", name)}
+	}
+
+	output, _ := exec.Command(path, versionArgs...).CombinedOutput()
+	version := strings.TrimSpace(strings.SplitN(string(output), "\n", 2)[0])
+	if version == "" {
+		version = path
+	}
+	return Result{Status: Pass, Detail: version}
+}
+
+func checkSudo() Result {
+	if scanner.NewPermissionManager().CheckSudoAvailable() {
+		return Result{Status: Pass, Detail: "passwordless sudo available"}
+	}
+	return Result{Status: Warn, Detail: "not available without a password (privileged scans will prompt or be skipped)",
+		Fix: "Run privileged tools with capabilities instead, e.g. sudo setcap cap_net_raw+ep $(which nmap)"}
+}
+
+func checkShadowDir() Result {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return Result{Status: Fail, Detail: err.Error()}
+	}
+
+	dir := filepath.Join(home, ".shadow")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return Result{Status: Fail, Detail: err.Error(), Fix: "Check ownership and permissions of " + dir}
+	}
+
+	probe, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return Result{Status: Fail, Detail: err.Error(), Fix: "Check ownership and permissions of " + dir}
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	return Result{Status: Pass, Detail: dir}
+}
+
+func checkNetwork() Result {
+	conn, err := net.DialTimeout("tcp", aiEndpoint, 5*time.Second)
+	if err != nil {
+		return Result{Status: Fail, Detail: err.Error(), Fix: "Check your network, proxy and firewall settings"}
+	}
+	conn.Close()
+	return Result{Status: Pass, Detail: aiEndpoint}
+}
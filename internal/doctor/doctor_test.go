@@ -0,0 +1,109 @@
+package doctor
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunAllNamesResults(t *testing.T) {
+	results := RunAll([]Check{
+		{Name: "first", Run: func() Result { return Result{Status: Pass} }},
+		{Name: "second", Run: func() Result { return Result{Name: "ignored", Status: Fail} }},
+	})
+	if len(results) != 2 || results[0].Name != "first" || results[1].Name != "second" {
+		t.Errorf("results = %+v, want check names in order", results)
+	}
+}
+
+func TestPrint(t *testing.T) {
+	var buf bytes.Buffer
+	failures := Print(&buf, []Result{
+		{Name: "Authentication", Status: Pass, Detail: "OAuth valid", Fix: "hidden"},
+		{Name: "nmap", Status: Warn, Detail: "not installed", Fix: "Install nmap"},
+		{Name: "pi CLI", Status: Fail, Detail: "not found", Fix: "Install pi"},
+	})
+	if failures != 1 {
+		t.Errorf("failures = %d, want 1", failures)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"💡 Install nmap", "💡 Install pi", "1 passed, 1 warnings, 1 failed"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "hidden") {
+		t.Errorf("fix shown for a passing check:\n%s", out)
+	}
+}
+
+// withHome points the home directory at a fresh temp dir and, when
+// credentials is non-empty, writes it as the Claude Code credentials file
+func withHome(t *testing.T, credentials string) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if credentials != "" {
+		if err := os.MkdirAll(filepath.Join(home, ".claude"), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(home, ".claude", ".credentials.json"), []byte(credentials), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return home
+}
+
+func TestCheckAuth(t *testing.T) {
+	future := time.Now().Add(time.Hour).UnixMilli()
+	past := time.Now().Add(-time.Hour).UnixMilli()
+	oauth := func(expiresAt int64, scopes string) string {
+		return fmt.Sprintf(`{"claudeAiOauth":{"accessToken":"t","expiresAt":%d,"scopes":[%s]}}`, expiresAt, scopes)
+	}
+
+	tests := []struct {
+		name        string
+		credentials string
+		apiKey      string
+		want        Status
+	}{
+		{"valid oauth", oauth(future, `"user:inference"`), "", Pass},
+		{"missing scope", oauth(future, `"user:profile"`), "", Fail},
+		{"missing scope with api key", oauth(future, `"user:profile"`), "sk-ant-test", Pass},
+		{"expired oauth with api key", oauth(past, ""), "sk-ant-test", Warn},
+		{"expired oauth", oauth(past, ""), "", Fail},
+		{"api key only", "", "sk-ant-test", Pass},
+		{"nothing", "", "", Fail},
+	}
+	for _, tt := range tests {
+		withHome(t, tt.credentials)
+		t.Setenv("ANTHROPIC_API_KEY", tt.apiKey)
+		if got := checkAuth(); got.Status != tt.want {
+			t.Errorf("%s: status %v (%s), want %v", tt.name, got.Status, got.Detail, tt.want)
+		}
+	}
+}
+
+func TestCheckToolMissing(t *testing.T) {
+	got := checkTool("shadow-doctor-no-such-tool", nil)
+	if got.Status != Warn || got.Fix == "" {
+		t.Errorf("got %+v, want a warning with a fix", got)
+	}
+}
+
+func TestCheckShadowDir(t *testing.T) {
+	home := withHome(t, "")
+	got := checkShadowDir()
+	if got.Status != Pass || got.Detail != filepath.Join(home, ".shadow") {
+		t.Errorf("got %+v, want pass for %s", got, filepath.Join(home, ".shadow"))
+	}
+	entries, _ := os.ReadDir(filepath.Join(home, ".shadow"))
+	if len(entries) != 0 {
+		t.Errorf("probe file left behind: %v", entries)
+	}
+}
@@ -0,0 +1,110 @@
+package ignore
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+// DefaultFile is looked up in the working directory when no file is given
+const DefaultFile = ".shadowignore"
+
+// Rule actions
+const (
+	ActionSuppress   = "suppress"
+	ActionReclassify = "reclassify"
+)
+
+// Rule suppresses or reclassifies one finding, identified by fingerprint
+type Rule struct {
+	Fingerprint string `yaml:"fingerprint"`
+	Action      string `yaml:"action"`   // suppress or reclassify
+	Severity    string `yaml:"severity"` // new severity for reclassify
+	Reason      string `yaml:"reason"`
+}
+
+// File is a parsed .shadowignore file:
+//
+//	rules:
+//	  - fingerprint: 3f2a9c0d1b7e4a55
+//	    action: suppress
+//	    reason: accepted risk, tracked in SEC-123
+//	  - fingerprint: 8b1e0f6a2c9d7e41
+//	    action: reclassify
+//	    severity: low
+//	    reason: internal-only service
+type File struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// Load reads and validates an ignore file
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ignore file: %w", err)
+	}
+
+	var file File
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse ignore file %s: %w", path, err)
+	}
+
+	for i, rule := range file.Rules {
+		if strings.TrimSpace(rule.Fingerprint) == "" {
+			return nil, fmt.Errorf("ignore rule %d: fingerprint is required", i+1)
+		}
+		switch rule.Action {
+		case ActionSuppress:
+		case ActionReclassify:
+			if models.SeverityRank(rule.Severity) == 0 {
+				return nil, fmt.Errorf("ignore rule %d: reclassify needs a valid severity, got %q", i+1, rule.Severity)
+			}
+		default:
+			return nil, fmt.Errorf("ignore rule %d: unknown action %q (use suppress or reclassify)", i+1, rule.Action)
+		}
+	}
+
+	return &file, nil
+}
+
+// Apply marks matching findings in place. Suppressed findings are kept but
+// flagged in metadata; reclassified findings keep their original severity
+// in metadata.
+func (f *File) Apply(findings []models.Finding) (suppressed int, reclassified int) {
+	rules := make(map[string]Rule, len(f.Rules))
+	for _, rule := range f.Rules {
+		rules[strings.TrimSpace(rule.Fingerprint)] = rule
+	}
+
+	for i := range findings {
+		finding := &findings[i]
+		rule, ok := rules[finding.EnsureFingerprint()]
+		if !ok {
+			continue
+		}
+
+		if finding.Metadata == nil {
+			finding.Metadata = make(map[string]string)
+		}
+
+		switch rule.Action {
+		case ActionSuppress:
+			finding.Metadata[models.MetaSuppressed] = "true"
+			finding.Metadata[models.MetaSuppressedReason] = rule.Reason
+			suppressed++
+		case ActionReclassify:
+			if _, exists := finding.Metadata[models.MetaSeverityOriginal]; !exists {
+				finding.Metadata[models.MetaSeverityOriginal] = finding.Severity
+			}
+			finding.Metadata["severity.reclassified"] = rule.Reason
+			finding.Severity = strings.ToLower(rule.Severity)
+			reclassified++
+		}
+	}
+
+	return suppressed, reclassified
+}
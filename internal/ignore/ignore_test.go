@@ -0,0 +1,87 @@
+package ignore
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+func writeIgnoreFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), DefaultFile)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestApply(t *testing.T) {
+	findings := []models.Finding{
+		{Type: "security-header", Severity: "medium", Title: "Missing X-Frame-Options header", Location: "https://example.com"},
+		{Type: "open-port", Severity: "medium", Title: "Open TCP port 8080", Location: "example.com:8080"},
+		{Type: "open-port", Severity: "info", Title: "Open TCP port 443", Location: "example.com:443"},
+	}
+	header := findings[0].ComputeFingerprint()
+	port := findings[1].ComputeFingerprint()
+
+	file, err := Load(writeIgnoreFile(t, `rules:
+  - fingerprint: `+header+`
+    action: suppress
+    reason: accepted risk, tracked in SEC-123
+  - fingerprint: " `+port+` "
+    action: reclassify
+    severity: LOW
+    reason: internal-only service
+  - fingerprint: 0000000000000000
+    action: suppress
+`))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	suppressed, reclassified := file.Apply(findings)
+	if suppressed != 1 || reclassified != 1 {
+		t.Fatalf("suppressed %d, reclassified %d; want 1 and 1", suppressed, reclassified)
+	}
+	if !findings[0].IsSuppressed() || findings[0].Metadata[models.MetaSuppressedReason] != "accepted risk, tracked in SEC-123" {
+		t.Errorf("suppressed finding metadata = %v", findings[0].Metadata)
+	}
+	if findings[1].Severity != "low" || findings[1].Metadata["severity.original"] != "medium" || findings[1].IsSuppressed() {
+		t.Errorf("reclassified finding = %s %v", findings[1].Severity, findings[1].Metadata)
+	}
+	if findings[2].Metadata != nil {
+		t.Errorf("unmatched finding changed: %v", findings[2].Metadata)
+	}
+	if active := models.ActiveFindings(findings); len(active) != 2 {
+		t.Errorf("active findings = %d, want 2", len(active))
+	}
+
+	// Applying again keeps the first original severity
+	file.Apply(findings)
+	if findings[1].Metadata["severity.original"] != "medium" {
+		t.Errorf("original severity overwritten: %v", findings[1].Metadata)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{"rules:\n  - action: suppress\n", "rule 1: fingerprint is required"},
+		{"rules:\n  - fingerprint: abc\n    action: reclassify\n    severity: urgent\n", "needs a valid severity"},
+		{"rules:\n  - fingerprint: abc\n    action: delete\n", `unknown action "delete"`},
+		{"rules: [", "failed to parse ignore file"},
+	}
+	for _, tt := range tests {
+		if _, err := Load(writeIgnoreFile(t, tt.content)); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Load(%q) error = %v, want %q", tt.content, err, tt.want)
+		}
+	}
+	if _, err := Load(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("loaded a missing ignore file")
+	}
+}
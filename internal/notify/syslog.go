@@ -0,0 +1,187 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+const syslogTimeout = 10 * time.Second
+
+// syslogFacility is local0; combined with a severity it forms the PRI value
+const syslogFacility = 16
+
+// cefSeverity maps finding severity to the CEF 0-10 scale
+var cefSeverity = map[string]int{
+	"critical": 10,
+	"high":     8,
+	"medium":   5,
+	"low":      3,
+	"info":     1,
+}
+
+// syslogSeverity maps finding severity to the RFC 5424 severity
+var syslogSeverity = map[string]int{
+	"critical": 2, // critical
+	"high":     3, // error
+	"medium":   4, // warning
+	"low":      5, // notice
+	"info":     6, // informational
+}
+
+// Syslog sends each active finding as a CEF message to endpoint, given as
+// udp://host:port, tcp://host:port or tls://host:port. TCP and TLS
+// messages are newline-framed.
+func Syslog(ctx context.Context, endpoint string, version string, result *models.ScanResult) error {
+	network, address, useTLS, err := parseSyslogEndpoint(endpoint)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, syslogTimeout)
+	defer cancel()
+
+	var conn net.Conn
+	if useTLS {
+		dialer := &tls.Dialer{NetDialer: &net.Dialer{}}
+		conn, err = dialer.DialContext(ctx, network, address)
+	} else {
+		var dialer net.Dialer
+		conn, err = dialer.DialContext(ctx, network, address)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to syslog %s: %w", endpoint, err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	hostname, _ := os.Hostname()
+	if hostname == "" {
+		hostname = "-"
+	}
+
+	for _, finding := range models.ActiveFindings(result.Findings) {
+		message := SyslogMessage(hostname, version, result.Target, finding)
+		if network != "udp" {
+			message += "\n"
+		}
+		if _, err := conn.Write([]byte(message)); err != nil {
+			return fmt.Errorf("failed to send syslog message: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// parseSyslogEndpoint splits a udp://, tcp:// or tls:// endpoint
+func parseSyslogEndpoint(endpoint string) (network string, address string, useTLS bool, err error) {
+	parsed, err := url.Parse(endpoint)
+	if err != nil || parsed.Host == "" {
+		return "", "", false, fmt.Errorf("invalid syslog endpoint %q (use udp://, tcp:// or tls://host:port)", endpoint)
+	}
+
+	address = parsed.Host
+	if parsed.Port() == "" {
+		port := "514"
+		if parsed.Scheme == "tls" {
+			port = "6514"
+		}
+		address = net.JoinHostPort(parsed.Hostname(), port)
+	}
+
+	switch parsed.Scheme {
+	case "udp", "tcp":
+		return parsed.Scheme, address, false, nil
+	case "tls":
+		return "tcp", address, true, nil
+	default:
+		return "", "", false, fmt.Errorf("unsupported syslog scheme %q (use udp, tcp or tls)", parsed.Scheme)
+	}
+}
+
+// SyslogMessage formats one finding as an RFC 5424 syslog line carrying a
+// CEF payload
+func SyslogMessage(hostname string, version string, target string, finding models.Finding) string {
+	severity := strings.ToLower(finding.Severity)
+	sysSeverity, ok := syslogSeverity[severity]
+	if !ok {
+		sysSeverity = syslogSeverity["info"]
+	}
+
+	timestamp := finding.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	return fmt.Sprintf("<%d>1 %s %s shadow - - - %s",
+		syslogFacility*8+sysSeverity,
+		timestamp.UTC().Format(time.RFC3339),
+		hostname,
+		CEF(version, target, finding))
+}
+
+// CEF formats a finding as a Common Event Format record:
+// CEF:0|Shadow|Shadow|version|type|title|severity|extensions
+func CEF(version string, target string, finding models.Finding) string {
+	severity, ok := cefSeverity[strings.ToLower(finding.Severity)]
+	if !ok {
+		severity = cefSeverity["info"]
+	}
+
+	signature := finding.Type
+	if signature == "" {
+		signature = "finding"
+	}
+
+	extensions := []string{
+		"dhost=" + cefValue(target),
+		"cat=" + cefValue(finding.Type),
+		"msg=" + cefValue(finding.Description),
+	}
+	if finding.Location != "" {
+		extensions = append(extensions, "request="+cefValue(finding.Location))
+	}
+	if !finding.Timestamp.IsZero() {
+		extensions = append(extensions, fmt.Sprintf("rt=%d", finding.Timestamp.UnixMilli()))
+	}
+	if finding.Fingerprint != "" {
+		extensions = append(extensions, "cs1Label=fingerprint", "cs1="+cefValue(finding.Fingerprint))
+	}
+	if finding.CVE != "" {
+		extensions = append(extensions, "cs2Label=cve", "cs2="+cefValue(finding.CVE))
+	}
+
+	return fmt.Sprintf("CEF:0|Shadow|Shadow|%s|%s|%s|%d|%s",
+		cefHeader(version),
+		cefHeader(signature),
+		cefHeader(finding.Title),
+		severity,
+		strings.Join(extensions, " "))
+}
+
+// cefHeader escapes a CEF header field (backslash and pipe)
+func cefHeader(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, "|", `\|`)
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// cefValue escapes a CEF extension value (backslash, equals and newlines)
+func cefValue(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, "=", `\=`)
+	value = strings.ReplaceAll(value, "\r\n", `\n`)
+	value = strings.ReplaceAll(value, "\n", `\n`)
+	value = strings.ReplaceAll(value, "\r", `\r`)
+	return value
+}
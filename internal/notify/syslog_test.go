@@ -0,0 +1,155 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+func syslogTestResult() *models.ScanResult {
+	return &models.ScanResult{
+		Target: "example.com",
+		Findings: []models.Finding{
+			{
+				Type:        "xss",
+				Title:       "Reflected XSS | search",
+				Severity:    "high",
+				Description: "a=b\nline two",
+				Location:    "https://example.com/search?q=1",
+				Fingerprint: "abc123",
+				Timestamp:   time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+			},
+			{Type: "open-port", Title: "Open TCP port 22", Severity: "info",
+				Metadata: map[string]string{models.MetaSuppressed: "true"}},
+		},
+	}
+}
+
+func TestCEF(t *testing.T) {
+	finding := syslogTestResult().Findings[0]
+	finding.CVE = "CVE-2024-0001"
+
+	got := CEF("1.2.0", "example.com", finding)
+	want := `CEF:0|Shadow|Shadow|1.2.0|xss|Reflected XSS \| search|8|` +
+		`dhost=example.com cat=xss msg=a\=b\nline two request=https://example.com/search?q\=1 ` +
+		`rt=1772366400000 cs1Label=fingerprint cs1=abc123 cs2Label=cve cs2=CVE-2024-0001`
+	if got != want {
+		t.Errorf("CEF =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestCEFEscaping(t *testing.T) {
+	if got := cefHeader(`back\slash|pipe  and
+newline`); got != `back\\slash\|pipe and newline` {
+		t.Errorf("cefHeader = %q", got)
+	}
+	if got := cefValue("a\\b=c\r\nd\re"); got != `a\\b\=c\nd\re` {
+		t.Errorf("cefValue = %q", got)
+	}
+}
+
+func TestSyslogMessage(t *testing.T) {
+	finding := syslogTestResult().Findings[0]
+	got := SyslogMessage("scanner-1", "1.2.0", "example.com", finding)
+	// local0 (16) * 8 + error (3)
+	if !strings.HasPrefix(got, "<131>1 2026-03-01T12:00:00Z scanner-1 shadow - - - CEF:0|Shadow|Shadow|1.2.0|xss|") {
+		t.Errorf("message = %q", got)
+	}
+
+	finding.Severity = "unknown"
+	if got := SyslogMessage("h", "1", "t", finding); !strings.HasPrefix(got, "<134>1 ") {
+		t.Errorf("unknown severity message = %q, want informational priority", got)
+	}
+}
+
+func TestSyslogUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if err := Syslog(context.Background(), "udp://"+conn.LocalAddr().String(), "1.2.0", syslogTestResult()); err != nil {
+		t.Fatalf("Syslog: %v", err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 4096)
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if message := string(buf[:n]); !strings.Contains(message, "|xss|") || strings.HasSuffix(message, "\n") {
+		t.Errorf("datagram = %q, want one unframed xss message", message)
+	}
+
+	// The suppressed finding isn't sent
+	conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	if n, _, err := conn.ReadFrom(buf); err == nil {
+		t.Errorf("unexpected second datagram %q", buf[:n])
+	}
+}
+
+func TestSyslogTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	lines := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			lines <- nil
+			return
+		}
+		defer conn.Close()
+		var got []string
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			got = append(got, scanner.Text())
+		}
+		lines <- got
+	}()
+
+	result := syslogTestResult()
+	result.Findings[1].Metadata = nil
+	if err := Syslog(context.Background(), "tcp://"+listener.Addr().String(), "1.2.0", result); err != nil {
+		t.Fatalf("Syslog: %v", err)
+	}
+
+	got := <-lines
+	if len(got) != 2 || !strings.Contains(got[0], "|xss|") || !strings.Contains(got[1], "|open-port|Open TCP port 22|1|") {
+		t.Errorf("received %q, want one newline-framed message per finding", got)
+	}
+}
+
+func TestParseSyslogEndpoint(t *testing.T) {
+	tests := []struct {
+		endpoint, network, address string
+		tls                        bool
+	}{
+		{"udp://siem.example.com", "udp", "siem.example.com:514", false},
+		{"tcp://10.0.0.5:1514", "tcp", "10.0.0.5:1514", false},
+		{"tls://siem.example.com", "tcp", "siem.example.com:6514", true},
+		{"tls://[2001:db8::1]", "tcp", "[2001:db8::1]:6514", true},
+	}
+	for _, tt := range tests {
+		network, address, useTLS, err := parseSyslogEndpoint(tt.endpoint)
+		if err != nil || network != tt.network || address != tt.address || useTLS != tt.tls {
+			t.Errorf("parseSyslogEndpoint(%q) = %q, %q, %v, %v", tt.endpoint, network, address, useTLS, err)
+		}
+	}
+
+	for _, endpoint := range []string{"siem.example.com:514", "http://siem.example.com"} {
+		if _, _, _, err := parseSyslogEndpoint(endpoint); err == nil {
+			t.Errorf("parseSyslogEndpoint(%q) accepted an invalid endpoint", endpoint)
+		}
+	}
+}
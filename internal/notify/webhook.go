@@ -0,0 +1,59 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/kumaraguru1735/shadow/internal/httpclient"
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+const webhookTimeout = 10 * time.Second
+
+// Event is the JSON body posted to a webhook
+type Event struct {
+	Event       string           `json:"event"` // e.g. "new_findings"
+	Target      string           `json:"target"`
+	ScanID      string           `json:"scan_id"`
+	BaselineID  string           `json:"baseline_id,omitempty"`
+	NewFindings []models.Finding `json:"new_findings"`
+	FixedCount  int              `json:"fixed_count"`
+	Timestamp   time.Time        `json:"timestamp"`
+}
+
+// Webhook posts the event as JSON and expects a 2xx response
+func Webhook(ctx context.Context, url string, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook event: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client, err := httpclient.New(httpclient.Options{UserAgent: "shadow-webhook", Timeout: webhookTimeout})
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned HTTP %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+func TestWebhookPostsEvent(t *testing.T) {
+	received := make(chan Event, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("got %s with Content-Type %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		var event Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("body is not an event: %v", err)
+		}
+		received <- event
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	event := Event{
+		Event:       "new_findings",
+		Target:      "example.com",
+		ScanID:      "scan-2",
+		BaselineID:  "scan-1",
+		NewFindings: []models.Finding{{Title: "Open TCP port 8080", Severity: "medium"}},
+		FixedCount:  1,
+		Timestamp:   time.Now(),
+	}
+	if err := Webhook(context.Background(), server.URL, event); err != nil {
+		t.Fatalf("Webhook: %v", err)
+	}
+
+	got := <-received
+	if got.ScanID != "scan-2" || got.BaselineID != "scan-1" || len(got.NewFindings) != 1 || got.FixedCount != 1 {
+		t.Errorf("webhook received %+v", got)
+	}
+}
+
+func TestWebhookErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	if err := Webhook(context.Background(), server.URL, Event{}); err == nil || !strings.Contains(err.Error(), "HTTP 502") {
+		t.Errorf("Webhook error = %v, want the status reported", err)
+	}
+	if err := Webhook(context.Background(), "://bad", Event{}); err == nil || !strings.Contains(err.Error(), "invalid webhook URL") {
+		t.Errorf("Webhook(bad URL) error = %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Printer serializes progress output from concurrent modules and agents so
+// lines never interleave. On a terminal it can also keep a compact status
+// block at the bottom, one line per active task.
+type Printer struct {
+	mu       sync.Mutex
+	w        io.Writer
+	tty      bool
+	order    []string          // status keys in the order they appeared
+	status   map[string]string // key → current status line
+	rendered int               // status lines currently on screen
+}
+
+// Stdout is the shared printer for command output
+var Stdout = NewPrinter(os.Stdout)
+
+// NewPrinter returns a printer writing to w. The status block is only drawn
+// when w is a terminal.
+func NewPrinter(w io.Writer) *Printer {
+	return &Printer{
+		w:      w,
+		tty:    isTerminal(w),
+		status: make(map[string]string),
+	}
+}
+
+// Println writes one line atomically
+func (p *Printer) Println(line string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.clearStatus()
+	fmt.Fprintln(p.w, Style(strings.TrimRight(line, "\n")))
+	p.drawStatus()
+}
+
+// Printf formats and writes one line atomically
+func (p *Printer) Printf(format string, args ...any) {
+	p.Println(fmt.Sprintf(format, args...))
+}
+
+// Progress returns a callback that prints each message with the given
+// indent; it fits ai.ProgressCallback
+func (p *Printer) Progress(indent string) func(string) {
+	return func(msg string) {
+		p.Println(indent + msg)
+	}
+}
+
+// SetStatus shows or updates the status line for key. Without a terminal
+// status lines are dropped; the regular progress lines still appear.
+func (p *Printer) SetStatus(key string, line string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.tty {
+		return
+	}
+	if _, ok := p.status[key]; !ok {
+		p.order = append(p.order, key)
+	}
+	p.status[key] = line
+
+	p.clearStatus()
+	p.drawStatus()
+}
+
+// ClearStatus removes the status line for key
+func (p *Printer) ClearStatus(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.status[key]; !ok {
+		return
+	}
+	delete(p.status, key)
+	for i, k := range p.order {
+		if k == key {
+			p.order = append(p.order[:i], p.order[i+1:]...)
+			break
+		}
+	}
+
+	p.clearStatus()
+	p.drawStatus()
+}
+
+// clearStatus erases the status block; the caller holds mu
+func (p *Printer) clearStatus() {
+	for ; p.rendered > 0; p.rendered-- {
+		fmt.Fprint(p.w, "\033[1A\033[2K")
+	}
+}
+
+// drawStatus redraws the status block below the log; the caller holds mu
+func (p *Printer) drawStatus() {
+	for _, key := range p.order {
+		fmt.Fprintln(p.w, Style(p.status[key]))
+		p.rendered++
+	}
+}
+
+// isTerminal reports whether w is a character device such as a TTY
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
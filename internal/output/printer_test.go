@@ -0,0 +1,73 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestPrinterLinesDoNotInterleave(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewPrinter(&buf)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				p.Printf("worker %02d line %02d %s", i, j, strings.Repeat("x", 64))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 1000 {
+		t.Fatalf("got %d lines, want 1000", len(lines))
+	}
+	for _, line := range lines {
+		var worker, n int
+		var rest string
+		if _, err := fmt.Sscanf(line, "worker %d line %d %s", &worker, &n, &rest); err != nil || rest != strings.Repeat("x", 64) {
+			t.Fatalf("garbled line %q", line)
+		}
+	}
+}
+
+func TestPrinterDropsStatusWithoutTerminal(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewPrinter(&buf)
+
+	p.SetStatus("recon", "⏳ recon: running")
+	p.Progress("   ")("done")
+	p.ClearStatus("recon")
+
+	if got := buf.String(); got != "   done\n" {
+		t.Errorf("output = %q, want only the progress line", got)
+	}
+}
+
+func TestPrinterRedrawsStatusBlock(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewPrinter(&buf)
+	p.tty = true
+
+	p.SetStatus("recon", "recon: running")
+	p.SetStatus("vuln", "vuln: running")
+	buf.Reset()
+
+	p.Println("finding")
+	const erase = "\033[1A\033[2K"
+	if want := erase + erase + "finding\nrecon: running\nvuln: running\n"; buf.String() != want {
+		t.Errorf("after Println: %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	p.ClearStatus("recon")
+	if want := erase + erase + "vuln: running\n"; buf.String() != want {
+		t.Errorf("after ClearStatus: %q, want %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,178 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// plain is set when output must stay ASCII: NO_COLOR, --no-color, or
+// stdout not being a terminal (logs, pipes, non-UTF consoles)
+var plain bool
+
+// SetPlain turns plain ASCII output on or off for the whole process
+func SetPlain(enabled bool) {
+	plain = enabled
+}
+
+// IsPlain reports whether output is being reduced to plain ASCII
+func IsPlain() bool {
+	return plain
+}
+
+// DetectPlain reports whether output should be plain: when noColor is set
+// (the --no-color flag), NO_COLOR is set to any non-empty value, or stdout
+// is not a terminal
+func DetectPlain(noColor bool) bool {
+	return noColor || os.Getenv("NO_COLOR") != "" || !isTerminal(os.Stdout)
+}
+
+// asciiSymbols are the plain equivalents of the symbols Shadow prints.
+// Status icons become bracketed words; other emoji are dropped by Style.
+var asciiSymbols = map[rune]string{
+	'✅': "[OK]",
+	'❌': "[ERROR]",
+	'⚠': "[WARN]",
+	'ℹ': "[INFO]",
+	'✓': "+",
+	'✗': "x",
+	'━': "-",
+	'─': "-",
+	'═': "=",
+	'│': "|",
+	'┃': "|",
+	'├': "+",
+	'└': "+",
+	'┌': "+",
+	'┐': "+",
+	'┘': "+",
+	'┤': "+",
+	'┬': "+",
+	'┴': "+",
+	'┼': "+",
+	'•': "-",
+	'→': "->",
+	'←': "<-",
+	'…': "...",
+	'≈': "~",
+	'×': "x",
+	'–': "-",
+	'—': "-",
+	'“': `"`,
+	'”': `"`,
+	'‘': "'",
+	'’': "'",
+}
+
+// Style returns s unchanged unless output is plain. In plain mode known
+// symbols and box-drawing become their ASCII equivalents and any other
+// emoji is removed together with the spacing that followed it. Letters in
+// any script are kept.
+func Style(s string) string {
+	if !plain {
+		return s
+	}
+
+	var b strings.Builder
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		replacement, known := asciiSymbols[r]
+		if !known && !isDecoration(r) {
+			b.WriteRune(r)
+			continue
+		}
+		b.WriteString(replacement)
+
+		// Emoji are padded for their display width: collapse the padding to
+		// one space after a status word, and drop it with a removed icon
+		// unless the icon sat between two words
+		spaced := false
+		for i+1 < len(runes) && (isJoiner(runes[i+1]) || (len(replacement) != 1 && runes[i+1] == ' ')) {
+			spaced = spaced || runes[i+1] == ' '
+			i++
+		}
+		if spaced && (replacement != "" || endsInWord(b.String())) {
+			b.WriteRune(' ')
+		}
+	}
+	return b.String()
+}
+
+// endsInWord reports whether s is non-empty and doesn't end in whitespace
+func endsInWord(s string) bool {
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return s != "" && !unicode.IsSpace(r)
+}
+
+// isDecoration reports whether r is an emoji, pictograph, box-drawing or
+// other non-ASCII symbol with no ASCII meaning. ASCII is always kept.
+func isDecoration(r rune) bool {
+	if r < 0x80 {
+		return false
+	}
+	return isJoiner(r) ||
+		unicode.Is(unicode.So, r) ||
+		(r >= 0x1F000 && r <= 0x1FAFF) || // emoji and pictographs
+		(r >= 0x2500 && r <= 0x259F) // box drawing and block elements
+}
+
+// isJoiner reports whether r only modifies a neighbouring emoji
+// (variation selectors, zero-width joiner, keycap)
+func isJoiner(r rune) bool {
+	return (r >= 0xFE00 && r <= 0xFE0F) || r == 0x200D || r == 0x20E3
+}
+
+// styledWriter applies Style to everything written through it
+type styledWriter struct {
+	w io.Writer
+}
+
+// NewWriter returns a writer that applies Style before writing to w. Each
+// Write must hold whole runes, as the fmt functions guarantee.
+func NewWriter(w io.Writer) io.Writer {
+	return styledWriter{w: w}
+}
+
+func (sw styledWriter) Write(p []byte) (int, error) {
+	if !plain {
+		return sw.w.Write(p)
+	}
+	if _, err := io.WriteString(sw.w, Style(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Printf is fmt.Printf with Style applied
+func Printf(format string, args ...any) {
+	fmt.Fprint(os.Stdout, Style(fmt.Sprintf(format, args...)))
+}
+
+// Println is fmt.Println with Style applied
+func Println(args ...any) {
+	fmt.Fprint(os.Stdout, Style(fmt.Sprintln(args...)))
+}
+
+// Print is fmt.Print with Style applied
+func Print(args ...any) {
+	fmt.Fprint(os.Stdout, Style(fmt.Sprint(args...)))
+}
+
+// Fprintf is fmt.Fprintf with Style applied
+func Fprintf(w io.Writer, format string, args ...any) {
+	fmt.Fprint(w, Style(fmt.Sprintf(format, args...)))
+}
+
+// Fprintln is fmt.Fprintln with Style applied
+func Fprintln(w io.Writer, args ...any) {
+	fmt.Fprint(w, Style(fmt.Sprintln(args...)))
+}
+
+// Fprint is fmt.Fprint with Style applied
+func Fprint(w io.Writer, args ...any) {
+	fmt.Fprint(w, Style(fmt.Sprint(args...)))
+}
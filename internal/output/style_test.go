@@ -0,0 +1,59 @@
+package output
+
+import "testing"
+
+// withPlain turns plain output on for one test
+func withPlain(t *testing.T) {
+	t.Helper()
+	original := plain
+	t.Cleanup(func() { plain = original })
+	SetPlain(true)
+}
+
+func TestStyleInfoSymbol(t *testing.T) {
+	withPlain(t)
+	if got := Style("ℹ️  Nothing to analyze"); got != "[INFO] Nothing to analyze" {
+		t.Errorf("Style = %q, want the info symbol as [INFO]", got)
+	}
+}
+
+func TestStylePlain(t *testing.T) {
+	withPlain(t)
+	tests := []struct {
+		in, want string
+	}{
+		{"✅ Scan completed", "[OK] Scan completed"},
+		{"⚠️  Rate limited", "[WARN] Rate limited"},
+		{"🔍 Stage 2/3: Vulnerability Analysis", "Stage 2/3: Vulnerability Analysis"},
+		{"    ✓ Found 3 findings", "    + Found 3 findings"},
+		{"━━━━", "----"},
+		{"Done 🎉 now", "Done now"},
+		{"Done🎉 now", "Done now"},
+		{"x^2 and `code`", "x^2 and `code`"}, // ASCII modifier symbols are kept
+		{"Café résumé, Привет, 東京", "Café résumé, Привет, 東京"},
+	}
+	for _, tt := range tests {
+		if got := Style(tt.in); got != tt.want {
+			t.Errorf("Style(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestStyleNotPlain(t *testing.T) {
+	original := plain
+	defer func() { plain = original }()
+	SetPlain(false)
+	if got := Style("✅ Done 🎉"); got != "✅ Done 🎉" {
+		t.Errorf("Style = %q, want it unchanged", got)
+	}
+}
+
+func TestDetectPlainNoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	if !DetectPlain(false) {
+		t.Error("NO_COLOR set, but output is not plain")
+	}
+	if !DetectPlain(true) {
+		t.Error("--no-color set, but output is not plain")
+	}
+}
@@ -0,0 +1,100 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+// severityColors are the ANSI colors of severity labels on a terminal
+var severityColors = map[string]string{
+	"critical": "\033[1;35m", // bold magenta
+	"high":     "\033[1;31m", // bold red
+	"medium":   "\033[33m",   // yellow
+	"low":      "\033[36m",   // cyan
+	"info":     "\033[2m",    // dim
+}
+
+const colorReset = "\033[0m"
+
+// maxTableTitle caps the "top finding" column so rows fit a terminal
+const maxTableTitle = 60
+
+// ColorSeverity wraps text in the color of severity. Plain output and
+// unknown severities are returned unchanged.
+func ColorSeverity(severity string, text string) string {
+	color, ok := severityColors[strings.ToLower(severity)]
+	if plain || !ok {
+		return text
+	}
+	return color + text + colorReset
+}
+
+// FindingsTable writes an aligned summary of findings to w, one row per
+// severity that has findings, most severe first. Each row shows the count
+// and the most confident finding's title, followed by a total row.
+func FindingsTable(w io.Writer, findings []models.Finding) error {
+	counts := make(map[string]int)
+	top := make(map[string]models.Finding)
+	for _, finding := range findings {
+		severity := strings.ToLower(finding.Severity)
+		counts[severity]++
+		if current, ok := top[severity]; !ok ||
+			models.ConfidenceRank(string(finding.Confidence)) > models.ConfidenceRank(string(current.Confidence)) {
+			top[severity] = finding
+		}
+	}
+
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SEVERITY\tCOUNT\tTOP FINDING")
+
+	rows := make([]string, 0, len(models.SeverityLevels))
+	total := 0
+	for _, severity := range models.SeverityLevels {
+		count := counts[severity]
+		if count == 0 {
+			continue
+		}
+		title := truncateTitle(top[severity].Title)
+		if count > 1 {
+			title += fmt.Sprintf(" (+%d more)", count-1)
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\n", strings.ToUpper(severity), count, title)
+		rows = append(rows, severity)
+		total += count
+	}
+	fmt.Fprintf(tw, "TOTAL\t%d\t\n", total)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	// Colors are added after alignment, since tabwriter would count the
+	// escape codes as text
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	for i, severity := range rows {
+		line := lines[i+1]
+		label := len(severity)
+		lines[i+1] = ColorSeverity(severity, line[:label]) + line[label:]
+	}
+
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, Style(strings.TrimRight(line, " "))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// truncateTitle shortens long titles to maxTableTitle runes
+func truncateTitle(title string) string {
+	runes := []rune(title)
+	if len(runes) <= maxTableTitle {
+		return title
+	}
+	return string(runes[:maxTableTitle-1]) + "…"
+}
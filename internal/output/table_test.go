@@ -0,0 +1,71 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+func TestFindingsTable(t *testing.T) {
+	withPlain(t)
+	findings := []models.Finding{
+		{Severity: "low", Title: "Verbose banner"},
+		{Severity: "HIGH", Title: "Possible SQL injection", Confidence: models.ConfidenceLow},
+		{Severity: "high", Title: "Exposed admin panel", Confidence: models.ConfidenceHigh},
+		{Severity: "high", Title: "Weak TLS", Confidence: models.ConfidenceMedium},
+	}
+
+	var buf bytes.Buffer
+	if err := FindingsTable(&buf, findings); err != nil {
+		t.Fatal(err)
+	}
+	want := "SEVERITY  COUNT  TOP FINDING\n" +
+		"HIGH      3      Exposed admin panel (+2 more)\n" +
+		"LOW       1      Verbose banner\n" +
+		"TOTAL     4\n"
+	if buf.String() != want {
+		t.Errorf("table =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestFindingsTableColors(t *testing.T) {
+	original := plain
+	t.Cleanup(func() { plain = original })
+	SetPlain(false)
+
+	var buf bytes.Buffer
+	if err := FindingsTable(&buf, []models.Finding{{Severity: "critical", Title: "RCE"}}); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(buf.String(), "\n")
+	if lines[1] != "\033[1;35mCRITICAL\033[0m  1      RCE" {
+		t.Errorf("critical row = %q, want only the label colored", lines[1])
+	}
+	if strings.Contains(lines[0], "\033") || strings.Contains(lines[2], "\033") {
+		t.Error("header or total row colored")
+	}
+}
+
+func TestColorSeverity(t *testing.T) {
+	withPlain(t)
+	if got := ColorSeverity("high", "HIGH"); got != "HIGH" {
+		t.Errorf("plain ColorSeverity = %q", got)
+	}
+	SetPlain(false)
+	if got := ColorSeverity("unknown", "X"); got != "X" {
+		t.Errorf("unknown severity colored: %q", got)
+	}
+}
+
+func TestTruncateTitle(t *testing.T) {
+	long := strings.Repeat("é", maxTableTitle+5)
+	got := truncateTitle(long)
+	if len([]rune(got)) != maxTableTitle || !strings.HasSuffix(got, "…") {
+		t.Errorf("truncateTitle = %q (%d runes)", got, len([]rune(got)))
+	}
+	if truncateTitle("short") != "short" {
+		t.Error("short title changed")
+	}
+}
@@ -0,0 +1,32 @@
+package redact
+
+import "regexp"
+
+// Placeholder replaces every redacted secret
+const Placeholder = "[REDACTED]"
+
+var patterns = []struct {
+	re          *regexp.Regexp
+	replacement string
+}{
+	// Anthropic API keys and OAuth tokens
+	{regexp.MustCompile(`sk-ant-[A-Za-z0-9_\-]{8,}`), Placeholder},
+	// Authorization headers and bearer tokens
+	{regexp.MustCompile(`(?i)(authorization\s*[:=]\s*)(bearer\s+|basic\s+)?[^\s"',;]+`), "${1}${2}" + Placeholder},
+	{regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9_\-\.=+/]{8,}`), "${1}" + Placeholder},
+	// Cookie headers (the whole value)
+	{regexp.MustCompile(`(?i)((?:set-)?cookie\s*:\s*)[^\r\n]+`), "${1}" + Placeholder},
+	// key=value / "key": "value" secrets
+	{regexp.MustCompile(`(?i)((?:api[_-]?key|access[_-]?token|refresh[_-]?token|secret|password|passwd|token)["']?\s*[:=]\s*["']?)[^\s"',;&]+`), "${1}" + Placeholder},
+	// JSON Web Tokens
+	{regexp.MustCompile(`eyJ[A-Za-z0-9_\-]{5,}\.[A-Za-z0-9_\-]{5,}\.[A-Za-z0-9_\-]*`), Placeholder},
+}
+
+// String removes API keys, tokens, cookies and passwords from text before
+// it is logged or written to disk
+func String(text string) string {
+	for _, p := range patterns {
+		text = p.re.ReplaceAllString(text, p.replacement)
+	}
+	return text
+}
@@ -0,0 +1,259 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+// maxRecurringIssues caps the "top recurring issues" list
+const maxRecurringIssues = 10
+
+// AggregateData is the model for an executive report across many scans
+type AggregateData struct {
+	Targets        []TargetSummary  `json:"targets"`
+	Summary        Summary          `json:"summary"`
+	TopIssues      []RecurringIssue `json:"top_issues"`
+	PostureSummary string           `json:"posture_summary"`
+	GeneratedAt    time.Time        `json:"generated_at"`
+}
+
+// TargetSummary is one scan's row in the aggregate report
+type TargetSummary struct {
+	ScanID    string    `json:"scan_id"`
+	Target    string    `json:"target"`
+	ScannedAt time.Time `json:"scanned_at"`
+	Summary   Summary   `json:"summary"`
+	RiskScore int       `json:"risk_score"`
+	AIScored  bool      `json:"ai_scored"` // RiskScore came from AI analysis rather than the heuristic
+}
+
+// RecurringIssue is a finding seen on more than one scan
+type RecurringIssue struct {
+	Title       string   `json:"title"`
+	Type        string   `json:"type"`
+	Severity    string   `json:"severity"`
+	Occurrences int      `json:"occurrences"`
+	Targets     []string `json:"targets"`
+}
+
+// NewAggregateData builds the aggregate model for a set of scans
+func NewAggregateData(results []*models.ScanResult) AggregateData {
+	data := AggregateData{
+		Targets:     make([]TargetSummary, 0, len(results)),
+		GeneratedAt: time.Now(),
+	}
+
+	allFindings := make([]models.Finding, 0)
+	issues := make(map[string]*RecurringIssue)
+
+	for _, result := range results {
+		result = result.WithActiveFindings()
+		target := TargetSummary{
+			ScanID:    result.ID,
+			Target:    result.Target,
+			ScannedAt: result.StartTime,
+			Summary:   summarize(result.Findings),
+			RiskScore: models.ComputeRiskScore(result.Findings),
+		}
+		if result.Analysis != nil {
+			target.RiskScore = result.Analysis.RiskScore
+			target.AIScored = true
+		}
+		data.Targets = append(data.Targets, target)
+
+		// Count each issue once per target, however often it repeats there
+		seen := make(map[string]bool)
+		for _, finding := range result.Findings {
+			allFindings = append(allFindings, finding)
+
+			key := strings.ToLower(finding.Type + "|" + finding.Title)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			issue, ok := issues[key]
+			if !ok {
+				issue = &RecurringIssue{Title: finding.Title, Type: finding.Type, Severity: finding.Severity}
+				issues[key] = issue
+			}
+			issue.Occurrences++
+			issue.Targets = append(issue.Targets, result.Target)
+			if models.SeverityRank(finding.Severity) > models.SeverityRank(issue.Severity) {
+				issue.Severity = finding.Severity
+			}
+		}
+	}
+
+	data.Summary = summarize(allFindings)
+
+	for _, issue := range issues {
+		if issue.Occurrences > 1 {
+			data.TopIssues = append(data.TopIssues, *issue)
+		}
+	}
+	sort.Slice(data.TopIssues, func(i, j int) bool {
+		a, b := data.TopIssues[i], data.TopIssues[j]
+		if a.Occurrences != b.Occurrences {
+			return a.Occurrences > b.Occurrences
+		}
+		if models.SeverityRank(a.Severity) != models.SeverityRank(b.Severity) {
+			return models.SeverityRank(a.Severity) > models.SeverityRank(b.Severity)
+		}
+		return a.Title < b.Title
+	})
+	if len(data.TopIssues) > maxRecurringIssues {
+		data.TopIssues = data.TopIssues[:maxRecurringIssues]
+	}
+
+	sort.SliceStable(data.Targets, func(i, j int) bool {
+		return data.Targets[i].RiskScore > data.Targets[j].RiskScore
+	})
+
+	data.PostureSummary = defaultPostureSummary(data)
+
+	return data
+}
+
+// RenderAggregate writes the aggregate report in the requested format
+func RenderAggregate(w io.Writer, format string, data AggregateData) error {
+	switch normalizeFormat(format) {
+	case "html":
+		return aggregateHTML.Execute(w, data)
+	case "markdown":
+		return renderAggregateMarkdown(w, data)
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(data)
+	default:
+		return fmt.Errorf("unknown aggregate report format %q (supported: html, markdown, json)", format)
+	}
+}
+
+// defaultPostureSummary is used when no AI-generated summary is available
+func defaultPostureSummary(data AggregateData) string {
+	critical, high := 0, 0
+	for _, count := range data.Summary.BySeverity {
+		switch count.Severity {
+		case "critical":
+			critical = count.Count
+		case "high":
+			high = count.Count
+		}
+	}
+
+	atRisk := 0
+	for _, target := range data.Targets {
+		if target.RiskScore >= 50 {
+			atRisk++
+		}
+	}
+
+	return fmt.Sprintf("%d findings across %d targets (%d critical, %d high). %d of %d targets have a risk score of 50 or more.",
+		data.Summary.TotalFindings, len(data.Targets), critical, high, atRisk, len(data.Targets))
+}
+
+func renderAggregateMarkdown(w io.Writer, data AggregateData) error {
+	var b strings.Builder
+
+	b.WriteString("# Shadow Executive Security Report\n\n")
+	b.WriteString(fmt.Sprintf("- **Targets**: %d\n", len(data.Targets)))
+	b.WriteString(fmt.Sprintf("- **Generated**: %s\n\n", data.GeneratedAt.Format(time.RFC3339)))
+
+	b.WriteString("## Overall Posture\n\n")
+	b.WriteString(data.PostureSummary + "\n\n")
+
+	b.WriteString("## Findings by Severity\n\n")
+	b.WriteString("| Severity | Count |\n|---|---|\n")
+	for _, count := range data.Summary.BySeverity {
+		b.WriteString(fmt.Sprintf("| %s | %d |\n", count.Severity, count.Count))
+	}
+	b.WriteString(fmt.Sprintf("| **total** | **%d** |\n\n", data.Summary.TotalFindings))
+
+	b.WriteString("## Risk by Target\n\n")
+	b.WriteString("| Target | Risk | Critical | High | Findings | Scan ID |\n|---|---|---|---|---|---|\n")
+	for _, target := range data.Targets {
+		b.WriteString(fmt.Sprintf("| %s | %d | %d | %d | %d | %s |\n",
+			target.Target, target.RiskScore,
+			target.Summary.BySeverity[0].Count, target.Summary.BySeverity[1].Count,
+			target.Summary.TotalFindings, target.ScanID))
+	}
+	b.WriteString("\n")
+
+	b.WriteString("## Top Recurring Issues\n\n")
+	if len(data.TopIssues) == 0 {
+		b.WriteString("No issue was found on more than one target.\n")
+	}
+	for i, issue := range data.TopIssues {
+		b.WriteString(fmt.Sprintf("%d. **[%s] %s** - %d targets (%s)\n",
+			i+1, issue.Severity, issue.Title, issue.Occurrences, strings.Join(issue.Targets, ", ")))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var aggregateHTML = template.Must(template.New("aggregate").Funcs(htmlFuncs).Funcs(template.FuncMap{
+	"join": strings.Join,
+}).Parse(aggregateHTMLTemplate))
+
+const aggregateHTMLTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Shadow Executive Security Report</title>
+<style>
+  body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 0 auto; max-width: 960px; padding: 2rem; line-height: 1.5; color: #1f2328; }
+  .muted { color: #59636e; }
+  table { border-collapse: collapse; margin: 1rem 0; }
+  th, td { border: 1px solid #d1d9e0; padding: 0.3rem 0.8rem; text-align: left; }
+  .sev { display: inline-block; min-width: 4.5em; padding: 0.1em 0.5em; border-radius: 4px; color: #fff; font-size: 0.85em; font-weight: 600; text-align: center; text-transform: uppercase; }
+  .sev-critical { background: #b91c1c; } .sev-high { background: #ea580c; } .sev-medium { background: #ca8a04; } .sev-low { background: #16a34a; } .sev-info { background: #2563eb; }
+</style>
+</head>
+<body>
+<h1>Shadow Executive Security Report</h1>
+<p class="muted">{{len .Targets}} targets &middot; Generated {{formatTime .GeneratedAt}}</p>
+
+<h2>Overall Posture</h2>
+<p>{{.PostureSummary}}</p>
+
+<h2>Findings by Severity</h2>
+<table>
+  <tr><th>Severity</th><th>Findings</th></tr>
+  {{- range .Summary.BySeverity}}
+  <tr><td><span class="sev sev-{{.Severity}}">{{.Severity}}</span></td><td>{{.Count}}</td></tr>
+  {{- end}}
+  <tr><th>Total</th><th>{{.Summary.TotalFindings}}</th></tr>
+</table>
+
+<h2>Risk by Target</h2>
+<table>
+  <tr><th>Target</th><th>Risk</th><th>Findings</th><th>Scan ID</th></tr>
+  {{- range .Targets}}
+  <tr><td>{{.Target}}</td><td>{{.RiskScore}}/100</td><td>{{.Summary.TotalFindings}}</td><td><code>{{.ScanID}}</code></td></tr>
+  {{- end}}
+</table>
+
+<h2>Top Recurring Issues</h2>
+{{- if .TopIssues}}
+<ol>
+  {{- range .TopIssues}}
+  <li><span class="sev sev-{{lower .Severity}}">{{.Severity}}</span> {{.Title}} &mdash; {{.Occurrences}} targets <span class="muted">({{join .Targets ", "}})</span></li>
+  {{- end}}
+</ol>
+{{- else}}
+<p class="muted">No issue was found on more than one target.</p>
+{{- end}}
+</body>
+</html>
+`
@@ -0,0 +1,101 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+func aggregateScans() []*models.ScanResult {
+	hsts := models.Finding{Type: "security-header", Severity: "medium", Title: "Missing Strict-Transport-Security header"}
+	sqli := models.Finding{Type: "sqli", Severity: "critical", Title: "SQL injection in login"}
+	port := models.Finding{Type: "open-port", Severity: "info", Title: "Open TCP port 22"}
+
+	web := testScan() // analyzed: its AI risk score wins over the heuristic
+	web.Findings = []models.Finding{hsts, hsts, port}
+
+	api := &models.ScanResult{ID: "scan-2", Target: "api.example.com", Findings: []models.Finding{sqli, hsts, port}}
+	mail := &models.ScanResult{ID: "scan-3", Target: "mail.example.com", Findings: []models.Finding{port}}
+	// The recurring header issue is high on one target
+	mail.Findings = append(mail.Findings, hsts)
+	mail.Findings[1].Severity = "high"
+
+	return []*models.ScanResult{web, api, mail}
+}
+
+func TestNewAggregateDataCounts(t *testing.T) {
+	data := NewAggregateData(aggregateScans())
+
+	if data.Summary.TotalFindings != 8 {
+		t.Errorf("total findings = %d, want 8", data.Summary.TotalFindings)
+	}
+	want := map[string]int{"critical": 1, "high": 1, "medium": 3, "low": 0, "info": 3}
+	for _, count := range data.Summary.BySeverity {
+		if count.Count != want[count.Severity] {
+			t.Errorf("%s findings = %d, want %d", count.Severity, count.Count, want[count.Severity])
+		}
+	}
+
+	// Riskiest target first: the analyzed scan keeps its AI score (55),
+	// the others are scored from severities
+	var order []string
+	for _, target := range data.Targets {
+		order = append(order, target.Target)
+	}
+	if got := strings.Join(order, ","); got != "example.com,api.example.com,mail.example.com" {
+		t.Errorf("targets ordered %s", got)
+	}
+	if !data.Targets[0].AIScored || data.Targets[0].RiskScore != 55 || data.Targets[1].RiskScore != 48 || data.Targets[1].AIScored {
+		t.Errorf("risk scores = %+v", data.Targets)
+	}
+
+	// Issues count once per target, take their worst severity, and only
+	// recur when seen on more than one target
+	if len(data.TopIssues) != 2 {
+		t.Fatalf("recurring issues = %+v, want the header and the port", data.TopIssues)
+	}
+	// Equal occurrences: the more severe issue first
+	header := data.TopIssues[0]
+	if header.Occurrences != 3 || header.Severity != "high" || len(header.Targets) != 3 {
+		t.Errorf("header issue = %+v, want 3 targets at high", header)
+	}
+
+	if !strings.HasPrefix(data.PostureSummary, "8 findings across 3 targets (1 critical, 1 high). 1 of 3 targets") {
+		t.Errorf("posture summary = %q", data.PostureSummary)
+	}
+}
+
+func TestRenderAggregate(t *testing.T) {
+	data := NewAggregateData(aggregateScans())
+
+	var md bytes.Buffer
+	if err := RenderAggregate(&md, "markdown", data); err != nil {
+		t.Fatalf("markdown: %v", err)
+	}
+	for _, want := range []string{"| **total** | **8** |", "| api.example.com | 48 | 1 | 0 | 3 | scan-2 |", "- 3 targets (example.com, api.example.com, mail.example.com)"} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("markdown is missing %q:\n%s", want, md.String())
+		}
+	}
+
+	var js bytes.Buffer
+	if err := RenderAggregate(&js, "json", data); err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	var decoded AggregateData
+	if err := json.Unmarshal(js.Bytes(), &decoded); err != nil || len(decoded.Targets) != 3 || decoded.Summary.TotalFindings != 8 {
+		t.Errorf("json round trip = %+v, %v", decoded, err)
+	}
+
+	var html bytes.Buffer
+	if err := RenderAggregate(&html, "html", data); err != nil || !strings.Contains(html.String(), "<th>Total</th><th>8</th>") {
+		t.Errorf("html report missing the total (%v)", err)
+	}
+
+	if err := RenderAggregate(&bytes.Buffer{}, "csv", data); err == nil {
+		t.Error("rendered an unsupported aggregate format")
+	}
+}
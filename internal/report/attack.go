@@ -0,0 +1,55 @@
+package report
+
+import (
+	"sort"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+// AttackCoverage is one MITRE ATT&CK technique the findings map to
+type AttackCoverage struct {
+	Technique models.AttackTechnique
+	Findings  int // findings mapped to the technique
+}
+
+// BuildAttackCoverage counts the findings mapped to each ATT&CK technique,
+// ordered by tactic in kill chain order, then technique ID. Techniques
+// missing from models.AttackTechniques are listed by ID alone.
+func BuildAttackCoverage(findings []models.Finding) []AttackCoverage {
+	counts := make(map[string]int)
+	for i := range findings {
+		for _, id := range findings[i].AttackTechniqueIDs() {
+			counts[id]++
+		}
+	}
+
+	tacticOrder := make(map[string]int)
+	for i, tactic := range models.AttackTactics {
+		tacticOrder[tactic] = i
+	}
+
+	coverage := make([]AttackCoverage, 0, len(counts))
+	for id, count := range counts {
+		technique, ok := models.AttackTechniques[id]
+		if !ok {
+			technique = models.AttackTechnique{ID: id}
+		}
+		coverage = append(coverage, AttackCoverage{Technique: technique, Findings: count})
+	}
+	sort.Slice(coverage, func(i, j int) bool {
+		ti, tj := tacticRank(tacticOrder, coverage[i].Technique.Tactic), tacticRank(tacticOrder, coverage[j].Technique.Tactic)
+		if ti != tj {
+			return ti < tj
+		}
+		return coverage[i].Technique.ID < coverage[j].Technique.ID
+	})
+	return coverage
+}
+
+// tacticRank orders unknown tactics last
+func tacticRank(order map[string]int, tactic string) int {
+	if rank, ok := order[tactic]; ok {
+		return rank
+	}
+	return len(order)
+}
@@ -0,0 +1,33 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+func TestBuildAttackCoverage(t *testing.T) {
+	findings := []models.Finding{
+		{Type: "open-port", Metadata: map[string]string{models.MetaService: "ssh"}},
+		{Type: "open-port"},
+		{Type: "subdomain", Title: "api.example.com"},
+		{Type: "custom", Metadata: map[string]string{models.MetaAttackTechniques: "T9999"}},
+	}
+
+	got := make([]string, 0)
+	for _, entry := range BuildAttackCoverage(findings) {
+		got = append(got, fmt.Sprintf("%s=%d", entry.Technique.ID, entry.Findings))
+	}
+	// Reconnaissance first, then Initial Access, Discovery, Lateral
+	// Movement, and the unknown technique last
+	want := "T1590.002=1 T1133=1 T1046=2 T1021.004=1 T9999=1"
+	if strings.Join(got, " ") != want {
+		t.Errorf("coverage = %s, want %s", strings.Join(got, " "), want)
+	}
+
+	if coverage := BuildAttackCoverage([]models.Finding{{Type: "configuration", Title: "Target Reachable"}}); len(coverage) != 0 {
+		t.Errorf("unmapped findings gave coverage %+v", coverage)
+	}
+}
@@ -0,0 +1,56 @@
+package report
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// csvHeader is the column row of CSV reports
+var csvHeader = []string{"id", "severity", "type", "title", "location", "cve", "cvss", "tags"}
+
+// renderCSV writes one row per finding, in report order. Tags are joined
+// with semicolons.
+func renderCSV(w io.Writer, data Data) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, finding := range data.Scan.Findings {
+		cvss := ""
+		if finding.CVSS > 0 {
+			cvss = strconv.FormatFloat(finding.CVSS, 'f', 1, 64)
+		}
+
+		row := []string{
+			finding.ID,
+			finding.Severity,
+			finding.Type,
+			finding.Title,
+			finding.Location,
+			finding.CVE,
+			cvss,
+			strings.Join(finding.Tags, ";"),
+		}
+		for i := range row {
+			row[i] = csvSafe(row[i])
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvSafe stops spreadsheets from evaluating scanned content as a formula
+// by prefixing cells that start with a formula character with a quote
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
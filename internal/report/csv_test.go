@@ -0,0 +1,59 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+)
+
+func TestRenderCSV(t *testing.T) {
+	scan := testScan()
+	scan.Findings[0].CVSS = 6.5
+	scan.Findings[0].Tags = []string{"tls", "headers"}
+	scan.Findings[1].Title = `=HYPERLINK("http://evil.example", "click")`
+
+	var buf bytes.Buffer
+	if err := Render(&buf, "CSV", NewData(scan)); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("report is not valid CSV: %v", err)
+	}
+	if len(rows) != 4 || strings.Join(rows[0], ",") != "id,severity,type,title,location,cve,cvss,tags" {
+		t.Fatalf("rows = %q, want a header and one row per finding", rows)
+	}
+
+	// Report order: most severe first
+	if rows[1][0] != "f-2" || rows[2][0] != "f-1" || rows[3][0] != "f-3" {
+		t.Errorf("finding order = %s, %s, %s", rows[1][0], rows[2][0], rows[3][0])
+	}
+	if rows[1][3] != `'=HYPERLINK("http://evil.example", "click")` {
+		t.Errorf("formula title = %q, want it quoted so spreadsheets don't evaluate it", rows[1][3])
+	}
+	if rows[1][5] != "CVE-2026-0001" || rows[1][6] != "" {
+		t.Errorf("cve and cvss = %q, %q", rows[1][5], rows[1][6])
+	}
+	if rows[2][6] != "6.5" || rows[2][7] != "tls;headers" {
+		t.Errorf("cvss and tags = %q, %q", rows[2][6], rows[2][7])
+	}
+}
+
+func TestCSVSafe(t *testing.T) {
+	for value, want := range map[string]string{
+		"":            "",
+		"=1+1":        "'=1+1",
+		"+cmd":        "'+cmd",
+		"-2":          "'-2",
+		"@SUM(A1)":    "'@SUM(A1)",
+		"\tindented":  "'\tindented",
+		"plain title": "plain title",
+		"a=b":         "a=b",
+	} {
+		if got := csvSafe(value); got != want {
+			t.Errorf("csvSafe(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package report
+
+import (
+	"html/template"
+	"io"
+	"strings"
+	"time"
+)
+
+var htmlFuncs = template.FuncMap{
+	"lower": strings.ToLower,
+	"join":  strings.Join,
+	"trimBullet": func(s string) string {
+		return strings.TrimLeft(s, "-* ")
+	},
+	"formatTime": func(t time.Time) string {
+		return t.Format(time.RFC3339)
+	},
+	"round": func(d time.Duration) time.Duration {
+		return d.Round(time.Second)
+	},
+	"offset": timelineOffset,
+}
+
+var htmlReport = template.Must(template.New("report").Funcs(htmlFuncs).Parse(htmlTemplate))
+
+// renderHTML writes a self-contained HTML report (no external assets)
+func renderHTML(w io.Writer, data Data) error {
+	return htmlReport.Execute(w, data)
+}
+
+// htmlTemplate is the built-in report. Themes are switched by the
+// theme-light/theme-dark class on <body>; severity colors are shared by the
+// legend, the summary table and the finding badges.
+const htmlTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Shadow Security Report - {{.Scan.Target}}</title>
+<style>
+  body.theme-light { --bg: #ffffff; --fg: #1f2328; --muted: #59636e; --card: #f6f8fa; --border: #d1d9e0; }
+  body.theme-dark  { --bg: #0d1117; --fg: #e6edf3; --muted: #9198a1; --card: #161b22; --border: #30363d; }
+  :root { --sev-critical: #b91c1c; --sev-high: #ea580c; --sev-medium: #ca8a04; --sev-low: #16a34a; --sev-info: #2563eb; }
+  body { background: var(--bg); color: var(--fg); font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 0 auto; max-width: 960px; padding: 2rem; line-height: 1.5; }
+  h1, h2, h3 { margin-top: 1.5em; }
+  .meta, .muted { color: var(--muted); }
+  .card { background: var(--card); border: 1px solid var(--border); border-radius: 6px; padding: 1rem; margin: 1rem 0; }
+  table { border-collapse: collapse; }
+  th, td { border: 1px solid var(--border); padding: 0.3rem 0.8rem; text-align: left; }
+  pre { background: var(--card); border: 1px solid var(--border); padding: 0.75rem; overflow-x: auto; white-space: pre-wrap; }
+  .sev { display: inline-block; min-width: 4.5em; padding: 0.1em 0.5em; border-radius: 4px; color: #fff; font-size: 0.85em; font-weight: 600; text-align: center; text-transform: uppercase; }
+  .sev-critical { background: var(--sev-critical); }
+  .sev-high { background: var(--sev-high); }
+  .sev-medium { background: var(--sev-medium); }
+  .sev-low { background: var(--sev-low); }
+  .sev-info { background: var(--sev-info); }
+  .fp { border-left: 3px solid var(--sev-medium); padding-left: 0.5rem; }
+  .timeline { list-style: none; padding: 0; }
+  .timeline li { margin: 0.25rem 0; }
+  .severity-legend { list-style: none; display: flex; flex-wrap: wrap; gap: 0.5rem; padding: 0; }
+  .theme-toggle { position: fixed; top: 1rem; right: 1rem; background: var(--card); color: var(--fg); border: 1px solid var(--border); border-radius: 6px; padding: 0.4rem 0.8rem; cursor: pointer; }
+</style>
+</head>
+<body class="theme-light">
+<button class="theme-toggle" type="button" onclick="shadowToggleTheme()">Toggle dark mode</button>
+
+<h1>Shadow Security Report</h1>
+<p class="meta">
+  Target: <strong>{{.Scan.Target}}</strong><br>
+  Scan ID: {{.Scan.ID}} &middot; Profile: {{.Scan.Metadata.Profile}}<br>
+  Started: {{formatTime .Scan.StartTime}} &middot; Duration: {{round .Scan.Duration}}<br>
+  Generated: {{formatTime .GeneratedAt}}
+</p>
+
+<h2>Severity Legend</h2>
+<ul class="severity-legend">
+  <li><span class="sev sev-critical">critical</span></li>
+  <li><span class="sev sev-high">high</span></li>
+  <li><span class="sev sev-medium">medium</span></li>
+  <li><span class="sev sev-low">low</span></li>
+  <li><span class="sev sev-info">info</span></li>
+</ul>
+
+<h2>Summary</h2>
+<table>
+  <tr><th>Severity</th><th>Findings</th></tr>
+  {{- range .Summary.BySeverity}}
+  <tr><td><span class="sev sev-{{.Severity}}">{{.Severity}}</span></td><td>{{.Count}}</td></tr>
+  {{- end}}
+  <tr><th>Total</th><th>{{.Summary.TotalFindings}}</th></tr>
+</table>
+{{- if .Summary.Suppressed}}
+<p class="muted">{{.Summary.Suppressed}} suppressed findings not shown.</p>
+{{- end}}
+
+{{- with .Attack}}
+<h2>MITRE ATT&amp;CK Coverage</h2>
+<table>
+  <tr><th>Tactic</th><th>Technique</th><th>Findings</th></tr>
+  {{- range .}}
+  <tr><td>{{.Technique.Tactic}}</td><td><code>{{.Technique.ID}}</code> {{.Technique.Name}}</td><td>{{.Findings}}</td></tr>
+  {{- end}}
+</table>
+{{- end}}
+
+{{- with .Analysis}}
+<h2>AI Analysis</h2>
+<div class="card">
+  <p><strong>Risk Score:</strong> {{.RiskScore}}/100</p>
+  {{- if .Summary}}<p>{{.Summary}}</p>{{end}}
+  {{- if .CriticalIssues}}
+  <h3>Critical Issues</h3>
+  <ul>{{range .CriticalIssues}}<li>{{trimBullet .}}</li>{{end}}</ul>
+  {{- end}}
+  {{- if .Recommendations}}
+  <h3>Recommendations</h3>
+  <ol>{{range .Recommendations}}<li><span class="sev sev-{{lower .Priority}}">{{.Priority}}</span> {{.Title}}</li>{{end}}</ol>
+  {{- end}}
+</div>
+{{- end}}
+
+{{- with .Research}}
+<h2>Autonomous Research</h2>
+<div class="card">
+  <p class="muted">{{range $i, $phase := .Phases}}{{if $i}} → {{end}}{{$phase}}{{end}}</p>
+  {{- if .Note}}<p class="muted"><em>{{.Note}}.</em></p>{{end}}
+  {{- if .Conclusions}}<pre>{{.Conclusions}}</pre>{{end}}
+</div>
+{{- end}}
+
+<h2>Findings</h2>
+{{- range $i, $f := .Scan.Findings}}
+<div class="card">
+  <h3><span class="sev sev-{{lower $f.Severity}}">{{$f.Severity}}</span> {{$f.Title}}</h3>
+  {{- with $f.FalsePositiveReason}}<p class="fp"><strong>Likely false positive</strong> (AI cross-check): {{.}}</p>{{end}}
+  {{- if $f.Description}}<p>{{$f.Description}}</p>{{end}}
+  <p class="muted">
+    Type: {{$f.Type}}{{if $f.Confidence}} &middot; Confidence: {{$f.Confidence}}{{end}}{{if $f.Location}} &middot; Location: {{$f.Location}}{{end}}{{if $f.CVE}} &middot; {{$f.CVE}}{{end}}
+    {{- with $f.ReportMetadata}}<br>{{join . ", "}}{{end}}
+    {{- if $f.Fingerprint}}<br>Fingerprint: <code>{{$f.Fingerprint}}</code>{{end}}
+  </p>
+  {{- if $f.Evidence}}<pre>{{$f.Evidence}}</pre>{{end}}
+  {{- with $f.Notes}}
+  <p><strong>Analyst notes</strong></p>
+  <ul>
+    {{- range .}}
+    <li>{{.Text}} <span class="muted">&mdash; {{.Author}}, {{formatTime .Timestamp}}</span></li>
+    {{- end}}
+  </ul>
+  {{- end}}
+</div>
+{{- else}}
+<p class="muted">No findings.</p>
+{{- end}}
+
+{{- with .Timeline}}
+<h2>Timeline</h2>
+<p class="muted">When each finding was discovered, relative to the scan start, by module.</p>
+{{- range .}}
+<div class="card">
+  <h3>{{.Name}} <span class="muted">({{offset $.Scan.StartTime .Start}} → {{offset $.Scan.StartTime .End}})</span></h3>
+  <ul class="timeline">
+    {{- range .Findings}}
+    <li><code>{{offset $.Scan.StartTime .Timestamp}}</code> <span class="sev sev-{{lower .Severity}}">{{.Severity}}</span> {{.Title}}{{if .Location}} <span class="muted">{{.Location}}</span>{{end}}</li>
+    {{- end}}
+  </ul>
+</div>
+{{- end}}
+{{- end}}
+
+{{- with .Analysis}}{{if .RawText}}
+<h2>Appendix: Full AI Analysis</h2>
+<details class="card">
+  <summary>The AI's complete response, before parsing</summary>
+  <pre>{{.RawText}}</pre>
+</details>
+{{- end}}{{end}}
+
+<script>
+  function shadowApplyTheme(theme) {
+    document.body.classList.remove("theme-light", "theme-dark");
+    document.body.classList.add("theme-" + theme);
+  }
+  function shadowToggleTheme() {
+    var next = document.body.classList.contains("theme-dark") ? "light" : "dark";
+    shadowApplyTheme(next);
+    try { localStorage.setItem("shadow-report-theme", next); } catch (e) {}
+  }
+  (function () {
+    var saved = null;
+    try { saved = localStorage.getItem("shadow-report-theme"); } catch (e) {}
+    if (saved) {
+      shadowApplyTheme(saved);
+    } else if (window.matchMedia && window.matchMedia("(prefers-color-scheme: dark)").matches) {
+      shadowApplyTheme("dark");
+    }
+  })();
+</script>
+</body>
+</html>
+`
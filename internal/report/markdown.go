@@ -0,0 +1,157 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// renderMarkdown writes a plain Markdown report
+func renderMarkdown(w io.Writer, data Data) error {
+	var b strings.Builder
+	scan := data.Scan
+
+	b.WriteString(fmt.Sprintf("# Shadow Security Report: %s\n\n", scan.Target))
+	b.WriteString(fmt.Sprintf("- **Scan ID**: %s\n", scan.ID))
+	b.WriteString(fmt.Sprintf("- **Profile**: %s\n", scan.Metadata.Profile))
+	b.WriteString(fmt.Sprintf("- **Started**: %s\n", scan.StartTime.Format(time.RFC3339)))
+	b.WriteString(fmt.Sprintf("- **Duration**: %v\n", scan.Duration.Round(time.Second)))
+	b.WriteString(fmt.Sprintf("- **Generated**: %s\n\n", data.GeneratedAt.Format(time.RFC3339)))
+
+	b.WriteString("## Summary\n\n")
+	b.WriteString("| Severity | Count |\n|---|---|\n")
+	for _, count := range data.Summary.BySeverity {
+		b.WriteString(fmt.Sprintf("| %s | %d |\n", count.Severity, count.Count))
+	}
+	b.WriteString(fmt.Sprintf("| **total** | **%d** |\n\n", data.Summary.TotalFindings))
+	if data.Summary.Suppressed > 0 {
+		b.WriteString(fmt.Sprintf("_%d suppressed findings not shown._\n\n", data.Summary.Suppressed))
+	}
+
+	if len(data.Attack) > 0 {
+		b.WriteString("## MITRE ATT&CK Coverage\n\n")
+		b.WriteString("| Tactic | Technique | Findings |\n|---|---|---|\n")
+		for _, coverage := range data.Attack {
+			technique := coverage.Technique
+			b.WriteString(fmt.Sprintf("| %s | %s %s | %d |\n", technique.Tactic, technique.ID, technique.Name, coverage.Findings))
+		}
+		b.WriteString("\n")
+	}
+
+	if analysis := data.Analysis; analysis != nil {
+		b.WriteString("## AI Analysis\n\n")
+		b.WriteString(fmt.Sprintf("**Risk Score**: %d/100\n\n", analysis.RiskScore))
+		if analysis.Summary != "" {
+			b.WriteString(analysis.Summary + "\n\n")
+		}
+		if len(analysis.CriticalIssues) > 0 {
+			b.WriteString("### Critical Issues\n\n")
+			for _, issue := range analysis.CriticalIssues {
+				b.WriteString(fmt.Sprintf("- %s\n", strings.TrimLeft(issue, "-* ")))
+			}
+			b.WriteString("\n")
+		}
+		if len(analysis.Recommendations) > 0 {
+			b.WriteString("### Recommendations\n\n")
+			for i, rec := range analysis.Recommendations {
+				b.WriteString(fmt.Sprintf("%d. **[%s]** %s\n", i+1, rec.Priority, rec.Title))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if research := data.Research; research != nil {
+		b.WriteString("## Autonomous Research\n\n")
+		b.WriteString(fmt.Sprintf("**Phases**: %s\n\n", strings.Join(research.Phases, " → ")))
+		if research.Note != "" {
+			b.WriteString(fmt.Sprintf("_%s._\n\n", research.Note))
+		}
+		if research.Conclusions != "" {
+			b.WriteString(research.Conclusions + "\n\n")
+		}
+	}
+
+	b.WriteString("## Findings\n\n")
+	if len(scan.Findings) == 0 {
+		b.WriteString("No findings.\n")
+	}
+	for i, finding := range scan.Findings {
+		b.WriteString(fmt.Sprintf("### %d. [%s] %s\n\n", i+1, finding.Severity, finding.Title))
+		if finding.Description != "" {
+			b.WriteString(finding.Description + "\n\n")
+		}
+		b.WriteString(fmt.Sprintf("- **Type**: %s\n", finding.Type))
+		if finding.Confidence != "" {
+			b.WriteString(fmt.Sprintf("- **Confidence**: %s\n", finding.Confidence))
+		}
+		if reason := finding.FalsePositiveReason(); reason != "" {
+			b.WriteString(fmt.Sprintf("- **Likely false positive** (AI cross-check): %s\n", reason))
+		}
+		if finding.Location != "" {
+			b.WriteString(fmt.Sprintf("- **Location**: %s\n", finding.Location))
+		}
+		if finding.CVE != "" {
+			b.WriteString(fmt.Sprintf("- **CVE**: %s\n", finding.CVE))
+		}
+		if metadata := finding.ReportMetadata(); len(metadata) > 0 {
+			b.WriteString(fmt.Sprintf("- **Metadata**: %s\n", strings.Join(metadata, ", ")))
+		}
+		if finding.Fingerprint != "" {
+			b.WriteString(fmt.Sprintf("- **Fingerprint**: `%s`\n", finding.Fingerprint))
+		}
+		if finding.Evidence != "" {
+			b.WriteString(fmt.Sprintf("\n```\n%s\n```\n", finding.Evidence))
+		}
+		if len(finding.Notes) > 0 {
+			b.WriteString("\n**Analyst notes**\n\n")
+			for _, note := range finding.Notes {
+				b.WriteString(fmt.Sprintf("- %s (%s, %s)\n", note.Text, note.Author, note.Timestamp.Format("2006-01-02 15:04")))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	if len(data.Timeline) > 0 {
+		b.WriteString("\n## Timeline\n\n")
+		b.WriteString("_When each finding was discovered, relative to the scan start, by module._\n")
+		for _, phase := range data.Timeline {
+			b.WriteString(fmt.Sprintf("\n### %s (%s → %s)\n\n", phase.Name,
+				timelineOffset(scan.StartTime, phase.Start), timelineOffset(scan.StartTime, phase.End)))
+			for _, finding := range phase.Findings {
+				b.WriteString(fmt.Sprintf("- `%s` **[%s]** %s", timelineOffset(scan.StartTime, finding.Timestamp), finding.Severity, finding.Title))
+				if finding.Location != "" {
+					b.WriteString(fmt.Sprintf(" (%s)", finding.Location))
+				}
+				b.WriteString("\n")
+			}
+		}
+	}
+
+	if analysis := data.Analysis; analysis != nil && analysis.RawText != "" {
+		// The raw text is Markdown with its own headings, so it is fenced
+		// to keep it from breaking the report's structure
+		fence := codeFence(analysis.RawText)
+		b.WriteString("\n## Appendix: Full AI Analysis\n\n")
+		b.WriteString(fmt.Sprintf("%stext\n%s\n%s\n", fence, strings.TrimRight(analysis.RawText, "\n"), fence))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// codeFence returns a backtick fence longer than any backtick run in text
+func codeFence(text string) string {
+	longest, run := 0, 0
+	for _, r := range text {
+		if r != '`' {
+			run = 0
+			continue
+		}
+		run++
+		if run > longest {
+			longest = run
+		}
+	}
+	return strings.Repeat("`", max(3, longest+1))
+}
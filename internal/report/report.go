@@ -0,0 +1,121 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+// Data is the model handed to every report renderer
+type Data struct {
+	Scan        *models.ScanResult
+	Analysis    *models.AIAnalysis
+	Research    *models.ResearchSummary
+	Summary     Summary
+	GeneratedAt time.Time
+	Timeline    []TimelinePhase // empty unless requested, see BuildTimeline
+	Attack      []AttackCoverage
+}
+
+// Summary holds aggregate statistics about a scan's findings
+type Summary struct {
+	TotalFindings int
+	BySeverity    []SeverityCount // ordered critical → info
+	Suppressed    int             // findings hidden by .shadowignore
+}
+
+// SeverityCount is the number of findings at one severity
+type SeverityCount struct {
+	Severity string
+	Count    int
+}
+
+// NewData builds the report model for a scan result
+func NewData(result *models.ScanResult) Data {
+	// Suppressed findings stay in the stored result but not in the report
+	findings := models.ActiveFindings(result.Findings)
+
+	// Most severe first, then most certain, keeping module order otherwise
+	sort.SliceStable(findings, func(i, j int) bool {
+		si, sj := models.SeverityRank(findings[i].Severity), models.SeverityRank(findings[j].Severity)
+		if si != sj {
+			return si > sj
+		}
+		return models.ConfidenceRank(string(findings[i].Confidence)) > models.ConfidenceRank(string(findings[j].Confidence))
+	})
+
+	scan := *result
+	scan.Findings = findings
+
+	summary := summarize(findings)
+	summary.Suppressed = len(result.Findings) - len(findings)
+
+	return Data{
+		Scan:        &scan,
+		Analysis:    result.Analysis,
+		Research:    result.Research,
+		Summary:     summary,
+		GeneratedAt: time.Now(),
+		Attack:      BuildAttackCoverage(findings),
+	}
+}
+
+// Render writes the report in the requested format
+func Render(w io.Writer, format string, data Data) error {
+	switch normalizeFormat(format) {
+	case "html":
+		return renderHTML(w, data)
+	case "markdown":
+		return renderMarkdown(w, data)
+	case "csv":
+		return renderCSV(w, data)
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(data.Scan)
+	case "pdf":
+		return fmt.Errorf("pdf reports are not supported yet - render html and print it to PDF")
+	default:
+		return fmt.Errorf("unknown report format %q (supported: html, markdown, json, csv)", format)
+	}
+}
+
+// FileExtension returns the conventional file extension for a format
+func FileExtension(format string) string {
+	switch normalizeFormat(format) {
+	case "markdown":
+		return "md"
+	default:
+		return normalizeFormat(format)
+	}
+}
+
+func normalizeFormat(format string) string {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "md" {
+		return "markdown"
+	}
+	return format
+}
+
+func summarize(findings []models.Finding) Summary {
+	counts := make(map[string]int)
+	for _, finding := range findings {
+		counts[strings.ToLower(finding.Severity)]++
+	}
+
+	summary := Summary{TotalFindings: len(findings)}
+	for _, severity := range models.SeverityLevels {
+		summary.BySeverity = append(summary.BySeverity, SeverityCount{
+			Severity: severity,
+			Count:    counts[severity],
+		})
+	}
+
+	return summary
+}
@@ -0,0 +1,305 @@
+package report
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// testScan is a fixed scan result, so rendered reports are reproducible
+func testScan() *models.ScanResult {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	return &models.ScanResult{
+		ID:        "scan-1",
+		Target:    "example.com",
+		StartTime: start,
+		EndTime:   start.Add(42 * time.Second),
+		Duration:  42 * time.Second,
+		Status:    "completed",
+		Metadata:  models.ScanMetadata{Version: "1.0.0", Profile: "standard"},
+		Findings: []models.Finding{
+			{
+				ID:          "f-1",
+				Fingerprint: "0123456789abcdef",
+				Type:        "security-header",
+				Severity:    "medium",
+				Title:       "Missing Strict-Transport-Security header",
+				Description: "The site can be downgraded to HTTP.",
+				Evidence:    "HTTP 200 without Strict-Transport-Security",
+				Location:    "https://example.com",
+			},
+			{
+				ID:       "f-2",
+				Type:     "xss",
+				Severity: "high",
+				Title:    "Reflected input in search",
+				Evidence: `q=<script>alert("x")</script>`,
+				Location: "https://example.com/search",
+				CVE:      "CVE-2026-0001",
+			},
+			{
+				ID:       "f-3",
+				Type:     "configuration",
+				Severity: "info",
+				Title:    "Target Reachable",
+				Location: "example.com",
+			},
+		},
+		Analysis: &models.AIAnalysis{
+			ScanID:         "scan-1",
+			Summary:        "One reflected XSS and a missing HSTS header.",
+			CriticalIssues: []string{"- Reflected XSS in search"},
+			Recommendations: []models.Recommendation{
+				{Priority: "High", Title: "Encode search output"},
+			},
+			RiskScore: 55,
+		},
+	}
+}
+
+func testData() Data {
+	data := NewData(testScan())
+	data.GeneratedAt = time.Date(2026, 3, 1, 13, 0, 0, 0, time.UTC)
+	return data
+}
+
+// checkGolden compares got with testdata/name, rewriting it with -update
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s differs from the rendered report; rerun with -update and review the diff", path)
+	}
+}
+
+func TestRenderHTMLGolden(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Render(&buf, "html", testData()); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	checkGolden(t, "report.golden.html", buf.Bytes())
+
+	html := buf.String()
+	if strings.Contains(html, "<script>alert") {
+		t.Error("finding evidence not escaped")
+	}
+	for _, want := range []string{`class="severity-legend"`, "shadowToggleTheme()", "prefers-color-scheme: dark"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("report is missing %s", want)
+		}
+	}
+}
+
+func TestNewDataOrdersBySeverity(t *testing.T) {
+	data := testData()
+	var order []string
+	for _, finding := range data.Scan.Findings {
+		order = append(order, finding.Severity)
+	}
+	if got := strings.Join(order, ","); got != "high,medium,info" {
+		t.Errorf("findings ordered %s, want most severe first", got)
+	}
+	if data.Summary.TotalFindings != 3 || data.Summary.BySeverity[1].Count != 1 {
+		t.Errorf("summary = %+v", data.Summary)
+	}
+}
+
+func TestNewDataOrdersByConfidenceWithinSeverity(t *testing.T) {
+	data := NewData(&models.ScanResult{Findings: []models.Finding{
+		{Title: "unsure", Severity: "high", Confidence: models.ConfidenceLow},
+		{Title: "unrated", Severity: "high"},
+		{Title: "info", Severity: "info", Confidence: models.ConfidenceHigh},
+		{Title: "confirmed", Severity: "high", Confidence: models.ConfidenceHigh},
+	}})
+	var order []string
+	for _, finding := range data.Scan.Findings {
+		order = append(order, finding.Title)
+	}
+	if got := strings.Join(order, ","); got != "confirmed,unrated,unsure,info" {
+		t.Errorf("findings ordered %s, want most certain first within a severity", got)
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Render(&buf, "md", testData()); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	for _, want := range []string{"# Shadow Security Report: example.com", "| high | 1 |", "| **total** | **3** |", "**Risk Score**: 55/100"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("markdown report is missing %q", want)
+		}
+	}
+}
+
+func TestRenderShowsMetadata(t *testing.T) {
+	scan := testScan()
+	scan.Findings[0].SetMeta(models.MetaHTTPStatus, "200")
+	scan.Findings[0].SetMeta(models.MetaHost, "example.com")
+	data := NewData(scan)
+
+	for format, want := range map[string]string{
+		"markdown": "- **Metadata**: host=example.com, http.status=200",
+		"html":     "<br>host=example.com, http.status=200",
+	} {
+		var buf bytes.Buffer
+		if err := Render(&buf, format, data); err != nil {
+			t.Fatalf("%s: Render: %v", format, err)
+		}
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("%s report is missing %q", format, want)
+		}
+	}
+}
+
+func TestRenderShowsNotes(t *testing.T) {
+	scan := testScan()
+	scan.Findings[1].Notes = []models.Note{{Author: "alice", Text: "verified <b>exploitable</b>", Timestamp: time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC)}}
+	data := NewData(scan)
+
+	for format, want := range map[string]string{
+		"markdown": "- verified <b>exploitable</b> (alice, 2026-03-02 09:30)",
+		"html":     "<li>verified &lt;b&gt;exploitable&lt;/b&gt; <span class=\"muted\">&mdash; alice,",
+	} {
+		var buf bytes.Buffer
+		if err := Render(&buf, format, data); err != nil {
+			t.Fatalf("%s: Render: %v", format, err)
+		}
+		if !strings.Contains(buf.String(), "Analyst notes") || !strings.Contains(buf.String(), want) {
+			t.Errorf("%s report is missing the note %q", format, want)
+		}
+	}
+}
+
+func TestRenderShowsFalsePositives(t *testing.T) {
+	scan := testScan()
+	scan.Findings[0].SetMeta(models.MetaLikelyFalsePositive, "Header is set on <redirect>")
+	data := NewData(scan)
+
+	for format, want := range map[string]string{
+		"markdown": "- **Likely false positive** (AI cross-check): Header is set on <redirect>",
+		"html":     `<p class="fp"><strong>Likely false positive</strong> (AI cross-check): Header is set on &lt;redirect&gt;</p>`,
+	} {
+		var buf bytes.Buffer
+		if err := Render(&buf, format, data); err != nil {
+			t.Fatalf("%s: Render: %v", format, err)
+		}
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("%s report is missing %q", format, want)
+		}
+	}
+}
+
+func TestRenderShowsRawAnalysis(t *testing.T) {
+	scan := testScan()
+	scan.Analysis.RawText = "## Summary\nUse ```` fences <here>.\n"
+	data := NewData(scan)
+
+	for format, want := range map[string]string{
+		"markdown": "## Appendix: Full AI Analysis\n\n`````text\n## Summary\nUse ```` fences <here>.\n`````\n",
+		"html":     "<pre>## Summary\nUse ```` fences &lt;here&gt;.\n</pre>",
+	} {
+		var buf bytes.Buffer
+		if err := Render(&buf, format, data); err != nil {
+			t.Fatalf("%s: Render: %v", format, err)
+		}
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("%s report is missing the raw analysis %q:\n%s", format, want, buf.String())
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := Render(&buf, "markdown", testData()); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "Appendix") {
+		t.Error("appendix rendered without raw text")
+	}
+}
+
+func TestCodeFence(t *testing.T) {
+	for text, want := range map[string]string{
+		"plain":             "```",
+		"inline `code`":     "```",
+		"```go\nx\n```":     "````",
+		"a ```` b `````` c": "```````",
+	} {
+		if got := codeFence(text); got != want {
+			t.Errorf("codeFence(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+func TestRenderUnknownFormat(t *testing.T) {
+	if err := Render(&bytes.Buffer{}, "docx", testData()); err == nil || !strings.Contains(err.Error(), "unknown report format") {
+		t.Errorf("Render(docx) error = %v", err)
+	}
+	if ext := FileExtension("Markdown"); ext != "md" {
+		t.Errorf("FileExtension(Markdown) = %q", ext)
+	}
+}
+
+func TestNewDataLeavesOutSuppressed(t *testing.T) {
+	scan := testScan()
+	scan.Findings[1].Metadata = map[string]string{models.MetaSuppressed: "true"}
+
+	data := NewData(scan)
+	if len(data.Scan.Findings) != 2 || data.Summary.TotalFindings != 2 || data.Summary.Suppressed != 1 {
+		t.Errorf("findings %d, summary %+v; want the suppressed finding counted but not shown", len(data.Scan.Findings), data.Summary)
+	}
+	if len(scan.Findings) != 3 {
+		t.Error("NewData changed the stored result")
+	}
+
+	var buf bytes.Buffer
+	Render(&buf, "markdown", data)
+	if !strings.Contains(buf.String(), "_1 suppressed findings not shown._") || strings.Contains(buf.String(), "Reflected input") {
+		t.Error("markdown report shows the suppressed finding or hides the count")
+	}
+}
+
+func TestRenderResearch(t *testing.T) {
+	data := testData()
+	data.Research = &models.ResearchSummary{
+		Phases:      []string{"Reconnaissance", "Hypothesis Testing"},
+		Conclusions: "The <admin> panel is reachable without authentication.",
+		Note:        "Partial research: the research was interrupted after 2 of 4 iterations",
+	}
+
+	for _, format := range []string{"markdown", "html"} {
+		var buf bytes.Buffer
+		if err := Render(&buf, format, data); err != nil {
+			t.Fatalf("%s: Render: %v", format, err)
+		}
+		for _, want := range []string{"Autonomous Research", "Reconnaissance → Hypothesis Testing", "interrupted after 2 of 4 iterations."} {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("%s report is missing %q", format, want)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := Render(&buf, "html", data); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "The &lt;admin&gt; panel") {
+		t.Error("html report doesn't escape research conclusions")
+	}
+}
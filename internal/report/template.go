@@ -0,0 +1,74 @@
+package report
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	texttemplate "text/template"
+)
+
+// Template is satisfied by both html/template and text/template templates
+type Template interface {
+	Execute(w io.Writer, data any) error
+}
+
+// LoadTemplate parses a user-supplied template for the given format.
+// HTML templates are parsed with html/template (auto-escaping), Markdown
+// templates with text/template. Templates receive a Data value:
+//
+//	.Scan                   the scan result (ID, Target, StartTime, Duration, Findings, Metadata)
+//	.Analysis               the AI analysis, nil when the scan wasn't analyzed
+//	.Summary.TotalFindings  number of findings
+//	.Summary.BySeverity     []{Severity, Count}, ordered critical → info
+//	.GeneratedAt            when the report was rendered
+//	.Timeline               []{Name, Start, End, Findings}, only with --timeline
+//	.Attack                 []{Technique{ID, Name, Tactic}, Findings}, MITRE ATT&CK techniques mapped
+//
+// The built-in helpers lower, trimBullet, formatTime, round and offset
+// (offset .Scan.StartTime .Timestamp) are available.
+func LoadTemplate(path string, format string) (Template, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+
+	name := filepath.Base(path)
+
+	switch normalizeFormat(format) {
+	case "html":
+		tmpl, err := htmltemplate.New(name).Funcs(htmlFuncs).Parse(string(content))
+		if err != nil {
+			return nil, fmt.Errorf("invalid HTML template %s: %w", path, err)
+		}
+		return tmpl, nil
+	case "markdown":
+		tmpl, err := texttemplate.New(name).Funcs(texttemplate.FuncMap(htmlFuncs)).Parse(string(content))
+		if err != nil {
+			return nil, fmt.Errorf("invalid Markdown template %s: %w", path, err)
+		}
+		return tmpl, nil
+	default:
+		return nil, fmt.Errorf("custom templates are only supported for html and markdown, not %q", format)
+	}
+}
+
+// RenderWithTemplate renders using a custom template, falling back to the
+// built-in renderer when templatePath is empty
+func RenderWithTemplate(w io.Writer, format string, templatePath string, data Data) error {
+	if templatePath == "" {
+		return Render(w, format, data)
+	}
+
+	tmpl, err := LoadTemplate(templatePath, format)
+	if err != nil {
+		return err
+	}
+
+	if err := tmpl.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to render template %s: %w", templatePath, err)
+	}
+
+	return nil
+}
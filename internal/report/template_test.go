@@ -0,0 +1,78 @@
+package report
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTemplate(t *testing.T, name string, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRenderWithTemplate(t *testing.T) {
+	tests := []struct {
+		format   string
+		template string
+		want     string
+	}{
+		// html/template escapes finding content
+		{"html", `{{range .Scan.Findings}}<p>{{.Evidence}}</p>{{end}}`, "&lt;script&gt;"},
+		// text/template leaves Markdown alone
+		{"markdown", `{{range .Scan.Findings}}{{.Evidence}}|{{end}}`, `<script>alert("x")</script>`},
+		{"md", `{{.Scan.Target}}: {{.Summary.TotalFindings}} findings, {{formatTime .GeneratedAt}}`, "example.com: 3 findings, 2026-03-01T13:00:00Z"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		path := writeTemplate(t, "custom.tmpl", tt.template)
+		if err := RenderWithTemplate(&buf, tt.format, path, testData()); err != nil {
+			t.Errorf("%s: %v", tt.format, err)
+			continue
+		}
+		if !strings.Contains(buf.String(), tt.want) {
+			t.Errorf("%s: rendered %q, want %q", tt.format, buf.String(), tt.want)
+		}
+	}
+}
+
+func TestRenderWithTemplateDefault(t *testing.T) {
+	var custom, builtin bytes.Buffer
+	data := testData()
+	RenderWithTemplate(&custom, "html", "", data)
+	Render(&builtin, "html", data)
+	if custom.String() != builtin.String() {
+		t.Error("no template path didn't render the built-in report")
+	}
+}
+
+func TestLoadTemplateErrors(t *testing.T) {
+	tests := []struct {
+		path   string
+		format string
+		want   string
+	}{
+		{writeTemplate(t, "bad.html", "{{.Scan"), "html", "invalid HTML template"},
+		{writeTemplate(t, "bad.md", "{{end}}"), "markdown", "invalid Markdown template"},
+		{writeTemplate(t, "ok.json", "{}"), "json", "only supported for html and markdown"},
+		{filepath.Join(t.TempDir(), "missing.html"), "html", "failed to read template"},
+	}
+	for _, tt := range tests {
+		if _, err := LoadTemplate(tt.path, tt.format); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("LoadTemplate(%s, %s) error = %v, want %q", filepath.Base(tt.path), tt.format, err, tt.want)
+		}
+	}
+
+	// Missing fields fail at execution, not silently
+	var buf bytes.Buffer
+	path := writeTemplate(t, "missing-field.html", "{{.Scan.NoSuchField}}")
+	if err := RenderWithTemplate(&buf, "html", path, testData()); err == nil || !strings.Contains(err.Error(), "failed to render template") {
+		t.Errorf("RenderWithTemplate(missing field) error = %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+// TimelinePhase is the findings one module (phase of the scan) reported,
+// in the order they were discovered
+type TimelinePhase struct {
+	Name     string
+	Start    time.Time // first finding of the phase
+	End      time.Time // last finding of the phase
+	Findings []models.Finding
+}
+
+// BuildTimeline orders findings by discovery time and groups them by the
+// module that reported them, phases ordered by their first finding.
+// Findings from scans made before modules were recorded are grouped by
+// type instead.
+func BuildTimeline(findings []models.Finding) []TimelinePhase {
+	ordered := append([]models.Finding(nil), findings...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Timestamp.Before(ordered[j].Timestamp)
+	})
+
+	phases := make([]TimelinePhase, 0)
+	index := make(map[string]int)
+	for _, finding := range ordered {
+		name := finding.Metadata[models.MetaModule]
+		if name == "" {
+			name = finding.Type
+		}
+
+		i, ok := index[name]
+		if !ok {
+			i = len(phases)
+			index[name] = i
+			phases = append(phases, TimelinePhase{Name: name, Start: finding.Timestamp})
+		}
+		phases[i].End = finding.Timestamp
+		phases[i].Findings = append(phases[i].Findings, finding)
+	}
+	return phases
+}
+
+// timelineOffset formats t relative to the scan start, e.g. "+1m5.2s".
+// Times before the start (or without one) are shown as clock times.
+func timelineOffset(start time.Time, t time.Time) string {
+	if start.IsZero() || t.Before(start) {
+		return t.Format("15:04:05")
+	}
+	return fmt.Sprintf("+%v", t.Sub(start).Round(100*time.Millisecond))
+}
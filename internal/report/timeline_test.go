@@ -0,0 +1,88 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+func TestBuildTimeline(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	finding := func(title, module, findingType string, after time.Duration) models.Finding {
+		f := models.Finding{Title: title, Type: findingType, Timestamp: start.Add(after)}
+		f.SetMeta(models.MetaModule, module)
+		return f
+	}
+	phases := BuildTimeline([]models.Finding{
+		finding("Missing HSTS header", "Security Headers", "security-header", 3*time.Second),
+		finding("Open TCP port 22", "Port Scan", "open-port", time.Second),
+		finding("Missing CSP header", "Security Headers", "security-header", 2*time.Second),
+		finding("Legacy finding", "", "xss", 4*time.Second),
+	})
+
+	var got []string
+	for _, phase := range phases {
+		titles := make([]string, 0, len(phase.Findings))
+		for _, f := range phase.Findings {
+			titles = append(titles, f.Title)
+		}
+		got = append(got, phase.Name+": "+strings.Join(titles, ", "))
+	}
+	want := "Port Scan: Open TCP port 22 | Security Headers: Missing CSP header, Missing HSTS header | xss: Legacy finding"
+	if strings.Join(got, " | ") != want {
+		t.Errorf("timeline = %s\nwant       %s", strings.Join(got, " | "), want)
+	}
+	if headers := phases[1]; !headers.Start.Equal(start.Add(2*time.Second)) || !headers.End.Equal(start.Add(3*time.Second)) {
+		t.Errorf("phase spans %v to %v", headers.Start, headers.End)
+	}
+}
+
+func TestTimelineOffset(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		start, at time.Time
+		want      string
+	}{
+		{start, start.Add(65*time.Second + 240*time.Millisecond), "+1m5.2s"},
+		{start, start.Add(-time.Second), "11:59:59"},
+		{time.Time{}, start, "12:00:00"},
+	}
+	for _, tt := range tests {
+		if got := timelineOffset(tt.start, tt.at); got != tt.want {
+			t.Errorf("timelineOffset(%v, %v) = %q, want %q", tt.start, tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestRenderShowsTimeline(t *testing.T) {
+	scan := testScan()
+	for i := range scan.Findings {
+		scan.Findings[i].Timestamp = scan.StartTime.Add(time.Duration(i+1) * time.Second)
+	}
+	data := NewData(scan)
+	data.Timeline = BuildTimeline(data.Scan.Findings)
+
+	for format, want := range map[string]string{
+		"markdown": "- `+1s` **[medium]** Missing Strict-Transport-Security header (https://example.com)",
+		"html":     "<li><code>&#43;1s</code> <span class=\"sev sev-medium\">medium</span> Missing Strict-Transport-Security header",
+	} {
+		var buf bytes.Buffer
+		if err := Render(&buf, format, data); err != nil {
+			t.Fatalf("%s: Render: %v", format, err)
+		}
+		if !strings.Contains(buf.String(), "Timeline") || !strings.Contains(buf.String(), want) {
+			t.Errorf("%s report is missing the timeline entry %q", format, want)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := Render(&buf, "markdown", testData()); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "## Timeline") {
+		t.Error("timeline rendered without being requested")
+	}
+}
@@ -0,0 +1,77 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+// verificationOrder is the order verification report sections appear in
+var verificationOrder = []string{
+	models.VerificationStillPresent,
+	models.VerificationInconclusive,
+	models.VerificationFixed,
+}
+
+// RenderVerification writes a remediation verification report in the
+// requested format
+func RenderVerification(w io.Writer, format string, result *models.VerificationResult) error {
+	switch normalizeFormat(format) {
+	case "markdown":
+		return renderVerificationMarkdown(w, result)
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	default:
+		return fmt.Errorf("unknown verification report format %q (supported: markdown, json)", format)
+	}
+}
+
+func renderVerificationMarkdown(w io.Writer, result *models.VerificationResult) error {
+	var b strings.Builder
+
+	b.WriteString("# Shadow Remediation Verification\n\n")
+	b.WriteString(fmt.Sprintf("- **Target**: %s\n", result.Target))
+	b.WriteString(fmt.Sprintf("- **Original scan**: %s\n", result.ScanID))
+	b.WriteString(fmt.Sprintf("- **Verified**: %s\n\n", result.EndTime.Format(time.RFC3339)))
+
+	b.WriteString("| Status | Findings |\n|---|---|\n")
+	for _, status := range verificationOrder {
+		b.WriteString(fmt.Sprintf("| %s | %d |\n", status, result.Count(status)))
+	}
+	b.WriteString(fmt.Sprintf("| **total** | **%d** |\n", len(result.Verifications)))
+
+	for _, status := range verificationOrder {
+		if result.Count(status) == 0 {
+			continue
+		}
+		b.WriteString(fmt.Sprintf("\n## %s\n\n", verificationHeading(status)))
+		for _, verification := range result.Verifications {
+			if verification.Status != status {
+				continue
+			}
+			finding := verification.Finding
+			b.WriteString(fmt.Sprintf("- **[%s] %s** (%s) - %s\n",
+				strings.ToUpper(finding.Severity), finding.Title, finding.Location, verification.Detail))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func verificationHeading(status string) string {
+	switch status {
+	case models.VerificationStillPresent:
+		return "Still Present"
+	case models.VerificationFixed:
+		return "Fixed"
+	default:
+		return "Inconclusive"
+	}
+}
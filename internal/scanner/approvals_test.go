@@ -0,0 +1,116 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRememberedApprovalPersistsAcrossManagers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shadow", "approvals.json")
+
+	first := &PermissionManager{userApproved: make(map[string]bool), approvalsPath: path}
+	first.rememberApproval("nmap")
+
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Fatalf("approvals file: %v, %v; want mode 0600", info, err)
+	}
+
+	second, auditPath := testPermissionManager(t)
+	second.approvalsPath = path
+	second.loadApprovals()
+
+	// Any nmap command is approved without a prompt; other tools aren't
+	if approved, err := second.RequestRootPermission("nmap", "SYN scan", "sudo nmap -sS other-host"); !approved || err != nil {
+		t.Errorf("remembered approval = %v, %v", approved, err)
+	}
+	if second.userApproved["masscan:*"] {
+		t.Error("approval leaked to another tool")
+	}
+	if lines := readAuditLog(t, auditPath); len(lines) != 1 || !strings.Contains(lines[0], "decision=cached-approved-always") {
+		t.Errorf("audit log = %q", lines)
+	}
+}
+
+func TestLoadApprovalsIgnoresBrokenFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "approvals.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	pm := &PermissionManager{userApproved: make(map[string]bool), approvalsPath: path}
+	pm.loadApprovals()
+	if len(pm.userApproved) != 0 {
+		t.Errorf("approvals loaded from a broken file: %v", pm.userApproved)
+	}
+}
+
+func TestForgetApprovals(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "approvals.json")
+	pm := &PermissionManager{userApproved: make(map[string]bool), approvalsPath: path}
+	pm.rememberApproval("nmap")
+	pm.userApproved["nmap:*"] = true
+	pm.userApproved["nmap:sudo nmap host"] = true
+
+	if err := pm.ForgetApprovals(); err != nil {
+		t.Fatalf("ForgetApprovals: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("approvals file still exists: %v", err)
+	}
+	if pm.userApproved["nmap:*"] || !pm.userApproved["nmap:sudo nmap host"] {
+		t.Errorf("userApproved = %v, want only the wildcard removed", pm.userApproved)
+	}
+
+	// Forgetting twice is fine
+	if err := pm.ForgetApprovals(); err != nil {
+		t.Errorf("second ForgetApprovals: %v", err)
+	}
+}
+
+// withStdin feeds input to code reading os.Stdin
+func withStdin(t *testing.T, input string) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.WriteString(input)
+	w.Close()
+
+	stdin := os.Stdin
+	os.Stdin = r
+	t.Cleanup(func() {
+		os.Stdin = stdin
+		r.Close()
+	})
+}
+
+func TestDisableAlwaysApproval(t *testing.T) {
+	pm, auditPath := testPermissionManager(t)
+	pm.approvalsPath = filepath.Join(t.TempDir(), "approvals.json")
+	pm.sudoTested, pm.sudoAvailable = true, true
+	pm.userApproved["nmap:*"] = true // remembered from an earlier run
+	pm.DisableAlwaysApproval()
+
+	withStdin(t, "always\n")
+	if approved, err := pm.RequestRootPermission("nmap", "SYN scan", "sudo nmap -sS a.example.com"); !approved || err != nil {
+		t.Fatalf("always answer = %v, %v; want this command approved", approved, err)
+	}
+	if _, err := os.Stat(pm.approvalsPath); !os.IsNotExist(err) {
+		t.Error("always approval was remembered")
+	}
+
+	// The next command is asked again despite the wildcard
+	withStdin(t, "no\n")
+	if approved, _ := pm.RequestRootPermission("nmap", "SYN scan", "sudo nmap -sS b.example.com"); approved {
+		t.Error("second command approved without confirmation")
+	}
+
+	lines := readAuditLog(t, auditPath)
+	if len(lines) != 2 || !strings.Contains(lines[0], "decision=approved ") || !strings.Contains(lines[1], "decision=denied") {
+		t.Errorf("audit log = %q, want each command confirmed individually", lines)
+	}
+}
@@ -0,0 +1,79 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/kumaraguru1735/shadow/internal/output"
+)
+
+// Audit decisions recorded for root permission requests and executions
+const (
+	AuditApproved       = "approved"
+	AuditApprovedAlways = "approved-always"
+	AuditDenied         = "denied"
+	AuditCached         = "cached"
+	AuditUnavailable    = "sudo-unavailable"
+	AuditRefused        = "refused" // not allowlisted or unsafe arguments
+	AuditExecuted       = "executed"
+	AuditFailed         = "failed"
+	AuditTimedOut       = "timed-out"
+)
+
+// DefaultAuditFile returns ~/.shadow/audit.log
+func DefaultAuditFile() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".shadow", "audit.log"), nil
+}
+
+// SetAuditFile records permission requests and privileged executions to
+// path instead of the default ~/.shadow/audit.log. An empty path disables
+// auditing.
+func (pm *PermissionManager) SetAuditFile(path string) {
+	pm.auditPath = path
+}
+
+// audit appends one line to the audit log:
+//
+//	2026-01-02T15:04:05Z event=request tool="nmap" decision=approved command="sudo nmap -sS host"
+//
+// The file is opened append-only and kept at 0600. Audit failures are
+// reported but never block the scan.
+func (pm *PermissionManager) audit(event string, tool string, decision string, command string) {
+	if pm.auditPath == "" {
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(pm.auditPath), 0700); err != nil {
+		output.Printf("⚠️  Audit log unavailable: %v\n", err)
+		return
+	}
+
+	file, err := os.OpenFile(pm.auditPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		output.Printf("⚠️  Audit log unavailable: %v\n", err)
+		return
+	}
+	defer file.Close()
+
+	// Tighten a pre-existing file created with looser permissions
+	if info, err := file.Stat(); err == nil && info.Mode().Perm() != 0600 {
+		_ = file.Chmod(0600)
+	}
+
+	line := fmt.Sprintf("%s event=%s tool=%s decision=%s command=%s\n",
+		time.Now().UTC().Format(time.RFC3339),
+		event,
+		strconv.Quote(tool),
+		decision,
+		strconv.Quote(command))
+	if _, err := file.WriteString(line); err != nil {
+		output.Printf("⚠️  Audit log write failed: %v\n", err)
+	}
+}
@@ -0,0 +1,92 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func testPermissionManager(t *testing.T) (*PermissionManager, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "shadow", "audit.log")
+	pm := &PermissionManager{userApproved: make(map[string]bool)}
+	pm.SetAuditFile(path)
+	return pm, path
+}
+
+func readAuditLog(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+func TestAuditLineFormat(t *testing.T) {
+	pm, path := testPermissionManager(t)
+	pm.audit("request", "nmap", AuditApproved, `sudo nmap -sS "host"`)
+	pm.audit("exec", "nmap", AuditFailed, "sudo nmap -sS host")
+
+	lines := readAuditLog(t, path)
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	format := regexp.MustCompile(`^\d{4}-\d\d-\d\dT\d\d:\d\d:\d\dZ event=request tool="nmap" decision=approved command="sudo nmap -sS \\"host\\""$`)
+	if !format.MatchString(lines[0]) {
+		t.Errorf("line = %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "event=exec") || !strings.Contains(lines[1], "decision=failed") {
+		t.Errorf("line = %q", lines[1])
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("audit log mode = %v, %v; want 0600", info.Mode().Perm(), err)
+	}
+}
+
+func TestAuditTightensPermissions(t *testing.T) {
+	pm, path := testPermissionManager(t)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("earlier entry\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pm.audit("request", "nmap", AuditDenied, "sudo nmap host")
+
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("audit log mode = %v, want 0600", info.Mode().Perm())
+	}
+	if lines := readAuditLog(t, path); len(lines) != 2 || lines[0] != "earlier entry" {
+		t.Errorf("audit log = %q, want the new line appended", lines)
+	}
+}
+
+func TestAuditDisabled(t *testing.T) {
+	pm := &PermissionManager{}
+	pm.SetAuditFile("")
+	pm.audit("request", "nmap", AuditApproved, "sudo nmap host") // must not panic or write anywhere
+}
+
+func TestRequestRootPermissionAuditsCachedDecision(t *testing.T) {
+	pm, path := testPermissionManager(t)
+	pm.userApproved["nmap:sudo nmap host"] = true
+	pm.userApproved["masscan:sudo masscan host"] = false
+
+	if approved, err := pm.RequestRootPermission("nmap", "SYN scan", "sudo nmap host"); !approved || err != nil {
+		t.Errorf("cached approval = %v, %v", approved, err)
+	}
+	if approved, _ := pm.RequestRootPermission("masscan", "fast scan", "sudo masscan host"); approved {
+		t.Error("cached denial approved")
+	}
+
+	lines := readAuditLog(t, path)
+	if len(lines) != 2 || !strings.Contains(lines[0], "decision=cached-approved") || !strings.Contains(lines[1], "decision=cached-denied") {
+		t.Errorf("audit log = %q", lines)
+	}
+}
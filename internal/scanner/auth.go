@@ -0,0 +1,133 @@
+package scanner
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kumaraguru1735/shadow/internal/httpclient"
+	"github.com/kumaraguru1735/shadow/internal/redact"
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+// LoadAuthFile reads a session blob: one "Name: value" header per line, as
+// copied from a browser or proxy (e.g. Cookie and Authorization). Blank lines
+// and # comments are ignored.
+func LoadAuthFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open auth file: %w", err)
+	}
+	defer file.Close()
+
+	headers := make(map[string]string)
+	lines := bufio.NewScanner(file)
+	for lineNo := 1; lines.Scan(); lineNo++ {
+		line := strings.TrimSpace(lines.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, err := httpclient.ParseHeader(line)
+		if err != nil {
+			// Don't echo the line, it likely holds a secret
+			return nil, fmt.Errorf("auth file line %d: expected \"Name: value\"", lineNo)
+		}
+		headers[name] = value
+	}
+	if err := lines.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read auth file: %w", err)
+	}
+
+	return headers, nil
+}
+
+// authSecrets returns the literal values that must never leave memory: each
+// auth header value, the values of user-supplied --header flags that may be
+// credentials (see customHeaderSecrets) and, for cookies, every individual
+// cookie value
+func authSecrets(authHeaders map[string]string, customHeaders map[string]string) []string {
+	return append(headerSecrets(authHeaders), headerSecrets(customHeaderSecrets(customHeaders))...)
+}
+
+// plainHeaders are standard request headers whose values aren't credentials
+var plainHeaders = map[string]bool{
+	"accept":           true,
+	"accept-encoding":  true,
+	"accept-language":  true,
+	"cache-control":    true,
+	"content-type":     true,
+	"origin":           true,
+	"pragma":           true,
+	"referer":          true,
+	"user-agent":       true,
+	"x-forwarded-for":  true,
+	"x-requested-with": true,
+}
+
+// minCustomSecretLength keeps short --header values such as "1" or "true"
+// from being scrubbed everywhere they appear in findings
+const minCustomSecretLength = 6
+
+// customHeaderSecrets returns the --header values that may be credentials,
+// such as X-Api-Key or a custom token header: all but the plain headers
+// and very short values
+func customHeaderSecrets(headers map[string]string) map[string]string {
+	secrets := make(map[string]string)
+	for name, value := range headers {
+		if plainHeaders[strings.ToLower(name)] || len(strings.TrimSpace(value)) < minCustomSecretLength {
+			continue
+		}
+		secrets[name] = value
+	}
+	return secrets
+}
+
+// headerSecrets returns the values in headers, splitting out cookie values
+// and bearer tokens
+func headerSecrets(headers map[string]string) []string {
+	secrets := make([]string, 0, len(headers))
+	for name, value := range headers {
+		if value == "" {
+			continue
+		}
+		secrets = append(secrets, value)
+
+		if strings.EqualFold(name, "Cookie") {
+			for _, pair := range strings.Split(value, ";") {
+				if _, cookie, ok := strings.Cut(pair, "="); ok && len(strings.TrimSpace(cookie)) >= 4 {
+					secrets = append(secrets, strings.TrimSpace(cookie))
+				}
+			}
+		}
+		if token, ok := strings.CutPrefix(value, "Bearer "); ok {
+			secrets = append(secrets, strings.TrimSpace(token))
+		}
+	}
+	return secrets
+}
+
+// scrubSecrets removes auth values a module may have echoed into a finding,
+// so they never reach the stored result, reports or AI prompts
+func scrubSecrets(findings []models.Finding, secrets []string) {
+	if len(secrets) == 0 {
+		return
+	}
+
+	replacer := make([]string, 0, len(secrets)*2)
+	for _, secret := range secrets {
+		replacer = append(replacer, secret, redact.Placeholder)
+	}
+	r := strings.NewReplacer(replacer...)
+
+	for i := range findings {
+		finding := &findings[i]
+		finding.Title = r.Replace(finding.Title)
+		finding.Description = r.Replace(finding.Description)
+		finding.Evidence = r.Replace(finding.Evidence)
+		finding.Location = r.Replace(finding.Location)
+		for key, value := range finding.Metadata {
+			finding.Metadata[key] = r.Replace(value)
+		}
+	}
+}
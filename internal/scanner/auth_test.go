@@ -0,0 +1,107 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/kumaraguru1735/shadow/internal/redact"
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+func TestAuthSecrets(t *testing.T) {
+	secrets := authSecrets(
+		map[string]string{
+			"Cookie":        "session=abcd1234; theme=x",
+			"Authorization": "Bearer tok-123456",
+		},
+		map[string]string{
+			"X-Api-Key":    "key-987654",
+			"X-Debug":      "1",
+			"Content-Type": "application/json",
+		},
+	)
+	sort.Strings(secrets)
+
+	want := []string{"Bearer tok-123456", "abcd1234", "key-987654", "session=abcd1234; theme=x", "tok-123456"}
+	if strings.Join(secrets, "|") != strings.Join(want, "|") {
+		t.Errorf("authSecrets = %q, want %q", secrets, want)
+	}
+}
+
+func TestScrubSecrets(t *testing.T) {
+	findings := []models.Finding{{
+		Title:       "Reflected key-987654",
+		Description: "Cookie abcd1234 echoed",
+		Evidence:    "X-Api-Key: key-987654",
+		Location:    "https://example.com/?k=key-987654",
+		Metadata:    map[string]string{"header": "key-987654"},
+	}}
+	scrubSecrets(findings, []string{"key-987654", "abcd1234"})
+
+	finding := findings[0]
+	for field, value := range map[string]string{
+		"title":       finding.Title,
+		"description": finding.Description,
+		"evidence":    finding.Evidence,
+		"location":    finding.Location,
+		"metadata":    finding.Metadata["header"],
+	} {
+		if strings.Contains(value, "key-987654") || strings.Contains(value, "abcd1234") || !strings.Contains(value, redact.Placeholder) {
+			t.Errorf("%s = %q, want the secrets redacted", field, value)
+		}
+	}
+}
+
+func TestLoadAuthFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.txt")
+	content := "# copied from the browser\n\nCookie: session=abcd1234\nAuthorization: Bearer tok-123456\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	headers, err := LoadAuthFile(path)
+	if err != nil {
+		t.Fatalf("LoadAuthFile: %v", err)
+	}
+	if headers["Cookie"] != "session=abcd1234" || headers["Authorization"] != "Bearer tok-123456" {
+		t.Errorf("headers = %v", headers)
+	}
+
+	if err := os.WriteFile(path, []byte("not a header with secret-value\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	_, err = LoadAuthFile(path)
+	if err == nil || strings.Contains(err.Error(), "secret-value") {
+		t.Errorf("malformed line error = %v, want an error that doesn't echo the line", err)
+	}
+}
+
+func TestAuthHeadersSentWithRequests(t *testing.T) {
+	received := make(chan http.Header, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Clone()
+	}))
+	defer server.Close()
+
+	client, err := newHTTPClient(models.ScanConfig{
+		Headers:     map[string]string{"Cookie": "overridden=1"},
+		AuthHeaders: map[string]string{"Cookie": "session=abcd1234"},
+	})
+	if err != nil {
+		t.Fatalf("newHTTPClient: %v", err)
+	}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if cookie := (<-received).Get("Cookie"); cookie != "session=abcd1234" {
+		t.Errorf("Cookie = %q, want the session cookie to win over --header", cookie)
+	}
+}
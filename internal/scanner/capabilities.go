@@ -0,0 +1,129 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/kumaraguru1735/shadow/internal/output"
+)
+
+// NetworkCapabilities lets raw-socket scanners run without sudo
+const NetworkCapabilities = "cap_net_raw,cap_net_admin,cap_net_bind_service+eip"
+
+// capabilityTools are the tools setup-caps will grant NetworkCapabilities to
+var capabilityTools = map[string]bool{
+	"nmap":    true,
+	"masscan": true,
+	"naabu":   true,
+}
+
+// CommandRunner runs external commands. Run returns the combined output;
+// RunContext writes it to out as it arrives and stops the command once
+// ctx is done.
+type CommandRunner interface {
+	Run(name string, args ...string) ([]byte, error)
+	RunContext(ctx context.Context, out io.Writer, name string, args ...string) error
+}
+
+// execRunner runs commands with os/exec
+type execRunner struct{}
+
+func (execRunner) Run(name string, args ...string) ([]byte, error) {
+	return exec.Command(name, args...).CombinedOutput()
+}
+
+// RunContext sends SIGTERM first when ctx is done, because sudo relays it
+// to the tool while a SIGKILL would only kill sudo and leave the tool
+// running. The tool is killed if it hasn't exited after toolKillGrace.
+func (execRunner) RunContext(ctx context.Context, out io.Writer, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(syscall.SIGTERM)
+	}
+	cmd.WaitDelay = toolKillGrace
+	cmd.Stdout = out
+	cmd.Stderr = out
+	return cmd.Run()
+}
+
+// SupportedCapabilityTools lists the tools SetupCapabilities accepts
+func SupportedCapabilityTools() []string {
+	tools := make([]string, 0, len(capabilityTools))
+	for tool := range capabilityTools {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+	return tools
+}
+
+// resolveToolPath finds tool on PATH and resolves symlinks, since setcap
+// must be applied to the real binary
+func resolveToolPath(tool string) (string, error) {
+	path, err := exec.LookPath(tool)
+	if err != nil {
+		return "", fmt.Errorf("%s not found in PATH: %w", tool, err)
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	return filepath.Abs(path)
+}
+
+// SetupCapabilities grants NetworkCapabilities to tool with sudo setcap,
+// after confirmation, and verifies the result with getcap
+func (pm *PermissionManager) SetupCapabilities(tool string) error {
+	if !capabilityTools[tool] {
+		return fmt.Errorf("%s is not supported (supported: %s)", tool, strings.Join(SupportedCapabilityTools(), ", "))
+	}
+
+	path, err := resolveToolPath(tool)
+	if err != nil {
+		return err
+	}
+
+	command := []string{"setcap", NetworkCapabilities, path}
+	output.Println("\n🔐 Grant Linux Capabilities")
+	output.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	output.Printf("\n📋 Tool: %s (%s)\n", tool, path)
+	output.Printf("💻 Command: sudo %s\n", strings.Join(command, " "))
+	output.Println("\n⚠️  Any user who can run this binary gets raw network access")
+
+	output.Print("\nRun this command? (yes/no): ")
+	response, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	response = strings.ToLower(strings.TrimSpace(response))
+	if err != nil || (response != "yes" && response != "y") {
+		pm.audit("setcap", tool, AuditDenied, "sudo "+strings.Join(command, " "))
+		return fmt.Errorf("not confirmed")
+	}
+
+	return pm.applyCapabilities(tool, path)
+}
+
+// applyCapabilities runs sudo setcap on path and checks getcap reports it
+func (pm *PermissionManager) applyCapabilities(tool string, path string) error {
+	command := "sudo setcap " + NetworkCapabilities + " " + path
+
+	if out, err := pm.runner.Run("sudo", "setcap", NetworkCapabilities, path); err != nil {
+		pm.audit("setcap", tool, AuditFailed, command)
+		return fmt.Errorf("setcap failed: %w\nOutput: %s", err, strings.TrimSpace(string(out)))
+	}
+	pm.audit("setcap", tool, AuditExecuted, command)
+
+	out, err := pm.runner.Run("getcap", path)
+	if err != nil {
+		return fmt.Errorf("could not verify capabilities with getcap: %w", err)
+	}
+	if !strings.Contains(string(out), "cap_net_raw") {
+		return fmt.Errorf("capabilities not set on %s (getcap: %q)", path, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
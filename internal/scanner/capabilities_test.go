@@ -0,0 +1,87 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// fakeRunner records commands and answers from tables keyed by the command
+// name, or by name and first argument ("sudo visudo") when that is present
+type fakeRunner struct {
+	calls   []string
+	outputs map[string]string
+	errs    map[string]error
+}
+
+func (r *fakeRunner) Run(name string, args ...string) ([]byte, error) {
+	r.calls = append(r.calls, strings.Join(append([]string{name}, args...), " "))
+	key := name
+	if len(args) > 0 {
+		if _, ok := r.outputs[name+" "+args[0]]; ok {
+			key = name + " " + args[0]
+		}
+		if _, ok := r.errs[name+" "+args[0]]; ok {
+			key = name + " " + args[0]
+		}
+	}
+	return []byte(r.outputs[key]), r.errs[key]
+}
+
+func (r *fakeRunner) RunContext(ctx context.Context, out io.Writer, name string, args ...string) error {
+	output, err := r.Run(name, args...)
+	out.Write(output)
+	return err
+}
+
+func TestApplyCapabilities(t *testing.T) {
+	pm, path := testPermissionManager(t)
+	runner := &fakeRunner{outputs: map[string]string{"getcap": "/usr/bin/nmap cap_net_bind_service,cap_net_admin,cap_net_raw=eip"}}
+	pm.runner = runner
+
+	if err := pm.applyCapabilities("nmap", "/usr/bin/nmap"); err != nil {
+		t.Fatalf("applyCapabilities: %v", err)
+	}
+	want := []string{"sudo setcap " + NetworkCapabilities + " /usr/bin/nmap", "getcap /usr/bin/nmap"}
+	if strings.Join(runner.calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("calls = %q, want %q", runner.calls, want)
+	}
+	if lines := readAuditLog(t, path); len(lines) != 1 || !strings.Contains(lines[0], "event=setcap") || !strings.Contains(lines[0], "decision=executed") {
+		t.Errorf("audit log = %q", lines)
+	}
+}
+
+func TestApplyCapabilitiesFailures(t *testing.T) {
+	tests := []struct {
+		name    string
+		runner  *fakeRunner
+		wantErr string
+	}{
+		{"setcap fails", &fakeRunner{outputs: map[string]string{"sudo": "operation not permitted"}, errs: map[string]error{"sudo": errors.New("exit status 1")}}, "setcap failed"},
+		{"getcap fails", &fakeRunner{errs: map[string]error{"getcap": errors.New("not found")}}, "could not verify"},
+		{"capabilities missing", &fakeRunner{outputs: map[string]string{"getcap": ""}}, "capabilities not set"},
+	}
+	for _, tt := range tests {
+		pm, _ := testPermissionManager(t)
+		pm.runner = tt.runner
+		if err := pm.applyCapabilities("nmap", "/usr/bin/nmap"); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestSetupCapabilitiesRejectsUnsupportedTool(t *testing.T) {
+	pm, _ := testPermissionManager(t)
+	runner := &fakeRunner{}
+	pm.runner = runner
+
+	err := pm.SetupCapabilities("bash")
+	if err == nil || !strings.Contains(err.Error(), "supported: masscan, naabu, nmap") {
+		t.Errorf("error = %v, want the supported tools listed", err)
+	}
+	if len(runner.calls) != 0 {
+		t.Errorf("ran %q for an unsupported tool", runner.calls)
+	}
+}
@@ -0,0 +1,376 @@
+package scanner
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/net/html"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+// maxPageBytes caps how much of a page PageContentModule parses
+const maxPageBytes = 2 << 20
+
+// maxContentFindings caps the findings of each kind per page, so a page
+// with hundreds of http:// images doesn't flood the report
+const maxContentFindings = 20
+
+// PageContentModule parses the target's HTML for mixed content (http://
+// resources on an HTTPS page) and forms that submit over HTTP or to
+// another domain. It also records the software the response identifies
+// (Server and X-Powered-By headers, generator meta tag, page title), which
+// default credential checks match.
+type PageContentModule struct {
+	client *http.Client
+}
+
+func (m *PageContentModule) Name() string {
+	return "Page Content"
+}
+
+func (m *PageContentModule) Run(ctx context.Context, target string) ([]models.Finding, error) {
+	resp, err := getContext(ctx, m.client, targetURL(target))
+	if err != nil {
+		var certErr *tls.CertificateVerificationError
+		if errors.As(err, &certErr) {
+			return nil, fmt.Errorf("request failed: %w (scan with --insecure to check targets with untrusted certificates)", err)
+		}
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	page := resp.Request.URL
+	var content pageContent
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" || strings.Contains(strings.ToLower(contentType), "html") {
+		content, err = checkPageContent(page, io.LimitReader(resp.Body, maxPageBytes))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", page, err)
+		}
+	}
+
+	findings := make([]models.Finding, 0, len(content.issues)+1)
+	if finding, ok := fingerprintFinding(page, resp, content); ok {
+		findings = append(findings, finding)
+	}
+	for _, issue := range content.issues {
+		finding := models.Finding{
+			ID:          uuid.New().String(),
+			Type:        issue.kind,
+			Severity:    "medium",
+			Confidence:  models.ConfidenceHigh,
+			Title:       issue.title,
+			Description: issue.description,
+			Evidence:    issue.element,
+			Location:    page.String(),
+			Tags:        []string{"http", issue.kind},
+			Timestamp:   time.Now(),
+		}
+		finding.SetMeta(models.MetaHost, page.Hostname())
+		finding.SetMeta(models.MetaHTTPStatus, strconv.Itoa(resp.StatusCode))
+		findings = append(findings, finding)
+	}
+	return findings, nil
+}
+
+// fingerprintFinding reports the software a response identifies through
+// its Server and X-Powered-By headers, generator meta tag and title, if
+// any. The product banner goes in MetaVersion like a port scanner's.
+func fingerprintFinding(page *url.URL, resp *http.Response, content pageContent) (models.Finding, bool) {
+	banners := make([]string, 0, 3)
+	for _, banner := range []string{resp.Header.Get("Server"), resp.Header.Get("X-Powered-By"), content.generator} {
+		if banner = strings.TrimSpace(banner); banner != "" {
+			banners = append(banners, banner)
+		}
+	}
+	if len(banners) == 0 && content.title == "" {
+		return models.Finding{}, false
+	}
+
+	identified := strings.Join(banners, "; ")
+	if identified == "" {
+		identified = strconv.Quote(content.title)
+	}
+	evidence := make([]string, 0, 4)
+	for _, header := range []string{"Server", "X-Powered-By"} {
+		if value := resp.Header.Get(header); value != "" {
+			evidence = append(evidence, header+": "+value)
+		}
+	}
+	if content.generator != "" {
+		evidence = append(evidence, fmt.Sprintf("<meta name=\"generator\" content=%q>", content.generator))
+	}
+	if content.title != "" {
+		evidence = append(evidence, "<title>"+content.title+"</title>")
+	}
+
+	finding := models.Finding{
+		ID:          uuid.New().String(),
+		Type:        "http-fingerprint",
+		Severity:    "info",
+		Confidence:  models.ConfidenceHigh,
+		Title:       fmt.Sprintf("Web software identified: %s", identified),
+		Description: fmt.Sprintf("The response from %s identifies the software serving it. Version banners help attackers pick matching exploits; consider removing them.", page),
+		Evidence:    strings.Join(evidence, "\n"),
+		Location:    page.String(),
+		Tags:        []string{"http", "fingerprint"},
+		Timestamp:   time.Now(),
+	}
+	finding.SetMeta(models.MetaHost, page.Hostname())
+	finding.SetMeta(models.MetaHTTPStatus, strconv.Itoa(resp.StatusCode))
+	finding.SetMeta(models.MetaVersion, strings.Join(banners, "; "))
+	finding.SetMeta(models.MetaHTTPTitle, content.title)
+	return finding, true
+}
+
+// maxTitleLength caps the page title recorded from a page
+const maxTitleLength = 120
+
+// pageContent is what checkPageContent found in a page's HTML
+type pageContent struct {
+	issues    []contentIssue
+	title     string // first <title>, whitespace collapsed
+	generator string // <meta name="generator"> content, e.g. "WordPress 6.4"
+}
+
+// contentIssue is a problem found in a page's HTML
+type contentIssue struct {
+	kind        string // finding type: mixed-content or insecure-form
+	title       string
+	description string
+	element     string // the offending element's start tag
+}
+
+// mixedContentAttrs lists the elements that load subresources and the
+// attribute holding the URL. Scripts, stylesheets, frames and plugins are
+// "active" content: browsers block them, breaking the page, and an
+// attacker on the network who can modify them controls the page.
+var mixedContentAttrs = []struct {
+	tag    string
+	attr   string
+	active bool
+}{
+	{"script", "src", true},
+	{"link", "href", true},
+	{"iframe", "src", true},
+	{"frame", "src", true},
+	{"object", "data", true},
+	{"embed", "src", true},
+	{"img", "src", false},
+	{"audio", "src", false},
+	{"video", "src", false},
+	{"video", "poster", false},
+	{"source", "src", false},
+	{"track", "src", false},
+}
+
+// linkLoadsResource reports whether a <link> rel makes the browser fetch
+// its href as part of the page (unlike rel=canonical or alternate)
+func linkLoadsResource(rel string) bool {
+	for _, value := range strings.Fields(strings.ToLower(rel)) {
+		switch value {
+		case "stylesheet", "icon", "preload", "modulepreload", "prefetch", "manifest":
+			return true
+		}
+	}
+	return false
+}
+
+// checkPageContent parses the HTML of the page at pageURL and returns its
+// mixed content, found only when the page is served over HTTPS, forms
+// that submit over HTTP or to a different domain, and its title and
+// generator. Malformed HTML is parsed the way browsers would.
+func checkPageContent(pageURL *url.URL, body io.Reader) (pageContent, error) {
+	doc, err := html.Parse(body)
+	if err != nil {
+		return pageContent{}, err
+	}
+	var content pageContent
+
+	// <base href> changes what relative URLs resolve against
+	base := pageURL
+	https := strings.EqualFold(pageURL.Scheme, "https")
+	mixed := make([]contentIssue, 0)
+	forms := make([]contentIssue, 0)
+	seen := make(map[string]bool)
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "title":
+				// <title> inside <svg> names the drawing, not the page
+				if content.title == "" && n.Namespace == "" {
+					content.title = nodeText(n)
+				}
+			case "meta":
+				if name, _ := htmlAttr(n, "name"); content.generator == "" && strings.EqualFold(name, "generator") {
+					content.generator, _ = htmlAttr(n, "content")
+				}
+			case "base":
+				if href, ok := htmlAttr(n, "href"); ok {
+					if resolved, err := base.Parse(strings.TrimSpace(href)); err == nil {
+						base = resolved
+					}
+				}
+			case "form":
+				action, _ := htmlAttr(n, "action")
+				if issue, ok := checkFormAction(pageURL, base, n, action); ok && !seen[issue.element] && len(forms) < maxContentFindings {
+					seen[issue.element] = true
+					forms = append(forms, issue)
+				}
+			case "button", "input":
+				// formaction overrides the form's action for this button
+				if action, ok := htmlAttr(n, "formaction"); ok {
+					if issue, ok := checkFormAction(pageURL, base, n, action); ok && !seen[issue.element] && len(forms) < maxContentFindings {
+						seen[issue.element] = true
+						forms = append(forms, issue)
+					}
+				}
+			}
+
+			if https {
+				for _, candidate := range mixedContentAttrs {
+					if n.Data != candidate.tag {
+						continue
+					}
+					if n.Data == "link" {
+						if rel, _ := htmlAttr(n, "rel"); !linkLoadsResource(rel) {
+							continue
+						}
+					}
+					value, ok := htmlAttr(n, candidate.attr)
+					if !ok {
+						continue
+					}
+					resource, err := base.Parse(strings.TrimSpace(value))
+					if err != nil || !strings.EqualFold(resource.Scheme, "http") {
+						continue
+					}
+					element := startTag(n)
+					if seen[element] || len(mixed) >= maxContentFindings {
+						continue
+					}
+					seen[element] = true
+
+					kind := "passive"
+					consequence := "An attacker on the network can replace or observe it, and browsers flag the page as not fully secure."
+					if candidate.active {
+						kind = "active"
+						consequence = "Browsers block it, breaking the page, and where it loads an attacker on the network can modify it to take over the page."
+					}
+					mixed = append(mixed, contentIssue{
+						kind:  "mixed-content",
+						title: fmt.Sprintf("Mixed content: <%s> loaded over HTTP (%s)", n.Data, resource.Host),
+						description: fmt.Sprintf("The HTTPS page loads %s over plain HTTP (%s mixed content). %s "+
+							"Serve the resource over HTTPS or from the same origin.", resource, kind, consequence),
+						element: element,
+					})
+				}
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+
+	content.issues = append(mixed, forms...)
+	return content, nil
+}
+
+// nodeText returns the text inside n with whitespace collapsed, capped at
+// maxTitleLength
+func nodeText(n *html.Node) string {
+	var b strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.TextNode {
+			b.WriteString(child.Data)
+		}
+	}
+	text := strings.Join(strings.Fields(b.String()), " ")
+	if len(text) > maxTitleLength {
+		text = strings.ToValidUTF8(text[:maxTitleLength], "") + "…"
+	}
+	return text
+}
+
+// checkFormAction reports a form (or formaction button) n whose action
+// submits over HTTP or to another domain than the page's
+func checkFormAction(pageURL *url.URL, base *url.URL, n *html.Node, action string) (contentIssue, bool) {
+	action = strings.TrimSpace(action)
+	if action == "" {
+		// Submits to the page itself
+		return contentIssue{}, false
+	}
+	target, err := base.Parse(action)
+	if err != nil {
+		return contentIssue{}, false
+	}
+	scheme := strings.ToLower(target.Scheme)
+	if scheme != "http" && scheme != "https" {
+		// javascript:, mailto: and the like don't post anywhere themselves
+		return contentIssue{}, false
+	}
+
+	if scheme == "http" {
+		return contentIssue{
+			kind:  "insecure-form",
+			title: fmt.Sprintf("Form submits over HTTP (%s)", target.Host),
+			description: fmt.Sprintf("The form on %s submits to %s over plain HTTP, so anything entered, such as "+
+				"credentials or personal data, can be read and modified on the network. Post to an HTTPS URL.", pageURL, target),
+			element: startTag(n),
+		}, true
+	}
+
+	if !sameSite(pageURL.Hostname(), target.Hostname()) {
+		return contentIssue{
+			kind:  "insecure-form",
+			title: fmt.Sprintf("Form submits to external domain %s", target.Hostname()),
+			description: fmt.Sprintf("The form on %s submits to %s, another domain, which receives everything entered. "+
+				"Check that the destination is intended and trusted; injected forms pointing elsewhere are a "+
+				"common way to harvest credentials.", pageURL, target),
+			element: startTag(n),
+		}, true
+	}
+	return contentIssue{}, false
+}
+
+// sameSite reports whether a and b are the same host or one is a
+// subdomain of the other, e.g. example.com and login.example.com
+func sameSite(a string, b string) bool {
+	a, b = strings.ToLower(strings.TrimSuffix(a, ".")), strings.ToLower(strings.TrimSuffix(b, "."))
+	return a == b || strings.HasSuffix(a, "."+b) || strings.HasSuffix(b, "."+a)
+}
+
+// htmlAttr returns the value of n's attribute key
+func htmlAttr(n *html.Node, key string) (string, bool) {
+	for _, attr := range n.Attr {
+		if attr.Namespace == "" && attr.Key == key {
+			return attr.Val, true
+		}
+	}
+	return "", false
+}
+
+// startTag renders n's start tag, e.g. <form action="http://x/login">, for
+// evidence
+func startTag(n *html.Node) string {
+	var b strings.Builder
+	b.WriteString("<" + n.Data)
+	for _, attr := range n.Attr {
+		b.WriteString(" " + attr.Key + "=" + strconv.Quote(attr.Val))
+	}
+	b.WriteString(">")
+	return b.String()
+}
@@ -0,0 +1,102 @@
+package scanner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const mixedContentPage = `<!DOCTYPE html>
+<html><head>
+<title>Shop</title>
+<script src="http://cdn.example.net/app.js"></script>
+<link rel="stylesheet" href="http://cdn.example.net/site.css">
+<link rel="canonical" href="http://shop.example.com/">
+</head><body>
+<img src="http://img.example.net/logo.png">
+<img src="http://img.example.net/logo.png">
+<img src="/relative.png">
+<a href="http://elsewhere.example.net/">links don't load anything</a>
+<form action="http://shop.example.com/login" method="post"></form>
+<form action="https://collector.evil.example/steal"></form>
+<form action="https://login.shop.example.com/session"></form>
+<form action="javascript:void(0)"></form>
+<form><button formaction="http://shop.example.com/pay">Pay</button></form>
+</body></html>`
+
+func contentKinds(t *testing.T, pageURL string, page string) map[string][]string {
+	t.Helper()
+	parsed, _ := url.Parse(pageURL)
+	content, err := checkPageContent(parsed, strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	kinds := make(map[string][]string)
+	for _, issue := range content.issues {
+		kinds[issue.kind] = append(kinds[issue.kind], issue.title)
+	}
+	return kinds
+}
+
+func TestCheckPageContentHTTPS(t *testing.T) {
+	kinds := contentKinds(t, "https://shop.example.com/", mixedContentPage)
+
+	// Script, stylesheet and one image; duplicates, relative URLs,
+	// canonical links and anchors don't count
+	mixed := kinds["mixed-content"]
+	if len(mixed) != 3 {
+		t.Errorf("mixed content = %q, want script, stylesheet and image", mixed)
+	}
+	if len(mixed) > 0 && !strings.Contains(mixed[0], "<script>") {
+		t.Errorf("first mixed content = %q, want the script", mixed[0])
+	}
+
+	// HTTP form, external form and HTTP formaction; the same-site HTTPS
+	// and javascript: forms are fine
+	forms := strings.Join(kinds["insecure-form"], "\n")
+	for _, want := range []string{"Form submits over HTTP (shop.example.com)", "external domain collector.evil.example"} {
+		if !strings.Contains(forms, want) {
+			t.Errorf("insecure forms = %q, want %q", forms, want)
+		}
+	}
+	if len(kinds["insecure-form"]) != 3 {
+		t.Errorf("insecure forms = %q, want 3", kinds["insecure-form"])
+	}
+}
+
+func TestCheckPageContentHTTP(t *testing.T) {
+	// Mixed content only exists on HTTPS pages
+	kinds := contentKinds(t, "http://shop.example.com/", mixedContentPage)
+	if len(kinds["mixed-content"]) != 0 {
+		t.Errorf("mixed content on an HTTP page: %q", kinds["mixed-content"])
+	}
+}
+
+func TestCheckPageContentBaseHref(t *testing.T) {
+	page := `<html><head><base href="http://static.example.net/"></head>
+<body><script src="app.js"></script></body></html>`
+	kinds := contentKinds(t, "https://shop.example.com/", page)
+	if len(kinds["mixed-content"]) != 1 {
+		t.Errorf("mixed content = %q, want the script resolved against <base href>", kinds["mixed-content"])
+	}
+}
+
+func TestPageContentModuleSkipsNonHTML(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"form": "<form action=\"http://x\">"}`))
+	}))
+	defer server.Close()
+
+	module := &PageContentModule{client: server.Client()}
+	findings, err := module.Run(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(findings) != 0 {
+		t.Errorf("findings for a JSON response: %+v", findings)
+	}
+}
@@ -0,0 +1,288 @@
+package scanner
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+// defaultCredential describes a product that ships with well-known
+// credentials (or none at all)
+type defaultCredential struct {
+	product     string
+	keywords    []string // lowercase product names matched in banners, titles and nuclei template IDs
+	pairs       []string // documented defaults, user/password
+	severity    string
+	remediation string
+}
+
+// defaultCredentials lists products with documented default credentials.
+// Shadow never tries them; a match only flags that they may still work.
+var defaultCredentials = []defaultCredential{
+	{
+		product:     "MySQL",
+		keywords:    []string{"mysql", "mariadb"},
+		pairs:       []string{"root/(empty)"},
+		severity:    "high",
+		remediation: "Set a strong root password (mysql_secure_installation) and don't expose the port publicly.",
+	},
+	{
+		product:     "PostgreSQL",
+		keywords:    []string{"postgresql"},
+		pairs:       []string{"postgres/postgres"},
+		severity:    "high",
+		remediation: "Set a strong password for the postgres role and require md5/scram authentication in pg_hba.conf.",
+	},
+	{
+		product:     "Microsoft SQL Server",
+		keywords:    []string{"microsoft sql server"},
+		pairs:       []string{"sa/(empty)", "sa/sa"},
+		severity:    "high",
+		remediation: "Disable or rename the sa login, or give it a strong password.",
+	},
+	{
+		product:     "Oracle Database",
+		keywords:    []string{"oracle tns"},
+		pairs:       []string{"system/manager", "sys/change_on_install", "scott/tiger"},
+		severity:    "high",
+		remediation: "Lock or change the passwords of the default accounts (SYSTEM, SYS, SCOTT).",
+	},
+	{
+		product:     "MongoDB",
+		keywords:    []string{"mongodb"},
+		pairs:       []string{"no authentication by default"},
+		severity:    "high",
+		remediation: "Enable authorization (security.authorization: enabled) and bind to private interfaces only.",
+	},
+	{
+		product:     "Redis",
+		keywords:    []string{"redis"},
+		pairs:       []string{"no password by default"},
+		severity:    "high",
+		remediation: "Set requirepass or ACL users, enable protected mode and bind to private interfaces only.",
+	},
+	{
+		product:     "Elasticsearch",
+		keywords:    []string{"elasticsearch"},
+		pairs:       []string{"elastic/changeme", "no authentication before 8.0"},
+		severity:    "high",
+		remediation: "Enable security features and reset the elastic user's password.",
+	},
+	{
+		product:     "Apache Tomcat Manager",
+		keywords:    []string{"tomcat"},
+		pairs:       []string{"tomcat/tomcat", "admin/admin", "tomcat/s3cret"},
+		severity:    "high",
+		remediation: "Remove the manager apps or restrict them to localhost, and set strong passwords in tomcat-users.xml.",
+	},
+	{
+		product:     "RabbitMQ",
+		keywords:    []string{"rabbitmq"},
+		pairs:       []string{"guest/guest"},
+		severity:    "medium",
+		remediation: "Delete the guest user or restrict it to localhost (the default since 3.3).",
+	},
+	{
+		product:     "Grafana",
+		keywords:    []string{"grafana"},
+		pairs:       []string{"admin/admin"},
+		severity:    "medium",
+		remediation: "Change the admin password and disable sign-up.",
+	},
+	{
+		product:     "MikroTik RouterOS",
+		keywords:    []string{"mikrotik", "routeros"},
+		pairs:       []string{"admin/(empty)"},
+		severity:    "high",
+		remediation: "Set an admin password, or create a new admin account and disable the default one.",
+	},
+	{
+		product:     "Cisco device",
+		keywords:    []string{"cisco"},
+		pairs:       []string{"cisco/cisco", "admin/admin"},
+		severity:    "high",
+		remediation: "Replace the factory accounts with unique credentials and restrict management access.",
+	},
+	{
+		product:     "Ubiquiti device",
+		keywords:    []string{"ubiquiti", "ubnt"},
+		pairs:       []string{"ubnt/ubnt"},
+		severity:    "high",
+		remediation: "Change the ubnt account's password and restrict management access.",
+	},
+	{
+		product:     "VNC",
+		keywords:    []string{"vnc"},
+		pairs:       []string{"no password", "password"},
+		severity:    "medium",
+		remediation: "Require a strong VNC password, or tunnel VNC over SSH or a VPN.",
+	},
+	{
+		product:     "FTP server",
+		keywords:    []string{"ftpd", "ftp server"},
+		pairs:       []string{"anonymous/anonymous", "ftp/ftp"},
+		severity:    "medium",
+		remediation: "Disable anonymous login unless the server is meant to be public.",
+	},
+	{
+		product:     "phpMyAdmin",
+		keywords:    []string{"phpmyadmin"},
+		pairs:       []string{"root/(empty)"},
+		severity:    "high",
+		remediation: "Set a MySQL root password and restrict phpMyAdmin to trusted addresses, or remove it.",
+	},
+	{
+		product:     "Jenkins",
+		keywords:    []string{"jenkins"},
+		pairs:       []string{"admin/(initialAdminPassword file)", "no authentication before 2.0"},
+		severity:    "high",
+		remediation: "Enable security, finish the setup wizard and disable anonymous read access.",
+	},
+	{
+		product:     "Oracle WebLogic",
+		keywords:    []string{"weblogic"},
+		pairs:       []string{"weblogic/weblogic1", "weblogic/welcome1"},
+		severity:    "high",
+		remediation: "Change the weblogic password and keep the admin console off public interfaces.",
+	},
+	{
+		product:     "JBoss / WildFly",
+		keywords:    []string{"jboss", "wildfly"},
+		pairs:       []string{"admin/admin"},
+		severity:    "high",
+		remediation: "Remove the default management users and restrict the admin console to localhost.",
+	},
+	{
+		product:     "GlassFish",
+		keywords:    []string{"glassfish"},
+		pairs:       []string{"admin/(empty)"},
+		severity:    "high",
+		remediation: "Set an admin password (asadmin change-admin-password) and disable secure-admin on public interfaces.",
+	},
+	{
+		product:     "Zabbix",
+		keywords:    []string{"zabbix"},
+		pairs:       []string{"Admin/zabbix"},
+		severity:    "high",
+		remediation: "Change the Admin user's password and disable the guest user.",
+	},
+	{
+		product:     "SonarQube",
+		keywords:    []string{"sonarqube"},
+		pairs:       []string{"admin/admin"},
+		severity:    "medium",
+		remediation: "Change the admin password and require authentication (sonar.forceAuthentication).",
+	},
+	{
+		product:     "Nexus Repository",
+		keywords:    []string{"nexus repository"},
+		pairs:       []string{"admin/admin123"},
+		severity:    "high",
+		remediation: "Change the admin password and disable anonymous access.",
+	},
+	{
+		product:     "Magnolia CMS",
+		keywords:    []string{"magnolia"},
+		pairs:       []string{"superuser/superuser"},
+		severity:    "high",
+		remediation: "Change the superuser password and keep the author instance off the internet.",
+	},
+	{
+		product:     "Liferay Portal",
+		keywords:    []string{"liferay"},
+		pairs:       []string{"test@liferay.com/test"},
+		severity:    "high",
+		remediation: "Delete or change the test@liferay.com account.",
+	},
+	{
+		product:     "OpenCms",
+		keywords:    []string{"opencms"},
+		pairs:       []string{"Admin/admin"},
+		severity:    "high",
+		remediation: "Change the Admin user's password.",
+	},
+	{
+		product:     "Plone",
+		keywords:    []string{"plone"},
+		pairs:       []string{"admin/admin"},
+		severity:    "high",
+		remediation: "Change the Zope admin password set at install time.",
+	},
+	{
+		product:     "TYPO3",
+		keywords:    []string{"typo3"},
+		pairs:       []string{"admin/password"},
+		severity:    "high",
+		remediation: "Change the backend admin password and the install tool password.",
+	},
+}
+
+// matchDefaultCredential returns the entry for the product a finding's
+// evidence reveals, if any: a version banner (nmap -sV, HTTP Server and
+// generator), a page title or a nuclei template ID. The service name is
+// not matched, since a port scan only guesses it from the port number.
+// Only metadata is matched, so a host name such as cisco.example.com
+// doesn't count as a Cisco device.
+func matchDefaultCredential(finding models.Finding) (defaultCredential, bool) {
+	evidence := strings.ToLower(strings.Join([]string{
+		finding.Metadata[models.MetaVersion],
+		finding.Metadata[models.MetaHTTPTitle],
+		finding.Metadata[metaNucleiTemplate],
+	}, "\n"))
+	if strings.TrimSpace(evidence) == "" {
+		return defaultCredential{}, false
+	}
+
+	for _, entry := range defaultCredentials {
+		for _, keyword := range entry.keywords {
+			if strings.Contains(evidence, keyword) {
+				return entry, true
+			}
+		}
+	}
+	return defaultCredential{}, false
+}
+
+// defaultCredentialFindings flags products among findings that ship with
+// default credentials, one finding per product and location. No login is
+// attempted, so the findings are low confidence.
+func defaultCredentialFindings(findings []models.Finding) []models.Finding {
+	flagged := make([]models.Finding, 0)
+	seen := make(map[string]bool)
+
+	for _, finding := range findings {
+		if finding.Type == "default-credentials" {
+			continue
+		}
+		entry, ok := matchDefaultCredential(finding)
+		if !ok || seen[entry.product+"|"+finding.Location] {
+			continue
+		}
+		seen[entry.product+"|"+finding.Location] = true
+
+		credential := models.Finding{
+			ID:         uuid.New().String(),
+			Type:       "default-credentials",
+			Severity:   entry.severity,
+			Confidence: models.ConfidenceLow,
+			Title:      fmt.Sprintf("Possible default credentials on %s", entry.product),
+			Description: fmt.Sprintf("%s was detected at %s. It ships with documented default credentials (%s); "+
+				"if they were never changed, anyone can log in. Shadow did not try them. Remediation: %s",
+				entry.product, finding.Location, strings.Join(entry.pairs, ", "), entry.remediation),
+			Evidence:  fmt.Sprintf("Based on: %s", finding.Title),
+			Location:  finding.Location,
+			Tags:      []string{"default-credentials", "authentication"},
+			Timestamp: time.Now(),
+		}
+		for _, key := range []string{models.MetaHost, models.MetaPort, models.MetaProtocol, models.MetaService, models.MetaVersion, models.MetaHTTPTitle} {
+			credential.SetMeta(key, finding.Metadata[key])
+		}
+		flagged = append(flagged, credential)
+	}
+
+	return flagged
+}
@@ -0,0 +1,110 @@
+package scanner
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+func TestMatchDefaultCredential(t *testing.T) {
+	tests := []struct {
+		name    string
+		meta    map[string]string
+		product string // empty for no match
+	}{
+		{"service guessed from the port", map[string]string{models.MetaService: "mysql", models.MetaPort: "3306"}, ""},
+		{"nmap banner", map[string]string{models.MetaService: "mysql", models.MetaVersion: "MySQL 8.0.32"}, "MySQL"},
+		{"nuclei template", map[string]string{metaNucleiTemplate: "tomcat-manager-login"}, "Apache Tomcat Manager"},
+		{"page title", map[string]string{models.MetaHTTPTitle: "Dashboard [Jenkins]"}, "Jenkins"},
+		{"generator", map[string]string{models.MetaVersion: "nginx; Plone - http://plone.com"}, "Plone"},
+		{"unrelated banner", map[string]string{models.MetaVersion: "OpenSSH 9.6"}, ""},
+	}
+	for _, tt := range tests {
+		entry, ok := matchDefaultCredential(models.Finding{Title: "cisco.example.com", Metadata: tt.meta})
+		if ok != (tt.product != "") || entry.product != tt.product {
+			t.Errorf("%s: matched %q (%v), want %q", tt.name, entry.product, ok, tt.product)
+		}
+	}
+}
+
+func TestDefaultCredentialFindingsIgnoresHostNames(t *testing.T) {
+	finding := models.Finding{Title: "Open TCP port 443", Location: "cisco.example.com:443"}
+	finding.SetMeta(models.MetaHost, "cisco.example.com")
+	finding.SetMeta(models.MetaService, "https")
+	if flagged := defaultCredentialFindings([]models.Finding{finding}); len(flagged) != 0 {
+		t.Errorf("host name flagged as a product: %+v", flagged)
+	}
+}
+
+func TestParseToolOutputFlagsDefaultCredentials(t *testing.T) {
+	output := "Nmap scan report for db.example.com\n" +
+		"3306/tcp open mysql MySQL 8.0.36\n" +
+		"8080/tcp open http Apache Tomcat 9.0.65\n" +
+		"8081/tcp open http Apache Tomcat 9.0.65\n"
+	findings, ok := ParseToolOutput("nmap", "db.example.com", []byte(output))
+	if !ok {
+		t.Fatal("nmap output not parsed")
+	}
+
+	var flagged []models.Finding
+	for _, finding := range findings {
+		if finding.Type == "default-credentials" {
+			flagged = append(flagged, finding)
+		}
+	}
+	if len(flagged) != 3 {
+		t.Fatalf("got %d default-credentials findings, want one per product and location: %+v", len(flagged), flagged)
+	}
+
+	mysql := flagged[0]
+	if mysql.Title != "Possible default credentials on MySQL" || mysql.Severity != "high" ||
+		mysql.Confidence != models.ConfidenceLow || mysql.Location != "db.example.com:3306" {
+		t.Errorf("mysql finding = %+v", mysql)
+	}
+	if !strings.Contains(mysql.Description, "root/(empty)") || mysql.Metadata[models.MetaVersion] != "MySQL 8.0.36" {
+		t.Errorf("mysql finding misses the default pair or banner: %+v", mysql)
+	}
+	if mysql.Fingerprint == "" {
+		t.Error("derived finding not fingerprinted")
+	}
+
+	// Enriching again doesn't flag the derived findings themselves
+	if again := defaultCredentialFindings(flagged); len(again) != 0 {
+		t.Errorf("derived findings flagged again: %+v", again)
+	}
+}
+
+func TestPageFingerprint(t *testing.T) {
+	page, _ := url.Parse("https://example.com/")
+	content, err := checkPageContent(page, strings.NewReader(`<html><head>
+		<title>
+			phpMyAdmin
+		</title>
+		<meta name="generator" content="WordPress 6.4">
+		</head><body><svg><title>logo</title></svg></body></html>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content.title != "phpMyAdmin" || content.generator != "WordPress 6.4" {
+		t.Errorf("title %q, generator %q", content.title, content.generator)
+	}
+
+	resp := &http.Response{StatusCode: 200, Header: http.Header{"Server": {"Apache/2.4.58"}}}
+	finding, ok := fingerprintFinding(page, resp, content)
+	if !ok {
+		t.Fatal("no fingerprint finding")
+	}
+	if finding.Metadata[models.MetaVersion] != "Apache/2.4.58; WordPress 6.4" || finding.Metadata[models.MetaHTTPTitle] != "phpMyAdmin" {
+		t.Errorf("metadata = %v", finding.Metadata)
+	}
+	if entry, ok := matchDefaultCredential(finding); !ok || entry.product != "phpMyAdmin" {
+		t.Errorf("fingerprint matched %q, want phpMyAdmin", entry.product)
+	}
+
+	if _, ok := fingerprintFinding(page, &http.Response{Header: http.Header{}}, pageContent{}); ok {
+		t.Error("fingerprint reported for a response that identifies nothing")
+	}
+}
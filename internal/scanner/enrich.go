@@ -0,0 +1,19 @@
+package scanner
+
+import "github.com/kumaraguru1735/shadow/pkg/models"
+
+// enrichFindings post-processes module findings before they are stored,
+// reported or sent to the AI, and returns them with any findings derived
+// from them appended
+func enrichFindings(findings []models.Finding) []models.Finding {
+	for i := range findings {
+		// CVSS is more precise than a module's hand-picked severity
+		models.ReconcileSeverityWithCVSS(&findings[i])
+	}
+
+	// Detected products that ship with default credentials
+	findings = append(findings, defaultCredentialFindings(findings)...)
+
+	models.MapAttackTechniques(findings)
+	return findings
+}
@@ -0,0 +1,57 @@
+package scanner
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/kumaraguru1735/shadow/internal/httpclient"
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+// newHTTPClient builds the client shared by all HTTP-based modules of a
+// scan from its config
+func newHTTPClient(config models.ScanConfig) (*http.Client, error) {
+	return httpclient.New(httpOptions(config))
+}
+
+// httpOptions maps a scan config to HTTP client options
+func httpOptions(config models.ScanConfig) httpclient.Options {
+	connsPerHost := config.ConcurrencyPerHost
+	if connsPerHost <= 0 {
+		connsPerHost = DefaultConcurrencyPerHost
+	}
+
+	return httpclient.Options{
+		Proxy:           config.Proxy,
+		Headers:         config.Headers,
+		AuthHeaders:     config.AuthHeaders,
+		UserAgent:       config.UserAgent,
+		Timeout:         config.HTTPTimeout,
+		MaxRedirects:    config.MaxRedirects,
+		MaxConnsPerHost: connsPerHost,
+		Insecure:        config.Insecure,
+	}
+}
+
+// targetURL turns a scan target into a URL, defaulting to https and
+// bracketing IPv6 literals
+func targetURL(target string) string {
+	if strings.Contains(target, "://") {
+		return target
+	}
+	parsed, err := ParseTarget(target)
+	if err != nil {
+		return "https://" + target
+	}
+	return parsed.URL()
+}
+
+// getContext GETs url with client, cancelled along with ctx
+func getContext(ctx context.Context, client *http.Client, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return client.Do(req)
+}
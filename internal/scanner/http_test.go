@@ -0,0 +1,154 @@
+package scanner
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+// recordingProxy is a plain HTTP forward proxy that answers every request
+// itself and records the absolute URLs it was asked for
+type recordingProxy struct {
+	mu   sync.Mutex
+	urls []string
+}
+
+func (p *recordingProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	p.urls = append(p.urls, r.URL.String())
+	p.mu.Unlock()
+	w.Header().Set("Strict-Transport-Security", "max-age=31536000")
+	io.WriteString(w, "proxied")
+}
+
+func (p *recordingProxy) requested() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.urls...)
+}
+
+func TestNewHTTPClientUsesProxy(t *testing.T) {
+	proxy := &recordingProxy{}
+	server := httptest.NewServer(proxy)
+	defer server.Close()
+
+	client, err := newHTTPClient(models.ScanConfig{Proxy: server.URL})
+	if err != nil {
+		t.Fatalf("newHTTPClient: %v", err)
+	}
+
+	resp, err := client.Get("http://target.example.invalid/login")
+	if err != nil {
+		t.Fatalf("request through the proxy failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if string(body) != "proxied" {
+		t.Errorf("body = %q, want the proxy's answer", body)
+	}
+	if urls := proxy.requested(); len(urls) != 1 || urls[0] != "http://target.example.invalid/login" {
+		t.Errorf("proxy saw %q, want the target URL", urls)
+	}
+}
+
+func TestNewHTTPClientAllProxyFallback(t *testing.T) {
+	proxy := &recordingProxy{}
+	server := httptest.NewServer(proxy)
+	defer server.Close()
+	t.Setenv("ALL_PROXY", server.URL)
+
+	client, err := newHTTPClient(models.ScanConfig{})
+	if err != nil {
+		t.Fatalf("newHTTPClient: %v", err)
+	}
+	resp, err := client.Get("http://target.example.invalid/")
+	if err != nil {
+		t.Fatalf("request through ALL_PROXY failed: %v", err)
+	}
+	resp.Body.Close()
+	if len(proxy.requested()) != 1 {
+		t.Error("ALL_PROXY was not used")
+	}
+
+	t.Setenv("ALL_PROXY", "ftp://proxy.example.com")
+	if _, err := newHTTPClient(models.ScanConfig{}); err == nil || !strings.Contains(err.Error(), "invalid ALL_PROXY") {
+		t.Errorf("newHTTPClient error = %v, want the bad ALL_PROXY reported", err)
+	}
+}
+
+func TestHeaderSecurityModule(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Frame-Options", "DENY")
+		w.Header().Set("Content-Security-Policy", "default-src 'self'")
+	}))
+	defer server.Close()
+
+	module := &HeaderSecurityModule{client: server.Client()}
+	findings, err := module.Run(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	missing := make([]string, 0)
+	for _, finding := range findings {
+		missing = append(missing, strings.TrimSuffix(strings.TrimPrefix(finding.Title, "Missing "), " header"))
+	}
+	if got := strings.Join(missing, ","); got != "Strict-Transport-Security,X-Content-Type-Options,Referrer-Policy" {
+		t.Errorf("missing headers = %s", got)
+	}
+}
+
+func TestNewHTTPClientSendsHeaders(t *testing.T) {
+	received := make(chan http.Header, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Clone()
+	}))
+	defer server.Close()
+
+	client, err := newHTTPClient(models.ScanConfig{
+		Headers:   map[string]string{"X-Bug-Bounty": "researcher-42", "Authorization": "Bearer token"},
+		UserAgent: "shadow-test/1.0",
+	})
+	if err != nil {
+		t.Fatalf("newHTTPClient: %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	header := <-received
+	if header.Get("X-Bug-Bounty") != "researcher-42" || header.Get("Authorization") != "Bearer token" || header.Get("User-Agent") != "shadow-test/1.0" {
+		t.Errorf("server received %v", header)
+	}
+	if len(req.Header) != 0 {
+		t.Errorf("the caller's request was modified: %v", req.Header)
+	}
+}
+
+func TestHTTPOptions(t *testing.T) {
+	for _, tt := range []struct {
+		configured, want int
+	}{
+		{0, DefaultConcurrencyPerHost},
+		{3, 3},
+	} {
+		opts := httpOptions(models.ScanConfig{ConcurrencyPerHost: tt.configured, MaxRedirects: -1, Insecure: true})
+		if opts.MaxConnsPerHost != tt.want {
+			t.Errorf("configured %d: MaxConnsPerHost = %d, want %d", tt.configured, opts.MaxConnsPerHost, tt.want)
+		}
+		if opts.MaxRedirects != -1 || !opts.Insecure {
+			t.Errorf("options = %+v, want the scan's redirect and TLS settings", opts)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package scanner
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+// Importer turns a file written by another scanner into findings
+type Importer func(data []byte) ([]models.Finding, error)
+
+// importers maps tool names to parsers of that tool's native output file
+var importers = map[string]Importer{
+	"nmap":   importNmapXML,
+	"nuclei": importNucleiJSONL,
+}
+
+// metaNucleiTemplate is the ID of the nuclei template that matched
+const metaNucleiTemplate = "nuclei.template"
+
+// ImportFormats lists the tools whose output files can be imported
+func ImportFormats() []string {
+	formats := make([]string, 0, len(importers))
+	for name := range importers {
+		formats = append(formats, name)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// ImportFindings parses a file written by tool and post-processes the
+// findings the same way scanner modules are
+func ImportFindings(tool string, data []byte) ([]models.Finding, error) {
+	importer, ok := importers[strings.ToLower(tool)]
+	if !ok {
+		return nil, fmt.Errorf("unknown import format %q (supported: %s)", tool, strings.Join(ImportFormats(), ", "))
+	}
+
+	findings, err := importer(data)
+	if err != nil {
+		return nil, err
+	}
+	findings = enrichFindings(findings)
+	for i := range findings {
+		findings[i].EnsureFingerprint()
+	}
+	return findings, nil
+}
+
+// nmapRun is the part of nmap's XML output (-oX) that findings come from
+type nmapRun struct {
+	XMLName xml.Name      `xml:"nmaprun"`
+	Hosts   []nmapXMLHost `xml:"host"`
+}
+
+type nmapXMLHost struct {
+	Addresses []struct {
+		Addr string `xml:"addr,attr"`
+		Type string `xml:"addrtype,attr"`
+	} `xml:"address"`
+	Hostnames []struct {
+		Name string `xml:"name,attr"`
+		Type string `xml:"type,attr"`
+	} `xml:"hostnames>hostname"`
+	Ports []struct {
+		Protocol string `xml:"protocol,attr"`
+		ID       int    `xml:"portid,attr"`
+		State    struct {
+			State string `xml:"state,attr"`
+		} `xml:"state"`
+		Service struct {
+			Name      string `xml:"name,attr"`
+			Product   string `xml:"product,attr"`
+			Version   string `xml:"version,attr"`
+			ExtraInfo string `xml:"extrainfo,attr"`
+		} `xml:"service"`
+	} `xml:"ports>port"`
+}
+
+// name returns the host name the user scanned, else any resolved name,
+// else the IP address
+func (h nmapXMLHost) name() string {
+	for _, hostname := range h.Hostnames {
+		if hostname.Type == "user" {
+			return hostname.Name
+		}
+	}
+	if len(h.Hostnames) > 0 {
+		return h.Hostnames[0].Name
+	}
+	for _, address := range h.Addresses {
+		if address.Type != "mac" {
+			return address.Addr
+		}
+	}
+	return ""
+}
+
+// importNmapXML reads nmap's XML output into open-port findings, in the
+// same shape as its normal output is parsed
+func importNmapXML(data []byte) ([]models.Finding, error) {
+	var run nmapRun
+	if err := xml.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("not nmap XML output (write it with nmap -oX): %w", err)
+	}
+
+	findings := make([]models.Finding, 0)
+	for _, host := range run.Hosts {
+		name := host.name()
+		if name == "" {
+			continue
+		}
+		for _, port := range host.Ports {
+			state := port.State.State
+			if state != "open" && state != "open|filtered" {
+				continue
+			}
+
+			service := port.Service.Name
+			if service == "" {
+				service = "unknown"
+			}
+			version := strings.TrimSpace(port.Service.Product + " " + port.Service.Version)
+			if port.Service.ExtraInfo != "" {
+				version = strings.TrimSpace(version + " (" + port.Service.ExtraInfo + ")")
+			}
+
+			// Evidence reads like the port's line in nmap's normal output
+			evidence := strings.TrimSpace(fmt.Sprintf("%d/%s %s %s %s", port.ID, port.Protocol, state, service, version))
+			findings = append(findings, openPortFinding(name, port.ID, port.Protocol, state, service, version, evidence))
+		}
+	}
+	return findings, nil
+}
+
+// nucleiResult is one line of nuclei's JSON lines output (-jsonl)
+type nucleiResult struct {
+	TemplateID string `json:"template-id"`
+	Info       struct {
+		Name           string     `json:"name"`
+		Severity       string     `json:"severity"`
+		Description    string     `json:"description"`
+		Remediation    string     `json:"remediation"`
+		Reference      stringList `json:"reference"`
+		Tags           stringList `json:"tags"`
+		Classification struct {
+			CVEID     stringList `json:"cve-id"`
+			CVSSScore float64    `json:"cvss-score"`
+		} `json:"classification"`
+	} `json:"info"`
+	Host             string    `json:"host"`
+	MatchedAt        string    `json:"matched-at"`
+	MatcherName      string    `json:"matcher-name"`
+	ExtractedResults []string  `json:"extracted-results"`
+	Timestamp        time.Time `json:"timestamp"`
+}
+
+// stringList decodes fields nuclei writes either as a list or as a single
+// comma-separated string, depending on the version and template
+type stringList []string
+
+func (l *stringList) UnmarshalJSON(data []byte) error {
+	var list []string
+	if err := json.Unmarshal(data, &list); err == nil {
+		*l = list
+		return nil
+	}
+
+	var joined string
+	if err := json.Unmarshal(data, &joined); err != nil {
+		return err
+	}
+	*l = nil
+	for _, item := range strings.Split(joined, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
+
+// importNucleiJSONL reads nuclei's JSON lines output into one finding per
+// match. A JSON array of results (-json-export) is accepted too.
+func importNucleiJSONL(data []byte) ([]models.Finding, error) {
+	var results []nucleiResult
+	if trimmed := bytes.TrimSpace(data); bytes.HasPrefix(trimmed, []byte("[")) {
+		if err := json.Unmarshal(trimmed, &results); err != nil {
+			return nil, fmt.Errorf("not nuclei JSON output: %w", err)
+		}
+	} else {
+		for i, line := range bytes.Split(data, []byte("\n")) {
+			if len(bytes.TrimSpace(line)) == 0 {
+				continue
+			}
+			var result nucleiResult
+			if err := json.Unmarshal(line, &result); err != nil {
+				return nil, fmt.Errorf("line %d: not nuclei JSON lines output (write it with nuclei -jsonl): %w", i+1, err)
+			}
+			results = append(results, result)
+		}
+	}
+
+	findings := make([]models.Finding, 0, len(results))
+	for i, result := range results {
+		if result.TemplateID == "" {
+			return nil, fmt.Errorf("result %d: not a nuclei result (no template-id)", i+1)
+		}
+		findings = append(findings, nucleiFinding(result))
+	}
+	return findings, nil
+}
+
+// nucleiFinding maps one nuclei match to a finding. Nuclei's "unknown"
+// severity is reported as info.
+func nucleiFinding(result nucleiResult) models.Finding {
+	severity := strings.ToLower(result.Info.Severity)
+	if models.SeverityRank(severity) == 0 {
+		severity = "info"
+	}
+
+	title := result.Info.Name
+	if title == "" {
+		title = result.TemplateID
+	}
+
+	location := result.MatchedAt
+	if location == "" {
+		location = result.Host
+	}
+
+	description := strings.TrimSpace(result.Info.Description)
+	if description == "" {
+		description = fmt.Sprintf("Nuclei template %s matched %s", result.TemplateID, location)
+	}
+	if remediation := strings.TrimSpace(result.Info.Remediation); remediation != "" {
+		description += " Remediation: " + remediation
+	}
+	if len(result.Info.Reference) > 0 {
+		description += " References: " + strings.Join(result.Info.Reference, ", ")
+	}
+
+	evidence := "Matched at " + location
+	if result.MatcherName != "" {
+		evidence += fmt.Sprintf(" (matcher %s)", result.MatcherName)
+	}
+	if len(result.ExtractedResults) > 0 {
+		evidence += "\nExtracted: " + strings.Join(result.ExtractedResults, ", ")
+	}
+
+	timestamp := result.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	finding := models.Finding{
+		ID:          uuid.New().String(),
+		Type:        "nuclei",
+		Severity:    severity,
+		Title:       title,
+		Description: description,
+		Evidence:    evidence,
+		Location:    location,
+		CVSS:        result.Info.Classification.CVSSScore,
+		Tags:        append([]string{"nuclei"}, result.Info.Tags...),
+		Timestamp:   timestamp,
+	}
+	if len(result.Info.Classification.CVEID) > 0 {
+		finding.CVE = strings.ToUpper(result.Info.Classification.CVEID[0])
+	}
+	if target, err := ParseTarget(location); err == nil {
+		finding.SetMeta(models.MetaHost, target.Host)
+		if target.Port != 0 {
+			finding.SetMeta(models.MetaPort, strconv.Itoa(target.Port))
+		}
+	}
+	finding.SetMeta(metaNucleiTemplate, result.TemplateID)
+	return finding
+}
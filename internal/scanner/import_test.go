@@ -0,0 +1,124 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+func importFixture(t *testing.T, tool string, name string) []models.Finding {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings, err := ImportFindings(tool, data)
+	if err != nil {
+		t.Fatalf("ImportFindings(%s): %v", tool, err)
+	}
+	return findings
+}
+
+// byType groups findings by type, keyed by location
+func byType(t *testing.T, findings []models.Finding) map[string]map[string]models.Finding {
+	t.Helper()
+	grouped := make(map[string]map[string]models.Finding)
+	for _, finding := range findings {
+		if grouped[finding.Type] == nil {
+			grouped[finding.Type] = make(map[string]models.Finding)
+		}
+		grouped[finding.Type][finding.Location] = finding
+		if finding.Fingerprint == "" {
+			t.Errorf("imported %q without a fingerprint", finding.Title)
+		}
+	}
+	return grouped
+}
+
+func TestImportNmapXML(t *testing.T) {
+	grouped := byType(t, importFixture(t, "nmap", "nmap.xml"))
+
+	ports := grouped["open-port"]
+	if len(ports) != 4 {
+		t.Fatalf("imported %d open ports, want 4 (closed ports skipped): %v", len(ports), ports)
+	}
+	mysql, ok := ports["db.example.com:3306"]
+	if !ok {
+		t.Fatalf("no finding for db.example.com:3306 (the user's host name): %v", ports)
+	}
+	if mysql.Metadata[models.MetaService] != "mysql" || mysql.Metadata[models.MetaVersion] != "MySQL 8.0.36" {
+		t.Errorf("mysql metadata = %v", mysql.Metadata)
+	}
+	if ssh := ports["db.example.com:22"]; ssh.Metadata[models.MetaVersion] != "OpenSSH 9.6p1 (Ubuntu Linux; protocol 2.0)" {
+		t.Errorf("ssh version = %q", ssh.Metadata[models.MetaVersion])
+	}
+	if _, ok := ports["192.0.2.11:5432"]; !ok {
+		t.Errorf("host without a name not imported by address: %v", ports)
+	}
+
+	// The MySQL banner is evidence; the bare postgresql service name isn't
+	credentials := grouped["default-credentials"]
+	if len(credentials) != 1 || !strings.Contains(credentials["db.example.com:3306"].Title, "MySQL") {
+		t.Errorf("default credential findings = %v, want only MySQL", credentials)
+	}
+}
+
+func TestImportNucleiJSONL(t *testing.T) {
+	grouped := byType(t, importFixture(t, "nuclei", "nuclei.jsonl"))
+
+	matches := grouped["nuclei"]
+	if len(matches) != 3 {
+		t.Fatalf("imported %d nuclei matches, want 3", len(matches))
+	}
+	tomcat := matches["https://app.example.com:8443/manager/html"]
+	if tomcat.Severity != "high" || strings.Join(tomcat.Tags, ",") != "nuclei,tomcat,default-login,panel" {
+		t.Errorf("tomcat = %s %v, want comma-separated tags split", tomcat.Severity, tomcat.Tags)
+	}
+	if tomcat.Metadata[models.MetaPort] != "8443" || tomcat.Metadata[metaNucleiTemplate] != "tomcat-default-login" {
+		t.Errorf("tomcat metadata = %v", tomcat.Metadata)
+	}
+	if !strings.Contains(tomcat.Description, "Remediation: Change the manager password.") {
+		t.Errorf("tomcat description = %q", tomcat.Description)
+	}
+
+	log4shell := matches["https://app.example.com/api"]
+	if log4shell.CVE != "CVE-2021-44228" || log4shell.CVSS != 10 || !strings.Contains(log4shell.Evidence, "jndi callback") {
+		t.Errorf("log4shell = %+v", log4shell)
+	}
+	if tech := matches["https://app.example.com"]; tech.Severity != "info" || tech.Title != "tech-detect" {
+		t.Errorf("unknown severity match = %s %q, want info titled by its template", tech.Severity, tech.Title)
+	}
+
+	if credentials := grouped["default-credentials"]; len(credentials) != 1 {
+		t.Errorf("default credential findings = %v, want the Tomcat template", credentials)
+	}
+}
+
+func TestImportNucleiJSONArray(t *testing.T) {
+	findings, err := ImportFindings("Nuclei", []byte(`[{"template-id":"x","info":{"severity":"low"},"host":"example.com"}]`))
+	if err != nil || len(findings) != 1 {
+		t.Errorf("ImportFindings(array) = %d findings, %v", len(findings), err)
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	tests := []struct {
+		tool string
+		data string
+		want string
+	}{
+		{"burp", "", "unknown import format"},
+		{"nmap", "{}", "not nmap XML output"},
+		{"nuclei", "{\"template-id\":\"a\"}\nnot json\n", "line 2"},
+		{"nuclei", `{"host":"example.com"}`, "no template-id"},
+	}
+	for _, tt := range tests {
+		_, err := ImportFindings(tt.tool, []byte(tt.data))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s %q: error %v, want %q", tt.tool, tt.data, err, tt.want)
+		}
+	}
+}
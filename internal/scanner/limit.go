@@ -0,0 +1,45 @@
+package scanner
+
+import (
+	"strings"
+	"sync"
+)
+
+// DefaultConcurrencyPerHost is deliberately low so fragile targets aren't
+// knocked over and rate-based blocking isn't triggered
+const DefaultConcurrencyPerHost = 5
+
+// hostLimiter caps simultaneous connections to any one host, independent of
+// the total thread count
+type hostLimiter struct {
+	mu    sync.Mutex
+	limit int
+	slots map[string]chan struct{}
+}
+
+func newHostLimiter(limit int) *hostLimiter {
+	if limit <= 0 {
+		limit = DefaultConcurrencyPerHost
+	}
+	return &hostLimiter{
+		limit: limit,
+		slots: make(map[string]chan struct{}),
+	}
+}
+
+// acquire blocks until a connection slot for host is free and returns the
+// function that releases it
+func (l *hostLimiter) acquire(host string) func() {
+	host = strings.ToLower(host)
+
+	l.mu.Lock()
+	slots, ok := l.slots[host]
+	if !ok {
+		slots = make(chan struct{}, l.limit)
+		l.slots[host] = slots
+	}
+	l.mu.Unlock()
+
+	slots <- struct{}{}
+	return func() { <-slots }
+}
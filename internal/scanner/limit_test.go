@@ -0,0 +1,61 @@
+package scanner
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHostLimiterCapsPerHost(t *testing.T) {
+	limiter := newHostLimiter(2)
+
+	var active, peak int32
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release := limiter.acquire("Example.com")
+			defer release()
+
+			n := atomic.AddInt32(&active, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&active, -1)
+		}()
+	}
+	wg.Wait()
+
+	if peak != 2 {
+		t.Errorf("peak concurrent connections = %d, want 2", peak)
+	}
+}
+
+func TestHostLimiterSeparatesHosts(t *testing.T) {
+	limiter := newHostLimiter(1)
+	releaseA := limiter.acquire("a.example.com")
+	defer releaseA()
+
+	done := make(chan struct{})
+	go func() {
+		limiter.acquire("b.example.com")()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("a busy host blocked a different host")
+	}
+}
+
+func TestNewHostLimiterDefault(t *testing.T) {
+	if got := newHostLimiter(0).limit; got != DefaultConcurrencyPerHost {
+		t.Errorf("limit = %d, want %d", got, DefaultConcurrencyPerHost)
+	}
+}
@@ -2,10 +2,14 @@ package scanner
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"strings"
+	"time"
+
+	"github.com/kumaraguru1735/shadow/internal/output"
 )
 
 // PermissionManager handles permission requests and sudo access
@@ -13,13 +17,30 @@ type PermissionManager struct {
 	sudoAvailable bool
 	sudoTested    bool
 	userApproved  map[string]bool // Track which commands user approved
+	auditPath     string          // every request and privileged run is logged here
+	approvalsPath string          // "always" approvals persist here
+	allowedTools  map[string]bool // only these tools may be run, see SetAllowedTools
+	noAlways      bool            // "always" is not offered; every command is confirmed
+	toolTimeout   time.Duration   // external tools are stopped after this long, 0 for no limit
+	progress      ToolProgress    // receives tool output live, see SetProgress
+	runner        CommandRunner
 }
 
-// NewPermissionManager creates a new permission manager
+// NewPermissionManager creates a new permission manager that audits to
+// ~/.shadow/audit.log and honors "always" approvals remembered in
+// ~/.shadow/approvals.json
 func NewPermissionManager() *PermissionManager {
-	return &PermissionManager{
-		userApproved: make(map[string]bool),
+	auditPath, _ := DefaultAuditFile()
+	approvalsPath, _ := DefaultApprovalsFile()
+	pm := &PermissionManager{
+		userApproved:  make(map[string]bool),
+		auditPath:     auditPath,
+		approvalsPath: approvalsPath,
+		runner:        execRunner{},
 	}
+	pm.SetAllowedTools(nil)
+	pm.loadApprovals()
+	return pm
 }
 
 // CheckSudoAvailable tests if sudo is available
@@ -49,34 +70,49 @@ func (pm *PermissionManager) CheckSudoAvailable() bool {
 func (pm *PermissionManager) RequestRootPermission(tool string, purpose string, command string) (bool, error) {
 	// Check if already approved
 	cacheKey := fmt.Sprintf("%s:%s", tool, command)
+	if pm.userApproved[tool+":*"] && !pm.noAlways {
+		pm.audit("request", tool, AuditCached+"-"+AuditApprovedAlways, command)
+		return true, nil
+	}
 	if approved, exists := pm.userApproved[cacheKey]; exists {
+		decision := AuditDenied
+		if approved {
+			decision = AuditApproved
+		}
+		pm.audit("request", tool, AuditCached+"-"+decision, command)
 		return approved, nil
 	}
 
-	fmt.Println("\n🔐 Root Permission Request")
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Printf("\n📋 Tool: %s\n", tool)
-	fmt.Printf("🎯 Purpose: %s\n", purpose)
-	fmt.Printf("💻 Command: %s\n", command)
+	output.Println("\n🔐 Root Permission Request")
+	output.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	output.Printf("\n📋 Tool: %s\n", tool)
+	output.Printf("🎯 Purpose: %s\n", purpose)
+	output.Printf("💻 Command: %s\n", command)
 
 	if !pm.CheckSudoAvailable() {
-		fmt.Println("\n⚠️  sudo is not available or not configured")
-		fmt.Println("💡 Options:")
-		fmt.Println("   1. Configure sudo access")
-		fmt.Println("   2. Run Shadow as root (not recommended)")
-		fmt.Println("   3. Skip this scan and use alternatives")
+		output.Println("\n⚠️  sudo is not available or not configured")
+		output.Println("💡 Options:")
+		output.Println("   1. Configure sudo access")
+		output.Println("   2. Run Shadow as root (not recommended)")
+		output.Println("   3. Skip this scan and use alternatives")
+		pm.audit("request", tool, AuditUnavailable, command)
 		return false, fmt.Errorf("sudo not available")
 	}
 
-	fmt.Println("\n⚠️  This command requires elevated privileges (root/sudo)")
-	fmt.Println("🔒 Shadow will ONLY run the specific command shown above")
-	fmt.Println("📊 This is needed for comprehensive security scanning")
+	output.Println("\n⚠️  This command requires elevated privileges (root/sudo)")
+	output.Println("🔒 Shadow will ONLY run the specific command shown above")
+	output.Println("📊 This is needed for comprehensive security scanning")
 
-	fmt.Print("\nAllow this command? (yes/no/always): ")
+	if pm.noAlways {
+		output.Print("\nAllow this command? (yes/no): ")
+	} else {
+		output.Print("\nAllow this command? (yes/no/always, always is remembered until --forget-approvals): ")
+	}
 
 	reader := bufio.NewReader(os.Stdin)
 	response, err := reader.ReadString('\n')
 	if err != nil {
+		pm.audit("request", tool, AuditDenied, command)
 		return false, err
 	}
 
@@ -85,26 +121,50 @@ func (pm *PermissionManager) RequestRootPermission(tool string, purpose string,
 	switch response {
 	case "yes", "y":
 		pm.userApproved[cacheKey] = true
+		pm.audit("request", tool, AuditApproved, command)
 		return true, nil
 	case "always", "a":
-		// Approve all future requests for this tool
+		if pm.noAlways {
+			// Only this command; the next one is asked again
+			pm.userApproved[cacheKey] = true
+			pm.audit("request", tool, AuditApproved, command)
+			return true, nil
+		}
+
+		// Approve all future requests for this tool, in later runs too
 		pm.userApproved[tool+":*"] = true
 		pm.userApproved[cacheKey] = true
+		pm.rememberApproval(tool)
+		pm.audit("request", tool, AuditApprovedAlways, command)
 		return true, nil
 	case "no", "n":
 		pm.userApproved[cacheKey] = false
+		pm.audit("request", tool, AuditDenied, command)
 		return false, nil
 	default:
-		fmt.Println("⚠️  Invalid response, treating as 'no'")
+		output.Println("⚠️  Invalid response, treating as 'no'")
 		pm.userApproved[cacheKey] = false
+		pm.audit("request", tool, AuditDenied, command)
 		return false, nil
 	}
 }
 
+// DisableAlwaysApproval stops offering "always": an "always" answer only
+// approves the current command, and remembered approvals are ignored, so
+// every privileged command is individually confirmed and audited
+func (pm *PermissionManager) DisableAlwaysApproval() {
+	pm.noAlways = true
+}
+
 // RunWithSudo executes a command with sudo after getting permission
 func (pm *PermissionManager) RunWithSudo(tool string, purpose string, args ...string) ([]byte, error) {
 	command := fmt.Sprintf("sudo %s %s", tool, strings.Join(args, " "))
 
+	if err := pm.ValidateCommand(tool, args); err != nil {
+		pm.audit("request", tool, AuditRefused, command)
+		return nil, err
+	}
+
 	// Request permission
 	approved, err := pm.RequestRootPermission(tool, purpose, command)
 	if err != nil {
@@ -116,17 +176,22 @@ func (pm *PermissionManager) RunWithSudo(tool string, purpose string, args ...st
 	}
 
 	// Execute with sudo
-	fmt.Printf("\n🔧 Executing: %s\n", command)
+	output.Printf("\n🔧 Executing: %s\n", command)
 
 	cmdArgs := append([]string{tool}, args...)
-	cmd := exec.Command("sudo", cmdArgs...)
-	output, err := cmd.CombinedOutput()
+	out, err := pm.runTimed("sudo", cmdArgs...)
 
+	if errors.Is(err, ErrToolTimeout) {
+		pm.audit("exec", tool, AuditTimedOut, command)
+		return out, err
+	}
 	if err != nil {
-		return output, fmt.Errorf("command failed: %w\nOutput: %s", err, string(output))
+		pm.audit("exec", tool, AuditFailed, command)
+		return out, fmt.Errorf("command failed: %w\nOutput: %s", err, string(out))
 	}
+	pm.audit("exec", tool, AuditExecuted, command)
 
-	return output, nil
+	return out, nil
 }
 
 // RunWithFallback tries to run with sudo, falls back to non-root version
@@ -136,95 +201,92 @@ func (pm *PermissionManager) RunWithFallback(
 	rootArgs []string,
 	fallbackArgs []string,
 ) ([]byte, bool, error) {
+	if err := pm.ValidateCommand(tool, append(append([]string{}, rootArgs...), fallbackArgs...)); err != nil {
+		pm.audit("request", tool, AuditRefused, fmt.Sprintf("%s %s", tool, strings.Join(rootArgs, " ")))
+		return nil, false, err
+	}
+
 	// Try with sudo first
 	if pm.CheckSudoAvailable() {
 		command := fmt.Sprintf("sudo %s %s", tool, strings.Join(rootArgs, " "))
 		approved, err := pm.RequestRootPermission(tool, purpose, command)
 
 		if err == nil && approved {
-			fmt.Printf("\n🔧 Executing privileged scan: %s\n", command)
+			output.Printf("\n🔧 Executing privileged scan: %s\n", command)
 			cmdArgs := append([]string{tool}, rootArgs...)
-			cmd := exec.Command("sudo", cmdArgs...)
-			output, err := cmd.CombinedOutput()
+			out, err := pm.runTimed("sudo", cmdArgs...)
 
 			if err == nil {
-				fmt.Println("✓ Privileged scan completed")
-				return output, true, nil
+				pm.audit("exec", tool, AuditExecuted, command)
+				output.Println("✓ Privileged scan completed")
+				return out, true, nil
+			}
+
+			// A rerun without root would most likely hang the same way
+			if errors.Is(err, ErrToolTimeout) {
+				pm.audit("exec", tool, AuditTimedOut, command)
+				return out, true, err
 			}
 
-			fmt.Printf("⚠️  Privileged scan failed: %v\n", err)
-			fmt.Println("💡 Falling back to non-privileged scan...")
+			pm.audit("exec", tool, AuditFailed, command)
+			output.Printf("⚠️  Privileged scan failed: %v\n", err)
+			output.Println("💡 Falling back to non-privileged scan...")
 		}
 	}
 
 	// Fallback to non-root version
-	fmt.Printf("🔧 Running non-privileged scan: %s %s\n", tool, strings.Join(fallbackArgs, " "))
+	output.Printf("🔧 Running non-privileged scan: %s %s\n", tool, strings.Join(fallbackArgs, " "))
 
-	cmd := exec.Command(tool, fallbackArgs...)
-	output, err := cmd.CombinedOutput()
+	out, err := pm.runTimed(tool, fallbackArgs...)
 
 	if err != nil {
-		return output, false, fmt.Errorf("fallback scan failed: %w", err)
+		return out, false, fmt.Errorf("fallback scan failed: %w", err)
 	}
 
-	fmt.Println("✓ Non-privileged scan completed")
-	return output, false, nil
+	output.Println("✓ Non-privileged scan completed")
+	return out, false, nil
 }
 
 // ShowCapabilityInfo displays information about setcap as an alternative to sudo
 func (pm *PermissionManager) ShowCapabilityInfo(tool string) {
-	fmt.Println("\n💡 Alternative: Use Linux Capabilities Instead of sudo")
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-
-	switch tool {
-	case "nmap":
-		fmt.Println("\n📝 To allow nmap without sudo:")
-		fmt.Println("   sudo setcap cap_net_raw,cap_net_admin,cap_net_bind_service+eip /usr/bin/nmap")
-		fmt.Println("\n✅ Benefits:")
-		fmt.Println("   • More secure than sudo")
-		fmt.Println("   • No password prompts")
-		fmt.Println("   • Granular permissions")
-		fmt.Println("\n⚠️  Note: You'll need sudo once to set capabilities")
+	output.Println("\n💡 Alternative: Use Linux Capabilities Instead of sudo")
+	output.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 
-	default:
-		fmt.Println("\n📝 Check if %s supports Linux capabilities", tool)
-		fmt.Println("   man capabilities")
+	if capabilityTools[tool] {
+		path, err := resolveToolPath(tool)
+		if err != nil {
+			path = "/usr/bin/" + tool
+		}
+		output.Printf("\n📝 To allow %s without sudo:\n", tool)
+		output.Printf("   shadow setup-caps %s\n", tool)
+		output.Printf("   (runs: sudo setcap %s %s)\n", NetworkCapabilities, path)
+		output.Println("\n✅ Benefits:")
+		output.Println("   • More secure than sudo")
+		output.Println("   • No password prompts")
+		output.Println("   • Granular permissions")
+		output.Println("\n⚠️  Note: You'll need sudo once to set capabilities")
+	} else {
+		output.Printf("\n📝 Check if %s supports Linux capabilities\n", tool)
+		output.Println("   man capabilities")
 	}
 
-	fmt.Println()
+	output.Println()
 }
 
 // SuggestSudoersEntry suggests a sudoers configuration for the tool
 func (pm *PermissionManager) SuggestSudoersEntry(tool string) {
-	fmt.Println("\n💡 Persistent sudo Configuration")
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Println("\n📝 To avoid repeated password prompts:")
-
-	username := os.Getenv("USER")
-	if username == "" {
-		username = "yourusername"
-	}
-
-	switch tool {
-	case "nmap":
-		fmt.Printf("\n   echo '%s ALL=(ALL) NOPASSWD: /usr/bin/nmap' | sudo tee /etc/sudoers.d/shadow-nmap\n", username)
-		fmt.Println("   sudo chmod 440 /etc/sudoers.d/shadow-nmap")
-
-	default:
-		toolPath, _ := exec.LookPath(tool)
-		if toolPath == "" {
-			toolPath = "/usr/bin/" + tool
-		}
-		fmt.Printf("\n   echo '%s ALL=(ALL) NOPASSWD: %s' | sudo tee /etc/sudoers.d/shadow-%s\n",
-			username, toolPath, tool)
-		fmt.Printf("   sudo chmod 440 /etc/sudoers.d/shadow-%s\n", tool)
-	}
-
-	fmt.Println("\n⚠️  Security Note:")
-	fmt.Println("   • Only allow specific tools, not ALL commands")
-	fmt.Println("   • Limit to absolute paths")
-	fmt.Println("   • Review sudoers entries regularly")
-	fmt.Println()
+	output.Println("\n💡 Persistent sudo Configuration")
+	output.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	output.Println("\n📝 To avoid repeated password prompts:")
+
+	output.Printf("\n   shadow install-sudoers %s\n", tool)
+	output.Printf("   (validates with visudo, then writes %s with mode 0440)\n", SudoersFile(tool))
+
+	output.Println("\n⚠️  Security Note:")
+	output.Println("   • Only allow specific tools, not ALL commands")
+	output.Println("   • Limit to absolute paths")
+	output.Println("   • Review sudoers entries regularly")
+	output.Println()
 }
 
 // GetApprovalSummary returns summary of approved commands
@@ -233,8 +295,8 @@ func (pm *PermissionManager) GetApprovalSummary() {
 		return
 	}
 
-	fmt.Println("\n📊 Permission Summary")
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	output.Println("\n📊 Permission Summary")
+	output.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 
 	approvedCount := 0
 	deniedCount := 0
@@ -247,7 +309,7 @@ func (pm *PermissionManager) GetApprovalSummary() {
 		}
 	}
 
-	fmt.Printf("✅ Approved: %d commands\n", approvedCount)
-	fmt.Printf("❌ Denied: %d commands\n", deniedCount)
-	fmt.Println()
+	output.Printf("✅ Approved: %d commands\n", approvedCount)
+	output.Printf("❌ Denied: %d commands\n", deniedCount)
+	output.Println()
 }
@@ -0,0 +1,126 @@
+package scanner
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+// HTTPProtocolModule reports which HTTP versions the target speaks: the
+// version of its response, negotiated over TLS ALPN by the shared client,
+// and HTTP/3 when the response advertises it in Alt-Svc. QUIC itself is
+// not dialled, so HTTP/3 is only as reliable as the advertisement.
+type HTTPProtocolModule struct {
+	client *http.Client
+}
+
+func (m *HTTPProtocolModule) Name() string {
+	return "HTTP Protocols"
+}
+
+func (m *HTTPProtocolModule) Run(ctx context.Context, target string) ([]models.Finding, error) {
+	findings := make([]models.Finding, 0)
+
+	resp, err := getContext(ctx, m.client, targetURL(target))
+	if err != nil {
+		var certErr *tls.CertificateVerificationError
+		if errors.As(err, &certErr) {
+			return nil, fmt.Errorf("request failed: %w (scan with --insecure to check targets with untrusted certificates)", err)
+		}
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	resp.Body.Close()
+
+	// Redirects may lead to another scheme or host, so report where the
+	// protocol was actually negotiated
+	location := resp.Request.URL.String()
+	alpn := ""
+	if resp.TLS != nil {
+		alpn = resp.TLS.NegotiatedProtocol
+	}
+
+	newFinding := func(severity string, confidence models.Confidence, title string, description string, evidence string) models.Finding {
+		finding := models.Finding{
+			ID:          uuid.New().String(),
+			Type:        "http-protocol",
+			Severity:    severity,
+			Confidence:  confidence,
+			Title:       title,
+			Description: description,
+			Evidence:    evidence,
+			Location:    location,
+			Tags:        []string{"http", "protocols"},
+			Timestamp:   time.Now(),
+		}
+		finding.SetMeta(models.MetaHost, resp.Request.URL.Hostname())
+		finding.SetMeta(models.MetaHTTPStatus, strconv.Itoa(resp.StatusCode))
+		finding.SetMeta(models.MetaHTTPProto, resp.Proto)
+		finding.SetMeta(models.MetaALPN, alpn)
+		if resp.TLS != nil {
+			finding.SetMeta(models.MetaTLSVersion, tls.VersionName(resp.TLS.Version))
+		}
+		return finding
+	}
+
+	evidence := fmt.Sprintf("HTTP %d from %s over %s", resp.StatusCode, location, resp.Proto)
+	switch {
+	case resp.TLS == nil:
+		evidence += " (cleartext, no ALPN)"
+	case alpn == "":
+		evidence += " (the server negotiated no protocol via ALPN)"
+	default:
+		evidence += fmt.Sprintf(" (ALPN negotiated %q)", alpn)
+	}
+
+	if resp.ProtoMajor == 2 {
+		findings = append(findings, newFinding("info", models.ConfidenceHigh, "HTTP/2 supported",
+			"The server negotiated HTTP/2 (h2) over TLS ALPN.", evidence))
+	} else {
+		findings = append(findings, newFinding("info", models.ConfidenceHigh, resp.Proto+" supported",
+			fmt.Sprintf("The server answered over %s.", resp.Proto), evidence))
+	}
+
+	altSvc := resp.Header.Get("Alt-Svc")
+	http3 := altSvcHTTP3(altSvc)
+	if len(http3) > 0 {
+		findings = append(findings, newFinding("info", models.ConfidenceMedium,
+			fmt.Sprintf("HTTP/3 advertised (%s)", strings.Join(http3, ", ")),
+			"The response advertises HTTP/3 over QUIC in its Alt-Svc header. QUIC was not dialled to confirm it.",
+			"Alt-Svc: "+altSvc))
+	}
+
+	// HTTP/2 needs TLS in browsers, so only HTTPS servers can be expected
+	// to offer something newer
+	if resp.TLS != nil && resp.ProtoMajor < 2 && len(http3) == 0 {
+		findings = append(findings, newFinding("low", models.ConfidenceMedium, "Only HTTP/1.x supported",
+			fmt.Sprintf("The server negotiated neither HTTP/2 nor advertised HTTP/3, so clients fall back to %s. "+
+				"HTTP/1.x has no multiplexing or header compression, and its text framing is what request "+
+				"smuggling exploits when a proxy and backend disagree on message length. Enable HTTP/2 on the "+
+				"server or the CDN/load balancer in front of it.", resp.Proto),
+			evidence))
+	}
+
+	return findings, nil
+}
+
+// altSvcHTTP3 returns the HTTP/3 protocol IDs (h3, h3-29, ...) an Alt-Svc
+// header advertises
+func altSvcHTTP3(header string) []string {
+	protocols := make([]string, 0)
+	for _, entry := range strings.Split(header, ",") {
+		id, _, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if ok && strings.HasPrefix(id, "h3") {
+			protocols = append(protocols, id)
+		}
+	}
+	return protocols
+}
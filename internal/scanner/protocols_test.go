@@ -0,0 +1,87 @@
+package scanner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+func TestHTTPProtocolModule(t *testing.T) {
+	tests := []struct {
+		name   string
+		tls    bool
+		http2  bool
+		altSvc string
+		want   []string // finding titles
+		alpn   string
+	}{
+		{"http2", true, true, "", []string{"HTTP/2 supported"}, "h2"},
+		{"http1 over tls", true, false, "", []string{"HTTP/1.1 supported", "Only HTTP/1.x supported"}, "http/1.1"},
+		{"http3 advertised", true, false, `h3=":443"; ma=86400, h3-29=":443", h2=":443"`,
+			[]string{"HTTP/1.1 supported", "HTTP/3 advertised (h3, h3-29)"}, "http/1.1"},
+		{"cleartext", false, false, "", []string{"HTTP/1.1 supported"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.altSvc != "" {
+					w.Header().Set("Alt-Svc", tt.altSvc)
+				}
+			}))
+			server.EnableHTTP2 = tt.http2
+			if tt.tls {
+				server.StartTLS()
+			} else {
+				server.Start()
+			}
+			defer server.Close()
+
+			// The scan client, so h2 must be offered despite --insecure
+			client, err := newHTTPClient(models.ScanConfig{Insecure: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			findings, err := (&HTTPProtocolModule{client: client}).Run(context.Background(), server.URL)
+			if err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+
+			titles := make([]string, 0, len(findings))
+			for _, finding := range findings {
+				titles = append(titles, finding.Title)
+			}
+			if strings.Join(titles, "; ") != strings.Join(tt.want, "; ") {
+				t.Errorf("findings = %q, want %q", titles, tt.want)
+			}
+			if alpn := findings[0].Metadata[models.MetaALPN]; alpn != tt.alpn {
+				t.Errorf("alpn = %q, want %q", alpn, tt.alpn)
+			}
+			if findings[0].Metadata[models.MetaHTTPProto] == "" {
+				t.Errorf("no http.proto in %v", findings[0].Metadata)
+			}
+		})
+	}
+}
+
+func TestAltSvcHTTP3(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", ""},
+		{"clear", ""},
+		{`h2=":443"`, ""},
+		{`h3=":443"; ma=86400`, "h3"},
+		{`h3-29=":443", h3=":8443", h2=":443"`, "h3-29,h3"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(altSvcHTTP3(tt.header), ","); got != tt.want {
+			t.Errorf("altSvcHTTP3(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
@@ -1,10 +1,19 @@
 package scanner
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/kumaraguru1735/shadow/internal/output"
 	"github.com/kumaraguru1735/shadow/pkg/models"
 )
 
@@ -12,12 +21,15 @@ import (
 type Scanner struct {
 	config  models.ScanConfig
 	modules []Module
+	client  *http.Client // shared by HTTP-based modules
+	hosts   *hostLimiter // per-host connection cap shared by network modules
 }
 
 // Module represents a scanning module interface
 type Module interface {
 	Name() string
-	Run(target string) ([]models.Finding, error)
+	// Run probes target. Probes stop when ctx is cancelled, e.g. by Ctrl-C.
+	Run(ctx context.Context, target string) ([]models.Finding, error)
 }
 
 // New creates a new Scanner instance
@@ -30,6 +42,13 @@ func New(config models.ScanConfig) *Scanner {
 
 // Run executes the security scan
 func (s *Scanner) Run() (*models.ScanResult, error) {
+	return s.RunContext(context.Background())
+}
+
+// RunContext executes the scan until ctx is cancelled. On cancellation the
+// findings gathered so far are returned with status "interrupted" together
+// with ctx.Err().
+func (s *Scanner) RunContext(ctx context.Context) (*models.ScanResult, error) {
 	startTime := time.Now()
 
 	result := &models.ScanResult{
@@ -47,25 +66,55 @@ func (s *Scanner) Run() (*models.ScanResult, error) {
 		},
 	}
 
-	fmt.Println("🔍 Starting reconnaissance...")
+	client, err := newHTTPClient(s.config)
+	if err != nil {
+		return nil, err
+	}
+	s.client = client
+	s.hosts = newHostLimiter(s.config.ConcurrencyPerHost)
+	secrets := authSecrets(s.config.AuthHeaders, s.config.Headers)
+
+	output.Println("🔍 Starting reconnaissance...")
 
 	// Load modules based on profile
 	s.loadModules()
 
 	// Execute modules
 	for _, module := range s.modules {
-		fmt.Printf("  ▶ Running %s module...\n", module.Name())
+		if ctx.Err() != nil {
+			return s.interrupted(result), ctx.Err()
+		}
 
-		findings, err := module.Run(s.config.Target)
+		output.Printf("  ▶ Running %s module...\n", module.Name())
+
+		findings, err := runModule(ctx, module, s.config.Target)
+		if ctx.Err() != nil {
+			output.Printf("    ⏹  %s module interrupted\n", module.Name())
+			return s.interrupted(result), ctx.Err()
+		}
 		if err != nil {
-			fmt.Printf("    ⚠️  %s module error: %v\n", module.Name(), err)
+			output.Printf("    ⚠️  %s module error: %v\n", module.Name(), err)
 			continue
 		}
 
+		scrubSecrets(findings, secrets)
+		findings = enrichFindings(findings)
+		models.ApplySeverityOverrides(findings, s.config.SeverityOverrides)
+		for i := range findings {
+			findings[i].EnsureFingerprint()
+			findings[i].SetMeta(models.MetaModule, module.Name())
+		}
+		s.truncateEvidence(result, findings)
+
 		result.Findings = append(result.Findings, findings...)
-		fmt.Printf("    ✓ Found %d findings\n", len(findings))
+		output.Printf("    ✓ Found %d findings\n", len(findings))
+		// Early signal on long scans; duplicates don't count twice
+		output.Printf("    📈 Running risk score: %d/100\n", models.ComputeRiskScore(models.DedupFindings(result.Findings)))
 	}
 
+	// Drop findings reported more than once (e.g. by overlapping modules)
+	result.Findings = models.DedupFindings(result.Findings)
+
 	// Finalize results
 	result.EndTime = time.Now()
 	result.Duration = result.EndTime.Sub(result.StartTime)
@@ -75,6 +124,66 @@ func (s *Scanner) Run() (*models.ScanResult, error) {
 	return result, nil
 }
 
+// runModule runs a module but stops waiting for it once ctx is cancelled,
+// even if a probe doesn't notice the cancellation right away
+func runModule(ctx context.Context, module Module, target string) ([]models.Finding, error) {
+	type outcome struct {
+		findings []models.Finding
+		err      error
+	}
+
+	done := make(chan outcome, 1)
+	go func() {
+		findings, err := module.Run(ctx, target)
+		done <- outcome{findings, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case out := <-done:
+		return out.findings, out.err
+	}
+}
+
+// interrupted finalizes a partial result after cancellation
+func (s *Scanner) interrupted(result *models.ScanResult) *models.ScanResult {
+	result.Findings = models.DedupFindings(result.Findings)
+	result.EndTime = time.Now()
+	result.Duration = result.EndTime.Sub(result.StartTime)
+	result.Status = "interrupted"
+	result.Metadata.EndTime = result.EndTime
+	return result
+}
+
+// truncateEvidence caps oversized evidence, keeping the full text on the
+// result so the store can save it separately
+func (s *Scanner) truncateEvidence(result *models.ScanResult, findings []models.Finding) {
+	max := s.config.MaxEvidenceLength
+	if max <= 0 {
+		max = models.DefaultMaxEvidenceLength
+	}
+
+	for i := range findings {
+		finding := &findings[i]
+		truncated, ok := models.TruncateEvidence(finding.Evidence, max)
+		if !ok {
+			continue
+		}
+
+		if result.FullEvidence == nil {
+			result.FullEvidence = make(map[string]string)
+		}
+		result.FullEvidence[finding.Fingerprint] = finding.Evidence
+
+		if finding.Metadata == nil {
+			finding.Metadata = make(map[string]string)
+		}
+		finding.Metadata[models.MetaEvidenceTruncated] = strconv.Itoa(len(finding.Evidence))
+		finding.Evidence = truncated
+	}
+}
+
 // loadModules loads scanning modules based on profile
 func (s *Scanner) loadModules() {
 	switch s.config.Profile {
@@ -85,15 +194,19 @@ func (s *Scanner) loadModules() {
 		// Standard scan - common vulnerabilities
 		s.modules = append(s.modules,
 			&BasicSecurityModule{},
-			&HeaderSecurityModule{},
+			&HeaderSecurityModule{client: s.client},
+			&HTTPProtocolModule{client: s.client},
+			&PageContentModule{client: s.client},
 		)
-	case "deep":
-		// Deep scan - comprehensive analysis
+	case "deep", "research":
+		// Deep scan - comprehensive analysis (research adds AI stages on top)
 		s.modules = append(s.modules,
 			&BasicSecurityModule{},
-			&HeaderSecurityModule{},
-			&SubdomainModule{},
-			&PortScanModule{},
+			&HeaderSecurityModule{client: s.client},
+			&HTTPProtocolModule{client: s.client},
+			&PageContentModule{client: s.client},
+			&SubdomainModule{threads: s.config.Threads, wordlist: s.config.SubdomainWordlist},
+			&PortScanModule{threads: s.config.Threads, hosts: s.hosts},
 		)
 	}
 
@@ -108,7 +221,7 @@ func (m *BasicSecurityModule) Name() string {
 	return "Basic Security"
 }
 
-func (m *BasicSecurityModule) Run(target string) ([]models.Finding, error) {
+func (m *BasicSecurityModule) Run(ctx context.Context, target string) ([]models.Finding, error) {
 	findings := make([]models.Finding, 0)
 
 	// Simulate some findings for demo
@@ -116,6 +229,7 @@ func (m *BasicSecurityModule) Run(target string) ([]models.Finding, error) {
 		ID:          uuid.New().String(),
 		Type:        "configuration",
 		Severity:    "info",
+		Confidence:  models.ConfidenceHigh,
 		Title:       "Target Reachable",
 		Description: fmt.Sprintf("Successfully connected to %s", target),
 		Location:    target,
@@ -126,47 +240,238 @@ func (m *BasicSecurityModule) Run(target string) ([]models.Finding, error) {
 }
 
 // HeaderSecurityModule checks HTTP security headers
-type HeaderSecurityModule struct{}
+type HeaderSecurityModule struct {
+	client *http.Client
+}
 
 func (m *HeaderSecurityModule) Name() string {
 	return "Security Headers"
 }
 
-func (m *HeaderSecurityModule) Run(target string) ([]models.Finding, error) {
+// securityHeaders lists the headers checked and the severity when missing
+var securityHeaders = []struct {
+	name     string
+	severity string
+	purpose  string
+}{
+	{"Strict-Transport-Security", "medium", "forces browsers to use HTTPS"},
+	{"Content-Security-Policy", "medium", "restricts where scripts and other resources may load from"},
+	{"X-Frame-Options", "low", "prevents clickjacking via framing"},
+	{"X-Content-Type-Options", "low", "disables MIME type sniffing"},
+	{"Referrer-Policy", "info", "limits referrer leakage to other sites"},
+}
+
+func (m *HeaderSecurityModule) Run(ctx context.Context, target string) ([]models.Finding, error) {
 	findings := make([]models.Finding, 0)
 
-	// Implementation will check for:
-	// - X-Frame-Options
-	// - Content-Security-Policy
-	// - Strict-Transport-Security
-	// - X-Content-Type-Options
-	// - etc.
+	location := targetURL(target)
+	resp, err := getContext(ctx, m.client, location)
+	if err != nil {
+		var certErr *tls.CertificateVerificationError
+		if errors.As(err, &certErr) {
+			return nil, fmt.Errorf("request failed: %w (scan with --insecure to check targets with untrusted certificates)", err)
+		}
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	resp.Body.Close()
+
+	// With --insecure the certificate wasn't checked, so report its problems
+	if finding, ok := certificateFinding(location, resp.Request.URL.Hostname(), resp.TLS); ok {
+		findings = append(findings, finding)
+	}
+
+	// A missing header on a redirect or error page may well be set on the
+	// real page, so those responses are weaker evidence
+	confidence := models.ConfidenceHigh
+	switch {
+	case resp.StatusCode >= 300 && resp.StatusCode < 400:
+		confidence = models.ConfidenceLow
+	case resp.StatusCode >= 400:
+		confidence = models.ConfidenceMedium
+	}
+
+	for _, header := range securityHeaders {
+		if resp.Header.Get(header.name) != "" {
+			continue
+		}
+		finding := models.Finding{
+			ID:          uuid.New().String(),
+			Type:        "security-header",
+			Severity:    header.severity,
+			Confidence:  confidence,
+			Title:       fmt.Sprintf("Missing %s header", header.name),
+			Description: fmt.Sprintf("The response does not set %s, which %s.", header.name, header.purpose),
+			Evidence:    fmt.Sprintf("HTTP %d from %s without %s", resp.StatusCode, location, header.name),
+			Location:    location,
+			Tags:        []string{"headers"},
+			Timestamp:   time.Now(),
+		}
+		finding.SetMeta(models.MetaHTTPStatus, strconv.Itoa(resp.StatusCode))
+		finding.SetMeta(models.MetaHost, resp.Request.URL.Hostname())
+		if resp.TLS != nil {
+			finding.SetMeta(models.MetaTLSVersion, tls.VersionName(resp.TLS.Version))
+		}
+		findings = append(findings, finding)
+	}
 
 	return findings, nil
 }
 
 // SubdomainModule discovers subdomains
-type SubdomainModule struct{}
+type SubdomainModule struct {
+	threads  int
+	wordlist []string // nil for the built-in list
+}
 
 func (m *SubdomainModule) Name() string {
 	return "Subdomain Discovery"
 }
 
-func (m *SubdomainModule) Run(target string) ([]models.Finding, error) {
+func (m *SubdomainModule) Run(ctx context.Context, target string) ([]models.Finding, error) {
 	findings := make([]models.Finding, 0)
-	// Implementation coming
+
+	subdomains, err := DiscoverSubdomains(ctx, target, m.threads, m.wordlist)
+	if errors.Is(err, errNotDomain) || errors.Is(err, errWildcardDNS) {
+		// Nothing to discover; not a module failure
+		return findings, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, subdomain := range subdomains {
+		finding := models.Finding{
+			ID:          uuid.New().String(),
+			Type:        "subdomain",
+			Severity:    "info",
+			Confidence:  models.ConfidenceHigh, // it resolved
+			Title:       fmt.Sprintf("Subdomain %s", subdomain),
+			Description: fmt.Sprintf("%s resolves in DNS and is part of the attack surface", subdomain),
+			Evidence:    fmt.Sprintf("DNS lookup of %s succeeded", subdomain),
+			Location:    subdomain,
+			Tags:        []string{"subdomains", "dns"},
+			Timestamp:   time.Now(),
+		}
+		finding.SetMeta(models.MetaHost, subdomain)
+		findings = append(findings, finding)
+	}
+
 	return findings, nil
 }
 
 // PortScanModule scans for open ports
-type PortScanModule struct{}
+type PortScanModule struct {
+	ports   []int
+	threads int
+	timeout time.Duration
+	hosts   *hostLimiter
+}
+
+// commonPorts are checked by the deep profile's port scan
+var commonPorts = []int{
+	21, 22, 23, 25, 53, 80, 110, 111, 135, 139, 143, 443, 445, 993, 995,
+	1433, 1521, 2049, 3306, 3389, 5432, 5900, 6379, 8000, 8080, 8443, 9200, 27017,
+}
+
+// commonServices names the service usually found on each of commonPorts
+var commonServices = map[int]string{
+	21: "ftp", 22: "ssh", 23: "telnet", 25: "smtp", 53: "domain", 80: "http", 110: "pop3",
+	111: "rpcbind", 135: "msrpc", 139: "netbios-ssn", 143: "imap", 443: "https", 445: "microsoft-ds",
+	993: "imaps", 995: "pop3s", 1433: "ms-sql-s", 1521: "oracle", 2049: "nfs", 3306: "mysql",
+	3389: "ms-wbt-server", 5432: "postgresql", 5900: "vnc", 6379: "redis", 8000: "http-alt",
+	8080: "http-proxy", 8443: "https-alt", 9200: "elasticsearch", 27017: "mongodb",
+}
 
 func (m *PortScanModule) Name() string {
 	return "Port Scanning"
 }
 
-func (m *PortScanModule) Run(target string) ([]models.Finding, error) {
+// Run does a TCP connect scan. Addresses are built with net.JoinHostPort so
+// IPv6 literals are bracketed, and hostnames resolve dual-stack.
+func (m *PortScanModule) Run(ctx context.Context, target string) ([]models.Finding, error) {
 	findings := make([]models.Finding, 0)
-	// Implementation coming
+
+	parsed, err := ParseTarget(target)
+	if err != nil {
+		return nil, err
+	}
+
+	ports := m.ports
+	if len(ports) == 0 {
+		ports = commonPorts
+	}
+	threads := m.threads
+	if threads <= 0 {
+		threads = 10
+	}
+	timeout := m.timeout
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	hosts := m.hosts
+	if hosts == nil {
+		hosts = newHostLimiter(DefaultConcurrencyPerHost)
+	}
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		sem  = make(chan struct{}, threads)
+		open = make([]int, 0)
+	)
+	dialer := net.Dialer{Timeout: timeout}
+	for _, port := range ports {
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(port int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			release := hosts.acquire(parsed.Host)
+			defer release()
+
+			conn, err := dialer.DialContext(ctx, "tcp", parsed.Address(port))
+			if err != nil {
+				return
+			}
+			conn.Close()
+
+			mu.Lock()
+			open = append(open, port)
+			mu.Unlock()
+		}(port)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		// Ports not yet probed would wrongly look closed
+		return nil, err
+	}
+
+	sort.Ints(open)
+	for _, port := range open {
+		address := parsed.Address(port)
+		finding := models.Finding{
+			ID:          uuid.New().String(),
+			Type:        "open-port",
+			Severity:    "info",
+			Confidence:  models.ConfidenceHigh, // the handshake completed
+			Title:       fmt.Sprintf("Open TCP port %d", port),
+			Description: fmt.Sprintf("TCP port %d accepted a connection on %s", port, parsed.Host),
+			Evidence:    fmt.Sprintf("TCP connect to %s succeeded", address),
+			Location:    address,
+			Tags:        []string{"ports"},
+			Timestamp:   time.Now(),
+		}
+		finding.SetMeta(models.MetaHost, parsed.Host)
+		finding.SetMeta(models.MetaPort, strconv.Itoa(port))
+		finding.SetMeta(models.MetaProtocol, "tcp")
+		finding.SetMeta(models.MetaService, commonServices[port])
+		findings = append(findings, finding)
+	}
+
 	return findings, nil
 }
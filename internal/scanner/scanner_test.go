@@ -0,0 +1,215 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+func TestTruncateEvidence(t *testing.T) {
+	s := New(models.ScanConfig{MaxEvidenceLength: 64})
+	result := &models.ScanResult{}
+	findings := []models.Finding{
+		{Fingerprint: "long", Evidence: strings.Repeat("x", 500)},
+		{Fingerprint: "short", Evidence: "HTTP 200"},
+	}
+	s.truncateEvidence(result, findings)
+
+	if len(findings[0].Evidence) > 100 || findings[0].Metadata[models.MetaEvidenceTruncated] != "500" {
+		t.Errorf("long evidence = %d bytes, metadata %v", len(findings[0].Evidence), findings[0].Metadata)
+	}
+	if result.FullEvidence["long"] != strings.Repeat("x", 500) {
+		t.Error("full evidence not kept for the store")
+	}
+	if findings[1].Evidence != "HTTP 200" || findings[1].Metadata != nil || len(result.FullEvidence) != 1 {
+		t.Errorf("short evidence changed: %+v", findings[1])
+	}
+}
+
+func TestHTTPModulesStopOnCancel(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	modules := []Module{
+		&HeaderSecurityModule{client: server.Client()},
+		&HTTPProtocolModule{client: server.Client()},
+		&PageContentModule{client: server.Client()},
+	}
+	for _, module := range modules {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		start := time.Now()
+		_, err := module.Run(ctx, server.URL)
+		cancel()
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("%s: error = %v, want the context's", module.Name(), err)
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("%s kept probing for %v after cancellation", module.Name(), elapsed)
+		}
+	}
+}
+
+func TestPortScanModule(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	open := listener.Addr().(*net.TCPAddr).Port
+
+	// A port that was just released is closed
+	closedListener, _ := net.Listen("tcp", "127.0.0.1:0")
+	closed := closedListener.Addr().(*net.TCPAddr).Port
+	closedListener.Close()
+
+	module := &PortScanModule{ports: []int{closed, open}, timeout: time.Second}
+	findings, err := module.Run(context.Background(), "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(findings) != 1 || findings[0].Location != fmt.Sprintf("127.0.0.1:%d", open) || findings[0].Type != "open-port" {
+		t.Errorf("findings = %+v, want only the open port", findings)
+	}
+	if meta := findings[0].Metadata; meta[models.MetaHost] != "127.0.0.1" || meta[models.MetaPort] != strconv.Itoa(open) || meta[models.MetaProtocol] != "tcp" {
+		t.Errorf("metadata = %v", meta)
+	}
+
+	// A cancelled scan reports nothing rather than every port closed
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if findings, err := module.Run(ctx, "127.0.0.1"); !errors.Is(err, context.Canceled) || findings != nil {
+		t.Errorf("cancelled Run = %v, %v; want context.Canceled", findings, err)
+	}
+}
+
+func TestHeaderSecurityModuleMetadata(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Frame-Options", "DENY")
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	module := &HeaderSecurityModule{client: server.Client()}
+	findings, err := module.Run(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(findings) == 0 {
+		t.Fatal("no missing headers reported")
+	}
+	for _, finding := range findings {
+		if finding.Title == "Missing X-Frame-Options header" {
+			t.Error("reported a header the response sets")
+		}
+		meta := finding.Metadata
+		if meta[models.MetaHTTPStatus] != "404" || meta[models.MetaHost] != "127.0.0.1" || !strings.HasPrefix(meta[models.MetaTLSVersion], "TLS 1.") {
+			t.Errorf("%s: metadata = %v", finding.Title, meta)
+		}
+		if finding.Confidence != models.ConfidenceMedium {
+			t.Errorf("%s: confidence %q, want medium for an error page", finding.Title, finding.Confidence)
+		}
+	}
+}
+
+func TestHeaderSecurityModuleUntrustedCertificate(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Config.ErrorLog = log.New(io.Discard, "", 0) // the rejected handshake is expected
+	server.StartTLS()
+	defer server.Close()
+
+	verifying, err := newHTTPClient(models.ScanConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = (&HeaderSecurityModule{client: verifying}).Run(context.Background(), server.URL)
+	if err == nil || !strings.Contains(err.Error(), "--insecure") {
+		t.Errorf("verifying client error = %v, want a hint at --insecure", err)
+	}
+
+	insecure, err := newHTTPClient(models.ScanConfig{Insecure: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings, err := (&HeaderSecurityModule{client: insecure}).Run(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Run with --insecure: %v", err)
+	}
+	var certificate *models.Finding
+	for i := range findings {
+		if findings[i].Type == "tls-certificate" {
+			certificate = &findings[i]
+		}
+	}
+	if certificate == nil {
+		t.Fatalf("untrusted certificate not reported: %+v", findings)
+	}
+	if certificate.Title != "Self-signed TLS certificate" || certificate.Severity != "medium" || certificate.Metadata[models.MetaHost] != "127.0.0.1" {
+		t.Errorf("certificate finding = %+v", certificate)
+	}
+}
+
+func TestPortScanModuleIPv6(t *testing.T) {
+	listener, err := net.Listen("tcp", "[::1]:0")
+	if err != nil {
+		t.Skip("no IPv6 loopback")
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	findings, err := (&PortScanModule{ports: []int{port}, timeout: time.Second}).Run(context.Background(), "::1")
+	if err != nil || len(findings) != 1 || findings[0].Location != fmt.Sprintf("[::1]:%d", port) {
+		t.Errorf("findings = %+v, %v; want the bracketed IPv6 address", findings, err)
+	}
+}
+
+func TestLoadModulesResearchMatchesDeep(t *testing.T) {
+	names := func(profile string) string {
+		s := New(models.ScanConfig{Profile: profile})
+		s.loadModules()
+		var names []string
+		for _, module := range s.modules {
+			names = append(names, module.Name())
+		}
+		return strings.Join(names, ",")
+	}
+
+	if deep, research := names("deep"), names("research"); research != deep || research == "" {
+		t.Errorf("research modules %q, want the deep profile's %q", research, deep)
+	}
+}
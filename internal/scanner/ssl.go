@@ -0,0 +1,220 @@
+package scanner
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+// expiryWarningDays flags certificates this close to expiry
+const expiryWarningDays = 30
+
+// tlsVersions are the protocol versions CheckSSL probes, oldest first
+var tlsVersions = []uint16{tls.VersionTLS10, tls.VersionTLS11, tls.VersionTLS12, tls.VersionTLS13}
+
+// probeCipherSuites offers every TLS 1.0-1.2 suite crypto/tls implements,
+// the insecure ones included. Go's default list leaves out RC4, 3DES and
+// RSA key exchange, so without it a server that only offers those looks
+// like it doesn't speak TLS 1.0/1.1 at all and a weak cipher is never
+// negotiated. TLS 1.3 suites aren't configurable and are always offered.
+func probeCipherSuites() []uint16 {
+	suites := make([]uint16, 0)
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		suites = append(suites, suite.ID)
+	}
+	return suites
+}
+
+// CheckSSL inspects target's TLS configuration: certificate validity and
+// expiry, the negotiated version and cipher, which protocol versions are
+// accepted, and an overall grade. Targets without a port use 443.
+func CheckSSL(target string, timeout time.Duration) (*models.SSLResult, error) {
+	parsed, err := ParseTarget(target)
+	if err != nil {
+		return nil, err
+	}
+	port := parsed.Port
+	if port == 0 {
+		port = 443
+	}
+	address := parsed.Address(port)
+	dialer := &net.Dialer{Timeout: timeout}
+	suites := probeCipherSuites()
+
+	result := &models.SSLResult{
+		Target: address,
+		Issues: make([]string, 0),
+	}
+
+	// Verified handshake first; if verification fails, look anyway. Old
+	// protocols are allowed so a TLS 1.0-only server is graded, not failed.
+	conn, err := tls.DialWithDialer(dialer, "tcp", address, &tls.Config{
+		ServerName:   parsed.Host,
+		CipherSuites: suites,
+		MinVersion:   tls.VersionTLS10,
+	})
+	if err == nil {
+		result.Valid = true
+	} else {
+		var certErr *tls.CertificateVerificationError
+		if !errors.As(err, &certErr) {
+			return nil, fmt.Errorf("TLS handshake with %s failed: %w", address, err)
+		}
+		result.Issues = append(result.Issues, "Certificate verification failed: "+certErr.Err.Error())
+
+		conn, err = tls.DialWithDialer(dialer, "tcp", address, &tls.Config{
+			ServerName:         parsed.Host,
+			CipherSuites:       suites,
+			MinVersion:         tls.VersionTLS10,
+			InsecureSkipVerify: true, // only to read the certificate that failed verification
+		})
+		if err != nil {
+			return nil, fmt.Errorf("TLS handshake with %s failed: %w", address, err)
+		}
+	}
+	state := conn.ConnectionState()
+	conn.Close()
+
+	result.Version = tls.VersionName(state.Version)
+	result.Cipher = tls.CipherSuiteName(state.CipherSuite)
+	if len(state.PeerCertificates) > 0 {
+		describeCertificate(result, state.PeerCertificates[0])
+	}
+
+	for _, suite := range tls.InsecureCipherSuites() {
+		if suite.ID == state.CipherSuite {
+			result.Issues = append(result.Issues, "Weak cipher suite negotiated: "+suite.Name)
+		}
+	}
+
+	for _, version := range tlsVersions {
+		config := &tls.Config{
+			ServerName:         parsed.Host,
+			CipherSuites:       suites,
+			InsecureSkipVerify: true, // probing protocol support, not trust
+			MinVersion:         version,
+			MaxVersion:         version,
+		}
+		conn, err := tls.DialWithDialer(dialer, "tcp", address, config)
+		if err != nil {
+			continue
+		}
+		conn.Close()
+		result.SupportedVersions = append(result.SupportedVersions, tls.VersionName(version))
+		if version < tls.VersionTLS12 {
+			result.Issues = append(result.Issues, tls.VersionName(version)+" is accepted (deprecated, downgrade risk)")
+		}
+	}
+
+	result.Grade = gradeSSL(result)
+	return result, nil
+}
+
+// describeCertificate copies the leaf certificate's details into result
+func describeCertificate(result *models.SSLResult, cert *x509.Certificate) {
+	result.Issuer = cert.Issuer.String()
+	result.Subject = cert.Subject.String()
+	result.NotBefore = cert.NotBefore
+	result.NotAfter = cert.NotAfter
+	result.DaysToExpiry = int(time.Until(cert.NotAfter).Hours() / 24)
+
+	switch {
+	case time.Now().After(cert.NotAfter):
+		result.Issues = append(result.Issues, "Certificate expired on "+cert.NotAfter.Format("2006-01-02"))
+	case result.DaysToExpiry < expiryWarningDays:
+		result.Issues = append(result.Issues, "Certificate expires in "+strconv.Itoa(result.DaysToExpiry)+" days")
+	}
+	if cert.Issuer.String() == cert.Subject.String() {
+		result.Issues = append(result.Issues, "Certificate is self-signed")
+	}
+}
+
+// gradeSSL scores the result: F for an untrusted or expired certificate,
+// C when pre-1.2 protocols are accepted, B for weak ciphers or imminent
+// expiry, A otherwise and A+ when TLS 1.3 is supported too
+func gradeSSL(result *models.SSLResult) string {
+	if !result.Valid || time.Now().After(result.NotAfter) {
+		return "F"
+	}
+
+	supported := make(map[string]bool)
+	for _, version := range result.SupportedVersions {
+		supported[version] = true
+	}
+
+	weakCipher := false
+	for _, suite := range tls.InsecureCipherSuites() {
+		if suite.Name == result.Cipher {
+			weakCipher = true
+		}
+	}
+
+	switch {
+	case supported["TLS 1.0"] || supported["TLS 1.1"]:
+		return "C"
+	case weakCipher || result.DaysToExpiry < expiryWarningDays:
+		return "B"
+	case supported["TLS 1.3"]:
+		return "A+"
+	default:
+		return "A"
+	}
+}
+
+// certificateFinding verifies the certificate of a response received with
+// verification turned off (--insecure) and reports why it isn't trusted.
+// Responses whose certificate was already verified have nothing to report.
+func certificateFinding(location string, host string, state *tls.ConnectionState) (models.Finding, bool) {
+	if state == nil || len(state.VerifiedChains) > 0 || len(state.PeerCertificates) == 0 {
+		return models.Finding{}, false
+	}
+
+	leaf := state.PeerCertificates[0]
+	intermediates := x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := leaf.Verify(x509.VerifyOptions{DNSName: host, Intermediates: intermediates})
+	if err == nil {
+		return models.Finding{}, false
+	}
+
+	title := "Untrusted TLS certificate"
+	var unknownAuthority x509.UnknownAuthorityError
+	var invalid x509.CertificateInvalidError
+	var hostname x509.HostnameError
+	switch {
+	case errors.As(err, &invalid) && invalid.Reason == x509.Expired:
+		title = "Expired or not yet valid TLS certificate"
+	case errors.As(err, &hostname):
+		title = "TLS certificate does not match the host name"
+	case errors.As(err, &unknownAuthority) && leaf.Issuer.String() == leaf.Subject.String():
+		title = "Self-signed TLS certificate"
+	}
+
+	finding := models.Finding{
+		ID:         uuid.New().String(),
+		Type:       "tls-certificate",
+		Severity:   "medium",
+		Confidence: models.ConfidenceHigh,
+		Title:      title,
+		Description: "Clients that verify certificates will refuse the connection or warn, and users who " +
+			"click through such warnings can't detect an interception. Use a certificate from a trusted CA " +
+			"that covers the host name.",
+		Evidence:  fmt.Sprintf("Certificate for %q issued by %q: %v", leaf.Subject.String(), leaf.Issuer.String(), err),
+		Location:  location,
+		Tags:      []string{"tls", "certificate"},
+		Timestamp: time.Now(),
+	}
+	finding.SetMeta(models.MetaHost, host)
+	finding.SetMeta(models.MetaTLSVersion, tls.VersionName(state.Version))
+	return finding, true
+}
@@ -0,0 +1,93 @@
+package scanner
+
+import (
+	"crypto/tls"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+// tlsServer starts an HTTPS server limited to the given versions and
+// cipher suite
+func tlsServer(t *testing.T, minVersion uint16, maxVersion uint16, suite uint16) string {
+	t.Helper()
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Config.ErrorLog = log.New(io.Discard, "", 0) // rejected probes are expected
+	server.TLS = &tls.Config{
+		MinVersion:   minVersion,
+		MaxVersion:   maxVersion,
+		CipherSuites: []uint16{suite},
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func hasIssue(result *models.SSLResult, prefix string) bool {
+	for _, issue := range result.Issues {
+		if strings.HasPrefix(issue, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCheckSSLWeakCipher(t *testing.T) {
+	target := tlsServer(t, tls.VersionTLS12, tls.VersionTLS12, tls.TLS_RSA_WITH_3DES_EDE_CBC_SHA)
+
+	result, err := CheckSSL(target, 5*time.Second)
+	if err != nil {
+		t.Fatalf("CheckSSL: %v", err)
+	}
+	if result.Cipher != "TLS_RSA_WITH_3DES_EDE_CBC_SHA" || !hasIssue(result, "Weak cipher suite negotiated") {
+		t.Errorf("cipher %s, issues %q; want the 3DES suite flagged", result.Cipher, result.Issues)
+	}
+}
+
+func TestCheckSSLOldProtocolsWithRSAKeyExchange(t *testing.T) {
+	target := tlsServer(t, tls.VersionTLS10, tls.VersionTLS11, tls.TLS_RSA_WITH_AES_128_CBC_SHA)
+
+	result, err := CheckSSL(target, 5*time.Second)
+	if err != nil {
+		t.Fatalf("CheckSSL: %v", err)
+	}
+	supported := strings.Join(result.SupportedVersions, ",")
+	if supported != "TLS 1.0,TLS 1.1" || !hasIssue(result, "TLS 1.0 is accepted") {
+		t.Errorf("supported %q, issues %q; want TLS 1.0 and 1.1 detected", supported, result.Issues)
+	}
+}
+
+func TestGradeSSL(t *testing.T) {
+	valid := func(cipher string, versions ...string) *models.SSLResult {
+		return &models.SSLResult{
+			Valid:             true,
+			NotAfter:          time.Now().AddDate(1, 0, 0),
+			DaysToExpiry:      365,
+			Cipher:            cipher,
+			SupportedVersions: versions,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		result *models.SSLResult
+		want   string
+	}{
+		{"untrusted", &models.SSLResult{Valid: false, NotAfter: time.Now().AddDate(1, 0, 0)}, "F"},
+		{"TLS 1.0 accepted", valid("TLS_AES_128_GCM_SHA256", "TLS 1.0", "TLS 1.2"), "C"},
+		{"weak cipher", valid("TLS_RSA_WITH_3DES_EDE_CBC_SHA", "TLS 1.2"), "B"},
+		{"TLS 1.2 only", valid("TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS 1.2"), "A"},
+		{"TLS 1.3", valid("TLS_AES_128_GCM_SHA256", "TLS 1.2", "TLS 1.3"), "A+"},
+	}
+	for _, tt := range tests {
+		if got := gradeSSL(tt.result); got != tt.want {
+			t.Errorf("%s: grade %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
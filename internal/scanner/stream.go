@@ -0,0 +1,45 @@
+package scanner
+
+import "bytes"
+
+// ToolProgress receives external tool output one line at a time while the
+// tool runs
+type ToolProgress func(line string)
+
+// SetProgress streams the output of every tool this manager runs to
+// progress, line by line. The full output is still returned for parsing.
+func (pm *PermissionManager) SetProgress(progress ToolProgress) {
+	pm.progress = progress
+}
+
+// lineStream keeps everything written to it and hands each complete line
+// to progress. execRunner passes it as both Stdout and Stderr, and
+// exec.Cmd serializes writes to a shared writer, so no locking is needed.
+type lineStream struct {
+	progress ToolProgress
+	output   bytes.Buffer
+	pending  []byte
+}
+
+func (s *lineStream) Write(p []byte) (int, error) {
+	s.output.Write(p)
+	s.pending = append(s.pending, p...)
+
+	for {
+		i := bytes.IndexByte(s.pending, '\n')
+		if i < 0 {
+			break
+		}
+		s.progress(string(bytes.TrimRight(s.pending[:i], "\r")))
+		s.pending = s.pending[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush hands over a final line that had no trailing newline
+func (s *lineStream) flush() {
+	if len(s.pending) > 0 {
+		s.progress(string(bytes.TrimRight(s.pending, "\r")))
+		s.pending = nil
+	}
+}
@@ -0,0 +1,44 @@
+package scanner
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLineStream(t *testing.T) {
+	var lines []string
+	stream := &lineStream{progress: func(line string) { lines = append(lines, line) }}
+
+	for _, chunk := range []string{"Starting Nmap\r\n22/tcp op", "en ssh\n", "\n80/tcp open http"} {
+		stream.Write([]byte(chunk))
+	}
+	if strings.Join(lines, "|") != "Starting Nmap|22/tcp open ssh|" {
+		t.Errorf("lines before flush = %q", lines)
+	}
+
+	stream.flush()
+	if len(lines) != 4 || lines[3] != "80/tcp open http" {
+		t.Errorf("lines after flush = %q, want the unterminated last line", lines)
+	}
+	if got := stream.output.String(); got != "Starting Nmap\r\n22/tcp open ssh\n\n80/tcp open http" {
+		t.Errorf("output = %q, want everything written kept verbatim", got)
+	}
+}
+
+func TestRunTimedStreamsProgress(t *testing.T) {
+	pm := &PermissionManager{runner: &fakeRunner{outputs: map[string]string{"nmap": "22/tcp open ssh\n80/tcp open http"}}}
+
+	var lines []string
+	pm.SetProgress(func(line string) { lines = append(lines, line) })
+
+	output, err := pm.runTimed("nmap", "example.com")
+	if err != nil {
+		t.Fatalf("runTimed: %v", err)
+	}
+	if strings.Join(lines, "|") != "22/tcp open ssh|80/tcp open http" {
+		t.Errorf("progress = %q", lines)
+	}
+	if string(output) != "22/tcp open ssh\n80/tcp open http" {
+		t.Errorf("output = %q, want the full output returned for parsing", output)
+	}
+}
@@ -0,0 +1,163 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kumaraguru1735/shadow/internal/output"
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+// subdomainWordlist holds the labels DiscoverSubdomains tries when no
+// custom wordlist is given
+var subdomainWordlist = []string{
+	"www", "mail", "webmail", "smtp", "imap", "pop", "mx", "ns1", "ns2",
+	"api", "app", "apps", "admin", "portal", "dashboard", "auth", "login", "sso",
+	"dev", "staging", "stage", "test", "qa", "uat", "beta", "demo", "sandbox",
+	"vpn", "remote", "gateway", "proxy", "cdn", "static", "assets", "media", "img",
+	"blog", "shop", "store", "docs", "help", "support", "status", "git", "gitlab",
+	"jenkins", "ci", "grafana", "kibana", "monitor", "db", "internal", "intranet",
+}
+
+// Targets subdomain discovery doesn't apply to
+var (
+	errNotDomain   = errors.New("not a domain")
+	errWildcardDNS = errors.New("wildcard DNS")
+)
+
+// InScope reports whether host is domain or one of its subdomains
+func InScope(domain string, host string) bool {
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// DiscoverSubdomains resolves each wordlist label under domain and returns
+// the names that exist, sorted. A nil wordlist uses the built-in list of
+// common names. Domains with wildcard DNS are refused, since every name
+// would appear to exist.
+func DiscoverSubdomains(ctx context.Context, domain string, threads int, wordlist []string) ([]string, error) {
+	parsed, err := ParseTarget(domain)
+	if err != nil {
+		return nil, err
+	}
+	domain = strings.TrimSuffix(strings.ToLower(parsed.Host), ".")
+	if net.ParseIP(domain) != nil {
+		return nil, fmt.Errorf("%s is an IP address: %w", domain, errNotDomain)
+	}
+
+	resolver := net.DefaultResolver
+	if _, err := resolver.LookupHost(ctx, uuid.New().String()+"."+domain); err == nil {
+		return nil, fmt.Errorf("%s has %w, subdomains can't be told apart", domain, errWildcardDNS)
+	}
+
+	if threads <= 0 {
+		threads = 10
+	}
+	if wordlist == nil {
+		wordlist = subdomainWordlist
+	}
+
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		sem   = make(chan struct{}, threads)
+		found = make([]string, 0)
+	)
+	for _, label := range wordlist {
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(name string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			lookupCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+			if _, err := resolver.LookupHost(lookupCtx, name); err != nil {
+				return
+			}
+
+			mu.Lock()
+			found = append(found, name)
+			mu.Unlock()
+		}(label + "." + domain)
+	}
+	wg.Wait()
+
+	sort.Strings(found)
+	return found, ctx.Err()
+}
+
+// ScanChildren scans each of targets as its own child scan of parent,
+// at most concurrency at a time, using config with the target swapped.
+// Children are linked to parent through ParentID and parent.Children.
+// Targets out of parent's scope are skipped.
+func ScanChildren(
+	ctx context.Context,
+	parent *models.ScanResult,
+	config models.ScanConfig,
+	targets []string,
+	concurrency int,
+) []*models.ScanResult {
+	scope := parent.Target
+	if parsed, err := ParseTarget(parent.Target); err == nil {
+		scope = parsed.Host
+	}
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	var (
+		wg       sync.WaitGroup
+		sem      = make(chan struct{}, concurrency)
+		children = make([]*models.ScanResult, len(targets))
+	)
+	for i, target := range targets {
+		if !InScope(scope, target) {
+			output.Printf("  ⏭️  %s is outside %s, skipped\n", target, scope)
+			continue
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, target string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			childConfig := config
+			childConfig.Target = target
+			child, err := New(childConfig).RunContext(ctx)
+			if child == nil {
+				output.Printf("  ⚠️  %s: %v\n", target, err)
+				return
+			}
+			child.ParentID = parent.ID
+			children[i] = child
+		}(i, target)
+	}
+	wg.Wait()
+
+	// Keep the discovery order, dropping the scans that failed
+	scanned := make([]*models.ScanResult, 0, len(children))
+	for _, child := range children {
+		if child != nil {
+			scanned = append(scanned, child)
+			parent.Children = append(parent.Children, child.ID)
+		}
+	}
+	return scanned
+}
@@ -0,0 +1,41 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestInScope(t *testing.T) {
+	tests := []struct {
+		domain string
+		host   string
+		want   bool
+	}{
+		{"example.com", "example.com", true},
+		{"example.com", "api.example.com", true},
+		{"example.com", "API.Example.com.", true},
+		{"example.com", "badexample.com", false},
+		{"example.com", "example.com.evil.net", false},
+	}
+	for _, tt := range tests {
+		if got := InScope(tt.domain, tt.host); got != tt.want {
+			t.Errorf("InScope(%q, %q) = %v, want %v", tt.domain, tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestDiscoverSubdomainsRejectsIP(t *testing.T) {
+	_, err := DiscoverSubdomains(context.Background(), "192.0.2.10", 1, []string{"www"})
+	if !errors.Is(err, errNotDomain) {
+		t.Errorf("DiscoverSubdomains(IP) error = %v, want errNotDomain", err)
+	}
+}
+
+func TestSubdomainModuleSkipsIPTargets(t *testing.T) {
+	module := &SubdomainModule{threads: 1, wordlist: []string{"www"}}
+	findings, err := module.Run(context.Background(), "https://192.0.2.10/")
+	if err != nil || len(findings) != 0 {
+		t.Errorf("Run(IP) = %v, %v; want no findings and no error", findings, err)
+	}
+}
@@ -0,0 +1,117 @@
+package scanner
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/user"
+	"regexp"
+	"strings"
+
+	"github.com/kumaraguru1735/shadow/internal/output"
+)
+
+// sudoersDir is where install-sudoers drops its entries
+const sudoersDir = "/etc/sudoers.d"
+
+var (
+	// sudoersName restricts user and tool names to characters that are
+	// safe both in a sudoers entry and in a file name
+	sudoersName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+	// sudoersPath rejects paths with whitespace or sudoers metacharacters
+	sudoersPath = regexp.MustCompile(`^/[A-Za-z0-9_./+-]+$`)
+)
+
+// SudoersEntry returns the NOPASSWD entry allowing username to run path
+func SudoersEntry(username string, path string) (string, error) {
+	if !sudoersName.MatchString(username) {
+		return "", fmt.Errorf("unsupported user name %q", username)
+	}
+	if !sudoersPath.MatchString(path) {
+		return "", fmt.Errorf("unsupported tool path %q (must be absolute, without spaces or special characters)", path)
+	}
+	return fmt.Sprintf("%s ALL=(ALL) NOPASSWD: %s\n", username, path), nil
+}
+
+// SudoersFile returns /etc/sudoers.d/shadow-<tool>
+func SudoersFile(tool string) string {
+	return sudoersDir + "/shadow-" + tool
+}
+
+// InstallSudoers writes a NOPASSWD sudoers entry for the tool's resolved
+// absolute path to /etc/sudoers.d/shadow-<tool>, after confirmation. The
+// entry is checked with visudo -c before anything is installed, and the
+// file is installed with mode 0440.
+func (pm *PermissionManager) InstallSudoers(tool string) error {
+	if !sudoersName.MatchString(tool) {
+		return fmt.Errorf("unsupported tool name %q", tool)
+	}
+
+	path, err := resolveToolPath(tool)
+	if err != nil {
+		return err
+	}
+
+	username := os.Getenv("USER")
+	if current, err := user.Current(); err == nil {
+		username = current.Username
+	}
+
+	entry, err := SudoersEntry(username, path)
+	if err != nil {
+		return err
+	}
+
+	target := SudoersFile(tool)
+	output.Println("\n🔐 Install sudoers Entry")
+	output.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	output.Printf("\n📄 File: %s (mode 0440)\n", target)
+	output.Printf("📝 Entry: %s", entry)
+	output.Printf("\n⚠️  %s will be able to run %s as root without a password\n", username, path)
+
+	output.Print("\nInstall this entry? (yes/no): ")
+	response, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	response = strings.ToLower(strings.TrimSpace(response))
+	if err != nil || (response != "yes" && response != "y") {
+		pm.audit("sudoers", tool, AuditDenied, "install "+target)
+		return fmt.Errorf("not confirmed")
+	}
+
+	return pm.installSudoersEntry(tool, entry, target)
+}
+
+// installSudoersEntry validates entry with visudo -c and only then installs
+// it to target
+func (pm *PermissionManager) installSudoersEntry(tool string, entry string, target string) error {
+	staged, err := os.CreateTemp("", "shadow-sudoers-*")
+	if err != nil {
+		return fmt.Errorf("failed to stage sudoers entry: %w", err)
+	}
+	defer os.Remove(staged.Name())
+
+	if _, err := staged.WriteString(entry); err != nil {
+		staged.Close()
+		return fmt.Errorf("failed to stage sudoers entry: %w", err)
+	}
+	if err := staged.Close(); err != nil {
+		return fmt.Errorf("failed to stage sudoers entry: %w", err)
+	}
+	if err := os.Chmod(staged.Name(), 0440); err != nil {
+		return fmt.Errorf("failed to stage sudoers entry: %w", err)
+	}
+
+	// Never install an entry visudo rejects: a broken sudoers.d file can
+	// lock the user out of sudo entirely
+	if out, err := pm.runner.Run("sudo", "visudo", "-c", "-f", staged.Name()); err != nil {
+		pm.audit("sudoers", tool, AuditFailed, "visudo -c "+target)
+		return fmt.Errorf("visudo rejected the entry, nothing was installed: %s", strings.TrimSpace(string(out)))
+	}
+
+	if out, err := pm.runner.Run("sudo", "install", "-m", "0440", "-o", "root", "-g", "root", staged.Name(), target); err != nil {
+		pm.audit("sudoers", tool, AuditFailed, "install "+target)
+		return fmt.Errorf("failed to install %s: %w\nOutput: %s", target, err, strings.TrimSpace(string(out)))
+	}
+	pm.audit("sudoers", tool, AuditExecuted, "install "+target+": "+strings.TrimSpace(entry))
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package scanner
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSudoersEntry(t *testing.T) {
+	entry, err := SudoersEntry("alice", "/usr/bin/nmap")
+	if err != nil || entry != "alice ALL=(ALL) NOPASSWD: /usr/bin/nmap\n" {
+		t.Errorf("SudoersEntry = %q, %v", entry, err)
+	}
+
+	for _, tt := range []struct{ user, path string }{
+		{"alice ALL", "/usr/bin/nmap"},
+		{"alice", "nmap"},
+		{"alice", "/usr/bin/nmap, /bin/sh"},
+		{"alice", "/opt/my tools/nmap"},
+		{"alice\nroot", "/usr/bin/nmap"},
+	} {
+		if _, err := SudoersEntry(tt.user, tt.path); err == nil {
+			t.Errorf("SudoersEntry(%q, %q) accepted an unsafe entry", tt.user, tt.path)
+		}
+	}
+}
+
+func TestInstallSudoersEntryValidatesFirst(t *testing.T) {
+	pm, path := testPermissionManager(t)
+	runner := &fakeRunner{}
+	pm.runner = runner
+
+	if err := pm.installSudoersEntry("nmap", "alice ALL=(ALL) NOPASSWD: /usr/bin/nmap\n", SudoersFile("nmap")); err != nil {
+		t.Fatalf("installSudoersEntry: %v", err)
+	}
+	if len(runner.calls) != 2 || !strings.HasPrefix(runner.calls[0], "sudo visudo -c -f ") ||
+		!strings.HasPrefix(runner.calls[1], "sudo install -m 0440 -o root -g root ") || !strings.HasSuffix(runner.calls[1], " /etc/sudoers.d/shadow-nmap") {
+		t.Errorf("calls = %q, want visudo then install", runner.calls)
+	}
+	if lines := readAuditLog(t, path); len(lines) != 1 || !strings.Contains(lines[0], "decision=executed") {
+		t.Errorf("audit log = %q", lines)
+	}
+}
+
+func TestInstallSudoersEntryRejected(t *testing.T) {
+	pm, path := testPermissionManager(t)
+	runner := &fakeRunner{
+		outputs: map[string]string{"sudo visudo": "syntax error near line 1"},
+		errs:    map[string]error{"sudo visudo": errors.New("exit status 1")},
+	}
+	pm.runner = runner
+
+	err := pm.installSudoersEntry("nmap", "broken entry\n", SudoersFile("nmap"))
+	if err == nil || !strings.Contains(err.Error(), "nothing was installed") || !strings.Contains(err.Error(), "syntax error") {
+		t.Errorf("error = %v, want visudo's rejection", err)
+	}
+	if len(runner.calls) != 1 {
+		t.Errorf("calls = %q, want nothing installed after visudo failed", runner.calls)
+	}
+	if lines := readAuditLog(t, path); len(lines) != 1 || !strings.Contains(lines[0], "decision=failed") {
+		t.Errorf("audit log = %q", lines)
+	}
+}
+
+func TestInstallSudoersRejectsUnsafeToolName(t *testing.T) {
+	pm, _ := testPermissionManager(t)
+	pm.runner = &fakeRunner{}
+	if err := pm.InstallSudoers("../../bin/sh"); err == nil || !strings.Contains(err.Error(), "unsupported tool name") {
+		t.Errorf("error = %v, want the tool name rejected", err)
+	}
+}
@@ -0,0 +1,89 @@
+package scanner
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Target is a parsed scan target. Host never carries IPv6 brackets.
+type Target struct {
+	Scheme string // empty when the target was given without one
+	Host   string
+	Port   int // 0 when no port was given
+}
+
+// ParseTarget accepts a hostname, IPv4/IPv6 literal or URL, with an
+// optional port: example.com, 10.0.0.1:8080, 2001:db8::1, [2001:db8::1]:443,
+// https://[::1]:8443/login
+func ParseTarget(raw string) (Target, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return Target{}, fmt.Errorf("empty target")
+	}
+
+	if strings.Contains(raw, "://") {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return Target{}, fmt.Errorf("invalid target %q: %w", raw, err)
+		}
+		target := Target{Scheme: strings.ToLower(u.Scheme), Host: u.Hostname()}
+		if p := u.Port(); p != "" {
+			port, err := parsePort(p)
+			if err != nil {
+				return Target{}, fmt.Errorf("invalid target %q: %w", raw, err)
+			}
+			target.Port = port
+		}
+		if target.Host == "" {
+			return Target{}, fmt.Errorf("invalid target %q: missing host", raw)
+		}
+		return target, nil
+	}
+
+	// A bare IPv6 literal has several colons and no port
+	if ip := net.ParseIP(strings.Trim(raw, "[]")); ip != nil {
+		return Target{Host: ip.String()}, nil
+	}
+
+	host, p, err := net.SplitHostPort(raw)
+	if err != nil {
+		// No port
+		return Target{Host: raw}, nil
+	}
+	port, err := parsePort(p)
+	if err != nil {
+		return Target{}, fmt.Errorf("invalid target %q: %w", raw, err)
+	}
+	return Target{Host: host, Port: port}, nil
+}
+
+// Address returns host:port, bracketing IPv6 literals ([2001:db8::1]:443)
+func (t Target) Address(port int) string {
+	return net.JoinHostPort(t.Host, strconv.Itoa(port))
+}
+
+// URL returns the target as an http(s) URL, defaulting to https
+func (t Target) URL() string {
+	scheme := t.Scheme
+	if scheme == "" {
+		scheme = "https"
+	}
+	host := t.Host
+	if t.Port != 0 {
+		host = t.Address(t.Port)
+	} else if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	return scheme + "://" + host
+}
+
+func parsePort(p string) (int, error) {
+	port, err := strconv.Atoi(p)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("invalid port %q", p)
+	}
+	return port, nil
+}
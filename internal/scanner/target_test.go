@@ -0,0 +1,54 @@
+package scanner
+
+import "testing"
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    Target
+		address string
+		url     string
+	}{
+		{"example.com", Target{Host: "example.com"}, "example.com:443", "https://example.com"},
+		{"10.0.0.1:8080", Target{Host: "10.0.0.1", Port: 8080}, "10.0.0.1:443", "https://10.0.0.1:8080"},
+		{"2001:db8::1", Target{Host: "2001:db8::1"}, "[2001:db8::1]:443", "https://[2001:db8::1]"},
+		{"[2001:db8::1]", Target{Host: "2001:db8::1"}, "[2001:db8::1]:443", "https://[2001:db8::1]"},
+		{"[2001:db8::1]:8443", Target{Host: "2001:db8::1", Port: 8443}, "[2001:db8::1]:443", "https://[2001:db8::1]:8443"},
+		{"http://[::1]:8000/login", Target{Scheme: "http", Host: "::1", Port: 8000}, "[::1]:443", "http://[::1]:8000"},
+		{" HTTPS://Example.com ", Target{Scheme: "https", Host: "Example.com"}, "Example.com:443", "https://Example.com"},
+	}
+	for _, tt := range tests {
+		got, err := ParseTarget(tt.raw)
+		if err != nil {
+			t.Errorf("ParseTarget(%q): %v", tt.raw, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseTarget(%q) = %+v, want %+v", tt.raw, got, tt.want)
+		}
+		if address := got.Address(443); address != tt.address {
+			t.Errorf("%q: Address(443) = %q, want %q", tt.raw, address, tt.address)
+		}
+		if url := got.URL(); url != tt.url {
+			t.Errorf("%q: URL() = %q, want %q", tt.raw, url, tt.url)
+		}
+	}
+
+	for _, raw := range []string{"", "example.com:0", "example.com:70000", "https://:443", "https://example.com:http"} {
+		if _, err := ParseTarget(raw); err == nil {
+			t.Errorf("ParseTarget(%q) accepted an invalid target", raw)
+		}
+	}
+}
+
+func TestTargetURL(t *testing.T) {
+	for target, want := range map[string]string{
+		"example.com":        "https://example.com",
+		"2001:db8::1":        "https://[2001:db8::1]",
+		"http://example.com": "http://example.com",
+	} {
+		if got := targetURL(target); got != want {
+			t.Errorf("targetURL(%q) = %q, want %q", target, got, want)
+		}
+	}
+}
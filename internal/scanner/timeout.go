@@ -0,0 +1,53 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// toolKillGrace is how long a timed-out tool gets to exit after SIGTERM
+// before it is killed outright
+const toolKillGrace = 5 * time.Second
+
+// ErrToolTimeout is returned when a tool is stopped for running past its
+// timeout. Whatever it printed before then is returned alongside it.
+var ErrToolTimeout = errors.New("tool timed out")
+
+// SetToolTimeout limits how long each external tool may run. Zero means
+// no limit.
+func (pm *PermissionManager) SetToolTimeout(timeout time.Duration) {
+	pm.toolTimeout = timeout
+}
+
+// runTimed runs name with args through the manager's runner, stopping it
+// once the tool timeout passes. Output is streamed to the progress
+// callback as it arrives, if one is set.
+func (pm *PermissionManager) runTimed(name string, args ...string) ([]byte, error) {
+	ctx := context.Background()
+	if pm.toolTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, pm.toolTimeout)
+		defer cancel()
+	}
+
+	var output []byte
+	var err error
+	if pm.progress != nil {
+		stream := &lineStream{progress: pm.progress}
+		err = pm.runner.RunContext(ctx, stream, name, args...)
+		stream.flush()
+		output = stream.output.Bytes()
+	} else {
+		var buf bytes.Buffer
+		err = pm.runner.RunContext(ctx, &buf, name, args...)
+		output = buf.Bytes()
+	}
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return output, fmt.Errorf("%s: %w after %s", name, ErrToolTimeout, pm.toolTimeout)
+	}
+	return output, err
+}
@@ -0,0 +1,61 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// hangingRunner prints a line, then runs until it is stopped
+type hangingRunner struct {
+	stopped bool
+}
+
+func (r *hangingRunner) Run(name string, args ...string) ([]byte, error) {
+	return nil, errors.New("unexpected untimed run")
+}
+
+func (r *hangingRunner) RunContext(ctx context.Context, out io.Writer, name string, args ...string) error {
+	io.WriteString(out, "Discovered open port 22/tcp\n")
+	<-ctx.Done()
+	r.stopped = true
+	return ctx.Err()
+}
+
+func TestRunTimedStopsHangingTool(t *testing.T) {
+	runner := &hangingRunner{}
+	pm := &PermissionManager{runner: runner}
+	pm.SetToolTimeout(20 * time.Millisecond)
+
+	output, err := pm.runTimed("nmap", "-sS", "example.com")
+	if !errors.Is(err, ErrToolTimeout) || !strings.Contains(err.Error(), "nmap") {
+		t.Errorf("runTimed error = %v, want ErrToolTimeout naming the tool", err)
+	}
+	if !runner.stopped {
+		t.Error("tool was not stopped")
+	}
+	if !strings.Contains(string(output), "22/tcp") {
+		t.Errorf("output %q, want the partial output kept", output)
+	}
+}
+
+func TestExecRunnerStopsOnTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	var out strings.Builder
+	start := time.Now()
+	err := execRunner{}.RunContext(ctx, &out, "sh", "-c", "echo started; exec sleep 30")
+	if err == nil {
+		t.Fatal("expected the command to be stopped")
+	}
+	if elapsed := time.Since(start); elapsed > toolKillGrace {
+		t.Errorf("stopped after %s, want it terminated promptly", elapsed)
+	}
+	if !strings.Contains(out.String(), "started") {
+		t.Errorf("output %q, want what it printed before the timeout", out.String())
+	}
+}
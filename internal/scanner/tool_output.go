@@ -0,0 +1,173 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+// ToolParser turns an external tool's raw output into findings for target
+type ToolParser func(target string, output []byte) []models.Finding
+
+// toolParsers maps tool names to their output parsers
+var toolParsers = map[string]ToolParser{
+	"nmap": parseNmapOutput,
+}
+
+// HasToolParser reports whether output from tool can be turned into findings
+func HasToolParser(tool string) bool {
+	_, ok := toolParsers[filepath.Base(tool)]
+	return ok
+}
+
+// ParseToolOutput routes output through the parser registered for tool and
+// post-processes the findings the same way scanner modules are. It reports
+// false when no parser is registered.
+func ParseToolOutput(tool string, target string, output []byte) ([]models.Finding, bool) {
+	parser, ok := toolParsers[filepath.Base(tool)]
+	if !ok {
+		return nil, false
+	}
+
+	findings := parser(target, output)
+	findings = enrichFindings(findings)
+	for i := range findings {
+		findings[i].EnsureFingerprint()
+	}
+	return findings, true
+}
+
+// RunToolFindings runs tool and parses its output into findings. Tools
+// that need root go through RunWithFallback, falling back to
+// UnprivilegedArgs without sudo; others run directly. The returned bool
+// reports whether the tool ran privileged. When the tool times out, the
+// findings parsed from its partial output are returned with ErrToolTimeout.
+func (pm *PermissionManager) RunToolFindings(
+	tool string,
+	purpose string,
+	target string,
+	args []string,
+	requiresRoot bool,
+) ([]models.Finding, bool, error) {
+	var output []byte
+	var privileged bool
+	var err error
+
+	if requiresRoot {
+		output, privileged, err = pm.RunWithFallback(tool, purpose, args, UnprivilegedArgs(tool, args))
+	} else if err = pm.ValidateCommand(tool, args); err == nil {
+		output, err = pm.runTimed(tool, args...)
+	}
+	if err != nil && !errors.Is(err, ErrToolTimeout) {
+		return nil, privileged, err
+	}
+
+	findings, ok := ParseToolOutput(tool, target, output)
+	if !ok {
+		return nil, privileged, fmt.Errorf("no output parser for %s", tool)
+	}
+	return findings, privileged, err
+}
+
+// UnprivilegedArgs adapts args for running tool without root: for nmap, SYN
+// scans become TCP connect scans and root-only options are dropped
+func UnprivilegedArgs(tool string, args []string) []string {
+	if filepath.Base(tool) != "nmap" {
+		return args
+	}
+
+	adapted := make([]string, 0, len(args))
+	for _, arg := range args {
+		switch arg {
+		case "-sS":
+			adapted = append(adapted, "-sT")
+		case "-sU", "-O", "--osscan-guess":
+			// need raw sockets, no unprivileged equivalent
+		default:
+			adapted = append(adapted, arg)
+		}
+	}
+	return adapted
+}
+
+var (
+	// nmapHost matches "Nmap scan report for example.com (93.184.216.34)"
+	nmapHost = regexp.MustCompile(`^Nmap scan report for (\S+)`)
+	// nmapPort matches "80/tcp open http nginx 1.25.3"
+	nmapPort = regexp.MustCompile(`^(\d+)/(tcp|udp)\s+(open|open\|filtered)\s+(\S+)(?:\s+(.+))?$`)
+)
+
+// parseNmapOutput reads nmap's normal output into open-port findings, in
+// the same shape PortScanModule produces
+func parseNmapOutput(target string, output []byte) []models.Finding {
+	findings := make([]models.Finding, 0)
+
+	host := target
+	if parsed, err := ParseTarget(target); err == nil {
+		host = parsed.Host
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		line = strings.TrimSpace(line)
+
+		if match := nmapHost.FindStringSubmatch(line); match != nil {
+			host = match[1]
+			continue
+		}
+
+		match := nmapPort.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		port, err := strconv.Atoi(match[1])
+		if err != nil {
+			continue
+		}
+		findings = append(findings, openPortFinding(host, port, match[2], match[3], match[4], strings.TrimSpace(match[5]), line))
+	}
+
+	return findings
+}
+
+// openPortFinding builds the finding for a port nmap reported in state
+// (open or open|filtered), with the output line it came from as evidence
+func openPortFinding(host string, port int, protocol string, state string, service string, version string, evidence string) models.Finding {
+	description := fmt.Sprintf("%s port %d is %s on %s (%s)", strings.ToUpper(protocol), port, state, host, service)
+	if version != "" {
+		description += ": " + version
+	}
+
+	// open|filtered means nmap got no answer either way
+	confidence := models.ConfidenceHigh
+	if state != "open" {
+		confidence = models.ConfidenceLow
+	}
+
+	finding := models.Finding{
+		ID:          uuid.New().String(),
+		Type:        "open-port",
+		Severity:    "info",
+		Confidence:  confidence,
+		Title:       fmt.Sprintf("Open %s port %d", strings.ToUpper(protocol), port),
+		Description: description,
+		Evidence:    evidence,
+		Location:    Target{Host: host}.Address(port),
+		Tags:        []string{"ports", "nmap"},
+		Timestamp:   time.Now(),
+	}
+	finding.SetMeta(models.MetaHost, host)
+	finding.SetMeta(models.MetaPort, strconv.Itoa(port))
+	finding.SetMeta(models.MetaProtocol, protocol)
+	finding.SetMeta(models.MetaService, service)
+	finding.SetMeta(models.MetaVersion, version)
+	return finding
+}
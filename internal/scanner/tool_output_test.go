@@ -0,0 +1,96 @@
+package scanner
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+const nmapSample = `Starting Nmap 7.94 ( https://nmap.org ) at 2026-03-01 12:00 UTC
+Nmap scan report for example.com (93.184.216.34)
+Host is up (0.012s latency).
+Not shown: 997 filtered tcp ports (no-response)
+PORT    STATE         SERVICE  VERSION
+22/tcp  closed        ssh
+80/tcp  open          http     nginx 1.25.3
+443/tcp open          https
+53/udp  open|filtered domain
+
+Nmap done: 1 IP address (1 host up) scanned in 4.20 seconds
+`
+
+func TestParseNmapOutput(t *testing.T) {
+	findings := parseNmapOutput("https://example.com", []byte(nmapSample))
+	if len(findings) != 3 {
+		t.Fatalf("got %d findings, want 3 open ports: %+v", len(findings), findings)
+	}
+
+	http := findings[0]
+	if http.Type != "open-port" || http.Title != "Open TCP port 80" || http.Location != "example.com:80" {
+		t.Errorf("port 80 finding = %+v", http)
+	}
+	if http.Description != "TCP port 80 is open on example.com (http): nginx 1.25.3" {
+		t.Errorf("description = %q", http.Description)
+	}
+	if got := strings.Join(http.ReportMetadata(), ","); got != "host=example.com,port=80,protocol=tcp,service=http,version=nginx 1.25.3" {
+		t.Errorf("metadata = %s", got)
+	}
+	if findings[2].Title != "Open UDP port 53" || findings[2].Confidence != models.ConfidenceLow {
+		t.Errorf("open|filtered udp finding = %+v, want low confidence", findings[2])
+	}
+	if http.Confidence != models.ConfidenceHigh {
+		t.Errorf("open port confidence = %q, want high", http.Confidence)
+	}
+}
+
+func TestParseNmapOutputIPv6(t *testing.T) {
+	output := "Nmap scan report for 2001:db8::1\n80/tcp open http\n"
+	findings := parseNmapOutput("2001:db8::1", []byte(output))
+	if len(findings) != 1 || findings[0].Location != "[2001:db8::1]:80" {
+		t.Errorf("findings = %+v, want a bracketed IPv6 location", findings)
+	}
+}
+
+func TestParseToolOutput(t *testing.T) {
+	findings, ok := ParseToolOutput("/usr/bin/nmap", "example.com", []byte(nmapSample))
+	if !ok || len(findings) != 3 {
+		t.Fatalf("ParseToolOutput = %d findings, %v", len(findings), ok)
+	}
+	if findings[0].Fingerprint == "" {
+		t.Error("findings not fingerprinted")
+	}
+
+	if _, ok := ParseToolOutput("whatweb", "example.com", nil); ok || HasToolParser("whatweb") {
+		t.Error("whatweb reported as parseable")
+	}
+}
+
+func TestUnprivilegedArgs(t *testing.T) {
+	got := UnprivilegedArgs("nmap", []string{"-sS", "-sU", "-O", "-p", "1-1000", "example.com"})
+	if strings.Join(got, " ") != "-sT -p 1-1000 example.com" {
+		t.Errorf("UnprivilegedArgs = %q", got)
+	}
+	if got := UnprivilegedArgs("masscan", []string{"-sS"}); got[0] != "-sS" {
+		t.Errorf("non-nmap args changed: %q", got)
+	}
+}
+
+func TestRunToolFindingsUnprivileged(t *testing.T) {
+	pm, _ := testPermissionManager(t)
+	pm.SetAllowedTools(nil)
+	runner := &fakeRunner{outputs: map[string]string{"nmap": nmapSample}}
+	pm.runner = runner
+
+	findings, privileged, err := pm.RunToolFindings("nmap", "port scan", "example.com", []string{"-sT", "example.com"}, false)
+	if err != nil || privileged || len(findings) != 3 {
+		t.Errorf("RunToolFindings = %d findings, privileged %v, %v", len(findings), privileged, err)
+	}
+	if len(runner.calls) != 1 || runner.calls[0] != "nmap -sT example.com" {
+		t.Errorf("calls = %q", runner.calls)
+	}
+
+	if _, _, err := pm.RunToolFindings("bash", "shell", "example.com", []string{"-c", "id"}, false); err == nil {
+		t.Error("ran a tool outside the allowlist")
+	}
+}
@@ -0,0 +1,227 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+// Verifier re-tests the findings of a stored scan to confirm remediation.
+// Each finding is checked by re-running only the probe that produced it,
+// against the same location, instead of rescanning the whole target.
+type Verifier struct {
+	client *http.Client
+	hosts  *hostLimiter
+
+	// headerChecks caches the header probe per URL, since a page usually
+	// has several missing-header findings
+	headerChecks map[string]headerCheck
+
+	// scannedPorts lists the ports each host had open in the original
+	// scan, probed to tell a closed port from a host that is down
+	scannedPorts map[string][]int
+	// portChecks caches TCP connects by address
+	portChecks map[string]bool
+}
+
+// headerCheck is the outcome of one header probe
+type headerCheck struct {
+	findings []models.Finding
+	err      error
+}
+
+// NewVerifier creates a verifier whose HTTP probes use config's proxy,
+// headers and credentials, as a scan would
+func NewVerifier(config models.ScanConfig) (*Verifier, error) {
+	client, err := newHTTPClient(config)
+	if err != nil {
+		return nil, err
+	}
+	return &Verifier{
+		client:       client,
+		hosts:        newHostLimiter(config.ConcurrencyPerHost),
+		headerChecks: make(map[string]headerCheck),
+		portChecks:   make(map[string]bool),
+	}, nil
+}
+
+// Verify re-tests every active finding of result, one at a time. Findings
+// left when ctx is cancelled are marked inconclusive.
+func (v *Verifier) Verify(ctx context.Context, result *models.ScanResult) *models.VerificationResult {
+	verification := &models.VerificationResult{
+		ScanID:        result.ID,
+		Target:        result.Target,
+		StartTime:     time.Now(),
+		Verifications: make([]models.Verification, 0, len(result.Findings)),
+	}
+
+	v.scannedPorts = make(map[string][]int)
+	for _, finding := range result.Findings {
+		if finding.Type != "open-port" {
+			continue
+		}
+		if host, port, ok := findingAddress(finding); ok {
+			host = strings.ToLower(host)
+			v.scannedPorts[host] = append(v.scannedPorts[host], port)
+		}
+	}
+
+	for _, finding := range models.ActiveFindings(result.Findings) {
+		status, detail := v.verify(ctx, finding)
+		verification.Verifications = append(verification.Verifications, models.Verification{
+			Finding: finding,
+			Status:  status,
+			Detail:  detail,
+		})
+	}
+
+	verification.EndTime = time.Now()
+	return verification
+}
+
+// verify re-tests one finding and returns its status with the reason
+func (v *Verifier) verify(ctx context.Context, finding models.Finding) (string, string) {
+	if ctx.Err() != nil {
+		return models.VerificationInconclusive, "verification was interrupted"
+	}
+
+	switch finding.Type {
+	case "security-header":
+		return v.verifyHeader(ctx, finding)
+	case "open-port":
+		return v.verifyPort(ctx, finding)
+	case "subdomain":
+		return verifySubdomain(ctx, finding)
+	case "default-credentials":
+		// Credentials are never tried, so only the exposure can be re-tested
+		if finding.Metadata[models.MetaPort] == "" {
+			return models.VerificationInconclusive, "the product wasn't tied to a port, so there is nothing to re-test"
+		}
+		status, detail := v.verifyPort(ctx, finding)
+		if status == models.VerificationStillPresent {
+			detail += "; the default credentials were not tried"
+		}
+		return status, detail
+	}
+
+	return models.VerificationInconclusive, fmt.Sprintf("%q findings have no re-test; run a new scan to confirm", finding.Type)
+}
+
+// verifyHeader requests the finding's URL again and checks whether the
+// header is still missing
+func (v *Verifier) verifyHeader(ctx context.Context, finding models.Finding) (string, string) {
+	check, ok := v.headerChecks[finding.Location]
+	if !ok {
+		check.findings, check.err = runModule(ctx, &HeaderSecurityModule{client: v.client}, finding.Location)
+		v.headerChecks[finding.Location] = check
+	}
+	if check.err != nil {
+		return models.VerificationInconclusive, check.err.Error()
+	}
+
+	for _, current := range check.findings {
+		if strings.EqualFold(current.Title, finding.Title) {
+			return models.VerificationStillPresent, current.Evidence
+		}
+	}
+	return models.VerificationFixed, fmt.Sprintf("%s now sets the header", finding.Location)
+}
+
+// verifyPort connects to the finding's port again. A closed port only
+// counts as fixed when the host is shown to be up, by another port from
+// the original scan still answering; otherwise the host may simply be
+// down, and the result is inconclusive. So is a host that no longer
+// resolves.
+func (v *Verifier) verifyPort(ctx context.Context, finding models.Finding) (string, string) {
+	host, port, ok := findingAddress(finding)
+	if !ok {
+		return models.VerificationInconclusive, fmt.Sprintf("no host and port in %q", finding.Location)
+	}
+	if protocol := finding.Metadata[models.MetaProtocol]; protocol != "" && !strings.EqualFold(protocol, "tcp") {
+		return models.VerificationInconclusive, fmt.Sprintf("%s ports can't be re-tested, only TCP", strings.ToUpper(protocol))
+	}
+	if _, err := net.DefaultResolver.LookupHost(ctx, host); err != nil {
+		return models.VerificationInconclusive, fmt.Sprintf("%s did not resolve: %v", host, err)
+	}
+
+	address := Target{Host: host}.Address(port)
+	open, err := v.portOpen(ctx, host, port)
+	if err != nil {
+		return models.VerificationInconclusive, err.Error()
+	}
+	if open {
+		return models.VerificationStillPresent, fmt.Sprintf("TCP connect to %s succeeded", address)
+	}
+
+	for _, other := range v.scannedPorts[strings.ToLower(host)] {
+		if other == port {
+			continue
+		}
+		if open, err := v.portOpen(ctx, host, other); err == nil && open {
+			return models.VerificationFixed, fmt.Sprintf("TCP connect to %s failed while port %d on the host still answers", address, other)
+		}
+	}
+	return models.VerificationInconclusive, fmt.Sprintf("TCP connect to %s failed, but no other port from the original scan answered, so the host may be down", address)
+}
+
+// portOpen connects to host:port, caching the outcome
+func (v *Verifier) portOpen(ctx context.Context, host string, port int) (bool, error) {
+	address := Target{Host: host}.Address(port)
+	if open, ok := v.portChecks[address]; ok {
+		return open, nil
+	}
+
+	module := &PortScanModule{ports: []int{port}, threads: 1, hosts: v.hosts}
+	findings, err := runModule(ctx, module, host)
+	if err != nil {
+		return false, err
+	}
+	v.portChecks[address] = len(findings) > 0
+	return len(findings) > 0, nil
+}
+
+// verifySubdomain looks the subdomain up again. Only a definite "no such
+// host" counts as fixed; other DNS errors are inconclusive.
+func verifySubdomain(ctx context.Context, finding models.Finding) (string, string) {
+	host := finding.Metadata[models.MetaHost]
+	if host == "" {
+		host = finding.Location
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := net.DefaultResolver.LookupHost(lookupCtx, host)
+	var dnsErr *net.DNSError
+	switch {
+	case err == nil:
+		return models.VerificationStillPresent, fmt.Sprintf("DNS lookup of %s succeeded", host)
+	case errors.As(err, &dnsErr) && dnsErr.IsNotFound:
+		return models.VerificationFixed, fmt.Sprintf("%s no longer resolves", host)
+	default:
+		return models.VerificationInconclusive, fmt.Sprintf("DNS lookup of %s failed: %v", host, err)
+	}
+}
+
+// findingAddress returns the host and port a finding was reported on,
+// from its metadata or else its host:port location
+func findingAddress(finding models.Finding) (string, int, bool) {
+	host := finding.Metadata[models.MetaHost]
+	port, err := strconv.Atoi(finding.Metadata[models.MetaPort])
+	if host != "" && err == nil {
+		return host, port, true
+	}
+
+	parsed, err := ParseTarget(finding.Location)
+	if err != nil || parsed.Port == 0 {
+		return "", 0, false
+	}
+	return parsed.Host, parsed.Port, true
+}
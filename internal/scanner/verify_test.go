@@ -0,0 +1,126 @@
+package scanner
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+// listen opens a local TCP port, returning it and a func to close it
+func listen(t *testing.T) (int, func()) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	return listener.Addr().(*net.TCPAddr).Port, func() { listener.Close() }
+}
+
+func portFinding(port int) models.Finding {
+	finding := models.Finding{
+		ID:       "port-" + strconv.Itoa(port),
+		Type:     "open-port",
+		Severity: "info",
+		Title:    "Open TCP port " + strconv.Itoa(port),
+		Location: net.JoinHostPort("127.0.0.1", strconv.Itoa(port)),
+	}
+	finding.SetMeta(models.MetaHost, "127.0.0.1")
+	finding.SetMeta(models.MetaPort, strconv.Itoa(port))
+	finding.SetMeta(models.MetaProtocol, "tcp")
+	return finding
+}
+
+func verifyStatuses(t *testing.T, result *models.ScanResult) map[string]string {
+	t.Helper()
+	verifier, err := NewVerifier(models.ScanConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	statuses := make(map[string]string)
+	for _, v := range verifier.Verify(context.Background(), result).Verifications {
+		statuses[v.Finding.ID] = v.Status
+	}
+	return statuses
+}
+
+func TestVerifyClosedPortOnLiveHostIsFixed(t *testing.T) {
+	stillOpen, _ := listen(t)
+	fixed, closeFixed := listen(t)
+	closeFixed()
+
+	statuses := verifyStatuses(t, &models.ScanResult{
+		ID:       "scan-1",
+		Target:   "127.0.0.1",
+		Findings: []models.Finding{portFinding(stillOpen), portFinding(fixed)},
+	})
+
+	if got := statuses["port-"+strconv.Itoa(stillOpen)]; got != models.VerificationStillPresent {
+		t.Errorf("open port: %s, want %s", got, models.VerificationStillPresent)
+	}
+	if got := statuses["port-"+strconv.Itoa(fixed)]; got != models.VerificationFixed {
+		t.Errorf("closed port on a live host: %s, want %s", got, models.VerificationFixed)
+	}
+}
+
+func TestVerifyDownHostIsInconclusive(t *testing.T) {
+	first, closeFirst := listen(t)
+	second, closeSecond := listen(t)
+	closeFirst()
+	closeSecond()
+
+	statuses := verifyStatuses(t, &models.ScanResult{
+		ID:       "scan-1",
+		Target:   "127.0.0.1",
+		Findings: []models.Finding{portFinding(first), portFinding(second)},
+	})
+
+	for id, status := range statuses {
+		if status != models.VerificationInconclusive {
+			t.Errorf("%s on a host with nothing answering: %s, want %s", id, status, models.VerificationInconclusive)
+		}
+	}
+}
+
+func TestVerifyHeader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Frame-Options", "DENY")
+	}))
+	defer server.Close()
+
+	statuses := verifyStatuses(t, &models.ScanResult{
+		ID:     "scan-1",
+		Target: server.URL,
+		Findings: []models.Finding{
+			{ID: "xfo", Type: "security-header", Title: "Missing X-Frame-Options header", Location: server.URL},
+			{ID: "csp", Type: "security-header", Title: "Missing Content-Security-Policy header", Location: server.URL},
+			{ID: "other", Type: "mixed-content", Title: "Mixed content", Location: server.URL},
+		},
+	})
+
+	want := map[string]string{
+		"xfo":   models.VerificationFixed,
+		"csp":   models.VerificationStillPresent,
+		"other": models.VerificationInconclusive,
+	}
+	for id, status := range want {
+		if statuses[id] != status {
+			t.Errorf("%s: %s, want %s", id, statuses[id], status)
+		}
+	}
+}
+
+func TestVerifyUDPPortIsInconclusive(t *testing.T) {
+	finding := portFinding(53)
+	finding.SetMeta(models.MetaProtocol, "udp")
+
+	statuses := verifyStatuses(t, &models.ScanResult{ID: "scan-1", Target: "127.0.0.1", Findings: []models.Finding{finding}})
+	if got := statuses[finding.ID]; got != models.VerificationInconclusive {
+		t.Errorf("udp port: %s, want %s", got, models.VerificationInconclusive)
+	}
+}
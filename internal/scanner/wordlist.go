@@ -0,0 +1,39 @@
+package scanner
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LoadWordlist reads a custom brute-force wordlist: one entry per line,
+// with blank lines and # comments ignored and duplicates dropped. A file
+// with no entries is an error, since it would silently test nothing.
+func LoadWordlist(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open wordlist: %w", err)
+	}
+	defer file.Close()
+
+	words := make([]string, 0)
+	seen := make(map[string]bool)
+	lines := bufio.NewScanner(file)
+	for lines.Scan() {
+		word := strings.ToLower(strings.TrimSpace(lines.Text()))
+		if word == "" || strings.HasPrefix(word, "#") || seen[word] {
+			continue
+		}
+		seen[word] = true
+		words = append(words, word)
+	}
+	if err := lines.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read wordlist %s: %w", path, err)
+	}
+	if len(words) == 0 {
+		return nil, fmt.Errorf("wordlist %s has no entries", path)
+	}
+
+	return words, nil
+}
@@ -0,0 +1,38 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadWordlist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.txt")
+	content := "# common names\nwww\n\n  API \napi\nstaging\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	words, err := LoadWordlist(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(words, ","); got != "www,api,staging" {
+		t.Errorf("LoadWordlist = %s, want comments, blanks and duplicates skipped", got)
+	}
+}
+
+func TestLoadWordlistErrors(t *testing.T) {
+	dir := t.TempDir()
+	empty := filepath.Join(dir, "empty.txt")
+	if err := os.WriteFile(empty, []byte("# nothing\n\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, path := range map[string]string{"missing": filepath.Join(dir, "missing.txt"), "empty": empty} {
+		if _, err := LoadWordlist(path); err == nil {
+			t.Errorf("%s wordlist loaded without error", name)
+		}
+	}
+}
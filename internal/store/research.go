@@ -0,0 +1,83 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+// ErrResearchNotFound is returned when a research run ID has no stored run
+var ErrResearchNotFound = errors.New("research run not found")
+
+// SaveResearchRun writes a research run to research/<id>.json, replacing
+// the previous copy atomically
+func (s *Store) SaveResearchRun(run *models.ResearchRun) error {
+	if run.ID == "" {
+		return fmt.Errorf("research run has no ID")
+	}
+
+	dir := s.researchDir()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create research directory: %w", err)
+	}
+
+	run.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal research run: %w", err)
+	}
+
+	path := filepath.Join(dir, run.ID+".json")
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write research run: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to write research run: %w", err)
+	}
+	return nil
+}
+
+// LoadResearchRun reads a research run by ID. A unique ID prefix is also
+// accepted.
+func (s *Store) LoadResearchRun(id string) (*models.ResearchRun, error) {
+	id = strings.TrimSpace(id)
+	if id == "" || strings.ContainsAny(id, `/\`) {
+		return nil, fmt.Errorf("invalid research run ID %q", id)
+	}
+
+	path := filepath.Join(s.researchDir(), id+".json")
+	if _, err := os.Stat(path); err != nil {
+		matches, _ := filepath.Glob(filepath.Join(s.researchDir(), id+"*.json"))
+		switch len(matches) {
+		case 0:
+			return nil, fmt.Errorf("%w: %s", ErrResearchNotFound, id)
+		case 1:
+			path = matches[0]
+		default:
+			return nil, fmt.Errorf("research run ID prefix %q is ambiguous (%d matches)", id, len(matches))
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read research run %s: %w", id, err)
+	}
+
+	var run models.ResearchRun
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("failed to parse research run %s: %w", id, err)
+	}
+	return &run, nil
+}
+
+// researchDir holds research runs, apart from the scan results List reads
+func (s *Store) researchDir() string {
+	return filepath.Join(s.dir, "research")
+}
@@ -0,0 +1,67 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+func TestResearchRunRoundTrip(t *testing.T) {
+	st := NewWithDir(filepath.Join(t.TempDir(), "scans"))
+	run := &models.ResearchRun{
+		ID:         "7c1d0e2a-0000-4000-8000-000000000001",
+		Target:     "example.com",
+		Model:      "claude-sonnet-4.5",
+		Thinking:   "low",
+		Findings:   []models.Finding{{Severity: "medium", Title: "Open TCP port 8080"}},
+		Iterations: []models.ResearchIteration{{Number: 1, Phase: "Initial Analysis", NewHypotheses: []string{"debug endpoint"}}},
+		Status:     models.ResearchFailed,
+	}
+	if err := st.SaveResearchRun(run); err != nil {
+		t.Fatalf("SaveResearchRun: %v", err)
+	}
+	if run.UpdatedAt.IsZero() {
+		t.Error("UpdatedAt not set")
+	}
+
+	loaded, err := st.LoadResearchRun("7c1d")
+	if err != nil {
+		t.Fatalf("LoadResearchRun(prefix): %v", err)
+	}
+	if loaded.Model != run.Model || loaded.Status != models.ResearchFailed || len(loaded.Findings) != 1 ||
+		len(loaded.Iterations) != 1 || loaded.Iterations[0].NewHypotheses[0] != "debug endpoint" {
+		t.Errorf("loaded %+v, want the saved run", loaded)
+	}
+
+	// Research runs live apart from scan results
+	if scans, err := st.List(); err != nil || len(scans) != 0 {
+		t.Errorf("List = %d scans, %v; want research runs left out", len(scans), err)
+	}
+	if _, err := os.Stat(filepath.Join(st.Dir(), "research", run.ID+".json.tmp")); !os.IsNotExist(err) {
+		t.Error("temporary file left behind")
+	}
+}
+
+func TestLoadResearchRunErrors(t *testing.T) {
+	st := NewWithDir(filepath.Join(t.TempDir(), "scans"))
+	for _, id := range []string{"ab01", "ab02"} {
+		if err := st.SaveResearchRun(&models.ResearchRun{ID: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := st.LoadResearchRun("missing"); !errors.Is(err, ErrResearchNotFound) {
+		t.Errorf("missing run: %v, want ErrResearchNotFound", err)
+	}
+	for _, id := range []string{"ab", "", "../ab01"} {
+		if _, err := st.LoadResearchRun(id); err == nil {
+			t.Errorf("LoadResearchRun(%q) succeeded", id)
+		}
+	}
+	if err := st.SaveResearchRun(&models.ResearchRun{}); err == nil {
+		t.Error("run without an ID saved")
+	}
+}
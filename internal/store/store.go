@@ -0,0 +1,210 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+// ErrNotFound is returned when a scan ID has no stored result
+var ErrNotFound = errors.New("scan not found")
+
+// Store persists scan results as JSON files, one per scan
+type Store struct {
+	dir string
+}
+
+// New creates a store rooted at ~/.shadow/scans
+func New() (*Store, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	return NewWithDir(filepath.Join(home, ".shadow", "scans")), nil
+}
+
+// NewWithDir creates a store rooted at the given directory
+func NewWithDir(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Dir returns the directory results are stored in
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Save writes a scan result, replacing any previous copy with the same ID
+func (s *Store) Save(result *models.ScanResult) error {
+	if result.ID == "" {
+		return fmt.Errorf("scan result has no ID")
+	}
+
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create store directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal scan result: %w", err)
+	}
+
+	// Write to a temp file first so a crash never leaves a truncated result
+	path := s.path(result.ID)
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write scan result: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to write scan result: %w", err)
+	}
+
+	return s.saveEvidence(path, result.FullEvidence)
+}
+
+// saveEvidence writes untruncated evidence to <id>.evidence/<fingerprint>.txt
+func (s *Store) saveEvidence(resultPath string, evidence map[string]string) error {
+	if len(evidence) == 0 {
+		return nil
+	}
+
+	dir := evidenceDir(resultPath)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create evidence directory: %w", err)
+	}
+
+	for fingerprint, text := range evidence {
+		if err := os.WriteFile(filepath.Join(dir, fingerprint+".txt"), []byte(text), 0600); err != nil {
+			return fmt.Errorf("failed to write evidence: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// LoadEvidence returns the full evidence of a finding whose evidence was
+// truncated in the stored result
+func (s *Store) LoadEvidence(id string, fingerprint string) (string, error) {
+	path, err := s.resolve(id)
+	if err != nil {
+		return "", err
+	}
+	if fingerprint == "" || strings.ContainsAny(fingerprint, `/\.`) {
+		return "", fmt.Errorf("invalid fingerprint %q", fingerprint)
+	}
+
+	data, err := os.ReadFile(filepath.Join(evidenceDir(path), fingerprint+".txt"))
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("%w: no stored evidence for %s", ErrNotFound, fingerprint)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read evidence: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// Load reads a scan result by ID. A unique ID prefix is also accepted.
+func (s *Store) Load(id string) (*models.ScanResult, error) {
+	path, err := s.resolve(id)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scan %s: %w", id, err)
+	}
+
+	var result models.ScanResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse scan %s: %w", id, err)
+	}
+
+	return &result, nil
+}
+
+// List returns all stored scan results, newest first
+func (s *Store) List() ([]*models.ScanResult, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*models.ScanResult{}, nil
+		}
+		return nil, fmt.Errorf("failed to read store directory: %w", err)
+	}
+
+	results := make([]*models.ScanResult, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		result, err := s.Load(strings.TrimSuffix(entry.Name(), ".json"))
+		if err != nil {
+			continue // skip unreadable results rather than failing the listing
+		}
+		results = append(results, result)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].StartTime.After(results[j].StartTime)
+	})
+
+	return results, nil
+}
+
+// Latest returns the most recent stored scan of a target
+func (s *Store) Latest(target string) (*models.ScanResult, error) {
+	results, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	// List is newest first, so the first match is the latest scan
+	for _, result := range results {
+		if result.Target == target {
+			return result, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: no scan of %s", ErrNotFound, target)
+}
+
+// resolve maps a scan ID (or unique prefix) to its file path
+func (s *Store) resolve(id string) (string, error) {
+	id = strings.TrimSpace(id)
+	if id == "" || strings.ContainsAny(id, `/\`) {
+		return "", fmt.Errorf("invalid scan ID %q", id)
+	}
+
+	path := s.path(id)
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(s.dir, id+"*.json"))
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("%w: %s", ErrNotFound, id)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("scan ID prefix %q is ambiguous (%d matches)", id, len(matches))
+	}
+}
+
+func (s *Store) path(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+// evidenceDir returns the directory holding a result's full evidence
+func evidenceDir(resultPath string) string {
+	return strings.TrimSuffix(resultPath, ".json") + ".evidence"
+}
@@ -0,0 +1,137 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kumaraguru1735/shadow/pkg/models"
+)
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	st := NewWithDir(filepath.Join(t.TempDir(), "scans"))
+	result := &models.ScanResult{
+		ID:        "3f2a9c1e-0000-4000-8000-000000000001",
+		Target:    "example.com",
+		StartTime: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		Findings:  []models.Finding{{ID: "f-1", Type: "open-port", Severity: "info", Title: "Open TCP port 443"}},
+	}
+	if err := st.Save(result); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := st.Load(result.ID)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if loaded.Target != result.Target || !loaded.StartTime.Equal(result.StartTime) || len(loaded.Findings) != 1 || loaded.Findings[0].Title != "Open TCP port 443" {
+		t.Errorf("loaded %+v, want the saved result", loaded)
+	}
+
+	// A unique prefix is enough
+	if byPrefix, err := st.Load("3f2a"); err != nil || byPrefix.ID != result.ID {
+		t.Errorf("Load(prefix) = %v, %v", byPrefix, err)
+	}
+
+	if _, err := os.Stat(filepath.Join(st.Dir(), result.ID+".json.tmp")); !os.IsNotExist(err) {
+		t.Error("temporary file left behind")
+	}
+}
+
+func TestNotesSurviveReload(t *testing.T) {
+	st := NewWithDir(filepath.Join(t.TempDir(), "scans"))
+	result := &models.ScanResult{ID: "scan-1", Target: "example.com", Findings: []models.Finding{{ID: "f-1", Title: "Open TCP port 22"}}}
+	result.Findings[0].AddNote("alice", "verified exploitable")
+	if err := st.Save(result); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := st.Load(result.ID)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if notes := loaded.Findings[0].Notes; len(notes) != 1 || notes[0].Author != "alice" || notes[0].Text != "verified exploitable" || notes[0].Timestamp.IsZero() {
+		t.Errorf("notes = %+v, want the saved note", notes)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	st := NewWithDir(t.TempDir())
+	st.Save(&models.ScanResult{ID: "abc-1"})
+	st.Save(&models.ScanResult{ID: "abc-2"})
+
+	if _, err := st.Load("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Load(missing) error = %v, want ErrNotFound", err)
+	}
+	if _, err := st.Load("abc"); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("Load(ambiguous prefix) error = %v", err)
+	}
+	if _, err := st.Load("../abc-1"); err == nil || !strings.Contains(err.Error(), "invalid scan ID") {
+		t.Errorf("Load(path) error = %v", err)
+	}
+	if err := st.Save(&models.ScanResult{}); err == nil {
+		t.Error("saved a result without an ID")
+	}
+}
+
+func TestListNewestFirst(t *testing.T) {
+	st := NewWithDir(t.TempDir())
+	if results, err := st.List(); err != nil || len(results) != 0 {
+		t.Errorf("List(empty) = %v, %v", results, err)
+	}
+
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	st.Save(&models.ScanResult{ID: "old", StartTime: day})
+	st.Save(&models.ScanResult{ID: "new", StartTime: day.AddDate(0, 0, 1)})
+	os.WriteFile(filepath.Join(st.Dir(), "broken.json"), []byte("{"), 0600)
+
+	results, err := st.List()
+	if err != nil || len(results) != 2 || results[0].ID != "new" {
+		t.Errorf("List = %v, %v; want new then old, skipping the unreadable file", results, err)
+	}
+}
+
+func TestLatest(t *testing.T) {
+	st := NewWithDir(t.TempDir())
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	st.Save(&models.ScanResult{ID: "a-old", Target: "example.com", StartTime: day})
+	st.Save(&models.ScanResult{ID: "a-new", Target: "example.com", StartTime: day.AddDate(0, 0, 1)})
+	st.Save(&models.ScanResult{ID: "b", Target: "other.example.com", StartTime: day.AddDate(0, 0, 2)})
+
+	if latest, err := st.Latest("example.com"); err != nil || latest.ID != "a-new" {
+		t.Errorf("Latest = %v, %v; want a-new", latest, err)
+	}
+	if _, err := st.Latest("never.example.com"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Latest(unscanned) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestFullEvidenceRoundTrip(t *testing.T) {
+	st := NewWithDir(t.TempDir())
+	full := strings.Repeat("<html>", 2000)
+	result := &models.ScanResult{
+		ID:           "scan-1",
+		Findings:     []models.Finding{{Fingerprint: "0123456789abcdef", Evidence: full[:100]}},
+		FullEvidence: map[string]string{"0123456789abcdef": full},
+	}
+	if err := st.Save(result); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, _ := st.Load("scan-1")
+	if loaded.FullEvidence != nil || loaded.Findings[0].Evidence != full[:100] {
+		t.Error("full evidence stored inside the result")
+	}
+	if evidence, err := st.LoadEvidence("scan-1", "0123456789abcdef"); err != nil || evidence != full {
+		t.Errorf("LoadEvidence = %d bytes, %v; want the full text", len(evidence), err)
+	}
+	if _, err := st.LoadEvidence("scan-1", "fedcba9876543210"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("LoadEvidence(untruncated) error = %v, want ErrNotFound", err)
+	}
+	if _, err := st.LoadEvidence("scan-1", "../scan-1"); err == nil || !strings.Contains(err.Error(), "invalid fingerprint") {
+		t.Errorf("LoadEvidence(path) error = %v", err)
+	}
+}
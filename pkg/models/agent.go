@@ -22,6 +22,13 @@ type AgentConfig struct {
 	UseCase      string
 }
 
+// ModelFallbacks maps a model to the substitute used when it can't be
+// started (access tier, deprecation). Opus falls back to Sonnet, Sonnet to Haiku.
+var ModelFallbacks = map[string]string{
+	"claude-opus-4.6":            "claude-sonnet-4.5-20250929",
+	"claude-sonnet-4.5-20250929": "claude-haiku-4.5",
+}
+
 // GetDefaultAgents returns the default agent configurations
 func GetDefaultAgents() []AgentConfig {
 	return []AgentConfig{
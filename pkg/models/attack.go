@@ -0,0 +1,178 @@
+package models
+
+import "strings"
+
+// MetaAttackTechniques lists the MITRE ATT&CK technique IDs a finding maps
+// to, comma-separated, e.g. "T1046,T1021.004"
+const MetaAttackTechniques = "attack.techniques"
+
+// AttackTechnique is a MITRE ATT&CK (Enterprise) technique
+type AttackTechnique struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Tactic string `json:"tactic"`
+}
+
+// AttackTactics lists the ATT&CK tactics findings map to, in kill chain order
+var AttackTactics = []string{
+	"Reconnaissance",
+	"Resource Development",
+	"Initial Access",
+	"Execution",
+	"Credential Access",
+	"Discovery",
+	"Lateral Movement",
+}
+
+// AttackTechniques are the techniques AttackRules refer to, by ID
+var AttackTechniques = map[string]AttackTechnique{
+	"T1021.001": {"T1021.001", "Remote Services: Remote Desktop Protocol", "Lateral Movement"},
+	"T1021.002": {"T1021.002", "Remote Services: SMB/Windows Admin Shares", "Lateral Movement"},
+	"T1021.004": {"T1021.004", "Remote Services: SSH", "Lateral Movement"},
+	"T1021.005": {"T1021.005", "Remote Services: VNC", "Lateral Movement"},
+	"T1046":     {"T1046", "Network Service Discovery", "Discovery"},
+	"T1059.007": {"T1059.007", "Command and Scripting Interpreter: JavaScript", "Execution"},
+	"T1078":     {"T1078", "Valid Accounts", "Initial Access"},
+	"T1078.001": {"T1078.001", "Valid Accounts: Default Accounts", "Initial Access"},
+	"T1110":     {"T1110", "Brute Force", "Credential Access"},
+	"T1133":     {"T1133", "External Remote Services", "Initial Access"},
+	"T1189":     {"T1189", "Drive-by Compromise", "Initial Access"},
+	"T1190":     {"T1190", "Exploit Public-Facing Application", "Initial Access"},
+	"T1557":     {"T1557", "Adversary-in-the-Middle", "Credential Access"},
+	"T1566.002": {"T1566.002", "Phishing: Spearphishing Link", "Initial Access"},
+	"T1584.001": {"T1584.001", "Compromise Infrastructure: Domains", "Resource Development"},
+	"T1590.002": {"T1590.002", "Gather Victim Network Information: DNS", "Reconnaissance"},
+	"T1592.002": {"T1592.002", "Gather Victim Host Information: Software", "Reconnaissance"},
+}
+
+// AttackRule maps findings to the ATT&CK techniques they enable an
+// attacker to use. A finding matches when every non-empty field matches,
+// and a field matches when any of its values does.
+type AttackRule struct {
+	Types      []string // finding types, exact
+	Services   []string // MetaService values, exact
+	Tags       []string // finding tags, exact
+	Keywords   []string // lowercase substrings of the title
+	Techniques []string // IDs in AttackTechniques
+}
+
+// AttackRules is the mapping table. Add rules (and their techniques to
+// AttackTechniques) to map new finding types.
+var AttackRules = []AttackRule{
+	{Types: []string{"open-port"}, Techniques: []string{"T1046"}},
+	{Services: []string{"ssh"}, Techniques: []string{"T1133", "T1021.004"}},
+	{Services: []string{"ms-wbt-server", "rdp"}, Techniques: []string{"T1133", "T1021.001"}},
+	{Services: []string{"microsoft-ds", "netbios-ssn", "smb"}, Techniques: []string{"T1021.002"}},
+	{Services: []string{"vnc"}, Techniques: []string{"T1133", "T1021.005"}},
+	{Services: []string{"telnet"}, Techniques: []string{"T1133"}},
+	{Types: []string{"default-credentials"}, Techniques: []string{"T1078.001"}},
+	{Tags: []string{"default-login"}, Techniques: []string{"T1078.001"}},
+	{Tags: []string{"panel"}, Techniques: []string{"T1078", "T1110"}},
+	{Keywords: []string{"admin panel", "login panel", "admin interface"}, Techniques: []string{"T1078", "T1110"}},
+	{Types: []string{"subdomain"}, Techniques: []string{"T1590.002"}},
+	{Tags: []string{"takeover"}, Techniques: []string{"T1584.001"}},
+	{Keywords: []string{"takeover"}, Techniques: []string{"T1584.001"}},
+	{Types: []string{"tls-certificate"}, Techniques: []string{"T1557"}},
+	{Keywords: []string{"strict-transport-security"}, Techniques: []string{"T1557"}},
+	{Types: []string{"mixed-content", "insecure-form"}, Techniques: []string{"T1557"}},
+	{Keywords: []string{"content-security-policy"}, Techniques: []string{"T1059.007", "T1189"}},
+	{Tags: []string{"xss"}, Techniques: []string{"T1059.007", "T1189"}},
+	{Tags: []string{"redirect"}, Techniques: []string{"T1566.002"}},
+	{Keywords: []string{"open redirect"}, Techniques: []string{"T1566.002"}},
+	{Tags: []string{"cve", "rce", "lfi", "sqli", "ssrf", "injection"}, Techniques: []string{"T1190"}},
+	{Types: []string{"http-protocol"}, Keywords: []string{"only http/1"}, Techniques: []string{"T1190"}},
+	{Tags: []string{"tech"}, Techniques: []string{"T1592.002"}},
+	{Keywords: []string{"version disclosure"}, Techniques: []string{"T1592.002"}},
+}
+
+// matches reports whether finding matches every non-empty field of r
+func (r AttackRule) matches(finding Finding) bool {
+	if len(r.Types) == 0 && len(r.Services) == 0 && len(r.Tags) == 0 && len(r.Keywords) == 0 {
+		return false
+	}
+	if len(r.Types) > 0 && !containsString(r.Types, finding.Type) {
+		return false
+	}
+	if len(r.Services) > 0 && !containsString(r.Services, strings.ToLower(finding.Metadata[MetaService])) {
+		return false
+	}
+	if len(r.Tags) > 0 {
+		tagged := false
+		for _, tag := range finding.Tags {
+			if containsString(r.Tags, strings.ToLower(tag)) {
+				tagged = true
+				break
+			}
+		}
+		if !tagged {
+			return false
+		}
+	}
+	if len(r.Keywords) > 0 {
+		title := strings.ToLower(finding.Title)
+		for _, keyword := range r.Keywords {
+			if strings.Contains(title, keyword) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchAttackTechniques returns the IDs of the techniques AttackRules map
+// finding to, without duplicates, in rule order
+func MatchAttackTechniques(finding Finding) []string {
+	ids := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, rule := range AttackRules {
+		if !rule.matches(finding) {
+			continue
+		}
+		for _, id := range rule.Techniques {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// MapAttackTechniques records the techniques each finding maps to in its
+// metadata. Returns how many findings were mapped.
+func MapAttackTechniques(findings []Finding) int {
+	mapped := 0
+	for i := range findings {
+		if ids := MatchAttackTechniques(findings[i]); len(ids) > 0 {
+			findings[i].SetMeta(MetaAttackTechniques, strings.Join(ids, ","))
+			mapped++
+		}
+	}
+	return mapped
+}
+
+// AttackTechniqueIDs returns the techniques recorded for the finding, or
+// for findings stored before the mapping existed, the ones it maps to now
+func (f *Finding) AttackTechniqueIDs() []string {
+	recorded, ok := f.Metadata[MetaAttackTechniques]
+	if !ok {
+		return MatchAttackTechniques(*f)
+	}
+	ids := make([]string, 0)
+	for _, id := range strings.Split(recorded, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
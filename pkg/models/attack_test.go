@@ -0,0 +1,63 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAttackRulesReferToKnownTechniques(t *testing.T) {
+	for i, rule := range AttackRules {
+		for _, id := range rule.Techniques {
+			technique, ok := AttackTechniques[id]
+			if !ok {
+				t.Errorf("rule %d refers to unknown technique %s", i, id)
+				continue
+			}
+			if !containsString(AttackTactics, technique.Tactic) {
+				t.Errorf("%s has tactic %q, which isn't in AttackTactics", id, technique.Tactic)
+			}
+		}
+	}
+}
+
+func TestMatchAttackTechniques(t *testing.T) {
+	ssh := Finding{Type: "open-port", Title: "Open TCP port 22"}
+	ssh.SetMeta(MetaService, "SSH")
+
+	tests := []struct {
+		name    string
+		finding Finding
+		want    string
+	}{
+		{"ssh port", ssh, "T1046,T1133,T1021.004"},
+		{"default credentials", Finding{Type: "default-credentials", Tags: []string{"default-credentials"}}, "T1078.001"},
+		{"nuclei panel", Finding{Type: "nuclei", Tags: []string{"nuclei", "Panel"}}, "T1078,T1110"},
+		{"keyword", Finding{Type: "security-header", Title: "Missing Strict-Transport-Security header"}, "T1557"},
+		{"type and keyword both needed", Finding{Type: "http-protocol", Title: "HTTP/2 supported"}, ""},
+		{"unmapped", Finding{Type: "configuration", Title: "Target Reachable"}, ""},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(MatchAttackTechniques(tt.finding), ","); got != tt.want {
+			t.Errorf("%s: %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAttackTechniqueIDs(t *testing.T) {
+	findings := []Finding{
+		{Type: "mixed-content", Title: "Mixed content"},
+		{Type: "configuration", Title: "Target Reachable"},
+	}
+	if mapped := MapAttackTechniques(findings); mapped != 1 {
+		t.Errorf("mapped %d findings, want 1", mapped)
+	}
+	if ids := findings[0].AttackTechniqueIDs(); strings.Join(ids, ",") != "T1557" {
+		t.Errorf("recorded techniques = %v", ids)
+	}
+
+	// Findings stored before the mapping existed are mapped on read
+	stored := Finding{Type: "subdomain", Title: "api.example.com"}
+	if ids := stored.AttackTechniqueIDs(); strings.Join(ids, ",") != "T1590.002" {
+		t.Errorf("techniques of an unmapped stored finding = %v", ids)
+	}
+}
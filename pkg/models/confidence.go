@@ -0,0 +1,81 @@
+package models
+
+import "strings"
+
+// Confidence is how certain a module or the AI is that a finding is real
+type Confidence string
+
+const (
+	ConfidenceHigh   Confidence = "high"
+	ConfidenceMedium Confidence = "medium"
+	ConfidenceLow    Confidence = "low"
+)
+
+// ConfidenceRank orders confidence levels so that higher is more certain.
+// Findings without a confidence rank as medium.
+func ConfidenceRank(confidence string) int {
+	switch strings.ToLower(strings.TrimSpace(confidence)) {
+	case "high":
+		return 3
+	case "low":
+		return 1
+	default:
+		return 2
+	}
+}
+
+// ApplyConfidence sets the confidence of findings whose title matches a
+// key of adjustments (case-insensitively), as returned by AI analysis.
+// Unknown levels are ignored. It returns how many findings changed.
+func ApplyConfidence(findings []Finding, adjustments map[string]string) int {
+	if len(adjustments) == 0 {
+		return 0
+	}
+
+	levels := make(map[string]Confidence, len(adjustments))
+	for title, level := range adjustments {
+		switch Confidence(strings.ToLower(strings.TrimSpace(level))) {
+		case ConfidenceHigh, ConfidenceMedium, ConfidenceLow:
+			levels[strings.ToLower(strings.TrimSpace(title))] = Confidence(strings.ToLower(strings.TrimSpace(level)))
+		}
+	}
+
+	changed := 0
+	for i := range findings {
+		level, ok := levels[strings.ToLower(strings.TrimSpace(findings[i].Title))]
+		if ok && findings[i].Confidence != level {
+			findings[i].Confidence = level
+			changed++
+		}
+	}
+	return changed
+}
+
+// MetaLikelyFalsePositive records the AI's reason for doubting a finding,
+// see MarkFalsePositives
+const MetaLikelyFalsePositive = "ai.false_positive"
+
+// FalsePositiveReason returns why the AI flagged the finding as a likely
+// false positive, or "" if it wasn't flagged
+func (f *Finding) FalsePositiveReason() string {
+	return f.Metadata[MetaLikelyFalsePositive]
+}
+
+// MarkFalsePositives flags the findings whose fingerprint has a reason in
+// verdicts: their confidence drops to low and the reason is recorded. The
+// findings stay in the result so reports can show them as flagged. It
+// returns how many findings were flagged.
+func MarkFalsePositives(findings []Finding, verdicts map[string]string) int {
+	flagged := 0
+	for i := range findings {
+		finding := &findings[i]
+		reason := strings.TrimSpace(verdicts[finding.EnsureFingerprint()])
+		if reason == "" {
+			continue
+		}
+		finding.Confidence = ConfidenceLow
+		finding.SetMeta(MetaLikelyFalsePositive, reason)
+		flagged++
+	}
+	return flagged
+}
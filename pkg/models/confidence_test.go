@@ -0,0 +1,58 @@
+package models
+
+import "testing"
+
+func TestConfidenceRank(t *testing.T) {
+	if ConfidenceRank(" HIGH ") != 3 || ConfidenceRank("low") != 1 {
+		t.Error("confidence levels ranked out of order")
+	}
+	if ConfidenceRank("") != ConfidenceRank("medium") || ConfidenceRank("certain") != ConfidenceRank("medium") {
+		t.Error("missing or unknown confidence should rank as medium")
+	}
+}
+
+func TestApplyConfidence(t *testing.T) {
+	findings := []Finding{
+		{Title: "Missing HSTS header", Confidence: ConfidenceHigh},
+		{Title: "Open TCP port 22", Confidence: ConfidenceHigh},
+		{Title: "Outdated nginx"},
+	}
+	changed := ApplyConfidence(findings, map[string]string{
+		"missing hsts header ": "LOW",
+		"Open TCP port 22":     "high",    // unchanged
+		"Outdated nginx":       "certain", // not a level
+		"Not a finding":        "low",
+	})
+
+	if changed != 1 {
+		t.Errorf("changed = %d, want 1", changed)
+	}
+	if findings[0].Confidence != ConfidenceLow || findings[1].Confidence != ConfidenceHigh || findings[2].Confidence != "" {
+		t.Errorf("confidences = %q, %q, %q", findings[0].Confidence, findings[1].Confidence, findings[2].Confidence)
+	}
+	if ApplyConfidence(findings, nil) != 0 {
+		t.Error("no adjustments should change nothing")
+	}
+}
+
+func TestMarkFalsePositives(t *testing.T) {
+	findings := []Finding{
+		{Type: "security-header", Title: "Missing HSTS header", Location: "https://example.com", Confidence: ConfidenceHigh},
+		{Type: "open-port", Title: "Open TCP port 22", Location: "example.com:22", Confidence: ConfidenceHigh},
+	}
+	flaggedFingerprint := findings[0].EnsureFingerprint()
+
+	flagged := MarkFalsePositives(findings, map[string]string{
+		flaggedFingerprint: " Served only over plain HTTP ",
+		"unknown":          "not a finding",
+	})
+	if flagged != 1 {
+		t.Errorf("flagged = %d, want 1", flagged)
+	}
+	if findings[0].Confidence != ConfidenceLow || findings[0].FalsePositiveReason() != "Served only over plain HTTP" {
+		t.Errorf("flagged finding = %+v", findings[0])
+	}
+	if findings[1].Confidence != ConfidenceHigh || findings[1].FalsePositiveReason() != "" {
+		t.Errorf("unflagged finding changed: %+v", findings[1])
+	}
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// DefaultMaxEvidenceLength caps evidence stored in findings and sent to AI
+const DefaultMaxEvidenceLength = 4096
+
+// MetaEvidenceTruncated records the original evidence size in bytes when
+// the evidence was truncated; the full text is kept in the evidence store
+const MetaEvidenceTruncated = "evidence.truncated"
+
+// TruncateEvidence keeps the head and tail of evidence longer than max
+// bytes, noting how much was omitted in between. It reports whether the
+// evidence was truncated.
+func TruncateEvidence(evidence string, max int) (string, bool) {
+	if max <= 0 || len(evidence) <= max {
+		return evidence, false
+	}
+
+	head := max * 2 / 3
+	tail := max - head
+	for head > 0 && !utf8.RuneStart(evidence[head]) {
+		head--
+	}
+	start := len(evidence) - tail
+	for start < len(evidence) && !utf8.RuneStart(evidence[start]) {
+		start++
+	}
+
+	omitted := start - head
+	return fmt.Sprintf("%s\n... [%d bytes omitted] ...\n%s", evidence[:head], omitted, evidence[start:]), true
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateEvidence(t *testing.T) {
+	if got, truncated := TruncateEvidence("short", 100); truncated || got != "short" {
+		t.Errorf("short evidence = %q, %v; want it unchanged", got, truncated)
+	}
+	if _, truncated := TruncateEvidence(strings.Repeat("x", 100), 0); truncated {
+		t.Error("truncated with no limit")
+	}
+
+	evidence := "HEAD" + strings.Repeat("a", 1000) + "TAIL"
+	got, truncated := TruncateEvidence(evidence, 90)
+	if !truncated || !strings.HasPrefix(got, "HEAD") || !strings.HasSuffix(got, "TAIL") {
+		t.Fatalf("TruncateEvidence = %q, %v; want the head and tail kept", got, truncated)
+	}
+	if !strings.Contains(got, "[918 bytes omitted]") {
+		t.Errorf("TruncateEvidence = %q, want the omitted byte count", got)
+	}
+}
+
+func TestTruncateEvidenceKeepsRunesWhole(t *testing.T) {
+	evidence := strings.Repeat("é", 100) // two bytes each
+	got, _ := TruncateEvidence(evidence, 31)
+	if !utf8.ValidString(got) {
+		t.Errorf("TruncateEvidence split a rune: %q", got)
+	}
+}
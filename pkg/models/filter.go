@@ -0,0 +1,462 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FindingFilter is a parsed filter expression selecting findings, e.g.
+//
+//	severity>=high AND type=tls-certificate OR tag=owasp:a05
+//
+// An expression compares fields with =, !=, <, <=, >, >= or ~ (contains)
+// and combines comparisons with NOT, AND and OR, in decreasing order of
+// precedence; parentheses group. Keywords are case-insensitive and values
+// containing spaces or operator characters are double-quoted.
+//
+// Fields are severity and confidence (ordered by rank), cvss (numeric),
+// type, title, location, cve, tag (any of the finding's tags) and the
+// metadata keys in ReportedMetadata plus module. Other metadata keys are
+// written meta.<key>. Metadata compares as numbers when both sides are
+// numeric, e.g. port<1024. Strings compare case-insensitively.
+type FindingFilter struct {
+	expr string
+	root filterNode
+}
+
+// FilterError reports a malformed filter expression
+type FilterError struct {
+	Expr   string
+	Column int // 1-based position of the offending token
+	Msg    string
+}
+
+func (e *FilterError) Error() string {
+	return fmt.Sprintf("invalid filter %q at column %d: %s", e.Expr, e.Column, e.Msg)
+}
+
+// ParseFindingFilter parses a filter expression
+func ParseFindingFilter(expr string) (*FindingFilter, error) {
+	tokens, err := lexFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{expr: expr, tokens: tokens}
+	if p.peek().kind == tokEOF {
+		return nil, p.errorf(p.peek(), "empty expression")
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.errorf(tok, "unexpected %s, expected AND, OR or end of expression", tok)
+	}
+	return &FindingFilter{expr: expr, root: root}, nil
+}
+
+// String returns the expression the filter was parsed from
+func (f *FindingFilter) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.expr
+}
+
+// Match reports whether the finding satisfies the filter. A nil filter
+// matches every finding.
+func (f *FindingFilter) Match(finding *Finding) bool {
+	return f == nil || f.root.match(finding)
+}
+
+// Filter returns the findings that satisfy the filter
+func (f *FindingFilter) Filter(findings []Finding) []Finding {
+	if f == nil {
+		return findings
+	}
+	matched := make([]Finding, 0)
+	for i := range findings {
+		if f.root.match(&findings[i]) {
+			matched = append(matched, findings[i])
+		}
+	}
+	return matched
+}
+
+type filterTokenKind int
+
+const (
+	tokEOF filterTokenKind = iota
+	tokWord
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+	tokAnd
+	tokOr
+	tokNot
+)
+
+type filterToken struct {
+	kind filterTokenKind
+	text string
+	col  int
+}
+
+func (t filterToken) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of expression"
+	case tokString:
+		return strconv.Quote(t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+const filterOpChars = "=!<>~"
+
+func lexFilter(expr string) ([]filterToken, error) {
+	tokens := make([]filterToken, 0)
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		col := i + 1
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, filterToken{tokLParen, "(", col})
+			i++
+		case c == ')':
+			tokens = append(tokens, filterToken{tokRParen, ")", col})
+			i++
+		case c == '"':
+			var value strings.Builder
+			j := i + 1
+			for ; j < len(expr) && expr[j] != '"'; j++ {
+				if expr[j] == '\\' && j+1 < len(expr) {
+					j++
+				}
+				value.WriteByte(expr[j])
+			}
+			if j >= len(expr) {
+				return nil, &FilterError{Expr: expr, Column: col, Msg: "unterminated quoted value"}
+			}
+			tokens = append(tokens, filterToken{tokString, value.String(), col})
+			i = j + 1
+		case strings.IndexByte(filterOpChars, c) >= 0:
+			op := string(c)
+			if i+1 < len(expr) && expr[i+1] == '=' && (c == '!' || c == '<' || c == '>') {
+				op += "="
+			}
+			if op == "!" {
+				return nil, &FilterError{Expr: expr, Column: col, Msg: `"!" must be followed by "=" (use NOT to negate)`}
+			}
+			tokens = append(tokens, filterToken{tokOp, op, col})
+			i += len(op)
+		default:
+			j := i
+			for j < len(expr) && !strings.ContainsRune(" \t\n\r()\""+filterOpChars, rune(expr[j])) {
+				j++
+			}
+			word := expr[i:j]
+			kind := tokWord
+			switch strings.ToUpper(word) {
+			case "AND":
+				kind = tokAnd
+			case "OR":
+				kind = tokOr
+			case "NOT":
+				kind = tokNot
+			}
+			tokens = append(tokens, filterToken{kind, word, col})
+			i = j
+		}
+	}
+	return append(tokens, filterToken{tokEOF, "", len(expr) + 1}), nil
+}
+
+type filterParser struct {
+	expr   string
+	tokens []filterToken
+	pos    int
+}
+
+func (p *filterParser) peek() filterToken {
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser) next() filterToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *filterParser) errorf(tok filterToken, format string, args ...interface{}) error {
+	return &FilterError{Expr: p.expr, Column: tok.col, Msg: fmt.Sprintf(format, args...)}
+}
+
+// parseOr: and { OR and }
+func (p *filterParser) parseOr() (filterNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = filterOr{left, right}
+	}
+	return left, nil
+}
+
+// parseAnd: unary { AND unary }
+func (p *filterParser) parseAnd() (filterNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokAnd {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = filterAnd{left, right}
+	}
+	return left, nil
+}
+
+// parseUnary: NOT unary | "(" or ")" | comparison
+func (p *filterParser) parseUnary() (filterNode, error) {
+	tok := p.peek()
+	switch tok.kind {
+	case tokNot:
+		p.next()
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return filterNot{node}, nil
+	case tokLParen:
+		p.next()
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, p.errorf(closing, "expected \")\" to close the \"(\" at column %d, got %s", tok.col, closing)
+		}
+		return node, nil
+	case tokWord:
+		return p.parseComparison()
+	default:
+		return nil, p.errorf(tok, "expected a field name, NOT or \"(\", got %s", tok)
+	}
+}
+
+// parseComparison: field op value
+func (p *filterParser) parseComparison() (filterNode, error) {
+	fieldTok := p.next()
+	field := strings.ToLower(fieldTok.text)
+	kind, ok := filterFieldKind(field)
+	if !ok {
+		return nil, p.errorf(fieldTok, "unknown field %q (use severity, confidence, cvss, type, title, location, cve, tag, a metadata key such as service or port, or meta.<key>)", fieldTok.text)
+	}
+
+	opTok := p.next()
+	if opTok.kind != tokOp {
+		return nil, p.errorf(opTok, "expected an operator (=, !=, <, <=, >, >=, ~) after %q, got %s", fieldTok.text, opTok)
+	}
+	op := opTok.text
+
+	valueTok := p.next()
+	if valueTok.kind != tokWord && valueTok.kind != tokString {
+		return nil, p.errorf(valueTok, "expected a value after %q, got %s", fieldTok.text+op, valueTok)
+	}
+	value := valueTok.text
+
+	ordered := op == "<" || op == "<=" || op == ">" || op == ">="
+	switch kind {
+	case fieldSeverity:
+		if SeverityRank(value) == 0 {
+			return nil, p.errorf(valueTok, "unknown severity %q (use critical, high, medium, low or info)", value)
+		}
+		if op == "~" {
+			return nil, p.errorf(opTok, "severity can't be compared with ~")
+		}
+	case fieldConfidence:
+		switch strings.ToLower(value) {
+		case "high", "medium", "low":
+		default:
+			return nil, p.errorf(valueTok, "unknown confidence %q (use high, medium or low)", value)
+		}
+		if op == "~" {
+			return nil, p.errorf(opTok, "confidence can't be compared with ~")
+		}
+	case fieldNumber:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return nil, p.errorf(valueTok, "%s needs a number, got %q", field, value)
+		}
+		if op == "~" {
+			return nil, p.errorf(opTok, "%s can't be compared with ~", field)
+		}
+	case fieldText, fieldTag:
+		if ordered {
+			return nil, p.errorf(opTok, "%s only supports =, != and ~", field)
+		}
+	case fieldMeta:
+		if ordered {
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return nil, p.errorf(valueTok, "%s needs a number for %s, got %q", field, op, value)
+			}
+		}
+	}
+
+	return filterComparison{field: strings.TrimPrefix(field, "meta."), kind: kind, op: op, value: value}, nil
+}
+
+type filterNode interface {
+	match(finding *Finding) bool
+}
+
+type filterAnd struct{ left, right filterNode }
+type filterOr struct{ left, right filterNode }
+type filterNot struct{ node filterNode }
+
+func (n filterAnd) match(finding *Finding) bool {
+	return n.left.match(finding) && n.right.match(finding)
+}
+
+func (n filterOr) match(finding *Finding) bool {
+	return n.left.match(finding) || n.right.match(finding)
+}
+
+func (n filterNot) match(finding *Finding) bool {
+	return !n.node.match(finding)
+}
+
+type filterFieldType int
+
+const (
+	fieldSeverity filterFieldType = iota
+	fieldConfidence
+	fieldNumber
+	fieldText
+	fieldTag
+	fieldMeta
+)
+
+// filterFieldKind returns how field compares, and false for unknown fields
+func filterFieldKind(field string) (filterFieldType, bool) {
+	switch field {
+	case "severity":
+		return fieldSeverity, true
+	case "confidence":
+		return fieldConfidence, true
+	case "cvss":
+		return fieldNumber, true
+	case "type", "title", "location", "cve":
+		return fieldText, true
+	case "tag":
+		return fieldTag, true
+	}
+	if strings.HasPrefix(field, "meta.") && len(field) > len("meta.") {
+		return fieldMeta, true
+	}
+	if field == MetaModule || containsString(ReportedMetadata, field) {
+		return fieldMeta, true
+	}
+	return 0, false
+}
+
+type filterComparison struct {
+	field string
+	kind  filterFieldType
+	op    string
+	value string
+}
+
+func (c filterComparison) match(finding *Finding) bool {
+	switch c.kind {
+	case fieldSeverity:
+		return compareOrdered(float64(SeverityRank(finding.Severity)), c.op, float64(SeverityRank(c.value)))
+	case fieldConfidence:
+		return compareOrdered(float64(ConfidenceRank(string(finding.Confidence))), c.op, float64(ConfidenceRank(c.value)))
+	case fieldNumber:
+		want, _ := strconv.ParseFloat(c.value, 64)
+		return compareOrdered(finding.CVSS, c.op, want)
+	case fieldTag:
+		// tag!=x means the finding carries no tag x
+		op := c.op
+		if op == "!=" {
+			op = "="
+		}
+		for _, tag := range finding.Tags {
+			if compareText(tag, op, c.value) {
+				return c.op != "!="
+			}
+		}
+		return c.op == "!="
+	case fieldText:
+		return compareText(c.textField(finding), c.op, c.value)
+	default:
+		actual := finding.Metadata[c.field]
+		got, gotErr := strconv.ParseFloat(actual, 64)
+		want, wantErr := strconv.ParseFloat(c.value, 64)
+		if gotErr == nil && wantErr == nil && c.op != "~" {
+			return compareOrdered(got, c.op, want)
+		}
+		if c.op != "=" && c.op != "!=" && c.op != "~" {
+			return false
+		}
+		return compareText(actual, c.op, c.value)
+	}
+}
+
+func (c filterComparison) textField(finding *Finding) string {
+	switch c.field {
+	case "type":
+		return finding.Type
+	case "title":
+		return finding.Title
+	case "location":
+		return finding.Location
+	default:
+		return finding.CVE
+	}
+}
+
+func compareOrdered(got float64, op string, want float64) bool {
+	switch op {
+	case "=":
+		return got == want
+	case "!=":
+		return got != want
+	case "<":
+		return got < want
+	case "<=":
+		return got <= want
+	case ">":
+		return got > want
+	default:
+		return got >= want
+	}
+}
+
+func compareText(got string, op string, want string) bool {
+	switch op {
+	case "=":
+		return strings.EqualFold(got, want)
+	case "!=":
+		return !strings.EqualFold(got, want)
+	default:
+		return strings.Contains(strings.ToLower(got), strings.ToLower(want))
+	}
+}
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func filterFindings() []Finding {
+	findings := []Finding{
+		{ID: "crit-tls", Type: "tls-certificate", Severity: "critical", Confidence: ConfidenceHigh, Title: "Expired certificate", CVSS: 9.1, Tags: []string{"tls"}},
+		{ID: "high-port", Type: "open-port", Severity: "high", Confidence: ConfidenceMedium, Title: "Telnet open", Tags: []string{"network"}},
+		{ID: "low-header", Type: "security-header", Severity: "low", Confidence: ConfidenceLow, Title: "Missing X-Frame-Options header", Tags: []string{"headers", "owasp:a05"}},
+		{ID: "info-ssh", Type: "open-port", Severity: "info", Title: "SSH open"},
+	}
+	findings[1].SetMeta(MetaPort, "23")
+	findings[3].SetMeta(MetaPort, "22")
+	findings[3].SetMeta("scanner.note", "banner grabbed")
+	return findings
+}
+
+func matchedIDs(t *testing.T, expr string) string {
+	t.Helper()
+	filter, err := ParseFindingFilter(expr)
+	if err != nil {
+		t.Fatalf("ParseFindingFilter(%q): %v", expr, err)
+	}
+	ids := make([]string, 0)
+	for _, finding := range filter.Filter(filterFindings()) {
+		ids = append(ids, finding.ID)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestFindingFilterPrecedence(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		// AND binds tighter than OR: a OR (b AND c)
+		{"severity=critical OR type=open-port AND port<23", "crit-tls,info-ssh"},
+		{"(severity=critical OR type=open-port) AND port<23", "info-ssh"},
+		// NOT binds tighter than AND
+		{"NOT type=open-port AND severity>=low", "crit-tls,low-header"},
+		{"NOT (type=open-port AND severity>=low)", "crit-tls,low-header,info-ssh"},
+		{"not type=open-port or tag=network", "crit-tls,high-port,low-header"},
+	}
+	for _, tt := range tests {
+		if got := matchedIDs(t, tt.expr); got != tt.want {
+			t.Errorf("%s: matched %q, want %q", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestFindingFilterFields(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"severity>=high", "crit-tls,high-port"},
+		{"confidence<high", "high-port,low-header,info-ssh"},
+		{"cvss>9", "crit-tls"},
+		{`title~"x-frame"`, "low-header"},
+		{"tag=owasp:a05", "low-header"},
+		{"tag!=tls", "high-port,low-header,info-ssh"},
+		{"port>=22 AND port<=23", "high-port,info-ssh"},
+		{`meta.scanner.note~banner`, "info-ssh"},
+		{"TYPE=OPEN-PORT", "high-port,info-ssh"},
+	}
+	for _, tt := range tests {
+		if got := matchedIDs(t, tt.expr); got != tt.want {
+			t.Errorf("%s: matched %q, want %q", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestFindingFilterMalformed(t *testing.T) {
+	tests := []struct {
+		expr   string
+		column int
+		msg    string
+	}{
+		{"", 1, "empty expression"},
+		{"severity=urgent", 10, "unknown severity"},
+		{"colour=red", 1, "unknown field"},
+		{"severity", 9, "expected an operator"},
+		{"severity>=", 11, "expected a value"},
+		{"(severity=high", 15, `expected ")"`},
+		{"severity=high AND", 18, "expected a field name"},
+		{"severity=high low", 15, "unexpected"},
+		{`title="unterminated`, 7, "unterminated quoted value"},
+		{"type!open-port", 5, `"!" must be followed by "="`},
+		{"title>abc", 6, "only supports"},
+		{"cvss=high", 6, "needs a number"},
+		{"severity~high", 9, "can't be compared with ~"},
+	}
+	for _, tt := range tests {
+		_, err := ParseFindingFilter(tt.expr)
+		var filterErr *FilterError
+		if !errors.As(err, &filterErr) {
+			t.Errorf("%q: error %v, want a FilterError", tt.expr, err)
+			continue
+		}
+		if filterErr.Column != tt.column || !strings.Contains(filterErr.Msg, tt.msg) {
+			t.Errorf("%q: column %d %q, want column %d containing %q", tt.expr, filterErr.Column, filterErr.Msg, tt.column, tt.msg)
+		}
+	}
+}
+
+func TestNilFindingFilterMatchesAll(t *testing.T) {
+	var filter *FindingFilter
+	if len(filter.Filter(filterFindings())) != 4 || !filter.Match(&Finding{}) || filter.String() != "" {
+		t.Error("nil filter doesn't match everything")
+	}
+}
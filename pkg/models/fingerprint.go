@@ -0,0 +1,166 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// ComputeFingerprint returns a deterministic ID for the logical finding.
+// Unlike ID (a random UUID per run), the fingerprint stays the same when the
+// same issue is found again at the same location, so findings can be tracked
+// across scans.
+func (f *Finding) ComputeFingerprint() string {
+	h := sha256.New()
+	for _, part := range []string{
+		normalizeFingerprintField(f.Type),
+		normalizeFingerprintField(f.Title),
+		normalizeFingerprintField(f.Location),
+		normalizeFingerprintField(f.Evidence),
+	} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// EnsureFingerprint sets the fingerprint if the finding doesn't have one yet
+func (f *Finding) EnsureFingerprint() string {
+	if f.Fingerprint == "" {
+		f.Fingerprint = f.ComputeFingerprint()
+	}
+	return f.Fingerprint
+}
+
+// DedupFindings removes findings that share a fingerprint, keeping the first
+func DedupFindings(findings []Finding) []Finding {
+	seen := make(map[string]bool, len(findings))
+	deduped := make([]Finding, 0, len(findings))
+
+	for _, finding := range findings {
+		fp := finding.EnsureFingerprint()
+		if seen[fp] {
+			continue
+		}
+		seen[fp] = true
+		deduped = append(deduped, finding)
+	}
+
+	return deduped
+}
+
+// FindingDiff describes how findings changed between two scans
+type FindingDiff struct {
+	New       []Finding `json:"new"`       // only in the current scan
+	Fixed     []Finding `json:"fixed"`     // only in the previous scan
+	Unchanged []Finding `json:"unchanged"` // in both scans (current copy)
+}
+
+// DiffFindings compares two finding sets by fingerprint
+func DiffFindings(previous, current []Finding) FindingDiff {
+	diff := FindingDiff{
+		New:       make([]Finding, 0),
+		Fixed:     make([]Finding, 0),
+		Unchanged: make([]Finding, 0),
+	}
+
+	previousByFP := make(map[string]bool, len(previous))
+	for i := range previous {
+		previousByFP[previous[i].EnsureFingerprint()] = true
+	}
+
+	currentByFP := make(map[string]bool, len(current))
+	for _, finding := range current {
+		fp := finding.EnsureFingerprint()
+		currentByFP[fp] = true
+		if previousByFP[fp] {
+			diff.Unchanged = append(diff.Unchanged, finding)
+		} else {
+			diff.New = append(diff.New, finding)
+		}
+	}
+
+	for _, finding := range previous {
+		if !currentByFP[finding.Fingerprint] {
+			diff.Fixed = append(diff.Fixed, finding)
+		}
+	}
+
+	return diff
+}
+
+// normalizeFingerprintField lowercases and collapses whitespace so cosmetic
+// differences in module output don't change the fingerprint
+func normalizeFingerprintField(value string) string {
+	return strings.Join(strings.Fields(strings.ToLower(value)), " ")
+}
+
+// Metadata keys used to mark suppressed findings. Suppressed findings stay
+// in the stored result but are left out of reports and AI prompts.
+const (
+	MetaSuppressed       = "suppressed"
+	MetaSuppressedReason = "suppressed.reason"
+)
+
+// IsSuppressed reports whether the finding was suppressed by an ignore rule
+func (f *Finding) IsSuppressed() bool {
+	return f.Metadata[MetaSuppressed] == "true"
+}
+
+// ActiveFindings returns the findings that are not suppressed
+func ActiveFindings(findings []Finding) []Finding {
+	active := make([]Finding, 0, len(findings))
+	for _, finding := range findings {
+		if !finding.IsSuppressed() {
+			active = append(active, finding)
+		}
+	}
+	return active
+}
+
+// WithActiveFindings returns a shallow copy of the result without suppressed findings
+func (r *ScanResult) WithActiveFindings() *ScanResult {
+	filtered := *r
+	filtered.Findings = ActiveFindings(r.Findings)
+	return &filtered
+}
+
+// MetaBaselineKnown marks a finding that was already present in a baseline
+// scan; the value is the baseline scan ID.
+const MetaBaselineKnown = "baseline.known"
+
+// IsKnown reports whether the finding was present in the baseline scan
+func (f *Finding) IsKnown() bool {
+	return f.Metadata[MetaBaselineKnown] != ""
+}
+
+// MarkKnown flags every current finding that also appears in the baseline
+// and returns how many were flagged. With exclude set, known findings are
+// also suppressed so only net-new findings reach reports and AI analysis.
+func MarkKnown(baseline *ScanResult, current []Finding, exclude bool) int {
+	diff := DiffFindings(baseline.Findings, current)
+
+	known := make(map[string]bool, len(diff.Unchanged))
+	for _, finding := range diff.Unchanged {
+		known[finding.Fingerprint] = true
+	}
+
+	marked := 0
+	for i := range current {
+		finding := &current[i]
+		if !known[finding.EnsureFingerprint()] {
+			continue
+		}
+		if finding.Metadata == nil {
+			finding.Metadata = make(map[string]string)
+		}
+		finding.Metadata[MetaBaselineKnown] = baseline.ID
+		if exclude && !finding.IsSuppressed() {
+			finding.Metadata[MetaSuppressed] = "true"
+			finding.Metadata[MetaSuppressedReason] = "known in baseline scan " + baseline.ID
+		}
+		marked++
+	}
+
+	return marked
+}
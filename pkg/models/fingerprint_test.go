@@ -0,0 +1,115 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func missingHSTS(id string, location string) Finding {
+	return Finding{
+		ID:        id,
+		Type:      "security-header",
+		Severity:  "medium",
+		Title:     "Missing Strict-Transport-Security header",
+		Location:  location,
+		Evidence:  "HTTP 200 from " + location + " without Strict-Transport-Security",
+		Timestamp: time.Now(),
+	}
+}
+
+func TestFingerprintStableAcrossRescans(t *testing.T) {
+	first := missingHSTS("uuid-1", "https://example.com")
+	rescan := missingHSTS("uuid-2", "https://example.com")
+	rescan.Timestamp = first.Timestamp.Add(24 * time.Hour)
+	rescan.Severity = "low" // overrides and calibration don't change identity
+	rescan.Title = "  missing strict-transport-security   HEADER "
+
+	if first.ComputeFingerprint() != rescan.ComputeFingerprint() {
+		t.Error("the same issue found again got a different fingerprint")
+	}
+	if len(first.ComputeFingerprint()) != 16 {
+		t.Errorf("fingerprint %q, want 16 hex characters", first.ComputeFingerprint())
+	}
+
+	elsewhere := missingHSTS("uuid-3", "https://api.example.com")
+	if first.ComputeFingerprint() == elsewhere.ComputeFingerprint() {
+		t.Error("the same issue at another location shares a fingerprint")
+	}
+}
+
+func TestEnsureFingerprintKeepsExisting(t *testing.T) {
+	finding := missingHSTS("uuid-1", "https://example.com")
+	finding.Fingerprint = "stored"
+	if finding.EnsureFingerprint() != "stored" {
+		t.Error("EnsureFingerprint replaced a stored fingerprint")
+	}
+}
+
+func TestDiffFindings(t *testing.T) {
+	previous := []Finding{missingHSTS("a", "https://example.com"), missingHSTS("b", "https://old.example.com")}
+	current := []Finding{missingHSTS("c", "https://example.com"), missingHSTS("d", "https://new.example.com")}
+
+	diff := DiffFindings(previous, current)
+	if len(diff.Unchanged) != 1 || diff.Unchanged[0].ID != "c" {
+		t.Errorf("unchanged = %+v, want the current copy of the shared finding", diff.Unchanged)
+	}
+	if len(diff.New) != 1 || diff.New[0].ID != "d" {
+		t.Errorf("new = %+v", diff.New)
+	}
+	if len(diff.Fixed) != 1 || diff.Fixed[0].ID != "b" {
+		t.Errorf("fixed = %+v", diff.Fixed)
+	}
+}
+
+func TestDedupFindings(t *testing.T) {
+	findings := DedupFindings([]Finding{
+		missingHSTS("a", "https://example.com"),
+		missingHSTS("b", "https://example.com"),
+		missingHSTS("c", "https://api.example.com"),
+	})
+	if len(findings) != 2 || findings[0].ID != "a" {
+		t.Errorf("deduped = %+v, want the first of each fingerprint", findings)
+	}
+}
+
+func TestWithActiveFindings(t *testing.T) {
+	suppressed := missingHSTS("a", "https://example.com")
+	suppressed.Metadata = map[string]string{MetaSuppressed: "true"}
+	result := &ScanResult{ID: "scan", Findings: []Finding{suppressed, missingHSTS("b", "https://api.example.com")}}
+
+	active := result.WithActiveFindings()
+	if len(active.Findings) != 1 || active.Findings[0].ID != "b" || active.ID != "scan" {
+		t.Errorf("active result = %+v", active)
+	}
+	if len(result.Findings) != 2 {
+		t.Error("WithActiveFindings changed the original result")
+	}
+}
+
+func TestMarkKnown(t *testing.T) {
+	baseline := &ScanResult{ID: "base", Findings: []Finding{missingHSTS("a", "https://example.com")}}
+	current := []Finding{missingHSTS("b", "https://example.com"), missingHSTS("c", "https://new.example.com")}
+
+	if marked := MarkKnown(baseline, current, true); marked != 1 {
+		t.Fatalf("marked %d, want 1", marked)
+	}
+	if !current[0].IsKnown() || !current[0].IsSuppressed() {
+		t.Errorf("known finding metadata = %v, want it marked and suppressed", current[0].Metadata)
+	}
+	if current[1].IsKnown() {
+		t.Error("new finding marked known")
+	}
+	if active := ActiveFindings(current); len(active) != 1 || active[0].ID != "c" {
+		t.Errorf("active = %+v, want only the new finding", active)
+	}
+}
+
+func TestMarkKnownWithoutExclude(t *testing.T) {
+	baseline := &ScanResult{ID: "base", Findings: []Finding{missingHSTS("a", "https://example.com")}}
+	current := []Finding{missingHSTS("b", "https://example.com")}
+
+	MarkKnown(baseline, current, false)
+	if current[0].Metadata[MetaBaselineKnown] != "base" || current[0].IsSuppressed() {
+		t.Errorf("metadata = %v, want marked with the baseline ID but still reported", current[0].Metadata)
+	}
+}
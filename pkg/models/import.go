@@ -0,0 +1,86 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ExternalFindingType is the type given to imported findings that don't
+// set one
+const ExternalFindingType = "external"
+
+// maxImportErrors caps how many invalid findings ParseFindings lists
+const maxImportErrors = 10
+
+// ParseFindings reads findings produced by another tool: a JSON array of
+// Finding objects. Unknown fields are rejected, so a misspelled key fails
+// instead of being silently dropped. Every finding needs a title and a
+// valid severity; confidence must be high, medium or low when set. Type,
+// timestamp and fingerprint are filled in when missing.
+func ParseFindings(data []byte) ([]Finding, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '[' {
+		return nil, errors.New("findings must be a JSON array of finding objects")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var findings []Finding
+	if err := decoder.Decode(&findings); err != nil {
+		return nil, fmt.Errorf("invalid findings JSON: %w", err)
+	}
+	if len(findings) == 0 {
+		return nil, errors.New("the findings array is empty")
+	}
+
+	problems := make([]string, 0)
+	now := time.Now()
+	for i := range findings {
+		finding := &findings[i]
+		if problem := validateImported(finding); problem != "" {
+			problems = append(problems, fmt.Sprintf("finding %d: %s", i+1, problem))
+			continue
+		}
+
+		finding.Severity = strings.ToLower(strings.TrimSpace(finding.Severity))
+		finding.Confidence = Confidence(strings.ToLower(strings.TrimSpace(string(finding.Confidence))))
+		if finding.Type == "" {
+			finding.Type = ExternalFindingType
+		}
+		if finding.Tags == nil {
+			finding.Tags = []string{}
+		}
+		if finding.Timestamp.IsZero() {
+			finding.Timestamp = now
+		}
+		finding.EnsureFingerprint()
+	}
+
+	if len(problems) > maxImportErrors {
+		problems = append(problems[:maxImportErrors], fmt.Sprintf("and %d more", len(problems)-maxImportErrors))
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid findings:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return findings, nil
+}
+
+// validateImported returns what is wrong with an imported finding, or ""
+func validateImported(finding *Finding) string {
+	if strings.TrimSpace(finding.Title) == "" {
+		return "missing title"
+	}
+	if SeverityRank(finding.Severity) == 0 {
+		return fmt.Sprintf("severity %q is not one of %s", finding.Severity, strings.Join(SeverityLevels, ", "))
+	}
+	switch Confidence(strings.ToLower(strings.TrimSpace(string(finding.Confidence)))) {
+	case "", ConfidenceHigh, ConfidenceMedium, ConfidenceLow:
+	default:
+		return fmt.Sprintf("confidence %q is not high, medium or low", finding.Confidence)
+	}
+	return ""
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseFindings(t *testing.T) {
+	data := []byte(` [
+		{"title": "Exposed .git directory", "severity": " HIGH ", "confidence": "Medium", "location": "https://example.com/.git/"},
+		{"id": "ext-2", "type": "tls", "title": "Weak cipher", "severity": "low", "tags": ["tls"]}
+	]`)
+	findings, err := ParseFindings(data)
+	if err != nil {
+		t.Fatalf("ParseFindings: %v", err)
+	}
+
+	first := findings[0]
+	if first.Severity != "high" || first.Confidence != ConfidenceMedium || first.Type != ExternalFindingType {
+		t.Errorf("first finding = %+v, want normalized severity and confidence and the external type", first)
+	}
+	if first.Tags == nil || first.Timestamp.IsZero() || first.Fingerprint == "" {
+		t.Errorf("first finding = %+v, want tags, timestamp and fingerprint filled in", first)
+	}
+	if findings[1].ID != "ext-2" || findings[1].Type != "tls" {
+		t.Errorf("second finding = %+v, want its own ID and type kept", findings[1])
+	}
+}
+
+func TestParseFindingsErrors(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+	}{
+		{`{"title": "x", "severity": "low"}`, "must be a JSON array"},
+		{``, "must be a JSON array"},
+		{`[]`, "array is empty"},
+		{`[{"title": "x", "severity": "low", "sevrity": "high"}]`, `unknown field "sevrity"`},
+		{`[{"severity": "low"}]`, "finding 1: missing title"},
+		{`[{"title": "ok", "severity": "low"}, {"title": "x", "severity": "urgent"}]`, `finding 2: severity "urgent"`},
+		{`[{"title": "x", "severity": "low", "confidence": "certain"}]`, `confidence "certain"`},
+	}
+	for _, tt := range tests {
+		if _, err := ParseFindings([]byte(tt.data)); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error = %v, want %q", tt.data, err, tt.want)
+		}
+	}
+}
+
+func TestParseFindingsCapsErrors(t *testing.T) {
+	items := make([]string, 0, 15)
+	for i := 0; i < 15; i++ {
+		items = append(items, fmt.Sprintf(`{"title": "t%d"}`, i))
+	}
+	_, err := ParseFindings([]byte("[" + strings.Join(items, ",") + "]"))
+	if err == nil || strings.Count(err.Error(), "finding ") != maxImportErrors || !strings.Contains(err.Error(), "and 5 more") {
+		t.Errorf("error = %v, want %d problems listed and the rest counted", err, maxImportErrors)
+	}
+}
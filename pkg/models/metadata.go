@@ -0,0 +1,82 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Metadata keys modules use to describe what they observed. Reports show
+// these, and findings can be filtered by them.
+const (
+	MetaHTTPStatus = "http.status" // status code of the response a finding is based on
+	MetaHTTPTitle  = "http.title"  // <title> of the page a finding is based on
+	MetaTLSVersion = "tls.version" // e.g. "TLS 1.3"
+	MetaALPN       = "tls.alpn"    // protocol negotiated via TLS ALPN, e.g. "h2"
+	MetaHTTPProto  = "http.proto"  // HTTP version of the response, e.g. "HTTP/2.0"
+	MetaPort       = "port"        // port number
+	MetaProtocol   = "protocol"    // tcp or udp
+	MetaService    = "service"     // service name, e.g. "http" or "ssh"
+	MetaVersion    = "version"     // product and version banner, e.g. "Apache Tomcat 9.0.65"
+	MetaHost       = "host"        // host name or address the finding is about
+	MetaModule     = "module"      // scanner module that reported the finding, e.g. "Port Scan"
+)
+
+// ReportedMetadata lists the metadata keys reports show, in display order
+var ReportedMetadata = []string{MetaHost, MetaPort, MetaProtocol, MetaService, MetaVersion, MetaHTTPTitle, MetaHTTPStatus, MetaHTTPProto, MetaTLSVersion, MetaALPN, MetaSeverityOriginal, MetaAttackTechniques}
+
+// SetMeta sets a metadata value, creating the map if needed. Empty values
+// are not recorded.
+func (f *Finding) SetMeta(key string, value string) {
+	if value == "" {
+		return
+	}
+	if f.Metadata == nil {
+		f.Metadata = make(map[string]string)
+	}
+	f.Metadata[key] = value
+}
+
+// ReportMetadata returns the finding's ReportedMetadata as "key=value"
+// pairs in display order
+func (f *Finding) ReportMetadata() []string {
+	pairs := make([]string, 0)
+	for _, key := range ReportedMetadata {
+		if value := f.Metadata[key]; value != "" {
+			pairs = append(pairs, key+"="+value)
+		}
+	}
+	return pairs
+}
+
+// ParseMetadataFilter parses "key=value" into a filter for FilterByMetadata
+func ParseMetadataFilter(raw string) (string, string, error) {
+	key, value, ok := strings.Cut(raw, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return "", "", fmt.Errorf("invalid metadata filter %q (want key=value)", raw)
+	}
+	return key, strings.TrimSpace(value), nil
+}
+
+// FilterByMetadata returns the findings whose metadata matches every
+// filter. Values compare case-insensitively.
+func FilterByMetadata(findings []Finding, filters map[string]string) []Finding {
+	if len(filters) == 0 {
+		return findings
+	}
+
+	matched := make([]Finding, 0)
+	for _, finding := range findings {
+		ok := true
+		for key, value := range filters {
+			if !strings.EqualFold(finding.Metadata[key], value) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			matched = append(matched, finding)
+		}
+	}
+	return matched
+}
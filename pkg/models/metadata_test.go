@@ -0,0 +1,59 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetMetaAndReportMetadata(t *testing.T) {
+	var finding Finding
+	finding.SetMeta(MetaService, "")
+	if finding.Metadata != nil {
+		t.Error("empty value created the metadata map")
+	}
+
+	finding.SetMeta(MetaTLSVersion, "TLS 1.3")
+	finding.SetMeta("module", "headers") // not reported
+	finding.SetMeta(MetaPort, "443")
+	finding.SetMeta(MetaHost, "example.com")
+	if got := strings.Join(finding.ReportMetadata(), ","); got != "host=example.com,port=443,tls.version=TLS 1.3" {
+		t.Errorf("ReportMetadata = %s, want reported keys in display order", got)
+	}
+}
+
+func TestParseMetadataFilter(t *testing.T) {
+	key, value, err := ParseMetadataFilter(" service = ssh ")
+	if err != nil || key != "service" || value != "ssh" {
+		t.Errorf("got %q, %q, %v", key, value, err)
+	}
+	for _, raw := range []string{"service", "=ssh", ""} {
+		if _, _, err := ParseMetadataFilter(raw); err == nil {
+			t.Errorf("%q: parsed without error", raw)
+		}
+	}
+}
+
+func TestFilterByMetadata(t *testing.T) {
+	findings := []Finding{
+		{Title: "ssh", Metadata: map[string]string{MetaService: "ssh", MetaPort: "22"}},
+		{Title: "http", Metadata: map[string]string{MetaService: "http", MetaPort: "80"}},
+		{Title: "none"},
+	}
+
+	titles := func(findings []Finding) string {
+		var names []string
+		for _, finding := range findings {
+			names = append(names, finding.Title)
+		}
+		return strings.Join(names, ",")
+	}
+	if got := titles(FilterByMetadata(findings, map[string]string{MetaService: "SSH"})); got != "ssh" {
+		t.Errorf("service=SSH matched %q", got)
+	}
+	if got := titles(FilterByMetadata(findings, map[string]string{MetaService: "http", MetaPort: "22"})); got != "" {
+		t.Errorf("conflicting filters matched %q, want every filter to match", got)
+	}
+	if got := FilterByMetadata(findings, nil); len(got) != 3 {
+		t.Errorf("no filters kept %d findings, want all", len(got))
+	}
+}
@@ -9,25 +9,51 @@ type ScanConfig struct {
 	AIAnalysis bool
 	Threads    int
 	Modules    []string
+	Proxy      string            // http://, https:// or socks5:// URL, optionally with user:pass@
+	Headers    map[string]string // extra headers sent with every HTTP module request
+	UserAgent  string
+
+	MaxEvidenceLength  int               // bytes of evidence kept per finding, 0 for the default
+	ConcurrencyPerHost int               // simultaneous connections to one host, 0 for the default
+	SubdomainWordlist  []string          // labels for subdomain discovery, nil for the built-in list
+	SeverityOverrides  map[string]string // finding type to severity, applied after every module
+
+	HTTPTimeout  time.Duration // per HTTP request, 0 for the default
+	MaxRedirects int           // redirects followed, 0 for the default and negative for none
+	Insecure     bool          // skip TLS certificate verification (self-signed targets)
+
+	// AuthHeaders carry session state (Cookie, Authorization). They are kept
+	// in memory only and their values are scrubbed from findings.
+	AuthHeaders map[string]string
 }
 
 // ScanResult represents the output of a security scan
 type ScanResult struct {
-	ID        string        `json:"id"`
-	Target    string        `json:"target"`
-	StartTime time.Time     `json:"start_time"`
-	EndTime   time.Time     `json:"end_time"`
-	Duration  time.Duration `json:"duration"`
-	Status    string        `json:"status"`
-	Findings  []Finding     `json:"findings"`
-	Metadata  ScanMetadata  `json:"metadata"`
+	ID        string           `json:"id"`
+	Target    string           `json:"target"`
+	StartTime time.Time        `json:"start_time"`
+	EndTime   time.Time        `json:"end_time"`
+	Duration  time.Duration    `json:"duration"`
+	Status    string           `json:"status"`
+	Findings  []Finding        `json:"findings"`
+	Metadata  ScanMetadata     `json:"metadata"`
+	Analysis  *AIAnalysis      `json:"analysis,omitempty"`
+	Research  *ResearchSummary `json:"research,omitempty"`
+	ParentID  string           `json:"parent_id,omitempty"` // set on child scans of --expand-subdomains
+	Children  []string         `json:"children,omitempty"`  // IDs of this scan's child scans
+
+	// FullEvidence holds untruncated evidence by fingerprint until the
+	// store writes it alongside the result
+	FullEvidence map[string]string `json:"-"`
 }
 
 // Finding represents a security finding
 type Finding struct {
 	ID          string            `json:"id"`
+	Fingerprint string            `json:"fingerprint"` // stable across rescans, see ComputeFingerprint
 	Type        string            `json:"type"`
-	Severity    string            `json:"severity"` // critical, high, medium, low, info
+	Severity    string            `json:"severity"`             // critical, high, medium, low, info
+	Confidence  Confidence        `json:"confidence,omitempty"` // high, medium, low; empty counts as medium
 	Title       string            `json:"title"`
 	Description string            `json:"description"`
 	Evidence    string            `json:"evidence"`
@@ -36,6 +62,7 @@ type Finding struct {
 	CVSS        float64           `json:"cvss,omitempty"`
 	Tags        []string          `json:"tags"`
 	Metadata    map[string]string `json:"metadata"`
+	Notes       []Note            `json:"notes,omitempty"` // analyst comments, see AddNote
 	Timestamp   time.Time         `json:"timestamp"`
 }
 
@@ -78,17 +105,18 @@ type OpenPort struct {
 
 // SSLResult represents SSL/TLS analysis
 type SSLResult struct {
-	Target       string        `json:"target"`
-	Valid        bool          `json:"valid"`
-	Issuer       string        `json:"issuer"`
-	Subject      string        `json:"subject"`
-	NotBefore    time.Time     `json:"not_before"`
-	NotAfter     time.Time     `json:"not_after"`
-	DaysToExpiry int           `json:"days_to_expiry"`
-	Version      string        `json:"version"`
-	Cipher       string        `json:"cipher"`
-	Issues       []string      `json:"issues"`
-	Grade        string        `json:"grade"` // A+, A, B, C, D, F
+	Target            string    `json:"target"`
+	Valid             bool      `json:"valid"`
+	Issuer            string    `json:"issuer"`
+	Subject           string    `json:"subject"`
+	NotBefore         time.Time `json:"not_before"`
+	NotAfter          time.Time `json:"not_after"`
+	DaysToExpiry      int       `json:"days_to_expiry"`
+	Version           string    `json:"version"` // negotiated by default
+	Cipher            string    `json:"cipher"`
+	SupportedVersions []string  `json:"supported_versions"` // every protocol version the server accepts
+	Issues            []string  `json:"issues"`
+	Grade             string    `json:"grade"` // A+, A, B, C, D, F
 }
 
 // AIAnalysis represents AI-powered analysis results
@@ -98,10 +126,22 @@ type AIAnalysis struct {
 	CriticalIssues  []string           `json:"critical_issues"`
 	Recommendations []Recommendation   `json:"recommendations"`
 	AttackChains    []AttackChain      `json:"attack_chains"`
-	RiskScore       int                `json:"risk_score"` // 0-100
+	RiskScore       int                `json:"risk_score"`                   // 0-100
+	Confidence      map[string]string  `json:"finding_confidence,omitempty"` // finding title -> revised confidence
+	Note            string             `json:"note,omitempty"`               // e.g. why the analysis is partial
+	RawText         string             `json:"raw_text,omitempty"`           // the model's full response, kept with --raw
 	Timestamp       time.Time          `json:"timestamp"`
 }
 
+// ResearchSummary is the outcome of an autonomous research run
+type ResearchSummary struct {
+	Phases      []string      `json:"phases"`
+	Conclusions string        `json:"conclusions"`
+	Note        string        `json:"note,omitempty"` // why the research is partial
+	Duration    time.Duration `json:"duration"`
+	Timestamp   time.Time     `json:"timestamp"`
+}
+
 // Recommendation represents an AI-generated recommendation
 type Recommendation struct {
 	Priority    string `json:"priority"` // critical, high, medium, low
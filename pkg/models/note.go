@@ -0,0 +1,28 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Note is an analyst's comment on a finding, e.g. "verified exploitable".
+// Notes are kept with the stored scan and shown in reports.
+type Note struct {
+	Author    string    `json:"author"`
+	Text      string    `json:"text"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// AddNote appends a note by author to the finding. Blank notes are ignored.
+func (f *Finding) AddNote(author string, text string) bool {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return false
+	}
+	f.Notes = append(f.Notes, Note{
+		Author:    author,
+		Text:      text,
+		Timestamp: time.Now(),
+	})
+	return true
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAddNote(t *testing.T) {
+	var finding Finding
+	if finding.AddNote("alice", "   ") || len(finding.Notes) != 0 {
+		t.Error("blank note was added")
+	}
+
+	before := time.Now()
+	if !finding.AddNote("alice", "  verified exploitable \n") || !finding.AddNote("bob", "ticket SEC-12") {
+		t.Fatal("note not added")
+	}
+	if len(finding.Notes) != 2 {
+		t.Fatalf("notes = %+v, want both kept in order", finding.Notes)
+	}
+	note := finding.Notes[0]
+	if note.Author != "alice" || note.Text != "verified exploitable" || note.Timestamp.Before(before) {
+		t.Errorf("note = %+v, want trimmed text with author and time", note)
+	}
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Research run states
+const (
+	ResearchRunning     = "running"
+	ResearchCompleted   = "completed"
+	ResearchInterrupted = "interrupted" // cancelled or past its deadline; resumable
+	ResearchFailed      = "failed"      // an iteration failed; resumable
+)
+
+// ResearchIteration is the outcome of one autonomous research iteration
+type ResearchIteration struct {
+	Number        int       `json:"number"`
+	Phase         string    `json:"phase"`
+	Findings      string    `json:"findings"` // the model's response
+	NewHypotheses []string  `json:"new_hypotheses,omitempty"`
+	NextSteps     []string  `json:"next_steps,omitempty"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// ResearchRun is the persisted state of an autonomous research run. It is
+// saved after every iteration, so a run that fails or is interrupted can
+// be resumed without repeating the completed iterations.
+type ResearchRun struct {
+	ID         string              `json:"id"`
+	Target     string              `json:"target"`
+	Model      string              `json:"model"`
+	Thinking   string              `json:"thinking"`
+	Findings   []Finding           `json:"findings"` // the initial scan's findings, so a resume doesn't rescan
+	Iterations []ResearchIteration `json:"iterations"`
+	Status     string              `json:"status"`
+	Error      string              `json:"error,omitempty"`
+	StartTime  time.Time           `json:"start_time"`
+	UpdatedAt  time.Time           `json:"updated_at"`
+}
@@ -0,0 +1,161 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity is a finding severity level
+type Severity string
+
+const (
+	SeverityCritical Severity = "critical"
+	SeverityHigh     Severity = "high"
+	SeverityMedium   Severity = "medium"
+	SeverityLow      Severity = "low"
+	SeverityInfo     Severity = "info"
+)
+
+// SeverityLevels lists finding severities from most to least severe
+var SeverityLevels = []string{"critical", "high", "medium", "low", "info"}
+
+// SeverityRank orders severities so that higher is more severe.
+// Unknown severities rank below info.
+func SeverityRank(severity string) int {
+	switch strings.ToLower(strings.TrimSpace(severity)) {
+	case "critical":
+		return 5
+	case "high":
+		return 4
+	case "medium":
+		return 3
+	case "low":
+		return 2
+	case "info":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// AtLeastSeverity returns the findings whose severity ranks at or above
+// minimum. An empty minimum keeps every finding.
+func AtLeastSeverity(findings []Finding, minimum string) []Finding {
+	if minimum == "" {
+		return findings
+	}
+	kept := make([]Finding, 0, len(findings))
+	for _, finding := range findings {
+		if SeverityRank(finding.Severity) >= SeverityRank(minimum) {
+			kept = append(kept, finding)
+		}
+	}
+	return kept
+}
+
+// WithoutInfo returns the findings that aren't info-level, such as
+// "Target Reachable", which are context rather than issues
+func WithoutInfo(findings []Finding) []Finding {
+	kept := make([]Finding, 0, len(findings))
+	for _, finding := range findings {
+		if !strings.EqualFold(finding.Severity, string(SeverityInfo)) {
+			kept = append(kept, finding)
+		}
+	}
+	return kept
+}
+
+// DeriveSeverityFromCVSS maps a CVSS v3 base score to its qualitative band
+// (0.0 none → info, 0.1-3.9 low, 4.0-6.9 medium, 7.0-8.9 high, 9.0-10.0 critical)
+func DeriveSeverityFromCVSS(score float64) Severity {
+	switch {
+	case score >= 9.0:
+		return SeverityCritical
+	case score >= 7.0:
+		return SeverityHigh
+	case score >= 4.0:
+		return SeverityMedium
+	case score > 0:
+		return SeverityLow
+	default:
+		return SeverityInfo
+	}
+}
+
+// Metadata keys recording why a finding's severity was changed
+const (
+	MetaSeverityOriginal = "severity.original" // severity the module reported, before any change
+	MetaSeverityOverride = "severity.override" // config entry that set the severity
+)
+
+// ApplySeverityOverrides sets the severity of findings whose type has an
+// entry in overrides (finding type to severity), keeping the reported
+// severity in metadata. Returns how many findings changed.
+func ApplySeverityOverrides(findings []Finding, overrides map[string]string) int {
+	changed := 0
+	for i := range findings {
+		finding := &findings[i]
+		severity, ok := overrides[finding.Type]
+		if !ok || SeverityRank(severity) == 0 || strings.EqualFold(finding.Severity, severity) {
+			continue
+		}
+
+		if _, exists := finding.Metadata[MetaSeverityOriginal]; !exists {
+			finding.SetMeta(MetaSeverityOriginal, finding.Severity)
+		}
+		finding.SetMeta(MetaSeverityOverride, "scanning.severity_overrides."+finding.Type)
+		finding.Severity = strings.ToLower(severity)
+		changed++
+	}
+	return changed
+}
+
+// ReconcileSeverityWithCVSS makes a finding's severity agree with its CVSS
+// score. The CVSS-derived severity wins; when the reported severity differed,
+// the original value and a warning are kept in the finding's metadata.
+// Returns true if the severity was changed.
+func ReconcileSeverityWithCVSS(f *Finding) bool {
+	if f.CVSS <= 0 || f.CVSS > 10 {
+		return false
+	}
+
+	derived := DeriveSeverityFromCVSS(f.CVSS)
+	if strings.EqualFold(f.Severity, string(derived)) {
+		return false
+	}
+
+	if f.Metadata == nil {
+		f.Metadata = make(map[string]string)
+	}
+	f.Metadata[MetaSeverityOriginal] = f.Severity
+	f.Metadata["severity.warning"] = fmt.Sprintf("reported severity %q does not match CVSS %.1f (%s); using %s",
+		f.Severity, f.CVSS, derived, derived)
+	f.Severity = string(derived)
+
+	return true
+}
+
+// ComputeRiskScore derives a deterministic 0-100 risk score from findings.
+// It is used when no AI analysis is available to provide one. Adding
+// findings never lowers the score, so it also serves as a running score
+// while a scan is still in progress.
+func ComputeRiskScore(findings []Finding) int {
+	score := 0
+	for _, finding := range findings {
+		switch strings.ToLower(finding.Severity) {
+		case "critical":
+			score += 40
+		case "high":
+			score += 20
+		case "medium":
+			score += 8
+		case "low":
+			score += 2
+		}
+	}
+
+	if score > 100 {
+		return 100
+	}
+	return score
+}
@@ -0,0 +1,154 @@
+package models
+
+import "testing"
+
+func TestSeverityRank(t *testing.T) {
+	if SeverityRank(" Critical ") != 5 || SeverityRank("info") != 1 || SeverityRank("urgent") != 0 {
+		t.Error("severities ranked out of order")
+	}
+	for i := 1; i < len(SeverityLevels); i++ {
+		if SeverityRank(SeverityLevels[i-1]) <= SeverityRank(SeverityLevels[i]) {
+			t.Errorf("SeverityLevels out of order at %s", SeverityLevels[i])
+		}
+	}
+}
+
+func TestComputeRiskScore(t *testing.T) {
+	tests := []struct {
+		severities []string
+		want       int
+	}{
+		{nil, 0},
+		{[]string{"info", "info"}, 0},
+		{[]string{"HIGH", "medium", "low"}, 30},
+		{[]string{"critical", "critical", "critical"}, 100}, // capped
+	}
+	for _, tt := range tests {
+		findings := make([]Finding, 0, len(tt.severities))
+		for _, severity := range tt.severities {
+			findings = append(findings, Finding{Severity: severity})
+		}
+		if got := ComputeRiskScore(findings); got != tt.want {
+			t.Errorf("ComputeRiskScore(%v) = %d, want %d", tt.severities, got, tt.want)
+		}
+	}
+}
+
+func TestComputeRiskScoreNeverDecreases(t *testing.T) {
+	// As a scan adds findings, including ones already reported, the
+	// running score over the deduplicated findings only goes up
+	added := []Finding{
+		{Type: "open-port", Location: "example.com:22", Severity: "low"},
+		{Type: "xss", Location: "https://example.com/search", Severity: "high"},
+		{Type: "xss", Location: "https://example.com/search", Severity: "high"},
+		{Type: "config", Location: "https://example.com", Severity: "info"},
+		{Type: "sqli", Location: "https://example.com/login", Severity: "critical"},
+		{Type: "rce", Location: "https://example.com/upload", Severity: "critical"},
+		{Type: "rce", Location: "https://example.com/admin", Severity: "critical"},
+	}
+	var findings []Finding
+	previous := 0
+	for _, finding := range added {
+		findings = append(findings, finding)
+		score := ComputeRiskScore(DedupFindings(findings))
+		if score < previous {
+			t.Errorf("score dropped from %d to %d after adding %s", previous, score, finding.Type)
+		}
+		previous = score
+	}
+	if previous != 100 {
+		t.Errorf("final score = %d, want 100", previous)
+	}
+}
+
+func TestDeriveSeverityFromCVSS(t *testing.T) {
+	for score, want := range map[float64]Severity{
+		0:   SeverityInfo,
+		0.1: SeverityLow,
+		3.9: SeverityLow,
+		4.0: SeverityMedium,
+		6.9: SeverityMedium,
+		7.0: SeverityHigh,
+		8.9: SeverityHigh,
+		9.0: SeverityCritical,
+		10:  SeverityCritical,
+	} {
+		if got := DeriveSeverityFromCVSS(score); got != want {
+			t.Errorf("DeriveSeverityFromCVSS(%.1f) = %s, want %s", score, got, want)
+		}
+	}
+}
+
+func TestReconcileSeverityWithCVSS(t *testing.T) {
+	mismatched := Finding{Severity: "low", CVSS: 9.8}
+	if !ReconcileSeverityWithCVSS(&mismatched) {
+		t.Fatal("a low finding with CVSS 9.8 was left alone")
+	}
+	if mismatched.Severity != "critical" || mismatched.Metadata["severity.original"] != "low" || mismatched.Metadata["severity.warning"] == "" {
+		t.Errorf("reconciled finding = %s, %v; want critical with the original and a warning kept", mismatched.Severity, mismatched.Metadata)
+	}
+
+	for _, finding := range []Finding{
+		{Severity: "HIGH", CVSS: 7.5},  // already agrees
+		{Severity: "medium"},           // no score
+		{Severity: "medium", CVSS: 11}, // out of range
+	} {
+		before := finding.Severity
+		if ReconcileSeverityWithCVSS(&finding) || finding.Severity != before {
+			t.Errorf("severity %s with CVSS %.1f changed to %s", before, finding.CVSS, finding.Severity)
+		}
+	}
+}
+
+func TestAtLeastSeverity(t *testing.T) {
+	findings := []Finding{{Severity: "info"}, {Severity: "HIGH"}, {Severity: "medium"}, {Severity: "critical"}}
+	if got := AtLeastSeverity(findings, ""); len(got) != 4 {
+		t.Errorf("empty minimum kept %d findings, want all", len(got))
+	}
+	got := AtLeastSeverity(findings, "high")
+	if len(got) != 2 || got[0].Severity != "HIGH" || got[1].Severity != "critical" {
+		t.Errorf("AtLeastSeverity(high) = %+v", got)
+	}
+}
+
+func TestWithoutInfo(t *testing.T) {
+	findings := []Finding{{Title: "Target Reachable", Severity: "INFO"}, {Title: "Open port", Severity: "low"}}
+	got := WithoutInfo(findings)
+	if len(got) != 1 || got[0].Title != "Open port" {
+		t.Errorf("WithoutInfo = %+v, want only the low finding", got)
+	}
+	if len(findings) != 2 {
+		t.Error("WithoutInfo changed its input")
+	}
+}
+
+func TestApplySeverityOverrides(t *testing.T) {
+	findings := []Finding{
+		{Type: "security-header", Severity: "low"},
+		{Type: "security-header", Severity: "high"},
+		{Type: "open-port", Severity: "info"},
+		{Type: "mixed-content", Severity: "medium"},
+	}
+	overrides := map[string]string{
+		"security-header": "High",
+		"open-port":       "bogus", // unknown severities are ignored
+	}
+
+	if changed := ApplySeverityOverrides(findings, overrides); changed != 1 {
+		t.Errorf("changed %d findings, want 1", changed)
+	}
+	first := findings[0]
+	if first.Severity != "high" || first.Metadata[MetaSeverityOriginal] != "low" ||
+		first.Metadata[MetaSeverityOverride] != "scanning.severity_overrides.security-header" {
+		t.Errorf("overridden finding = %s, %v", first.Severity, first.Metadata)
+	}
+	if findings[1].Metadata != nil || findings[2].Severity != "info" || findings[3].Severity != "medium" {
+		t.Errorf("findings without an applicable override changed: %+v", findings[1:])
+	}
+
+	// A second pass keeps the severity the module first reported
+	ApplySeverityOverrides(findings, map[string]string{"security-header": "critical"})
+	if findings[0].Metadata[MetaSeverityOriginal] != "low" {
+		t.Errorf("original severity = %q after a second override, want low", findings[0].Metadata[MetaSeverityOriginal])
+	}
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Verification outcomes for a re-tested finding
+const (
+	VerificationFixed        = "fixed"         // the check ran and the issue no longer reproduces
+	VerificationStillPresent = "still-present" // the check ran and found the issue again
+	VerificationInconclusive = "inconclusive"  // the check couldn't run or has no re-test
+)
+
+// Verification is the outcome of re-testing one finding of a previous scan
+type Verification struct {
+	Finding Finding `json:"finding"`
+	Status  string  `json:"status"`
+	Detail  string  `json:"detail"` // why the status was chosen
+}
+
+// VerificationResult is a remediation check of a stored scan: each of its
+// findings re-tested at the same location, without a full rescan
+type VerificationResult struct {
+	ScanID        string         `json:"scan_id"` // the scan whose findings were re-tested
+	Target        string         `json:"target"`
+	StartTime     time.Time      `json:"start_time"`
+	EndTime       time.Time      `json:"end_time"`
+	Verifications []Verification `json:"verifications"`
+}
+
+// Count returns how many findings ended with the given status
+func (r *VerificationResult) Count(status string) int {
+	count := 0
+	for _, verification := range r.Verifications {
+		if verification.Status == status {
+			count++
+		}
+	}
+	return count
+}